@@ -1,23 +1,28 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"github.com/joscha-alisch/dyve/internal/core/alerts"
 	"github.com/joscha-alisch/dyve/internal/core/api"
 	"github.com/joscha-alisch/dyve/internal/core/apps"
 	"github.com/joscha-alisch/dyve/internal/core/config"
 	coreDb "github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/discovery"
 	"github.com/joscha-alisch/dyve/internal/core/groups"
 	"github.com/joscha-alisch/dyve/internal/core/instances"
+	"github.com/joscha-alisch/dyve/internal/core/metrics"
 	"github.com/joscha-alisch/dyve/internal/core/pipelines"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
 	coreRecon "github.com/joscha-alisch/dyve/internal/core/reconciler"
 	"github.com/joscha-alisch/dyve/internal/core/routing"
 	"github.com/joscha-alisch/dyve/internal/core/service"
 	"github.com/joscha-alisch/dyve/internal/core/teams"
-	providerClient "github.com/joscha-alisch/dyve/internal/provider/client"
+	"github.com/joscha-alisch/dyve/internal/core/topology"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/pipeviz"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"net/http"
@@ -56,13 +61,18 @@ func main() {
 		panic(err)
 	}
 
-	providerService := provider.NewService(db)
-	teamService := teams.NewService(db)
-	appService := apps.NewService(db)
+	providerService := provider.NewServiceWithLeases(db, provider.LeaseOwner(),
+		time.Duration(c.Reconciliation.LeaseSeconds)*time.Second,
+	)
 	groupService := groups.NewService(db, providerService)
+	teamService := teams.NewService(db, groupService)
+	appService := apps.NewService(db)
 	pipelineService := pipelines.NewService(db)
 	routingService := routing.NewService(db)
 	instancesService := instances.NewService(db)
+	metricsService := metrics.NewService(providerService)
+	alertsService := alerts.NewService(db)
+	topologyService := topology.NewService(db)
 
 	core := service.Core{
 		Teams:     teamService,
@@ -72,6 +82,9 @@ func main() {
 		Pipelines: pipelineService,
 		Routing:   routingService,
 		Instances: instancesService,
+		Metrics:   metricsService,
+		Alerts:    alertsService,
+		Topology:  topologyService,
 	}
 
 	err = core.Pipelines.EnsureIndices()
@@ -84,44 +97,33 @@ func main() {
 		panic(err)
 	}
 
-	for _, providerConfig := range c.Providers {
-		for _, feature := range providerConfig.Features {
-			switch feature {
-			case provider.TypeApps:
-				p := providerClient.NewAppProviderClient(providerConfig.Host, nil)
-				err = core.Providers.AddAppProvider(providerConfig.Id, providerConfig.Name, p)
-				if err != nil {
-					panic(err)
-				}
-			case provider.TypePipelines:
-				p := providerClient.NewPipelineProviderClient(providerConfig.Host, nil)
-				err = core.Providers.AddPipelineProvider(providerConfig.Id, providerConfig.Name, p)
-				if err != nil {
-					panic(err)
-				}
-			case provider.TypeGroups:
-				p := providerClient.NewGroupProviderClient(providerConfig.Host, nil)
-				err = core.Providers.AddGroupProvider(providerConfig.Id, providerConfig.Name, p)
-				if err != nil {
-					panic(err)
-				}
-			case provider.TypeRouting:
-				p := providerClient.NewRoutingProviderClient(providerConfig.Host, nil)
-				err = core.Providers.AddRoutingProvider(providerConfig.Id, providerConfig.Name, p)
-				if err != nil {
-					panic(err)
-				}
-			case provider.TypeInstances:
-				p := providerClient.NewInstancesProviderClient(providerConfig.Host, nil)
-				err = core.Providers.AddInstancesProvider(providerConfig.Id, providerConfig.Name, p)
-				if err != nil {
-					panic(err)
-				}
-			}
-		}
+	err = core.Providers.EnsureIndices()
+	if err != nil {
+		panic(err)
+	}
+
+	d, err := discovery.New(core.Providers, c.Providers, c.Credentials)
+	if err != nil {
+		panic(err)
+	}
+
+	err = d.Restore()
+	if err != nil {
+		panic(err)
+	}
+
+	err = d.Discover()
+	if errors.Is(err, sdk.ErrIncompatibleProtocol) {
+		panic(err)
+	} else if err != nil {
+		log.Error().Err(err).Msg("couldn't discover all providers, retrying later")
 	}
+	d.Run(time.Duration(c.Reconciliation.DiscoverySeconds) * time.Second)
 
-	r := coreRecon.NewReconciler(core, time.Duration(c.Reconciliation.CacheSeconds)*time.Second)
+	r := coreRecon.NewReconciler(core,
+		time.Duration(c.Reconciliation.CacheSeconds)*time.Second,
+		time.Duration(c.Reconciliation.TimeoutSeconds)*time.Second,
+	)
 	s := recon.NewScheduler(r)
 	err = s.Run(8, 100*time.Millisecond)
 	if err != nil {
@@ -132,6 +134,7 @@ func main() {
 		DevConfig: c.DevConfig,
 		Url:       c.ExternalUrl,
 		Auth:      c.Auth,
+		Discovery: d,
 	})
 
 	err = http.ListenAndServe(fmt.Sprintf(":%d", c.Port), a)
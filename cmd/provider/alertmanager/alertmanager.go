@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joscha-alisch/dyve/internal/provider/alertmanager"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "./config.yaml", "path to config file")
+}
+
+func main() {
+	flag.Parse()
+	c, err := LoadFrom(configPath)
+	if err != nil {
+		panic(err)
+	}
+
+	p := alertmanager.NewProvider(alertmanager.NewDefaultApi(c.Alertmanager), c.Labels)
+
+	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:   "alertmanager",
+		Auth:   c.Auth,
+		Alerts: p,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/joscha-alisch/dyve/internal/provider/alertmanager"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Port         int                 `yaml:"port"`
+	Alertmanager alertmanager.Login  `yaml:"alertmanager"`
+	Labels       alertmanager.Labels `yaml:"labels"`
+	Auth         sdk.AuthConfig      `yaml:"auth"`
+}
+
+func LoadFrom(path string) (Config, error) {
+	viper.SetConfigFile(path)
+	viper.SetEnvPrefix("dyve")
+	err := viper.ReadInConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	viper.AutomaticEnv()
+
+	c := Config{}
+	err = viper.Unmarshal(&c)
+	if err != nil {
+		return Config{}, err
+	}
+	return c, err
+}
@@ -22,9 +22,16 @@ func main() {
 		panic(err)
 	}
 
-	cf, err := cloudfoundry.NewDefaultApi(cloudfoundry.CFLogin{
+	login := cloudfoundry.CFLogin{
 		Api: c.CloudFoundry.Api, User: c.CloudFoundry.User, Pass: c.CloudFoundry.Password,
-	})
+	}
+
+	cf, err := cloudfoundry.NewDefaultApi(login)
+	if err != nil {
+		panic(err)
+	}
+
+	logs, err := cloudfoundry.NewDefaultLogCache(login, c.CloudFoundry.LogCache)
 	if err != nil {
 		panic(err)
 	}
@@ -36,7 +43,7 @@ func main() {
 
 	r := cloudfoundry.NewReconciler(db, cf, time.Duration(c.Reconciliation.CacheSeconds)*time.Second)
 	s := recon.NewScheduler(r)
-	p := cloudfoundry.NewProvider(db, cf)
+	p := cloudfoundry.NewProvider(db, cf, logs, c.Teams)
 
 	err = s.Run(8, 10*time.Second)
 	if err != nil {
@@ -44,9 +51,16 @@ func main() {
 	}
 
 	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:      "cloudfoundry",
+		Auth:      c.Auth,
 		Apps:      p,
 		Routing:   p,
 		Instances: p,
+		Logs:      p,
+		Topology:  p,
+
+		AppActions: p,
+		Jobs:       p,
 	})
 	if err != nil {
 		panic(err)
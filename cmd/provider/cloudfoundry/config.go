@@ -1,20 +1,27 @@
 package main
 
 import (
+	"github.com/joscha-alisch/dyve/internal/provider/cloudfoundry"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	Database       DatabaseConfig
-	Port           int         `yaml:"port"`
-	CloudFoundry   CfConfig    `yaml:"cloudfoundry"`
-	Reconciliation ReconConfig `yaml:"reconciliation"`
+	Port           int            `yaml:"port"`
+	CloudFoundry   CfConfig       `yaml:"cloudfoundry"`
+	Reconciliation ReconConfig    `yaml:"reconciliation"`
+	Auth           sdk.AuthConfig `yaml:"auth"`
+	// Teams maps orgs and spaces to the teams that may take actions on their apps.
+	Teams cloudfoundry.Teams `yaml:"teams"`
 }
 
 type CfConfig struct {
 	Api      string `yaml:"api"`
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
+	// LogCache is the address of the log cache, defaults to the api address with 'api.' replaced by 'log-cache.'.
+	LogCache string `yaml:"logCache"`
 }
 
 type DatabaseConfig struct {
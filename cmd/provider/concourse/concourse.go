@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joscha-alisch/dyve/internal/provider/concourse"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "./config.yaml", "path to config file")
+}
+
+func main() {
+	flag.Parse()
+	c, err := LoadFrom(configPath)
+	if err != nil {
+		panic(err)
+	}
+
+	cc, err := concourse.NewDefaultApi(c.Concourse.Login)
+	if err != nil {
+		panic(err)
+	}
+
+	p := concourse.NewProvider(cc, c.Concourse.Teams)
+
+	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:      "concourse",
+		Auth:      c.Auth,
+		Pipelines: p,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
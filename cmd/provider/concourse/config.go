@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/joscha-alisch/dyve/internal/provider/concourse"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Port      int             `yaml:"port"`
+	Concourse ConcourseConfig `yaml:"concourse"`
+	Auth      sdk.AuthConfig  `yaml:"auth"`
+}
+
+type ConcourseConfig struct {
+	Login concourse.Login `yaml:"login"`
+	Teams []string        `yaml:"teams"`
+}
+
+func LoadFrom(path string) (Config, error) {
+	viper.SetConfigFile(path)
+	viper.SetEnvPrefix("dyve")
+	err := viper.ReadInConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	viper.AutomaticEnv()
+
+	c := Config{}
+	err = viper.Unmarshal(&c)
+	if err != nil {
+		return Config{}, err
+	}
+	return c, err
+}
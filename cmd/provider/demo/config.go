@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/spf13/viper"
+	"os"
+)
+
+type Config struct {
+	Port int            `yaml:"port"`
+	Auth sdk.AuthConfig `yaml:"auth"`
+}
+
+// LoadFrom reads the config from the file at path. Unlike the other providers the demo
+// runs without a config file, serving on port 9003 without authentication.
+func LoadFrom(path string) (Config, error) {
+	viper.SetConfigFile(path)
+	viper.SetEnvPrefix("dyve")
+	viper.SetDefault("port", 9003)
+
+	_, err := os.Stat(path)
+	if err == nil {
+		err = viper.ReadInConfig()
+		if err != nil {
+			return Config{}, err
+		}
+	}
+
+	viper.AutomaticEnv()
+
+	c := Config{}
+	err = viper.Unmarshal(&c)
+	if err != nil {
+		return Config{}, err
+	}
+	return c, err
+}
@@ -1,18 +1,36 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/joscha-alisch/dyve/internal/provider/demo"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 )
 
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "./config.yaml", "path to config file")
+}
+
 func main() {
+	flag.Parse()
+	c, err := LoadFrom(configPath)
+	if err != nil {
+		panic(err)
+	}
+
 	p := demo.NewProvider()
 
-	err := sdk.ListenAndServe(":9003", sdk.ProviderConfig{
-		Apps:      p,
-		Pipelines: p,
-		Instances: p,
-		Routing:   p,
+	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:      "demo",
+		Auth:      c.Auth,
+		Apps:      sdk.AppProviderWithContext(p),
+		Pipelines: sdk.PipelineProviderWithContext(p),
+		Instances: sdk.InstancesProviderWithContext(p),
+		Routing:   sdk.RoutingProviderWithContext(p),
+		StepLogs:  p,
+		Topology:  p,
 	})
 	if err != nil {
 		panic(err)
@@ -2,19 +2,22 @@ package main
 
 import (
 	"github.com/joscha-alisch/dyve/internal/provider/github"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	Database       DatabaseConfig
-	Port           int          `yaml:"port"`
-	GitHub         GithubConfig `yaml:"github"`
-	Reconciliation ReconConfig  `yaml:"reconciliation"`
+	Port           int            `yaml:"port"`
+	GitHub         GithubConfig   `yaml:"github"`
+	Reconciliation ReconConfig    `yaml:"reconciliation"`
+	Auth           sdk.AuthConfig `yaml:"auth"`
 }
 
 type GithubConfig struct {
-	Login github.Login
-	Org   string
+	Login        github.Login
+	Org          string
+	Repositories []string
 }
 
 type DatabaseConfig struct {
@@ -43,9 +43,14 @@ func main() {
 	}
 
 	p := github.NewGroupProvider(db)
+	pipelines := github.NewPipelineProvider(gh, c.GitHub.Org, c.GitHub.Repositories)
 
 	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
-		Groups: p,
+		Name:      "github",
+		Auth:      c.Auth,
+		Groups:    p,
+		Pipelines: pipelines,
+		Jobs:      github.NewJobsProvider(db),
 	})
 	if err != nil {
 		panic(err)
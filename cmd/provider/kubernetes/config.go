@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/joscha-alisch/dyve/internal/provider/kubernetes"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Database       DatabaseConfig
+	Port           int              `yaml:"port"`
+	Kubernetes     kubernetes.Login `yaml:"kubernetes"`
+	Reconciliation ReconConfig      `yaml:"reconciliation"`
+	Auth           sdk.AuthConfig   `yaml:"auth"`
+}
+
+type DatabaseConfig struct {
+	URI  string `yaml:"uri"`
+	Name string `yaml:"name"`
+}
+
+type ReconConfig struct {
+	CacheSeconds int `yaml:"cacheSeconds"`
+}
+
+func LoadFrom(path string) (Config, error) {
+	viper.SetConfigFile(path)
+	viper.SetEnvPrefix("dyve")
+	err := viper.ReadInConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	viper.AutomaticEnv()
+
+	c := Config{}
+	err = viper.Unmarshal(&c)
+	if err != nil {
+		return Config{}, err
+	}
+	return c, err
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joscha-alisch/dyve/internal/provider/kubernetes"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"time"
+)
+
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "./config.yaml", "path to config file")
+}
+
+func main() {
+	flag.Parse()
+	c, err := LoadFrom(configPath)
+	if err != nil {
+		panic(err)
+	}
+
+	k8s, err := kubernetes.NewDefaultApi(c.Kubernetes)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := kubernetes.NewMongoDatabase(kubernetes.MongoLogin{Uri: c.Database.URI, DB: c.Database.Name})
+	if err != nil {
+		panic(err)
+	}
+
+	r := kubernetes.NewReconciler(db, k8s, time.Duration(c.Reconciliation.CacheSeconds)*time.Second)
+	s := recon.NewScheduler(r)
+	p := kubernetes.NewProvider(db, k8s)
+
+	err = s.Run(8, 10*time.Second)
+	if err != nil {
+		panic(err)
+	}
+
+	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:      "kubernetes",
+		Auth:      c.Auth,
+		Apps:      p,
+		Routing:   p,
+		Instances: p,
+		Topology:  p,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
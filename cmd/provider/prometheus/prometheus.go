@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/joscha-alisch/dyve/internal/provider/prometheus"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+var configPath string
+
+func init() {
+	flag.StringVar(&configPath, "config", "./config.yaml", "path to config file")
+}
+
+func main() {
+	flag.Parse()
+	c, err := LoadFrom(configPath)
+	if err != nil {
+		panic(err)
+	}
+
+	p := prometheus.NewProvider(prometheus.NewDefaultApi(c.Prometheus), c.Queries)
+
+	err = sdk.ListenAndServe(fmt.Sprintf(":%d", c.Port), sdk.ProviderConfig{
+		Name:    "prometheus",
+		Auth:    c.Auth,
+		Metrics: p,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
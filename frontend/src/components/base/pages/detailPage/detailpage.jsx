@@ -8,10 +8,23 @@ import {FontAwesomeIcon} from "@fortawesome/react-fontawesome";
 import {faEdit, faSync, faTrash} from "@fortawesome/free-solid-svg-icons";
 import {ReadyState} from "react-use-websocket";
 
+// maxLogLines is the number of log lines kept when streaming logs
+const maxLogLines = 500
+
+// mergeLive merges a message of the live websocket into the previous state. Log lines are
+// appended, everything else replaces what was sent before.
+const mergeLive = (live = {}, data) => {
+    if (data.logs) {
+        return {...live, logs: [...(live.logs || []), ...data.logs].slice(-maxLogLines)}
+    }
+    return {...live, ...data}
+}
+
 const DetailPage = ({
     detailApi,
     useLive = (id, setDetail) => {return {status: "connecting", send: () => {}}},
     render,
+    streamLogs = false,
     title = (detail) => detail && detail.name,
     className,
     parent,
@@ -22,14 +35,17 @@ const DetailPage = ({
 }) => {
     const {id} = useParams()
     const [state, setState] = useState({})
-    const {status, send} = useLive(id, (liveData) => setState({
+    const {status, send} = useLive(id, (liveData) => setState((state) => ({
         ...state,
-        live: liveData
-    }))
+        live: mergeLive(state.live, liveData)
+    })))
 
     useEffect(() => {
         if (status === "Open") {
             send("update")
+            if (streamLogs) {
+                send("logs")
+            }
         }
     }, [status])
 
@@ -0,0 +1,23 @@
+import React from "react"
+import PropTypes from "prop-types"
+import Box from "../../base/box/box";
+import styles from "./logscard.module.sass"
+import {CircularProgress} from "@mui/material";
+
+const LogsCard = ({className, logs}) => <Box title="Logs" className={styles.Main}>
+    <pre className={styles.Logs}>
+        { !logs && <CircularProgress />}
+        { logs && logs.map((line, index) => <div key={index} className={line.stream === "stderr" ? styles.Err : styles.Out}>
+            <span className={styles.Date}>{(new Date(line.time)).toISOString()}</span>
+            {line.instance && <span className={styles.Instance}>[{line.source ? line.source + "/" : ""}{line.instance}]</span>}
+            {line.message}
+        </div>)}
+    </pre>
+</Box>
+
+
+LogsCard.propTypes = {
+    className: PropTypes.string,
+}
+
+export default LogsCard
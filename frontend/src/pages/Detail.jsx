@@ -8,15 +8,18 @@ import Box from "../components/base/box/box";
 import {prettyJ} from "../helpers/pretty";
 import RoutingCard from "../components/cards/routing/routingcard";
 import InstancesCard from "../components/cards/instances/instancescard";
+import LogsCard from "../components/cards/logs/logscard";
 
 export const AppDetail = () => <DetailPage
     parentRoute={"/apps"}
     parent={"Apps"}
     detailApi={API.Apps.Get}
     useLive={API.Apps.Live}
+    streamLogs={true}
     render={(detail, live) => <div>
         <RoutingCard routing={live.routing}/>
         <InstancesCard instances={live.instances} />
+        <LogsCard logs={live.logs} />
     </div>}
 />
 
@@ -0,0 +1,8 @@
+package alerts
+
+import "github.com/joscha-alisch/dyve/pkg/provider/sdk"
+
+type Alert struct {
+	sdk.Alert  `json:",inline" bson:",inline"`
+	ProviderId string `json:"providerId" bson:"provider"`
+}
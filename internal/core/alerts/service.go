@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const Collection database.Collection = "alerts"
+
+type Service interface {
+	// UpdateAlerts replaces the active alerts of the provider, resolving the ones no longer listed.
+	UpdateAlerts(providerId string, alerts []sdk.Alert) error
+	// ListAppAlerts returns the active alerts of the app, newest first.
+	ListAppAlerts(app string) ([]Alert, error)
+	// ListTeamAlerts returns the active alerts the team is responsible for, newest first.
+	ListTeamAlerts(team string) ([]Alert, error)
+}
+
+func NewService(db database.Database) Service {
+	return &service{
+		db: db,
+	}
+}
+
+type service struct {
+	db database.Database
+}
+
+func (s *service) UpdateAlerts(providerId string, alerts []sdk.Alert) error {
+	if len(alerts) == 0 {
+		return s.db.DeleteProvided(Collection, providerId)
+	}
+
+	alertMap := make(map[string]interface{}, len(alerts))
+	for _, alert := range alerts {
+		alertMap[alert.Id] = alert
+	}
+	return s.db.UpdateProvided(Collection, providerId, alertMap)
+}
+
+func (s *service) ListAppAlerts(app string) ([]Alert, error) {
+	return s.list(bson.M{"appId": app})
+}
+
+func (s *service) ListTeamAlerts(team string) ([]Alert, error) {
+	return s.list(bson.M{"team": team})
+}
+
+func (s *service) list(filter bson.M) ([]Alert, error) {
+	res := []Alert{}
+	err := s.db.FindManyWithOptions(Collection, filter, func(c database.Decodable) error {
+		alert := Alert{}
+		err := c.Decode(&alert)
+		if err != nil {
+			return err
+		}
+		res = append(res, alert)
+		return nil
+	}, bson.M{"started": -1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,163 @@
+package alerts
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+var someAlert = Alert{
+	Alert: sdk.Alert{
+		Id:       "alert-a",
+		AppId:    "app-a",
+		Team:     "team-a",
+		Kind:     sdk.AlertKindAlert,
+		Name:     "HighErrorRate",
+		Severity: sdk.AlertSeverityCritical,
+		Started:  someTime,
+	},
+	ProviderId: "provider-a",
+}
+var someErr = errors.New("some error")
+
+func TestService_UpdateAlerts(t *testing.T) {
+	tests := []struct {
+		desc        string
+		alerts      []sdk.Alert
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expectedErr error
+	}{
+		{
+			desc:   "updates alerts",
+			alerts: []sdk.Alert{someAlert.Alert},
+			db:     &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "alerts",
+				Provider:   "provider-a",
+				Updates:    map[string]interface{}{"alert-a": someAlert.Alert},
+			}},
+		},
+		{
+			desc: "resolves all alerts",
+			db:   &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "alerts",
+				Provider:   "provider-a",
+			}},
+		},
+		{
+			desc:        "error while updating alerts",
+			alerts:      []sdk.Alert{someAlert.Alert},
+			db:          &db.RecordingDatabase{Err: someErr},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db)
+			err := s.UpdateAlerts("provider-a", test.alerts)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
+
+func TestService_ListAlerts(t *testing.T) {
+	returnAlert := func(each func(decodable database.Decodable) error) {
+		_ = each(database.DecodableFunc(func(target interface{}) error {
+			*target.(*Alert) = someAlert
+			return nil
+		}))
+	}
+
+	tests := []struct {
+		desc        string
+		list        func(s Service) ([]Alert, error)
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expected    []Alert
+		expectedErr error
+	}{
+		{
+			desc: "lists app alerts",
+			list: func(s Service) ([]Alert, error) {
+				return s.ListAppAlerts("app-a")
+			},
+			db:       &db.RecordingDatabase{ReturnEach: returnAlert},
+			expected: []Alert{someAlert},
+			recorded: []db.DatabaseRecord{{
+				Collection: "alerts",
+				Filter:     bson.M{"appId": "app-a"},
+				Sort:       bson.M{"started": -1},
+			}},
+		},
+		{
+			desc: "lists team alerts",
+			list: func(s Service) ([]Alert, error) {
+				return s.ListTeamAlerts("team-a")
+			},
+			db:       &db.RecordingDatabase{ReturnEach: returnAlert},
+			expected: []Alert{someAlert},
+			recorded: []db.DatabaseRecord{{
+				Collection: "alerts",
+				Filter:     bson.M{"team": "team-a"},
+				Sort:       bson.M{"started": -1},
+			}},
+		},
+		{
+			desc: "lists no alerts",
+			list: func(s Service) ([]Alert, error) {
+				return s.ListAppAlerts("app-b")
+			},
+			db:       &db.RecordingDatabase{ReturnEach: func(each func(decodable database.Decodable) error) {}},
+			expected: []Alert{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "alerts",
+				Filter:     bson.M{"appId": "app-b"},
+				Sort:       bson.M{"started": -1},
+			}},
+		},
+		{
+			desc: "error while listing alerts",
+			list: func(s Service) ([]Alert, error) {
+				return s.ListAppAlerts("app-a")
+			},
+			db:          &db.RecordingDatabase{Err: someErr},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			res, err := test.list(NewService(test.db))
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("results mismatch: %s\n", cmp.Diff(test.expected, res))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
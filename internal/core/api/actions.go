@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"strconv"
+)
+
+var errForbidden = errors.New("admin or member access to the owning team is required")
+var errActionsUnsupported = errors.New("the provider of the app doesn't support actions")
+
+type appAction func(ctx context.Context, p sdk.AppActionsProvider, id string) error
+
+func (a *api) restartApp(w http.ResponseWriter, r *http.Request) {
+	a.takeAppAction(w, r, func(ctx context.Context, p sdk.AppActionsProvider, id string) error {
+		return p.RestartApp(ctx, id)
+	})
+}
+
+func (a *api) stopApp(w http.ResponseWriter, r *http.Request) {
+	a.takeAppAction(w, r, func(ctx context.Context, p sdk.AppActionsProvider, id string) error {
+		return p.StopApp(ctx, id)
+	})
+}
+
+func (a *api) startApp(w http.ResponseWriter, r *http.Request) {
+	a.takeAppAction(w, r, func(ctx context.Context, p sdk.AppActionsProvider, id string) error {
+		return p.StartApp(ctx, id)
+	})
+}
+
+func (a *api) scaleApp(w http.ResponseWriter, r *http.Request) {
+	req := sdk.AppScaleRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, sdk.ErrBodyMalformed)
+		return
+	}
+
+	err = req.Validate()
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	a.takeAppAction(w, r, func(ctx context.Context, p sdk.AppActionsProvider, id string) error {
+		return p.ScaleApp(ctx, id, req.Instances)
+	})
+}
+
+func (a *api) restartAppInstance(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(mux.Vars(r)["index"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	}
+
+	a.takeAppAction(w, r, func(ctx context.Context, p sdk.AppActionsProvider, id string) error {
+		return p.RestartAppInstance(ctx, id, index)
+	})
+}
+
+// takeAppAction authorizes the user against the team owning the app, then lets the app's
+// provider take the action and requests an update of the app's routing and instances.
+func (a *api) takeAppAction(w http.ResponseWriter, r *http.Request, action appAction) {
+	id := mux.Vars(r)["id"]
+
+	app, err := a.core.Apps.GetApp(id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.authorizeTeams(r, app.Team())
+	if errors.Is(err, errForbidden) {
+		respondErr(w, http.StatusForbidden, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	p, err := a.core.Providers.GetAppActionsProvider(app.ProviderId)
+	if errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusNotImplemented, errActionsUnsupported)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = action(r.Context(), p, id)
+	if errors.Is(err, sdk.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("app", id).Str("provider", app.ProviderId).Msg("error taking app action")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.core.Providers.RequestAppUpdate(id, provider.PriorityUser)
+	if err != nil {
+		log.Error().Err(err).Str("app", id).Msg("error requesting app update")
+	}
+
+	respondOk(w, nil)
+}
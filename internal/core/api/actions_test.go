@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/apps"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/internal/core/teams"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAppActions(t *testing.T) {
+	app := apps.App{ProviderId: "provider", App: sdk.App{
+		Id: "app-a", Labels: sdk.AppLabels{apps.TeamLabel: "team-a"},
+	}}
+	member := teams.ByAccess{Member: []teams.Team{{Id: "team-a"}}}
+
+	tests := []struct {
+		desc            string
+		path            string
+		body            string
+		app             apps.App
+		appErr          error
+		byAccess        teams.ByAccess
+		providerErr     error
+		noProvider      bool
+		expectedStatus  int
+		expectedErr     string
+		expectedActions []string
+		expectedUpdates []string
+	}{
+		{desc: "restarts app", path: "/api/apps/app-a/actions/restart", app: app, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"restart app-a"},
+			expectedUpdates: []string{"app-a"}},
+		{desc: "stops app", path: "/api/apps/app-a/actions/stop", app: app, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"stop app-a"},
+			expectedUpdates: []string{"app-a"}},
+		{desc: "starts app", path: "/api/apps/app-a/actions/start", app: app, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"start app-a"},
+			expectedUpdates: []string{"app-a"}},
+		{desc: "scales app", path: "/api/apps/app-a/actions/scale", body: `{"instances": 3}`, app: app,
+			byAccess: teams.ByAccess{Admin: []teams.Team{{Id: "team-a"}}}, expectedStatus: http.StatusOK,
+			expectedActions: []string{"scale app-a 3"}, expectedUpdates: []string{"app-a"}},
+		{desc: "restarts app instance", path: "/api/apps/app-a/actions/instances/1/restart", app: app, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"restart app-a 1"},
+			expectedUpdates: []string{"app-a"}},
+		{desc: "rejects negative scale", path: "/api/apps/app-a/actions/scale", body: `{"instances": -1}`, app: app,
+			byAccess: member, expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrScaleInvalid.Error()},
+		{desc: "rejects malformed scale", path: "/api/apps/app-a/actions/scale", body: `{`, app: app,
+			byAccess: member, expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrBodyMalformed.Error()},
+		{desc: "forbids viewers", path: "/api/apps/app-a/actions/restart", app: app,
+			byAccess: teams.ByAccess{Viewer: []teams.Team{{Id: "team-a"}}}, expectedStatus: http.StatusForbidden,
+			expectedErr: errForbidden.Error()},
+		{desc: "forbids members of other teams", path: "/api/apps/app-a/actions/restart", app: app,
+			byAccess: teams.ByAccess{Member: []teams.Team{{Id: "team-b"}}}, expectedStatus: http.StatusForbidden,
+			expectedErr: errForbidden.Error()},
+		{desc: "forbids actions on apps without team", path: "/api/apps/app-a/actions/restart",
+			app: apps.App{ProviderId: "provider", App: sdk.App{Id: "app-a"}}, byAccess: member,
+			expectedStatus: http.StatusForbidden, expectedErr: errForbidden.Error()},
+		{desc: "returns 404 for unknown app", path: "/api/apps/app-b/actions/restart", appErr: database.ErrNotFound,
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+		{desc: "returns 404 for unknown instance", path: "/api/apps/app-a/actions/instances/5/restart", app: app,
+			byAccess: member, providerErr: sdk.ErrNotFound, expectedStatus: http.StatusNotFound,
+			expectedErr: sdk.ErrNotFound.Error(), expectedActions: []string{"restart app-a 5"}},
+		{desc: "returns 501 without actions provider", path: "/api/apps/app-a/actions/restart", app: app,
+			byAccess: member, noProvider: true, expectedStatus: http.StatusNotImplemented,
+			expectedErr: errActionsUnsupported.Error()},
+		{desc: "returns 500 for failing provider", path: "/api/apps/app-a/actions/stop", app: app, byAccess: member,
+			providerErr: someErr, expectedStatus: http.StatusInternalServerError, expectedErr: sdk.ErrInternal.Error(),
+			expectedActions: []string{"stop app-a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := fakeProvider.AppActionsProvider()
+			p.Err = test.providerErr
+			providers := &fakes.ProviderService{AppActionsProviders: map[string]sdk.AppActionsProvider{}}
+			if !test.noProvider {
+				providers.AppActionsProviders["provider"] = p
+			}
+			teamsService := &fakes.RecordingTeamsService{ByAccess: test.byAccess}
+
+			h := New(service.Core{
+				Providers: providers,
+				Apps:      &fakes.RecordingAppsService{App: test.app, Err: test.appErr},
+				Teams:     teamsService,
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true, UserGroups: []string{"group-a"}},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", test.path, strings.NewReader(test.body)))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Err != test.expectedErr {
+				tt.Errorf("\nwanted error '%s', got '%s'", test.expectedErr, resp.Err)
+			}
+
+			if !cmp.Equal(test.expectedActions, p.RecordedActions) {
+				tt.Errorf("\ndiff between actions: \n%s\n", cmp.Diff(test.expectedActions, p.RecordedActions))
+			}
+			if !cmp.Equal(test.expectedUpdates, providers.AppUpdateRequests) {
+				tt.Errorf("\ndiff between app update requests: \n%s\n", cmp.Diff(test.expectedUpdates, providers.AppUpdateRequests))
+			}
+			if test.expectedStatus == http.StatusOK && !cmp.Equal([]string{"group-a"}, teamsService.Record.Groups) {
+				tt.Errorf("\nteams requested for wrong groups: %v", teamsService.Record.Groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/discovery"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+var errAdminRequired = errors.New("membership in an admin group is required")
+var errDefinitionExists = errors.New("a provider with this id already exists")
+var errDefinitionInvalid = errors.New("provider needs an id of lowercase letters, digits and dashes and an absolute host url")
+
+var providerIdPattern = regexp.MustCompile(`^[0-9a-z-]+$`)
+
+func (a *api) listProviderDefinitions(w http.ResponseWriter, r *http.Request) {
+	definitions, err := a.core.Providers.ListDefinitions()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, definitions)
+}
+
+func (a *api) getProviderDefinition(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	definition, err := a.core.Providers.GetDefinition(id)
+	if errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, definition)
+}
+
+func (a *api) createProviderDefinition(w http.ResponseWriter, r *http.Request) {
+	definition := provider.Definition{}
+	err := json.NewDecoder(r.Body).Decode(&definition)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, sdk.ErrBodyMalformed)
+		return
+	}
+
+	_, err = a.core.Providers.GetDefinition(definition.Id)
+	if err == nil {
+		respondErr(w, http.StatusConflict, errDefinitionExists)
+		return
+	} else if !errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	a.saveProviderDefinition(w, definition)
+}
+
+func (a *api) updateProviderDefinition(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	definition := provider.Definition{}
+	err := json.NewDecoder(r.Body).Decode(&definition)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, sdk.ErrBodyMalformed)
+		return
+	}
+	definition.Id = id
+
+	_, err = a.core.Providers.GetDefinition(id)
+	if errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	a.saveProviderDefinition(w, definition)
+}
+
+// saveProviderDefinition registers the provider before storing its definition, so that
+// definitions referencing unknown credentials are never stored.
+func (a *api) saveProviderDefinition(w http.ResponseWriter, definition provider.Definition) {
+	if !validDefinition(definition) {
+		respondErr(w, http.StatusBadRequest, errDefinitionInvalid)
+		return
+	}
+
+	_, err := a.discovery.Register(definition)
+	if errors.Is(err, discovery.ErrUnknownCredentials) {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("provider", definition.Id).Msg("error registering provider")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.core.Providers.SaveDefinition(definition)
+	if err != nil {
+		log.Error().Err(err).Str("provider", definition.Id).Msg("error saving provider definition")
+		err = a.discovery.Unregister(definition.Id)
+		if err != nil {
+			log.Error().Err(err).Str("provider", definition.Id).Msg("error unregistering unsaved provider")
+		}
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, definition)
+}
+
+func (a *api) deleteProviderDefinition(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	_, err := a.core.Providers.GetDefinition(id)
+	if errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.discovery.Unregister(id)
+	if err != nil && !errors.Is(err, provider.ErrNotFound) {
+		log.Error().Err(err).Str("provider", id).Msg("error unregistering provider")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.core.Providers.DeleteDefinition(id)
+	if err != nil && !errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, nil)
+}
+
+func validDefinition(d provider.Definition) bool {
+	if !providerIdPattern.MatchString(d.Id) {
+		return false
+	}
+
+	u, err := url.Parse(d.Host)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
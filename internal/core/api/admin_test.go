@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/discovery"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminProviders(t *testing.T) {
+	stored := provider.Definition{Id: "provider-a", Name: "Provider A", Host: "https://provider-a.com"}
+
+	tests := []struct {
+		desc                 string
+		method               string
+		path                 string
+		body                 string
+		groups               []string
+		discoveryErr         error
+		expectedStatus       int
+		expectedErr          string
+		expectedResult       interface{}
+		expectedDefinitions  map[string]provider.Definition
+		expectedRegistered   []provider.Definition
+		expectedUnregistered []string
+	}{
+		{desc: "lists definitions", method: "GET", path: "/api/admin/providers",
+			expectedStatus: http.StatusOK, expectedResult: []interface{}{
+				map[string]interface{}{"id": "provider-a", "name": "Provider A", "host": "https://provider-a.com"},
+			}},
+		{desc: "gets definition", method: "GET", path: "/api/admin/providers/provider-a",
+			expectedStatus: http.StatusOK, expectedResult: map[string]interface{}{
+				"id": "provider-a", "name": "Provider A", "host": "https://provider-a.com",
+			}},
+		{desc: "returns 404 for unknown definition", method: "GET", path: "/api/admin/providers/provider-b",
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+		{desc: "forbids users outside admin groups", method: "GET", path: "/api/admin/providers", groups: []string{"group-b"},
+			expectedStatus: http.StatusForbidden, expectedErr: errAdminRequired.Error()},
+		{desc: "creates definition", method: "POST", path: "/api/admin/providers",
+			body:           `{"id": "provider-b", "name": "Provider B", "host": "https://provider-b.com", "credentials": "shared"}`,
+			expectedStatus: http.StatusOK, expectedResult: map[string]interface{}{
+				"id": "provider-b", "name": "Provider B", "host": "https://provider-b.com", "credentials": "shared",
+			},
+			expectedDefinitions: map[string]provider.Definition{
+				"provider-a": stored,
+				"provider-b": {Id: "provider-b", Name: "Provider B", Host: "https://provider-b.com", Credentials: "shared"},
+			},
+			expectedRegistered: []provider.Definition{
+				{Id: "provider-b", Name: "Provider B", Host: "https://provider-b.com", Credentials: "shared"},
+			}},
+		{desc: "rejects existing definition", method: "POST", path: "/api/admin/providers",
+			body:           `{"id": "provider-a", "host": "https://provider-a.com"}`,
+			expectedStatus: http.StatusConflict, expectedErr: errDefinitionExists.Error()},
+		{desc: "rejects definition without host", method: "POST", path: "/api/admin/providers",
+			body:           `{"id": "provider-b", "host": "provider-b"}`,
+			expectedStatus: http.StatusBadRequest, expectedErr: errDefinitionInvalid.Error()},
+		{desc: "rejects definition with invalid id", method: "POST", path: "/api/admin/providers",
+			body:           `{"id": "Provider B", "host": "https://provider-b.com"}`,
+			expectedStatus: http.StatusBadRequest, expectedErr: errDefinitionInvalid.Error()},
+		{desc: "rejects malformed definition", method: "POST", path: "/api/admin/providers", body: `{`,
+			expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrBodyMalformed.Error()},
+		{desc: "rejects unknown credentials", method: "POST", path: "/api/admin/providers",
+			body:           `{"id": "provider-b", "host": "https://provider-b.com", "credentials": "unknown"}`,
+			discoveryErr:   fmt.Errorf("%w: 'unknown'", discovery.ErrUnknownCredentials),
+			expectedStatus: http.StatusBadRequest, expectedErr: "unknown provider credentials: 'unknown'"},
+		{desc: "updates definition", method: "PUT", path: "/api/admin/providers/provider-a",
+			body:           `{"name": "Provider A", "host": "https://provider-a.org"}`,
+			expectedStatus: http.StatusOK, expectedResult: map[string]interface{}{
+				"id": "provider-a", "name": "Provider A", "host": "https://provider-a.org",
+			},
+			expectedDefinitions: map[string]provider.Definition{
+				"provider-a": {Id: "provider-a", Name: "Provider A", Host: "https://provider-a.org"},
+			},
+			expectedRegistered: []provider.Definition{
+				{Id: "provider-a", Name: "Provider A", Host: "https://provider-a.org"},
+			}},
+		{desc: "returns 404 updating unknown definition", method: "PUT", path: "/api/admin/providers/provider-b",
+			body:           `{"host": "https://provider-b.com"}`,
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+		{desc: "deletes definition", method: "DELETE", path: "/api/admin/providers/provider-a",
+			expectedStatus: http.StatusOK, expectedDefinitions: map[string]provider.Definition{},
+			expectedUnregistered: []string{"provider-a"}},
+		{desc: "returns 500 if unregistering fails", method: "DELETE", path: "/api/admin/providers/provider-a",
+			discoveryErr: someErr, expectedStatus: http.StatusInternalServerError, expectedErr: sdk.ErrInternal.Error()},
+		{desc: "returns 404 deleting unknown definition", method: "DELETE", path: "/api/admin/providers/provider-b",
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{Definitions: map[string]provider.Definition{"provider-a": stored}}
+			d := &fakes.RecordingDiscoverer{Err: test.discoveryErr}
+			groups := test.groups
+			if groups == nil {
+				groups = []string{"group-a"}
+			}
+
+			h := New(service.Core{Providers: providers}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true, UserGroups: groups},
+				Auth:      config.AuthConfig{AdminGroups: []string{"group-a"}},
+				Discovery: d,
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Err != test.expectedErr {
+				tt.Errorf("\nwanted error '%s', got '%s'", test.expectedErr, resp.Err)
+			}
+			if !cmp.Equal(test.expectedResult, resp.Result) {
+				tt.Errorf("\ndiff between results: \n%s\n", cmp.Diff(test.expectedResult, resp.Result))
+			}
+
+			expectedDefinitions := test.expectedDefinitions
+			if expectedDefinitions == nil {
+				expectedDefinitions = map[string]provider.Definition{"provider-a": stored}
+			}
+			if !cmp.Equal(expectedDefinitions, providers.Definitions) {
+				tt.Errorf("\ndiff between definitions: \n%s\n", cmp.Diff(expectedDefinitions, providers.Definitions))
+			}
+			if !cmp.Equal(test.expectedRegistered, d.Registered) {
+				tt.Errorf("\ndiff between registered: \n%s\n", cmp.Diff(test.expectedRegistered, d.Registered))
+			}
+			if !cmp.Equal(test.expectedUnregistered, d.Unregistered) {
+				tt.Errorf("\ndiff between unregistered: \n%s\n", cmp.Diff(test.expectedUnregistered, d.Unregistered))
+			}
+		})
+	}
+}
+
+func TestAdminProvidersHooks(t *testing.T) {
+	providers := &fakes.ProviderService{}
+	d := &fakes.RecordingDiscoverer{Credentials: map[string]sdk.AuthConfig{"shared": {Token: "token"}}}
+
+	h := New(service.Core{Providers: providers}, &fakes.PipeViz{}, Opts{
+		DevConfig: config.DevConfig{DisableAuth: true, UserGroups: []string{"group-a"}},
+		Auth:      config.AuthConfig{AdminGroups: []string{"group-a"}},
+		Discovery: d,
+	})
+	s := httptest.NewServer(h)
+	defer s.Close()
+	c := sdk.NewCoreClient(s.URL, "provider-b", sdk.AuthConfig{Token: "token"}, nil)
+
+	err := c.RequestReconcile(sdk.ReconcileRequest{})
+	if !errors.Is(err, sdk.ErrUnauthorized) {
+		t.Fatalf("\nwanted err: %v\ngot: %v", sdk.ErrUnauthorized, err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/api/admin/providers",
+		strings.NewReader(`{"id": "provider-b", "host": "https://provider-b.com", "credentials": "shared"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("\nwanted status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	err = c.RequestReconcile(sdk.ReconcileRequest{})
+	if err != nil {
+		t.Errorf("\nregistered provider can't use hooks: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/admin/providers/provider-b", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("\nwanted status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	err = c.RequestReconcile(sdk.ReconcileRequest{})
+	if !errors.Is(err, sdk.ErrUnauthorized) {
+		t.Errorf("\nwanted err: %v\ngot: %v", sdk.ErrUnauthorized, err)
+	}
+}
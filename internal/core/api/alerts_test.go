@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/alerts"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAlerts(t *testing.T) {
+	alertA := alerts.Alert{ProviderId: "provider", Alert: sdk.Alert{
+		Id: "alert-a", AppId: "app-a", Team: "team-a", Kind: sdk.AlertKindAlert, Severity: sdk.AlertSeverityCritical, Started: someTime,
+	}}
+	alertB := alerts.Alert{ProviderId: "provider", Alert: sdk.Alert{
+		Id: "alert-b", AppId: "app-b", Team: "team-a", Kind: sdk.AlertKindIncident, Severity: sdk.AlertSeverityWarning, Started: someTime,
+	}}
+	state := []alerts.Alert{alertA, alertB}
+
+	tests := []struct {
+		desc           string
+		path           string
+		err            error
+		expectedStatus int
+		expected       []alerts.Alert
+	}{
+		{desc: "lists app alerts", path: "/api/apps/app-a/alerts", expectedStatus: http.StatusOK, expected: []alerts.Alert{alertA}},
+		{desc: "lists no app alerts", path: "/api/apps/app-c/alerts", expectedStatus: http.StatusOK, expected: []alerts.Alert{}},
+		{desc: "lists team alerts", path: "/api/teams/team-a/alerts", expectedStatus: http.StatusOK, expected: []alerts.Alert{alertA, alertB}},
+		{desc: "fails listing alerts", path: "/api/teams/team-a/alerts", err: someErr, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			h := New(service.Core{
+				Providers: &fakes.ProviderService{},
+				Alerts:    &fakes.MappingAlertsService{Alerts: state, Err: test.err},
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := struct {
+				Result []alerts.Alert
+			}{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if !cmp.Equal(test.expected, resp.Result) {
+				tt.Errorf("\ndiff between alerts: \n%s\n", cmp.Diff(test.expected, resp.Result))
+			}
+		})
+	}
+}
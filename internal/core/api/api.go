@@ -8,9 +8,11 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/joscha-alisch/dyve/internal/core/config"
 	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/discovery"
 	"github.com/joscha-alisch/dyve/internal/core/live"
 	"github.com/joscha-alisch/dyve/internal/core/service"
 	"github.com/joscha-alisch/dyve/pkg/pipeviz"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
 	"time"
 )
@@ -19,6 +21,12 @@ type Opts struct {
 	Url       string
 	DevConfig config.DevConfig
 	Auth      config.AuthConfig
+	// Providers holds the credentials providers authenticate with when pushing updates. With
+	// Discovery, the credentials of the providers it knows are used instead.
+	Providers []config.ProviderConfig
+	// Discovery registers the providers added through the admin API. Without it, providers
+	// can't be managed through the admin API.
+	Discovery discovery.Discoverer
 }
 
 func New(core service.Core, pipeGen pipeviz.PipeViz, opts Opts) http.Handler {
@@ -28,6 +36,15 @@ func New(core service.Core, pipeGen pipeviz.PipeViz, opts Opts) http.Handler {
 		pipeGen:            pipeGen,
 		appViewer:          live.NewAppViewer(core),
 		disableOriginCheck: opts.DevConfig.DisableOriginCheck,
+		disableAuth:        opts.DevConfig.DisableAuth,
+		devGroups:          opts.DevConfig.UserGroups,
+		adminGroups:        opts.Auth.AdminGroups,
+		discovery:          opts.Discovery,
+		providerAuth:       make(map[string]sdk.AuthConfig),
+	}
+
+	for _, p := range opts.Providers {
+		a.providerAuth[p.Id] = p.Auth
 	}
 
 	if opts.Auth.Secret == "" && opts.DevConfig.DisableAuth == false {
@@ -64,6 +81,11 @@ func New(core service.Core, pipeGen pipeviz.PipeViz, opts Opts) http.Handler {
 		}
 	}
 
+	hooks := a.PathPrefix("/hooks/providers/{provider:[0-9a-z-]+}").Subrouter()
+	hooks.Use(a.authenticateProvider)
+	hooks.Path("/updates").Methods("POST").HandlerFunc(a.pushUpdate)
+	hooks.Path("/reconcile").Methods("POST").HandlerFunc(a.requestReconcile)
+
 	authRoutes, avaRoutes := service.Handlers()
 	a.PathPrefix("/auth/avatars").Handler(avaRoutes)
 	a.PathPrefix("/auth").Handler(authRoutes)
@@ -78,20 +100,55 @@ func New(core service.Core, pipeGen pipeviz.PipeViz, opts Opts) http.Handler {
 
 	api.Path("/apps").Queries("perPage", "").Methods("GET").HandlerFunc(a.listAppsPaginated)
 	api.Path("/apps/{id:[0-9a-z-]+}/live").HandlerFunc(a.startWebsocketApp)
+	api.Path("/apps/{id:[0-9a-z-]+}/metrics").Methods("GET").HandlerFunc(a.getAppMetrics)
+	api.Path("/apps/{id:[0-9a-z-]+}/alerts").Methods("GET").HandlerFunc(a.listAppAlerts)
+	api.Path("/apps/{id:[0-9a-z-]+}/dependencies").Methods("GET").HandlerFunc(a.getAppDependencies)
+	api.Path("/apps/{id:[0-9a-z-]+}/actions/restart").Methods("POST").HandlerFunc(a.restartApp)
+	api.Path("/apps/{id:[0-9a-z-]+}/actions/stop").Methods("POST").HandlerFunc(a.stopApp)
+	api.Path("/apps/{id:[0-9a-z-]+}/actions/start").Methods("POST").HandlerFunc(a.startApp)
+	api.Path("/apps/{id:[0-9a-z-]+}/actions/scale").Methods("POST").HandlerFunc(a.scaleApp)
+	api.Path("/apps/{id:[0-9a-z-]+}/actions/instances/{index:[0-9]+}/restart").Methods("POST").HandlerFunc(a.restartAppInstance)
 	api.Path("/apps/{id:[0-9a-z-]+}").Methods("GET").HandlerFunc(a.getApp)
 
 	api.Path("/pipelines").Queries("perPage", "").HandlerFunc(a.listPipelinesPaginated)
 	api.Path("/pipelines/{id:[0-9a-z-]+}/status").HandlerFunc(a.getPipelineStatus)
 	api.Path("/pipelines/{id:[0-9a-z-]+}/runs").HandlerFunc(a.listPipelineRuns)
+	api.Path("/pipelines/{id:[0-9a-z-]+}/runs/{run}/steps/{step:[0-9]+}/logs").Methods("GET").HandlerFunc(a.getPipelineStepLogs)
+	api.Path("/pipelines/{id:[0-9a-z-]+}/trigger").Methods("POST").HandlerFunc(a.triggerPipeline)
+	api.Path("/pipelines/{id:[0-9a-z-]+}/abort").Methods("POST").HandlerFunc(a.abortPipelineRun)
+	api.Path("/pipelines/{id:[0-9a-z-]+}/steps/{step:[0-9]+}/rerun").Methods("POST").HandlerFunc(a.rerunPipelineStep)
+	api.Path("/pipelines/{id:[0-9a-z-]+}/steps/{step:[0-9]+}/approve").Methods("POST").HandlerFunc(a.approvePipelineStep)
 	api.Path("/pipelines/{id:[0-9a-z-]+}").HandlerFunc(a.getPipeline)
 
 	api.Path("/teams").Queries("perPage", "").HandlerFunc(a.listTeamsPaginated)
+	api.Path("/teams/{id:[0-9a-z-]+}/alerts").Methods("GET").HandlerFunc(a.listTeamAlerts)
 	api.Path("/teams/{id:[0-9a-z-]+}").Methods("GET").HandlerFunc(a.getTeam)
 	api.Path("/teams/{id:[0-9a-z-]+}").Methods("DELETE").HandlerFunc(a.deleteTeam)
 	api.Path("/teams/{id:[0-9a-z-]+}").Methods("POST").HandlerFunc(a.createTeam)
 	api.Path("/teams/{id:[0-9a-z-]+}").Methods("PUT").HandlerFunc(a.updateTeam)
 
 	api.Path("/groups").HandlerFunc(a.listGroups)
+	api.Path("/groups/{id}/members").Methods("GET").HandlerFunc(a.listGroupMembers)
+
+	api.Path("/routes/expiring").Methods("GET").HandlerFunc(a.listExpiringRoutes)
+
+	api.Path("/topology").Methods("GET").HandlerFunc(a.getTopology)
+
+	api.Path("/providers").Methods("GET").HandlerFunc(a.listProviders)
+	api.Path("/providers/{id:[0-9a-z-]+}/health").Methods("GET").HandlerFunc(a.getProviderHealth)
+
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(a.authorizeAdmin)
+	admin.Path("/jobs/dead-letters").Methods("GET").HandlerFunc(a.listDeadLetters)
+	admin.Path("/jobs/dead-letters/{type:[0-9a-z-]+}/{id}/requeue").Methods("POST").HandlerFunc(a.requeueDeadLetter)
+	admin.Path("/jobs/queue").Methods("GET").HandlerFunc(a.getRequestQueue)
+	if opts.Discovery != nil {
+		admin.Path("/providers").Methods("GET").HandlerFunc(a.listProviderDefinitions)
+		admin.Path("/providers").Methods("POST").HandlerFunc(a.createProviderDefinition)
+		admin.Path("/providers/{id:[0-9a-z-]+}").Methods("GET").HandlerFunc(a.getProviderDefinition)
+		admin.Path("/providers/{id:[0-9a-z-]+}").Methods("PUT").HandlerFunc(a.updateProviderDefinition)
+		admin.Path("/providers/{id:[0-9a-z-]+}").Methods("DELETE").HandlerFunc(a.deleteProviderDefinition)
+	}
 
 	a.appViewer.Run()
 
@@ -104,7 +161,12 @@ type api struct {
 	pipeGen            pipeviz.PipeViz
 	core               service.Core
 	disableOriginCheck bool
+	disableAuth        bool
+	devGroups          []string
+	adminGroups        []string
 	appViewer          *live.AppViewer
+	discovery          discovery.Discoverer
+	providerAuth       map[string]sdk.AuthConfig
 }
 
 func disableWebsocketXSRF(next http.Handler) http.Handler {
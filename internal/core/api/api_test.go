@@ -13,6 +13,8 @@ import (
 	"github.com/joscha-alisch/dyve/internal/core/fakes"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeGroups"
 	"github.com/joscha-alisch/dyve/internal/core/groups"
+	"github.com/joscha-alisch/dyve/internal/core/pipelines"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/service"
 	"github.com/joscha-alisch/dyve/internal/core/teams"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
@@ -45,6 +47,7 @@ func TestHttp(t *testing.T) {
 		expectedGroups    *fakeGroups.GroupsRecorder
 		headers           http.Header
 		overrideRequest   *http.Request
+		providers         *fakes.ProviderService
 	}{
 		{desc: "gets app", method: "GET", path: "/api/apps/guid-a", apps: &fakes.RecordingAppsService{
 			App: apps.App{
@@ -103,9 +106,9 @@ func TestHttp(t *testing.T) {
 			Page:    2,
 		}},
 		{desc: "gets pipeline", method: "GET", path: "/api/pipelines/guid-a", pipelines: &fakes.RecordingPipelinesService{
-			Pipeline: sdk.Pipeline{
+			Pipeline: pipelines.Pipeline{Pipeline: sdk.Pipeline{
 				Id: "guid-a", Name: "name-a",
-			},
+			}, ProviderId: "provider-a"},
 		}, expectedPipelines: &fakes.PipelinesRecorder{PipelineId: "guid-a"}},
 		{desc: "error while getting pipeline", method: "GET", path: "/api/pipelines/guid-a", pipelines: &fakes.RecordingPipelinesService{
 			Err: someErr,
@@ -139,7 +142,7 @@ func TestHttp(t *testing.T) {
 			Page:    2,
 		}},
 		{desc: "gets pipeline status", method: "GET", path: "/api/pipelines/pipeline-a/status", pipelines: &fakes.RecordingPipelinesService{
-			Pipeline: sdk.Pipeline{
+			Pipeline: pipelines.Pipeline{Pipeline: sdk.Pipeline{
 				Id:   "pipeline-a",
 				Name: "pipeline",
 				Current: sdk.PipelineVersion{
@@ -166,7 +169,7 @@ func TestHttp(t *testing.T) {
 						},
 					},
 				},
-			}, Runs: []sdk.PipelineStatus{
+			}}, Runs: []sdk.PipelineStatus{
 				{
 					PipelineId: "pipeline-a",
 					Started:    someTime.Add(-2 * time.Minute),
@@ -269,6 +272,13 @@ func TestHttp(t *testing.T) {
 				ToExcl:     someTime,
 				Limit:      10,
 			}},
+		{desc: "filters pipeline runs", method: "GET", path: "/api/pipelines/pipeline-a/runs?trigger=webhook&branch=main&author=jane",
+			pipelines: &fakes.RecordingPipelinesService{Runs: []sdk.PipelineStatus{}}, expectedPipelines: &fakes.PipelinesRecorder{
+				PipelineId: "pipeline-a",
+				ToExcl:     someTime,
+				Limit:      10,
+				Filter:     pipelines.RunFilter{Type: sdk.TriggerWebhook, Branch: "main", Author: "jane"},
+			}},
 		{
 			desc:   "gets team",
 			method: "GET",
@@ -514,6 +524,32 @@ func TestHttp(t *testing.T) {
 			},
 			expectedGroups: &fakeGroups.GroupsRecorder{},
 		},
+		{
+			desc:   "list group members",
+			method: "GET",
+			path:   "/api/groups/org:team-a/members",
+			groups: &fakeGroups.RecordingGroupsService{
+				Members: map[string][]sdk.Member{
+					"org:team-a": {
+						{Id: "member-a", Name: "Member A", Login: "member-a", Role: "maintainer"},
+						{Id: "member-b", Name: "Member B", Login: "member-b", Role: "member"},
+					},
+				},
+			},
+			expectedGroups: &fakeGroups.GroupsRecorder{},
+		},
+		{
+			desc:           "list members of unknown group",
+			method:         "GET",
+			path:           "/api/groups/org:unknown/members",
+			groups:         &fakeGroups.RecordingGroupsService{},
+			expectedGroups: &fakeGroups.GroupsRecorder{},
+		},
+		{desc: "lists providers", method: "GET", path: "/api/providers"},
+		{desc: "gets provider health", method: "GET", path: "/api/providers/provider-a/health"},
+		{desc: "gets health of unknown provider", method: "GET", path: "/api/providers/unknown/health"},
+		{desc: "error while listing providers", method: "GET", path: "/api/providers",
+			providers: &fakes.ProviderService{HealthErr: someErr}},
 		{
 			desc:   "start websocket app",
 			method: "GET",
@@ -538,11 +574,21 @@ func TestHttp(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
+			if test.providers == nil {
+				test.providers = &fakes.ProviderService{Health: []provider.Health{
+					{Id: "provider", Name: "Provider", Feature: provider.TypeApps, LastSuccess: &someTime, LatencyMillis: 120},
+					{Id: "provider-a", Name: "Provider A", Feature: provider.TypeGroups, LastSuccess: &someTime, LatencyMillis: 30},
+					{Id: "provider-a", Name: "Provider A", Feature: provider.TypePipelines, LastSuccess: &someTime,
+						LastFailure: &someTime, LastError: "some error", ConsecutiveFailures: 2, LatencyMillis: 5000},
+				}}
+			}
+
 			h := New(service.Core{
 				Apps:      test.apps,
 				Pipelines: test.pipelines,
 				Teams:     test.teams,
 				Groups:    test.groups,
+				Providers: test.providers,
 			}, &fakes.PipeViz{}, Opts{
 				DevConfig: config.DevConfig{DisableAuth: true},
 			})
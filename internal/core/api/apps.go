@@ -2,11 +2,23 @@ package api
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/apps"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/ws"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
 )
 
+type appPage struct {
+	sdk.AppPage
+	Freshness provider.Freshness `json:"freshness"`
+}
+
+type appWithFreshness struct {
+	apps.App
+	Freshness provider.Freshness `json:"freshness"`
+}
+
 func (a *api) listAppsPaginated(w http.ResponseWriter, r *http.Request) {
 	perPage, err := mustQueryInt(r, "perPage")
 	if err != nil {
@@ -25,7 +37,7 @@ func (a *api) listAppsPaginated(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOk(w, apps)
+	respondOk(w, appPage{AppPage: apps, Freshness: freshness(a.health(), provider.TypeApps)})
 }
 
 func (a *api) getApp(w http.ResponseWriter, r *http.Request) {
@@ -37,7 +49,37 @@ func (a *api) getApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOk(w, app)
+	respondOk(w, appWithFreshness{App: app, Freshness: freshness(a.health(), provider.TypeApps, app.ProviderId)})
+}
+
+func (a *api) getAppMetrics(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	q, err := sdk.ParseMetricsQuery(r, currentTime())
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	metrics, err := a.core.Metrics.GetAppMetrics(r.Context(), id, q)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, metrics)
+}
+
+func (a *api) listAppAlerts(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	alerts, err := a.core.Alerts.ListAppAlerts(id)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, alerts)
 }
 
 func (a *api) startWebsocketApp(w http.ResponseWriter, r *http.Request) {
@@ -82,17 +82,93 @@ func userIsInOrg(user *token.User, org string) bool {
 }
 
 func getUserOrgs(u *token.User) []string {
-	orgs, ok := u.Attributes["orgs"].([]interface{})
+	return getSliceAttr(u, "orgs")
+}
+
+func getUserGroups(u *token.User) []string {
+	return getSliceAttr(u, "groups")
+}
+
+// getSliceAttr reads a slice attribute, which is decoded from the token as []interface{}.
+func getSliceAttr(u *token.User, key string) []string {
+	values, ok := u.Attributes[key].([]interface{})
 	if !ok {
-		return nil
+		return u.SliceAttr(key)
 	}
 
 	var res []string
-	for _, org := range orgs {
-		if orgString, ok := org.(string); ok {
-			res = append(res, orgString)
+	for _, value := range values {
+		if valueString, ok := value.(string); ok {
+			res = append(res, valueString)
 		}
 	}
 
 	return res
 }
+
+// authorizeTeams returns errForbidden unless the user has admin or member access to all of
+// the teams. Empty team ids, or no teams at all, stand for things no team could be derived
+// for, which only members of the admin groups may operate. With auth disabled, the user is
+// in the groups of the dev config.
+func (a *api) authorizeTeams(r *http.Request, teamIds ...string) error {
+	groups, err := a.userGroups(r)
+	if err != nil {
+		return errForbidden
+	}
+	isAdmin := containsAny(a.adminGroups, groups)
+
+	if len(teamIds) == 0 {
+		if !isAdmin {
+			return errForbidden
+		}
+		return nil
+	}
+
+	byAccess, err := a.core.Teams.TeamsForGroups(groups)
+	if err != nil {
+		return err
+	}
+	for _, teamId := range teamIds {
+		if teamId == "" && !isAdmin || teamId != "" && !byAccess.CanOperate(teamId) {
+			return errForbidden
+		}
+	}
+	return nil
+}
+
+// authorizeAdmin only lets users pass that are in one of the admin groups. With auth
+// disabled, the user is in the groups of the dev config.
+func (a *api) authorizeAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		groups, err := a.userGroups(r)
+		if err != nil || !containsAny(a.adminGroups, groups) {
+			respondErr(w, http.StatusForbidden, errAdminRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *api) userGroups(r *http.Request) ([]string, error) {
+	if a.disableAuth {
+		return a.devGroups, nil
+	}
+
+	u, err := token.GetUserInfo(r)
+	if err != nil {
+		return nil, err
+	}
+	return getUserGroups(&u), nil
+}
+
+func containsAny(list []string, values []string) bool {
+	for _, s := range list {
+		for _, value := range values {
+			if s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
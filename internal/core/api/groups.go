@@ -1,6 +1,19 @@
 package api
 
-import "net/http"
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/groups"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+)
+
+type providerGroups struct {
+	groups.ProviderWithGroups
+	Freshness provider.Freshness `json:"freshness"`
+}
 
 func (a *api) listGroups(w http.ResponseWriter, r *http.Request) {
 	groups, err := a.core.Groups.ListGroupsByProvider()
@@ -9,5 +22,26 @@ func (a *api) listGroups(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOk(w, groups)
+	health := a.health()
+	res := make(map[string]providerGroups, len(groups))
+	for id, p := range groups {
+		res[id] = providerGroups{ProviderWithGroups: p, Freshness: freshness(health, provider.TypeGroups, id)}
+	}
+
+	respondOk(w, res)
+}
+
+func (a *api) listGroupMembers(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	members, err := a.core.Groups.ListMembers(id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, members)
 }
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+)
+
+var errHooksDisabled = errors.New("no credentials configured for provider, hooks are disabled")
+var errFeatureNotRegistered = errors.New("provider is not registered for the pushed feature")
+
+// reconcilableTypes can be reconciled per provider. Routing and instances are reconciled per app.
+var reconcilableTypes = []provider.Type{provider.TypeApps, provider.TypePipelines, provider.TypeGroups}
+
+// authenticateProvider only lets requests pass that carry the credentials configured for the provider.
+// Providers without token or hmac secret can't use the hooks at all.
+func (a *api) authenticateProvider(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["provider"]
+
+		auth, ok := a.authOf(id)
+		if !ok || (auth.Token == "" && auth.HmacSecret == "") {
+			respondErr(w, http.StatusUnauthorized, errHooksDisabled)
+			return
+		}
+
+		err := auth.VerifyRequest(r, currentTime())
+		if err != nil {
+			respondErr(w, http.StatusUnauthorized, sdk.ErrUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authOf returns the credentials of the provider. Discovery knows the providers registered
+// at runtime, also those registered through other replicas.
+func (a *api) authOf(id string) (sdk.AuthConfig, bool) {
+	if a.discovery == nil {
+		auth, ok := a.providerAuth[id]
+		return auth, ok
+	}
+
+	for _, p := range a.discovery.Providers() {
+		if p.Id == id {
+			return p.Auth, true
+		}
+	}
+	return sdk.AuthConfig{}, false
+}
+
+func (a *api) pushUpdate(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["provider"]
+
+	u := sdk.Update{}
+	err := json.NewDecoder(r.Body).Decode(&u)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	features := make(map[provider.Type]bool)
+	for _, t := range a.core.Providers.Features(id) {
+		features[t] = true
+	}
+
+	if (len(u.Apps) > 0 || len(u.DeletedApps) > 0) && !features[provider.TypeApps] ||
+		(len(u.Pipelines) > 0 || len(u.PipelineVersions) > 0 || len(u.PipelineRuns) > 0) && !features[provider.TypePipelines] ||
+		(len(u.Groups) > 0 || len(u.DeletedGroups) > 0) && !features[provider.TypeGroups] {
+		respondErr(w, http.StatusForbidden, errFeatureNotRegistered)
+		return
+	}
+
+	err = a.applyUpdate(id, u)
+	if err != nil {
+		log.Error().Err(err).Str("provider", id).Msg("error applying pushed update")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, nil)
+}
+
+func (a *api) applyUpdate(id string, u sdk.Update) error {
+	err := a.core.Apps.UpsertApps(id, u.Apps)
+	if err != nil {
+		return err
+	}
+
+	err = a.core.Apps.DeleteApps(id, u.DeletedApps)
+	if err != nil {
+		return err
+	}
+
+	err = a.core.Pipelines.UpsertPipelines(id, u.Pipelines)
+	if err != nil {
+		return err
+	}
+
+	if len(u.PipelineVersions) > 0 {
+		err = a.core.Pipelines.AddPipelineVersions(id, u.PipelineVersions)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(u.PipelineRuns) > 0 {
+		err = a.core.Pipelines.AddPipelineRuns(id, u.PipelineRuns)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = a.core.Groups.UpsertGroups(id, u.Groups)
+	if err != nil {
+		return err
+	}
+
+	return a.core.Groups.DeleteGroups(id, u.DeletedGroups)
+}
+
+func (a *api) requestReconcile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["provider"]
+
+	req := sdk.ReconcileRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && err != io.EOF {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	types := make([]provider.Type, 0, len(req.Features))
+	for _, feature := range req.Features {
+		types = append(types, provider.Type(feature))
+	}
+	if len(types) == 0 {
+		types = a.registeredReconcilableTypes(id)
+	}
+
+	for _, t := range types {
+		err = a.core.Providers.RequestReconcile(id, t, provider.PriorityProvider)
+		if errors.Is(err, provider.ErrNotFound) {
+			respondErr(w, http.StatusNotFound, fmt.Errorf("provider '%s' is not registered for %s", id, t))
+			return
+		} else if err != nil {
+			log.Error().Err(err).Str("provider", id).Msg("error requesting reconciliation")
+			respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+			return
+		}
+	}
+
+	for _, app := range req.Apps {
+		err = a.core.Providers.RequestAppUpdate(app, provider.PriorityProvider)
+		if err != nil {
+			log.Error().Err(err).Str("provider", id).Msg("error requesting app update")
+			respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+			return
+		}
+	}
+
+	respondOk(w, nil)
+}
+
+func (a *api) registeredReconcilableTypes(id string) []provider.Type {
+	registered := make(map[provider.Type]bool)
+	for _, t := range a.core.Providers.Features(id) {
+		registered[t] = true
+	}
+
+	var res []provider.Type
+	for _, t := range reconcilableTypes {
+		if registered[t] {
+			res = append(res, t)
+		}
+	}
+	return res
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeGroups"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	// signatures are only accepted for a few minutes around the current time
+	currentTime = time.Now
+	p := fakeProvider.AppProvider(nil)
+
+	tests := []struct {
+		desc              string
+		provider          string
+		auth              sdk.AuthConfig
+		push              func(c sdk.CoreClient) error
+		expectedErr       error
+		expectedApps      fakes.AppsRecorder
+		expectedPipelines fakes.PipelinesRecorder
+		expectedGroups    fakeGroups.GroupsRecorder
+		expectedRequests  []string
+	}{
+		{desc: "pushes apps", provider: "token-provider", auth: sdk.AuthConfig{Token: "token"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{
+				Apps:        []sdk.App{{Id: "app-a"}},
+				DeletedApps: []string{"app-b"},
+			})
+		}, expectedApps: fakes.AppsRecorder{
+			ProviderId: "token-provider",
+			Apps:       []sdk.App{{Id: "app-a"}},
+			DeletedIds: []string{"app-b"},
+		}, expectedPipelines: fakes.PipelinesRecorder{ProviderId: "token-provider"},
+			expectedGroups: fakeGroups.GroupsRecorder{ProviderId: "token-provider"}},
+		{desc: "pushes pipeline runs signed", provider: "hmac-provider", auth: sdk.AuthConfig{HmacSecret: "secret"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{
+				PipelineRuns: sdk.PipelineStatusList{{PipelineId: "pipeline-a", Started: someTime}},
+			})
+		}, expectedPipelines: fakes.PipelinesRecorder{
+			ProviderId: "hmac-provider",
+			Runs:       sdk.PipelineStatusList{{PipelineId: "pipeline-a", Started: someTime}},
+		}, expectedApps: fakes.AppsRecorder{ProviderId: "hmac-provider"},
+			expectedGroups: fakeGroups.GroupsRecorder{ProviderId: "hmac-provider"}},
+		{desc: "rejects wrong token", provider: "token-provider", auth: sdk.AuthConfig{Token: "other"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{Apps: []sdk.App{{Id: "app-a"}}})
+		}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "rejects wrong signature", provider: "hmac-provider", auth: sdk.AuthConfig{HmacSecret: "other"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{Apps: []sdk.App{{Id: "app-a"}}})
+		}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "rejects provider without credentials", provider: "open-provider", push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{Apps: []sdk.App{{Id: "app-a"}}})
+		}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "rejects unknown provider", provider: "unknown", auth: sdk.AuthConfig{Token: "token"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{Apps: []sdk.App{{Id: "app-a"}}})
+		}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "rejects features the provider isn't registered for", provider: "hmac-provider", auth: sdk.AuthConfig{HmacSecret: "secret"}, push: func(c sdk.CoreClient) error {
+			return c.PushUpdate(sdk.Update{Groups: []sdk.Group{{Id: "group-a"}}})
+		}, expectedErr: errAny},
+		{desc: "requests reconciliation of all features", provider: "token-provider", auth: sdk.AuthConfig{Token: "token"}, push: func(c sdk.CoreClient) error {
+			return c.RequestReconcile(sdk.ReconcileRequest{})
+		}, expectedRequests: []string{"apps/token-provider", "pipelines/token-provider"}},
+		{desc: "requests reconciliation of feature", provider: "token-provider", auth: sdk.AuthConfig{Token: "token"}, push: func(c sdk.CoreClient) error {
+			return c.RequestReconcile(sdk.ReconcileRequest{Features: []sdk.Feature{sdk.FeaturePipelines}})
+		}, expectedRequests: []string{"pipelines/token-provider"}},
+		{desc: "rejects reconciliation of unregistered feature", provider: "token-provider", auth: sdk.AuthConfig{Token: "token"}, push: func(c sdk.CoreClient) error {
+			return c.RequestReconcile(sdk.ReconcileRequest{Features: []sdk.Feature{sdk.FeatureGroups}})
+		}, expectedErr: sdk.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			apps := &fakes.RecordingAppsService{}
+			pipelines := &fakes.RecordingPipelinesService{}
+			groups := &fakeGroups.RecordingGroupsService{}
+			providers := &fakes.ProviderService{
+				AppProviders:      map[string]sdk.AppProviderContext{"token-provider": p},
+				PipelineProviders: map[string]sdk.PipelineProviderContext{"token-provider": p, "hmac-provider": p},
+			}
+
+			h := New(service.Core{
+				Apps:      apps,
+				Pipelines: pipelines,
+				Groups:    groups,
+				Providers: providers,
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+				Providers: []config.ProviderConfig{
+					{Id: "token-provider", Auth: sdk.AuthConfig{Token: "token"}},
+					{Id: "hmac-provider", Auth: sdk.AuthConfig{HmacSecret: "secret"}},
+					{Id: "open-provider"},
+				},
+			})
+			s := httptest.NewServer(h)
+			defer s.Close()
+
+			err := test.push(sdk.NewCoreClient(s.URL, test.provider, test.auth, nil))
+			if test.expectedErr == errAny && err == nil {
+				tt.Errorf("\nexpected an error")
+			} else if test.expectedErr != errAny && !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.expectedApps, apps.Record) {
+				tt.Errorf("app records don't match:%s\n", cmp.Diff(test.expectedApps, apps.Record))
+			}
+			if !cmp.Equal(test.expectedPipelines, pipelines.Record) {
+				tt.Errorf("pipeline records don't match:%s\n", cmp.Diff(test.expectedPipelines, pipelines.Record))
+			}
+			if !cmp.Equal(test.expectedGroups, groups.Record) {
+				tt.Errorf("group records don't match:%s\n", cmp.Diff(test.expectedGroups, groups.Record))
+			}
+			if !cmp.Equal(test.expectedRequests, providers.ReconcileRequests) {
+				tt.Errorf("reconcile requests don't match:%s\n", cmp.Diff(test.expectedRequests, providers.ReconcileRequests))
+			}
+		})
+	}
+}
+
+var errAny = errors.New("any error")
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+func (a *api) listDeadLetters(w http.ResponseWriter, r *http.Request) {
+	jobs, err := a.core.Providers.ListDeadLetters()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, jobs)
+}
+
+func (a *api) requeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	err := a.core.Providers.RequeueDeadLetter(recon.Type(vars["type"]), vars["id"])
+	if errors.Is(err, recon.ErrJobNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("job", vars["type"]+"/"+vars["id"]).Msg("error requeuing dead-lettered job")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, nil)
+}
+
+func (a *api) getRequestQueue(w http.ResponseWriter, r *http.Request) {
+	metrics, err := a.core.Providers.RequestMetrics()
+	if err != nil {
+		log.Error().Err(err).Msg("error getting request queue metrics")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, metrics)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/internal/queue"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeadLetters(t *testing.T) {
+	tests := []struct {
+		desc             string
+		method           string
+		path             string
+		groups           []string
+		expectedStatus   int
+		expectedErr      string
+		expectedResult   interface{}
+		expectedRequeued []string
+	}{
+		{desc: "lists dead letters", method: "GET", path: "/api/admin/jobs/dead-letters",
+			expectedStatus: http.StatusOK, expectedResult: []interface{}{
+				map[string]interface{}{
+					"type": "apps", "guid": "provider-a", "lastUpdated": "0001-01-01T00:00:00Z", "lastSynced": "0001-01-01T00:00:00Z",
+					"attempts": float64(8), "lastError": "some error",
+				},
+			}},
+		{desc: "forbids users outside admin groups", method: "GET", path: "/api/admin/jobs/dead-letters", groups: []string{"group-b"},
+			expectedStatus: http.StatusForbidden, expectedErr: errAdminRequired.Error()},
+		{desc: "requeues dead letter", method: "POST", path: "/api/admin/jobs/dead-letters/apps/provider-a/requeue",
+			expectedStatus: http.StatusOK, expectedRequeued: []string{"apps/provider-a"}},
+		{desc: "gets request queue metrics", method: "GET", path: "/api/admin/jobs/queue",
+			expectedStatus: http.StatusOK, expectedResult: map[string]interface{}{
+				"length": float64(2), "pushed": float64(5), "coalesced": float64(1), "popped": float64(2),
+				"maxWaitMillis": float64(1500), "avgWaitMillis": float64(750),
+			}},
+		{desc: "returns 404 for unknown dead letter", method: "POST", path: "/api/admin/jobs/dead-letters/apps/provider-b/requeue",
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{DeadLetters: []recon.Job{
+				{Type: "apps", Guid: "provider-a", Attempts: 8, LastError: "some error"},
+			}, RequestQueue: queue.Metrics{
+				Length: 2, Pushed: 5, Coalesced: 1, Popped: 2, MaxWaitMillis: 1500, AvgWaitMillis: 750,
+			}}
+			groups := test.groups
+			if groups == nil {
+				groups = []string{"group-a"}
+			}
+
+			h := New(service.Core{Providers: providers}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true, UserGroups: groups},
+				Auth:      config.AuthConfig{AdminGroups: []string{"group-a"}},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Err != test.expectedErr {
+				tt.Errorf("\nwanted error '%s', got '%s'", test.expectedErr, resp.Err)
+			}
+			if !cmp.Equal(test.expectedResult, resp.Result) {
+				tt.Errorf("\ndiff between results: \n%s\n", cmp.Diff(test.expectedResult, resp.Result))
+			}
+			if !cmp.Equal(test.expectedRequeued, providers.Requeued) {
+				tt.Errorf("\ndiff between requeued: \n%s\n", cmp.Diff(test.expectedRequeued, providers.Requeued))
+			}
+		})
+	}
+}
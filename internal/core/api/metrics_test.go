@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/metrics"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAppMetrics(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+
+	cpu := sdk.MetricSeries{Metric: sdk.MetricCpu, Unit: sdk.MetricUnitPercent, Points: []sdk.MetricPoint{{Time: someTime, Value: 50}}}
+
+	tests := []struct {
+		desc           string
+		path           string
+		provider       *fakeProvider.Provider
+		expectedStatus int
+		expected       sdk.AppMetrics
+		expectedQuery  sdk.MetricsQuery
+	}{
+		{desc: "gets metrics of the last hour", path: "/api/apps/app-a/metrics",
+			provider:       fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app-a": {cpu}}),
+			expectedStatus: http.StatusOK, expected: sdk.AppMetrics{cpu},
+			expectedQuery: sdk.MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: time.Minute}},
+		{desc: "gets metrics of range", path: "/api/apps/app-a/metrics?from=2006-01-01T12:00:00Z&to=2006-01-01T13:00:00Z&step=5m",
+			provider:       fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app-a": {cpu}}),
+			expectedStatus: http.StatusOK, expected: sdk.AppMetrics{cpu},
+			expectedQuery: sdk.MetricsQuery{From: someTime.Add(-3 * time.Hour), To: someTime.Add(-2 * time.Hour), Step: 5 * time.Minute}},
+		{desc: "rejects malformed step", path: "/api/apps/app-a/metrics?step=often",
+			provider:       fakeProvider.MetricsProvider(nil),
+			expectedStatus: http.StatusBadRequest},
+		{desc: "fails if providers fail", path: "/api/apps/app-a/metrics",
+			provider:       fakeProvider.NewErrProvider(someErr),
+			expectedStatus: http.StatusInternalServerError,
+			expectedQuery:  sdk.MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: time.Minute}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{
+				MetricsProviders: map[string]sdk.MetricsProvider{"provider": test.provider},
+			}
+			h := New(service.Core{
+				Providers: providers,
+				Metrics:   metrics.NewService(providers),
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := struct {
+				Result sdk.AppMetrics
+			}{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if !cmp.Equal(test.expected, resp.Result) {
+				tt.Errorf("\ndiff between metrics: \n%s\n", cmp.Diff(test.expected, resp.Result))
+			}
+			if !cmp.Equal(test.expectedQuery, test.provider.RecordedQuery) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(test.expectedQuery, test.provider.RecordedQuery))
+			}
+		})
+	}
+}
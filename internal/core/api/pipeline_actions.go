@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/pipelines"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"strconv"
+)
+
+var errPipelineActionsUnsupported = errors.New("the provider of the pipeline doesn't support actions")
+
+type pipelineAction func(ctx context.Context, p sdk.PipelineActionsProvider, id string) error
+
+func (a *api) triggerPipeline(w http.ResponseWriter, r *http.Request) {
+	a.takePipelineAction(w, r, func(ctx context.Context, p sdk.PipelineActionsProvider, id string) error {
+		return p.TriggerPipeline(ctx, id)
+	})
+}
+
+func (a *api) abortPipelineRun(w http.ResponseWriter, r *http.Request) {
+	a.takePipelineAction(w, r, func(ctx context.Context, p sdk.PipelineActionsProvider, id string) error {
+		return p.AbortRun(ctx, id)
+	})
+}
+
+func (a *api) rerunPipelineStep(w http.ResponseWriter, r *http.Request) {
+	stepId, err := strconv.Atoi(mux.Vars(r)["step"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	}
+
+	a.takePipelineAction(w, r, func(ctx context.Context, p sdk.PipelineActionsProvider, id string) error {
+		return p.RerunStep(ctx, id, stepId)
+	})
+}
+
+func (a *api) approvePipelineStep(w http.ResponseWriter, r *http.Request) {
+	stepId, err := strconv.Atoi(mux.Vars(r)["step"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	}
+
+	a.takePipelineAction(w, r, func(ctx context.Context, p sdk.PipelineActionsProvider, id string) error {
+		return p.ApproveStep(ctx, id, stepId)
+	})
+}
+
+// takePipelineAction authorizes the user against the teams owning the apps the pipeline
+// deploys, then lets the pipeline's provider take the action and requests a reconcile of
+// the provider's pipelines, so the new state shows up right away.
+func (a *api) takePipelineAction(w http.ResponseWriter, r *http.Request, action pipelineAction) {
+	id := mux.Vars(r)["id"]
+
+	pipeline, err := a.core.Pipelines.GetPipeline(id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	teamIds, err := a.pipelineTeams(pipeline)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.authorizeTeams(r, teamIds...)
+	if errors.Is(err, errForbidden) {
+		respondErr(w, http.StatusForbidden, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	pp, err := a.core.Providers.GetPipelineProvider(pipeline.ProviderId)
+	if err != nil && !errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+	p, ok := pp.(sdk.PipelineActionsProvider)
+	if !ok {
+		respondErr(w, http.StatusNotImplemented, errPipelineActionsUnsupported)
+		return
+	}
+
+	err = action(r.Context(), p, id)
+	if errors.Is(err, sdk.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, sdk.ErrConflict) {
+		respondErr(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("pipeline", id).Str("provider", pipeline.ProviderId).Msg("error taking pipeline action")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	err = a.core.Providers.RequestReconcile(pipeline.ProviderId, provider.TypePipelines, provider.PriorityUser)
+	if err != nil {
+		log.Error().Err(err).Str("provider", pipeline.ProviderId).Msg("error requesting pipeline reconcile")
+	}
+
+	respondOk(w, nil)
+}
+
+// pipelineTeams returns the teams owning the apps deployed by the current version of the
+// pipeline. Apps that are unknown or have no team are returned as an empty team, which
+// only admins can operate, just like pipelines deploying no apps at all.
+func (a *api) pipelineTeams(pipeline pipelines.Pipeline) ([]string, error) {
+	seen := make(map[string]bool)
+	var res []string
+	for _, step := range pipeline.Current.Definition.Steps {
+		for _, appId := range step.AppDeployments {
+			app, err := a.core.Apps.GetApp(appId)
+			if err != nil && !errors.Is(err, database.ErrNotFound) {
+				return nil, err
+			}
+
+			team := app.Team()
+			if !seen[team] {
+				seen[team] = true
+				res = append(res, team)
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/apps"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/pipelines"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/internal/core/teams"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPipelineActions(t *testing.T) {
+	pipeline := pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{
+		Id: "pipeline-a", Current: sdk.PipelineVersion{Definition: sdk.PipelineDefinition{
+			Steps: []sdk.PipelineStep{
+				{Id: 0, Name: "test"},
+				{Id: 1, Name: "deploy", AppDeployments: []string{"app-a", "app-b"}},
+				{Id: 2, Name: "deploy-other", AppDeployments: []string{"app-c"}},
+			},
+		}},
+	}}
+	appsService := &fakes.MappingAppsService{Apps: map[string]apps.App{
+		"app-a": {App: sdk.App{Id: "app-a", Labels: sdk.AppLabels{apps.TeamLabel: "team-a"}}},
+		"app-b": {App: sdk.App{Id: "app-b", Labels: sdk.AppLabels{apps.TeamLabel: "team-a"}}},
+		"app-c": {App: sdk.App{Id: "app-c", Labels: sdk.AppLabels{apps.TeamLabel: "team-b"}}},
+	}}
+	member := teams.ByAccess{Member: []teams.Team{{Id: "team-a"}}, Admin: []teams.Team{{Id: "team-b"}}}
+
+	tests := []struct {
+		desc               string
+		path               string
+		pipeline           pipelines.Pipeline
+		pipelineErr        error
+		byAccess           teams.ByAccess
+		adminGroups        []string
+		providerErr        error
+		noActions          bool
+		expectedStatus     int
+		expectedErr        string
+		expectedActions    []string
+		expectedReconciles []string
+	}{
+		{desc: "triggers pipeline", path: "/api/pipelines/pipeline-a/trigger", pipeline: pipeline, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"trigger pipeline-a"},
+			expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "aborts run", path: "/api/pipelines/pipeline-a/abort", pipeline: pipeline, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"abort pipeline-a"},
+			expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "reruns step", path: "/api/pipelines/pipeline-a/steps/1/rerun", pipeline: pipeline, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"rerun pipeline-a 1"},
+			expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "approves step", path: "/api/pipelines/pipeline-a/steps/2/approve", pipeline: pipeline, byAccess: member,
+			expectedStatus: http.StatusOK, expectedActions: []string{"approve pipeline-a 2"},
+			expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "forbids users not operating all deployed apps", path: "/api/pipelines/pipeline-a/trigger", pipeline: pipeline,
+			byAccess:       teams.ByAccess{Member: []teams.Team{{Id: "team-a"}}, Viewer: []teams.Team{{Id: "team-b"}}},
+			expectedStatus: http.StatusForbidden, expectedErr: errForbidden.Error()},
+		{desc: "forbids actions on pipelines deploying no apps", path: "/api/pipelines/pipeline-a/trigger",
+			pipeline: pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{Id: "pipeline-a"}}, byAccess: member,
+			expectedStatus: http.StatusForbidden, expectedErr: errForbidden.Error()},
+		{desc: "forbids actions on pipelines deploying unknown apps", path: "/api/pipelines/pipeline-a/trigger",
+			pipeline: pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{
+				Id: "pipeline-a", Current: sdk.PipelineVersion{Definition: sdk.PipelineDefinition{
+					Steps: []sdk.PipelineStep{{Id: 0, AppDeployments: []string{"app-a", "app-unknown"}}},
+				}},
+			}}, byAccess: member, expectedStatus: http.StatusForbidden, expectedErr: errForbidden.Error()},
+		{desc: "lets admins act on pipelines deploying no apps", path: "/api/pipelines/pipeline-a/trigger",
+			pipeline:    pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{Id: "pipeline-a"}},
+			adminGroups: []string{"group-a"}, expectedStatus: http.StatusOK, expectedActions: []string{"trigger pipeline-a"},
+			expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "lets admins act on pipelines deploying unknown apps", path: "/api/pipelines/pipeline-a/trigger",
+			pipeline: pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{
+				Id: "pipeline-a", Current: sdk.PipelineVersion{Definition: sdk.PipelineDefinition{
+					Steps: []sdk.PipelineStep{{Id: 0, AppDeployments: []string{"app-a", "app-unknown"}}},
+				}},
+			}}, byAccess: member, adminGroups: []string{"group-a"}, expectedStatus: http.StatusOK,
+			expectedActions: []string{"trigger pipeline-a"}, expectedReconciles: []string{"pipelines/provider"}},
+		{desc: "doesn't let admins act for teams they can't operate", path: "/api/pipelines/pipeline-a/trigger", pipeline: pipeline,
+			byAccess: teams.ByAccess{Member: []teams.Team{{Id: "team-a"}}}, adminGroups: []string{"group-a"},
+			expectedStatus: http.StatusForbidden, expectedErr: errForbidden.Error()},
+		{desc: "returns 404 for unknown pipeline", path: "/api/pipelines/pipeline-b/trigger", pipelineErr: database.ErrNotFound,
+			expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+		{desc: "returns 404 for unknown step", path: "/api/pipelines/pipeline-a/steps/5/rerun", pipeline: pipeline,
+			byAccess: member, providerErr: sdk.ErrNotFound, expectedStatus: http.StatusNotFound,
+			expectedErr: sdk.ErrNotFound.Error(), expectedActions: []string{"rerun pipeline-a 5"}},
+		{desc: "returns 409 for conflicting action", path: "/api/pipelines/pipeline-a/steps/1/approve", pipeline: pipeline,
+			byAccess: member, providerErr: sdk.ErrConflict, expectedStatus: http.StatusConflict,
+			expectedErr: sdk.ErrConflict.Error(), expectedActions: []string{"approve pipeline-a 1"}},
+		{desc: "returns 501 without pipeline actions", path: "/api/pipelines/pipeline-a/trigger", pipeline: pipeline,
+			byAccess: member, noActions: true, expectedStatus: http.StatusNotImplemented,
+			expectedErr: errPipelineActionsUnsupported.Error()},
+		{desc: "returns 500 for failing provider", path: "/api/pipelines/pipeline-a/abort", pipeline: pipeline, byAccess: member,
+			providerErr: someErr, expectedStatus: http.StatusInternalServerError, expectedErr: sdk.ErrInternal.Error(),
+			expectedActions: []string{"abort pipeline-a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{})
+			p.Err = test.providerErr
+			providers := &fakes.ProviderService{PipelineProviders: map[string]sdk.PipelineProviderContext{
+				"provider": p,
+			}}
+			if test.noActions {
+				providers.PipelineProviders["provider"] = struct{ sdk.PipelineProviderContext }{p}
+			}
+			teamsService := &fakes.RecordingTeamsService{ByAccess: test.byAccess}
+
+			h := New(service.Core{
+				Providers: providers,
+				Pipelines: &fakes.RecordingPipelinesService{Pipeline: test.pipeline, Err: test.pipelineErr},
+				Apps:      appsService,
+				Teams:     teamsService,
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true, UserGroups: []string{"group-a"}},
+				Auth:      config.AuthConfig{AdminGroups: test.adminGroups},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Err != test.expectedErr {
+				tt.Errorf("\nwanted error '%s', got '%s'", test.expectedErr, resp.Err)
+			}
+
+			if !cmp.Equal(test.expectedActions, p.RecordedActions) {
+				tt.Errorf("\ndiff between actions: \n%s\n", cmp.Diff(test.expectedActions, p.RecordedActions))
+			}
+			if !cmp.Equal(test.expectedReconciles, providers.ReconcileRequests) {
+				tt.Errorf("\ndiff between reconcile requests: \n%s\n", cmp.Diff(test.expectedReconciles, providers.ReconcileRequests))
+			}
+		})
+	}
+}
@@ -1,16 +1,34 @@
 package api
 
 import (
+	"errors"
 	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/pipelines"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/pkg/pipeviz"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
 	"net/http"
 	"sort"
+	"strconv"
 	"time"
 )
 
 var currentTime = time.Now
 
+var errStepLogsUnsupported = errors.New("the provider of the pipeline doesn't serve step logs")
+
+type pipelinePage struct {
+	sdk.PipelinePage
+	Freshness provider.Freshness `json:"freshness"`
+}
+
+type pipelineWithFreshness struct {
+	pipelines.Pipeline
+	Freshness provider.Freshness `json:"freshness"`
+}
+
 type pipelineStatus struct {
 	sdk.PipelineStatus
 	Svg string `json:"svg"`
@@ -34,7 +52,7 @@ func (a *api) listPipelinesPaginated(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOk(w, pipelines)
+	respondOk(w, pipelinePage{PipelinePage: pipelines, Freshness: freshness(a.health(), provider.TypePipelines)})
 }
 
 func (a *api) getPipeline(w http.ResponseWriter, r *http.Request) {
@@ -46,7 +64,7 @@ func (a *api) getPipeline(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOk(w, pipeline)
+	respondOk(w, pipelineWithFreshness{Pipeline: pipeline, Freshness: freshness(a.health(), provider.TypePipelines, pipeline.ProviderId)})
 }
 
 func (a *api) listPipelineRuns(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +72,7 @@ func (a *api) listPipelineRuns(w http.ResponseWriter, r *http.Request) {
 	before, _ := defaultQueryTime(r, "before", currentTime())
 	limit, _ := defaultQueryInt(r, "limit", 10)
 
-	runs, err := a.core.Pipelines.ListPipelineRunsLimit(id, before, limit)
+	runs, err := a.core.Pipelines.ListPipelineRunsLimit(id, before, limit, runFilter(r))
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
 		return
@@ -91,6 +109,16 @@ func (a *api) listPipelineRuns(w http.ResponseWriter, r *http.Request) {
 	respondOk(w, res)
 }
 
+// runFilter reads the trigger of the runs to list from the query, e.g. '?branch=main&author=jane'.
+func runFilter(r *http.Request) pipelines.RunFilter {
+	return pipelines.RunFilter{
+		Type:   sdk.TriggerType(r.FormValue("trigger")),
+		Commit: r.FormValue("commit"),
+		Branch: r.FormValue("branch"),
+		Author: r.FormValue("author"),
+	}
+}
+
 func (a *api) getPipelineStatus(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
@@ -100,7 +128,7 @@ func (a *api) getPipelineStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	runs, err := a.core.Pipelines.ListPipelineRuns(id, pipeline.Current.Created, currentTime())
+	runs, err := a.core.Pipelines.ListPipelineRuns(id, pipeline.Current.Created, currentTime(), pipelines.RunFilter{})
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
 		return
@@ -115,6 +143,65 @@ func (a *api) getPipelineStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// getPipelineStepLogs proxies a page of the logs of a step to the provider of the pipeline.
+// The run is identified by the RFC3339 time it started.
+func (a *api) getPipelineStepLogs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	run, err := time.Parse(time.RFC3339, mux.Vars(r)["run"])
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, sdk.ErrRunMalformed)
+		return
+	}
+	stepId, err := strconv.Atoi(mux.Vars(r)["step"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	}
+	perPage, err := defaultQueryInt(r, "perPage", 100)
+	if err != nil || perPage <= 0 {
+		respondErr(w, http.StatusBadRequest, sdk.ErrQueryPerPageMalformed)
+		return
+	}
+	page, err := defaultQueryInt(r, "page", 0)
+	if err != nil || page < 0 {
+		respondErr(w, http.StatusBadRequest, sdk.ErrQueryPageMalformed)
+		return
+	}
+
+	pipeline, err := a.core.Pipelines.GetPipeline(id)
+	if errors.Is(err, database.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	p, err := a.core.Providers.GetStepLogsProvider(pipeline.ProviderId)
+	if errors.Is(err, provider.ErrNotFound) {
+		respondErr(w, http.StatusNotImplemented, errStepLogsUnsupported)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	logs, err := p.GetStepLogs(r.Context(), id, run, stepId, perPage, page)
+	if errors.Is(err, sdk.ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, sdk.ErrPageExceeded) {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Str("pipeline", id).Str("provider", pipeline.ProviderId).Msg("error getting step logs")
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, logs)
+}
+
 func (a *api) toSvg(version sdk.PipelineVersion, status sdk.PipelineStatus) []byte {
 	g := pipeviz.Graph{
 		Edges: make([]pipeviz.Edge, len(version.Definition.Connections)),
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+func (a *api) listProviders(w http.ResponseWriter, r *http.Request) {
+	health, err := a.core.Providers.ListHealth()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, provider.Statuses(health))
+}
+
+func (a *api) getProviderHealth(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	health, err := a.core.Providers.ListHealth()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	for _, status := range provider.Statuses(health) {
+		if status.Id == id {
+			respondOk(w, status)
+			return
+		}
+	}
+
+	respondErr(w, http.StatusNotFound, sdk.ErrNotFound)
+}
+
+// health returns the health of all providers, or none if it can't be listed.
+func (a *api) health() []provider.Health {
+	health, err := a.core.Providers.ListHealth()
+	if err != nil {
+		log.Error().Err(err).Msg("error listing provider health")
+		return nil
+	}
+	return health
+}
+
+// freshness derives how current the data of the feature is from the health of the providers
+// with the given ids, or of all providers if no ids are given.
+func freshness(health []provider.Health, t provider.Type, ids ...string) provider.Freshness {
+	wanted := make(map[string]bool)
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var res []provider.Health
+	for _, h := range health {
+		if h.Feature == t && (len(ids) == 0 || wanted[h.Id]) {
+			res = append(res, h)
+		}
+	}
+	return provider.FreshnessOf(res...)
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"time"
+)
+
+// defaultExpiryWindow is how far ahead certificates are reported as expiring if not specified.
+const defaultExpiryWindow = 30 * 24 * time.Hour
+
+var errQueryWithinMalformed = errors.New("query parameter 'within' is malformed")
+
+func (a *api) listExpiringRoutes(w http.ResponseWriter, r *http.Request) {
+	within := defaultExpiryWindow
+	if q := r.URL.Query().Get("within"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d < 0 {
+			respondErr(w, http.StatusBadRequest, errQueryWithinMalformed)
+			return
+		}
+		within = d
+	}
+
+	routes, err := a.core.Routing.ListExpiringRoutes(currentTime().Add(within))
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, routes)
+}
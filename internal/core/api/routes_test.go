@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/routing"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExpiringRoutes(t *testing.T) {
+	inDay := someTime.Add(24 * time.Hour)
+	inWeek := someTime.Add(7 * 24 * time.Hour)
+	inYear := someTime.Add(365 * 24 * time.Hour)
+
+	routeA := sdk.AppRoute{Host: "a.example.com", Tls: &sdk.RouteTls{Enabled: true, Expires: &inWeek}}
+	routeB := sdk.AppRoute{Host: "b.example.com", Tls: &sdk.RouteTls{Enabled: true, Expires: &inDay}}
+	routeC := sdk.AppRoute{Host: "c.example.com", Tls: &sdk.RouteTls{Enabled: true, Expires: &inYear}}
+	state := map[string]sdk.AppRouting{
+		"app-a": {Routes: sdk.AppRoutes{routeA, {Host: "plain.example.com"}}},
+		"app-b": {Routes: sdk.AppRoutes{routeB, routeC}},
+	}
+
+	tests := []struct {
+		desc           string
+		path           string
+		err            error
+		expectedStatus int
+		expected       []routing.ExpiringRoute
+	}{
+		{desc: "lists routes expiring within a month", path: "/api/routes/expiring", expectedStatus: http.StatusOK, expected: []routing.ExpiringRoute{
+			{AppId: "app-b", Route: routeB},
+			{AppId: "app-a", Route: routeA},
+		}},
+		{desc: "lists routes expiring within given duration", path: "/api/routes/expiring?within=48h", expectedStatus: http.StatusOK, expected: []routing.ExpiringRoute{
+			{AppId: "app-b", Route: routeB},
+		}},
+		{desc: "fails on malformed duration", path: "/api/routes/expiring?within=soon", expectedStatus: http.StatusBadRequest},
+		{desc: "fails listing routes", path: "/api/routes/expiring", err: someErr, expectedStatus: http.StatusInternalServerError},
+	}
+
+	currentTime = func() time.Time {
+		return someTime
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			h := New(service.Core{
+				Providers: &fakes.ProviderService{},
+				Routing:   &fakes.MappingRoutesService{Routes: state, Err: test.err},
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := struct {
+				Result []routing.ExpiringRoute
+			}{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if !cmp.Equal(test.expected, resp.Result) {
+				tt.Errorf("\ndiff between routes: \n%s\n", cmp.Diff(test.expected, resp.Result))
+			}
+		})
+	}
+}
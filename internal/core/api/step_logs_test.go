@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/pipelines"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPipelineStepLogs(t *testing.T) {
+	lines := sdk.AppLogs{
+		{Time: someTime, Stream: sdk.LogStreamOut, Message: "first"},
+		{Time: someTime.Add(time.Second), Stream: sdk.LogStreamErr, Message: "second"},
+		{Time: someTime.Add(2 * time.Second), Stream: sdk.LogStreamOut, Message: "third"},
+	}
+	pipeline := pipelines.Pipeline{ProviderId: "provider", Pipeline: sdk.Pipeline{Id: "pipeline-a"}}
+	run := "2006-01-01T15:00:00Z"
+
+	tests := []struct {
+		desc           string
+		path           string
+		pipelineErr    error
+		providerErr    error
+		noProvider     bool
+		expectedStatus int
+		expectedErr    string
+		expected       sdk.StepLogsPage
+		expectedStep   string
+	}{
+		{desc: "gets step logs", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs",
+			expectedStatus: http.StatusOK, expectedStep: "pipeline-a 1", expected: sdk.StepLogsPage{
+				Pagination: sdk.Pagination{TotalResults: 3, TotalPages: 1, PerPage: 100},
+				Lines:      lines,
+			}},
+		{desc: "gets page of step logs", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs?perPage=2&page=1",
+			expectedStatus: http.StatusOK, expectedStep: "pipeline-a 1", expected: sdk.StepLogsPage{
+				Pagination: sdk.Pagination{TotalResults: 3, TotalPages: 2, PerPage: 2, Page: 1},
+				Lines:      lines[2:],
+			}},
+		{desc: "returns 400 for exceeded page", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs?perPage=2&page=2",
+			expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrPageExceeded.Error(), expectedStep: "pipeline-a 1"},
+		{desc: "returns 400 for malformed run", path: "/api/pipelines/pipeline-a/runs/yesterday/steps/1/logs",
+			expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrRunMalformed.Error()},
+		{desc: "returns 400 for malformed perPage", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs?perPage=-1",
+			expectedStatus: http.StatusBadRequest, expectedErr: sdk.ErrQueryPerPageMalformed.Error()},
+		{desc: "returns 404 for unknown pipeline", path: "/api/pipelines/pipeline-b/runs/" + run + "/steps/1/logs",
+			pipelineErr: database.ErrNotFound, expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error()},
+		{desc: "returns 404 for unknown step", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/5/logs",
+			providerErr: sdk.ErrNotFound, expectedStatus: http.StatusNotFound, expectedErr: sdk.ErrNotFound.Error(),
+			expectedStep: "pipeline-a 5"},
+		{desc: "returns 501 without step logs provider", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs",
+			noProvider: true, expectedStatus: http.StatusNotImplemented, expectedErr: errStepLogsUnsupported.Error()},
+		{desc: "returns 500 for failing provider", path: "/api/pipelines/pipeline-a/runs/" + run + "/steps/1/logs",
+			providerErr: someErr, expectedStatus: http.StatusInternalServerError, expectedErr: sdk.ErrInternal.Error(),
+			expectedStep: "pipeline-a 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := fakeProvider.StepLogsProvider(lines)
+			p.Err = test.providerErr
+			providers := &fakes.ProviderService{StepLogsProviders: map[string]sdk.StepLogsProvider{}}
+			if !test.noProvider {
+				providers.StepLogsProviders["provider"] = p
+			}
+
+			h := New(service.Core{
+				Providers: providers,
+				Pipelines: &fakes.RecordingPipelinesService{Pipeline: pipeline, Err: test.pipelineErr},
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+
+			resp := struct {
+				Err    string           `json:"error"`
+				Result sdk.StepLogsPage `json:"result"`
+			}{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Err != test.expectedErr {
+				tt.Errorf("\nwanted error '%s', got '%s'", test.expectedErr, resp.Err)
+			}
+			if !cmp.Equal(test.expected, resp.Result) {
+				tt.Errorf("\ndiff between logs: \n%s\n", cmp.Diff(test.expected, resp.Result))
+			}
+
+			if test.expectedStep != p.RecordedStep {
+				tt.Errorf("\nwanted step '%s', got '%s'", test.expectedStep, p.RecordedStep)
+			}
+			if test.expectedStep != "" && !p.RecordedTime.Equal(someTime) {
+				tt.Errorf("\nwanted run started at %v, got %v", someTime, p.RecordedTime)
+			}
+		})
+	}
+}
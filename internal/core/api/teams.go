@@ -41,6 +41,18 @@ func (a *api) getTeam(w http.ResponseWriter, r *http.Request) {
 	respondOk(w, team)
 }
 
+func (a *api) listTeamAlerts(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	alerts, err := a.core.Alerts.ListTeamAlerts(id)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, alerts)
+}
+
 func (a *api) createTeam(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
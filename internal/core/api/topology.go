@@ -0,0 +1,101 @@
+package api
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/joscha-alisch/dyve/internal/core/topology"
+	"github.com/joscha-alisch/dyve/pkg/pipeviz"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+)
+
+type topologyGraph struct {
+	topology.Topology
+	Svg string `json:"svg"`
+}
+
+func (a *api) getAppDependencies(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	t, err := a.core.Topology.GetAppDependencies(id)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, topologyGraph{
+		Topology: t,
+		Svg:      string(a.topologyToSvg(t)),
+	})
+}
+
+func (a *api) getTopology(w http.ResponseWriter, r *http.Request) {
+	t, err := a.core.Topology.GetTopology()
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, sdk.ErrInternal)
+		return
+	}
+
+	respondOk(w, topologyGraph{
+		Topology: t,
+		Svg:      string(a.topologyToSvg(t)),
+	})
+}
+
+// topologyToSvg renders apps and backing services as nodes with an edge from each app to the things it depends on.
+// Edges closing a cycle are left out, as the layout only supports acyclic graphs.
+func (a *api) topologyToSvg(t topology.Topology) []byte {
+	g := pipeviz.Graph{}
+	nodeIds := make(map[string]int)
+
+	addNode := func(key string, label string, class string) int {
+		if id, ok := nodeIds[key]; ok {
+			return id
+		}
+		id := len(g.Nodes)
+		nodeIds[key] = id
+		g.Nodes = append(g.Nodes, pipeviz.Node{Id: id, Label: label, Class: class})
+		return id
+	}
+	addApp := func(appId string) int {
+		label := appId
+		if app, err := a.core.Apps.GetApp(appId); err == nil && app.Name != "" {
+			label = app.Name
+		}
+		return addNode("app/"+appId, label, "app")
+	}
+
+	for _, s := range t.Services {
+		addNode("service/"+s.Id, s.Name, "service")
+	}
+
+	edges := make(map[int][]int)
+	for _, d := range t.Dependencies {
+		from := addApp(d.AppId)
+		var to int
+		if d.Target == sdk.DependencyTargetApp {
+			to = addApp(d.TargetId)
+		} else {
+			to = addNode("service/"+d.TargetId, d.TargetId, "service")
+		}
+
+		if reaches(edges, to, from) {
+			continue
+		}
+		edges[from] = append(edges[from], to)
+		g.Edges = append(g.Edges, pipeviz.Edge{From: from, To: to})
+	}
+
+	return a.pipeGen.Generate(g)
+}
+
+func reaches(edges map[int][]int, from int, to int) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range edges[from] {
+		if reaches(edges, next, to) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/apps"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/internal/core/topology"
+	"github.com/joscha-alisch/dyve/pkg/pipeviz"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTopology(t *testing.T) {
+	postgres := topology.BackingService{ProviderId: "provider", BackingService: sdk.BackingService{Id: "service-a", Name: "postgres"}}
+	frontendToBackend := topology.Dependency{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetApp, TargetId: "app-b"}}
+	backendToFrontend := topology.Dependency{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetApp, TargetId: "app-a"}}
+	backendToPostgres := topology.Dependency{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetService, TargetId: "service-a"}}
+	state := topology.Topology{
+		Services:     []topology.BackingService{postgres},
+		Dependencies: []topology.Dependency{frontendToBackend, backendToPostgres, backendToFrontend},
+	}
+
+	tests := []struct {
+		desc           string
+		path           string
+		err            error
+		expectedStatus int
+		expected       topology.Topology
+		expectedGraph  pipeviz.Graph
+	}{
+		{
+			desc: "gets app dependencies", path: "/api/apps/app-a/dependencies", expectedStatus: http.StatusOK,
+			expected: topology.Topology{
+				Services:     []topology.BackingService{},
+				Dependencies: []topology.Dependency{frontendToBackend, backendToFrontend},
+			},
+			expectedGraph: pipeviz.Graph{
+				Nodes: []pipeviz.Node{{Id: 0, Label: "frontend", Class: "app"}, {Id: 1, Label: "app-b", Class: "app"}},
+				Edges: []pipeviz.Edge{{From: 0, To: 1}},
+			},
+		},
+		{
+			desc: "gets whole topology", path: "/api/topology", expectedStatus: http.StatusOK,
+			expected: state,
+			expectedGraph: pipeviz.Graph{
+				Nodes: []pipeviz.Node{{Id: 0, Label: "postgres", Class: "service"}, {Id: 1, Label: "frontend", Class: "app"}, {Id: 2, Label: "app-b", Class: "app"}},
+				Edges: []pipeviz.Edge{{From: 1, To: 2}, {From: 2, To: 0}},
+			},
+		},
+		{desc: "fails getting app dependencies", path: "/api/apps/app-a/dependencies", err: someErr, expectedStatus: http.StatusInternalServerError},
+		{desc: "fails getting topology", path: "/api/topology", err: someErr, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			h := New(service.Core{
+				Providers: &fakes.ProviderService{},
+				Apps: &fakes.MappingAppsService{Apps: map[string]apps.App{
+					"app-a": {App: sdk.App{Id: "app-a", Name: "frontend"}},
+				}},
+				Topology: &fakes.MappingTopologyService{Topology: state, Err: test.err},
+			}, &fakes.PipeViz{}, Opts{
+				DevConfig: config.DevConfig{DisableAuth: true},
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.expectedStatus {
+				tt.Errorf("\nwanted status %d, got %d", test.expectedStatus, w.Code)
+			}
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			resp := struct {
+				Result struct {
+					topology.Topology
+					Svg string
+				}
+			}{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if !cmp.Equal(test.expected, resp.Result.Topology) {
+				tt.Errorf("\ndiff between topologies: \n%s\n", cmp.Diff(test.expected, resp.Result.Topology))
+			}
+
+			expectedSvg := (&fakes.PipeViz{}).Generate(test.expectedGraph)
+			if resp.Result.Svg != string(expectedSvg) {
+				tt.Errorf("\nwanted svg %s\n   got svg %s", expectedSvg, resp.Result.Svg)
+			}
+		})
+	}
+}
+
+func TestTopologySvg(t *testing.T) {
+	state := topology.Topology{
+		Services: []topology.BackingService{{ProviderId: "provider", BackingService: sdk.BackingService{Id: "service-a", Name: "postgres"}}},
+		Dependencies: []topology.Dependency{
+			{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetApp, TargetId: "app-b"}},
+			{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetApp, TargetId: "app-c"}},
+			{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetService, TargetId: "service-a"}},
+			{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-c", Target: sdk.DependencyTargetService, TargetId: "service-a"}},
+			{ProviderId: "provider", Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetApp, TargetId: "app-a"}},
+		},
+	}
+
+	h := New(service.Core{
+		Providers: &fakes.ProviderService{},
+		Apps: &fakes.MappingAppsService{Apps: map[string]apps.App{
+			"app-a": {App: sdk.App{Id: "app-a", Name: "frontend"}},
+		}},
+		Topology: &fakes.MappingTopologyService{Topology: state},
+	}, pipeviz.New(), Opts{
+		DevConfig: config.DevConfig{DisableAuth: true},
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/topology", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("\nwanted status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	resp := struct {
+		Result struct {
+			Svg string
+		}
+	}{}
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+
+	expectedSvg := pipeviz.New().Generate(pipeviz.Graph{
+		Nodes: []pipeviz.Node{
+			{Id: 0, Label: "postgres", Class: "service"},
+			{Id: 1, Label: "frontend", Class: "app"},
+			{Id: 2, Label: "app-b", Class: "app"},
+			{Id: 3, Label: "app-c", Class: "app"},
+		},
+		Edges: []pipeviz.Edge{{From: 1, To: 2}, {From: 1, To: 3}, {From: 2, To: 0}, {From: 3, To: 0}},
+	})
+	if resp.Result.Svg != string(expectedSvg) {
+		t.Errorf("\nwanted svg %s\n   got svg %s", expectedSvg, resp.Result.Svg)
+	}
+	for _, label := range []string{"postgres", "frontend", "app-b", "app-c"} {
+		if !strings.Contains(resp.Result.Svg, ">"+label+"<") {
+			t.Errorf("\nwanted svg to contain %s, got %s", label, resp.Result.Svg)
+		}
+	}
+}
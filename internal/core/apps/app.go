@@ -2,7 +2,15 @@ package apps
 
 import "github.com/joscha-alisch/dyve/pkg/provider/sdk"
 
+// TeamLabel is the label of apps holding the id of the team owning the app.
+const TeamLabel = "team"
+
 type App struct {
 	sdk.App    `json:",inline" bson:",inline"`
-	ProviderId string `json:"providerId" bson:"providerId"`
+	ProviderId string `json:"providerId" bson:"provider"`
+}
+
+// Team returns the id of the team owning the app, or an empty string if no team owns it.
+func (a App) Team() string {
+	return a.Labels[TeamLabel]
 }
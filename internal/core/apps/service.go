@@ -1,8 +1,10 @@
 package apps
 
 import (
+	"errors"
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 const Collection database.Collection = "apps"
@@ -12,6 +14,9 @@ type Service interface {
 	GetApp(id string) (App, error)
 	UpdateApps(providerId string, apps []sdk.App) error
 	UpdateApp(app sdk.App) error
+	// UpsertApps creates or updates the given apps of the provider, leaving its other apps untouched.
+	UpsertApps(providerId string, apps []sdk.App) error
+	DeleteApps(providerId string, ids []string) error
 }
 
 func NewService(db database.Database) Service {
@@ -54,3 +59,27 @@ func (m *service) UpdateApps(providerId string, apps []sdk.App) error {
 func (m *service) UpdateApp(app sdk.App) error {
 	return m.db.UpdateOneById(Collection, app.Id, false, app, nil)
 }
+
+func (m *service) UpsertApps(providerId string, apps []sdk.App) error {
+	if len(apps) == 0 {
+		return nil
+	}
+
+	filterMap := make(map[string]interface{}, len(apps))
+	appMap := make(map[string]interface{}, len(apps))
+	for _, app := range apps {
+		filterMap[app.Id] = bson.M{"provider": providerId, "id": app.Id}
+		appMap[app.Id] = app
+	}
+	return m.db.UpdateMany(Collection, filterMap, appMap)
+}
+
+func (m *service) DeleteApps(providerId string, ids []string) error {
+	for _, id := range ids {
+		err := m.db.DeleteOne(Collection, bson.M{"provider": providerId, "id": id})
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
 	"testing"
 )
 
@@ -250,3 +251,111 @@ func TestService_UpdateApp(t *testing.T) {
 		})
 	}
 }
+
+func TestService_UpsertApps(t *testing.T) {
+	tests := []struct {
+		desc        string
+		providerId  string
+		apps        []sdk.App
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expectedErr error
+	}{
+		{
+			desc:       "upserts apps",
+			providerId: "provider-a",
+			apps:       []sdk.App{someApp.App},
+			db:         &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "apps",
+				Filters:    map[string]interface{}{someApp.Id: bson.M{"provider": "provider-a", "id": someApp.Id}},
+				Updates:    map[string]interface{}{someApp.Id: someApp.App},
+			}},
+		},
+		{
+			desc:       "does nothing without apps",
+			providerId: "provider-a",
+			db:         &db.RecordingDatabase{},
+		},
+		{
+			desc:       "error while upserting apps",
+			providerId: "provider-a",
+			apps:       []sdk.App{someApp.App},
+			db: &db.RecordingDatabase{
+				Err: someErr,
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db)
+			err := s.UpsertApps(test.providerId, test.apps)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
+
+func TestService_DeleteApps(t *testing.T) {
+	tests := []struct {
+		desc        string
+		providerId  string
+		ids         []string
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expectedErr error
+	}{
+		{
+			desc:       "deletes apps",
+			providerId: "provider-a",
+			ids:        []string{"a", "b"},
+			db:         &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{
+				{Collection: "apps", Filter: bson.M{"provider": "provider-a", "id": "a"}},
+				{Collection: "apps", Filter: bson.M{"provider": "provider-a", "id": "b"}},
+			},
+		},
+		{
+			desc:       "ignores missing apps",
+			providerId: "provider-a",
+			ids:        []string{"a"},
+			db: &db.RecordingDatabase{
+				Err: database.ErrNotFound,
+			},
+		},
+		{
+			desc:       "error while deleting apps",
+			providerId: "provider-a",
+			ids:        []string{"a"},
+			db: &db.RecordingDatabase{
+				Err: someErr,
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db)
+			err := s.DeleteApps(test.providerId, test.ids)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/fatih/structs"
 	"github.com/jeremywohl/flatten"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -12,14 +13,16 @@ import (
 )
 
 type Config struct {
-	LogLevel       string           `yaml:"logLevel"`
-	DevConfig      DevConfig        `yaml:"devConfig"`
-	Providers      []ProviderConfig `yaml:"providers"`
-	Database       DatabaseConfig   `yaml:"database"`
-	Port           int              `yaml:"port"`
-	Reconciliation ReconConfig      `yaml:"reconciliation"`
-	Auth           AuthConfig       `yaml:"auth"`
-	ExternalUrl    string           `yaml:"externalUrl"`
+	LogLevel  string           `yaml:"logLevel"`
+	DevConfig DevConfig        `yaml:"devConfig"`
+	Providers []ProviderConfig `yaml:"providers"`
+	// Credentials are named credentials that providers registered at runtime reference.
+	Credentials    map[string]sdk.AuthConfig `yaml:"credentials"`
+	Database       DatabaseConfig            `yaml:"database"`
+	Port           int                       `yaml:"port"`
+	Reconciliation ReconConfig               `yaml:"reconciliation"`
+	Auth           AuthConfig                `yaml:"auth"`
+	ExternalUrl    string                    `yaml:"externalUrl"`
 }
 
 type DevConfig struct {
@@ -30,9 +33,13 @@ type DevConfig struct {
 }
 
 type ProviderConfig struct {
-	Id       string          `yaml:"id"`
-	Name     string          `yaml:"name"`
-	Host     string          `yaml:"host"`
+	Id   string `yaml:"id"`
+	Name string `yaml:"name"`
+	Host string `yaml:"host"`
+	// Auth holds the credentials to present to the provider.
+	Auth sdk.AuthConfig `yaml:"auth"`
+	// Deprecated: the features are discovered from the provider. If set, mismatches
+	// to the discovered features are logged.
 	Features []provider.Type `yaml:"features"`
 }
 
@@ -42,12 +49,21 @@ type DatabaseConfig struct {
 }
 
 type ReconConfig struct {
-	CacheSeconds int `yaml:"cacheSeconds"`
+	CacheSeconds     int `yaml:"cacheSeconds"`
+	DiscoverySeconds int `yaml:"discoverySeconds"`
+	// TimeoutSeconds is the deadline for a single reconcile job, including all
+	// requests to providers that it makes.
+	TimeoutSeconds int `yaml:"timeoutSeconds"`
+	// LeaseSeconds is how long a replica may run a reconcile job before other replicas take it
+	// over, e.g. because the replica crashed. It has to be longer than TimeoutSeconds.
+	LeaseSeconds int `yaml:"leaseSeconds"`
 }
 
 type AuthConfig struct {
 	Secret string             `yaml:"secret"`
 	GitHub AuthProviderConfig `yaml:"github"`
+	// AdminGroups are the groups whose members may manage providers.
+	AdminGroups []string `yaml:"adminGroups"`
 }
 type AuthProviderConfig struct {
 	Enabled bool
@@ -72,7 +88,10 @@ func defaults() Config {
 		},
 		Port: 9000,
 		Reconciliation: ReconConfig{
-			CacheSeconds: 20,
+			CacheSeconds:     20,
+			DiscoverySeconds: 60,
+			TimeoutSeconds:   60,
+			LeaseSeconds:     300,
 		},
 		Auth: AuthConfig{
 			Secret: "",
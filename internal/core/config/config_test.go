@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -25,8 +26,11 @@ func TestLoadFile(t *testing.T) {
 				URI:  "mongodb://localhost:27017",
 				Name: "dyve_core",
 			},
+			Auth: AuthConfig{
+				AdminGroups: []string{"admins"},
+			},
 			Port:           9000,
-			Reconciliation: ReconConfig{CacheSeconds: 20},
+			Reconciliation: ReconConfig{CacheSeconds: 20, DiscoverySeconds: 60, TimeoutSeconds: 60, LeaseSeconds: 300},
 			ExternalUrl:    "http://localhost:9000",
 			Providers: []ProviderConfig{
 				{Id: "provider-a", Host: "https://provider-a.com", Name: "Provider A", Features: []provider.Type{
@@ -36,6 +40,9 @@ func TestLoadFile(t *testing.T) {
 					provider.TypeGroups,
 				}},
 			},
+			Credentials: map[string]sdk.AuthConfig{
+				"shared": {Token: "token"},
+			},
 		}, nil},
 	}
 
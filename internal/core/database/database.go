@@ -23,16 +23,25 @@ type Database interface {
 	FindMany(coll Collection, filter bson.M, each func(c Decodable) error) error
 	FindManyWithOptions(coll Collection, filter bson.M, each func(c Decodable) error, sort bson.M, limit int) error
 	ListPaginated(coll Collection, perPage int, page int, p *sdk.Pagination, each func(c Decodable) error) error
+	Count(coll Collection, filter bson.M) (int, error)
 
 	UpdateProvided(coll Collection, provider string, updates map[string]interface{}) error
+	// DeleteProvided deletes all items of the provider, which UpdateProvided can't do without updates.
+	DeleteProvided(coll Collection, provider string) error
 	UpdateMany(coll Collection, filters map[string]interface{}, updates map[string]interface{}) error
 	UpdateOne(coll Collection, filter bson.M, createIfMissing bool, update interface{}, res interface{}) error
 	UpdateOneById(coll Collection, id string, createIfMissing bool, update interface{}, res interface{}) error
+	// Upsert atomically applies the update, which holds update operators or an aggregation pipeline
+	// rather than the fields to set, and creates the document if none matches. It reports whether
+	// a document matched.
+	Upsert(coll Collection, filter bson.M, update interface{}) (bool, error)
 
 	InsertOne(coll Collection, existsFilter interface{}, data interface{}) error
 
 	DeleteOne(coll Collection, filter bson.M) error
 	DeleteOneById(coll Collection, id string) error
+	// FindOneAndDelete atomically removes the first match in the given order and decodes it into res.
+	FindOneAndDelete(coll Collection, filter bson.M, sort bson.M, res interface{}) error
 
 	EnsureIndex(coll Collection, model mongo.IndexModel) error
 }
@@ -170,6 +170,22 @@ func (m *mongoDb) UpdateOneById(coll Collection, id string, createIfMissing bool
 	return m.UpdateOne(coll, bson.M{"id": id}, createIfMissing, update, res)
 }
 
+func (m *mongoDb) Upsert(coll Collection, filter bson.M, update interface{}) (bool, error) {
+	c := m.collection(coll)
+
+	o := options.UpdateOptions{}
+	o.SetUpsert(true)
+	res, err := c.UpdateOne(m.ctx, filter, update, &o)
+	if mongo.IsDuplicateKeyError(err) {
+		// another upsert inserted the document first, which this one now matches
+		res, err = c.UpdateOne(m.ctx, filter, update, &o)
+	}
+	if err != nil {
+		return false, handleMongoErr(err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
 func (m *mongoDb) DeleteOne(coll Collection, filter bson.M) error {
 	c := m.collection(coll)
 	res, err := c.DeleteOne(m.ctx, filter)
@@ -188,6 +204,16 @@ func (m *mongoDb) DeleteOneById(coll Collection, id string) error {
 	return m.DeleteOne(coll, bson.M{"id": id})
 }
 
+func (m *mongoDb) FindOneAndDelete(coll Collection, filter bson.M, sort bson.M, res interface{}) error {
+	c := m.collection(coll)
+	findResult := c.FindOneAndDelete(m.ctx, filter, options.FindOneAndDelete().SetSort(sort))
+	if err := handleMongoResult(findResult); err != nil {
+		return err
+	}
+
+	return findResult.Decode(res)
+}
+
 func handleMongoErr(err error) error {
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return ErrNotFound
@@ -249,6 +275,15 @@ func (m *mongoDb) ListPaginated(collName Collection, perPage int, page int, p *s
 	return nil
 }
 
+func (m *mongoDb) Count(coll Collection, filter bson.M) (int, error) {
+	c := m.collection(coll)
+	n, err := c.CountDocuments(m.ctx, filter)
+	if err != nil {
+		return 0, handleMongoErr(err)
+	}
+	return int(n), nil
+}
+
 func (m *mongoDb) collection(c Collection) *mongo.Collection {
 	if m.collections[c] == nil {
 		m.collections[c] = m.db.Collection(string(c))
@@ -256,6 +291,11 @@ func (m *mongoDb) collection(c Collection) *mongo.Collection {
 	return m.collections[c]
 }
 
+func (m *mongoDb) DeleteProvided(coll Collection, provider string) error {
+	_, err := m.collection(coll).DeleteMany(m.ctx, bson.M{"provider": provider})
+	return err
+}
+
 func (m *mongoDb) UpdateProvided(collName Collection, provider string, updates map[string]interface{}) error {
 	c := m.collection(collName)
 
@@ -168,6 +168,9 @@ func TestMongoIntegration(t *testing.T) {
 			}
 			return db.UpdateProvided(Provided, providedA.Provider, newProvided)
 		}},
+		{desc: "deletes all provided items", f: func(db Database, a *testSubject, resList *[]testSubject, tt *testing.T) error {
+			return db.DeleteProvided(Provided, providedA.Provider)
+		}},
 		{desc: "updates multiple properties", f: func(db Database, a *testSubject, resList *[]testSubject, tt *testing.T) error {
 			filters := map[string]interface{}{
 				subjectA.Id: bson.M{"id": subjectA.Id},
@@ -191,6 +194,13 @@ func TestMongoIntegration(t *testing.T) {
 		{desc: "creates item via update by id", f: func(db Database, a *testSubject, resList *[]testSubject, tt *testing.T) error {
 			return db.UpdateOneById(Subjects, subjectNew.Id, true, subjectNew, a)
 		}, expectsOne: &subjectNew},
+		{desc: "upserts with an update pipeline", f: func(db Database, a *testSubject, resList *[]testSubject, tt *testing.T) error {
+			matched, err := db.Upsert(Subjects, bson.M{"id": subjectA.Id}, bson.A{
+				bson.M{"$set": bson.M{"property": bson.M{"$concat": bson.A{"$property", "-upserted"}}}},
+			})
+			requireEqual(true, matched, tt)
+			return err
+		}},
 		{desc: "update returns not found without createIfMissing", f: func(db Database, a *testSubject, resList *[]testSubject, tt *testing.T) error {
 			return db.UpdateOne(Subjects, bson.M{"id": subjectNew.Id}, false, subjectNew, a)
 		}, expectedErr: ErrNotFound},
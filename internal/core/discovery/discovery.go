@@ -0,0 +1,506 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	providerClient "github.com/joscha-alisch/dyve/internal/provider/client"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// infoTimeout is the deadline for a provider to answer the info request.
+const infoTimeout = 10 * time.Second
+
+// Discoverer asks the known providers which features they serve and keeps the
+// registrations of the provider service in sync with their answers.
+type Discoverer interface {
+	// Discover queries every provider once. Providers that can't be reached keep their
+	// current registrations, providers with an incompatible protocol lose all of them.
+	Discover() error
+	// Restore stores the definitions of configured providers that aren't stored yet and adds
+	// all stored providers. The config file only seeds the stored definitions, so changes
+	// made at runtime take precedence.
+	Restore() error
+	// Reload adds stored providers that are unknown or changed and removes the providers
+	// whose definitions were deleted, which picks up changes made through other replicas.
+	// A provider that fails doesn't keep the others from being reloaded.
+	Reload() error
+	// Register adds the provider or replaces the one with the same id and discovers it right
+	// away. It returns the config the provider is talked to with.
+	Register(d provider.Definition) (config.ProviderConfig, error)
+	// Unregister stops discovering the provider and removes all of its registrations.
+	Unregister(id string) error
+	// Providers returns the configs of all known providers.
+	Providers() []config.ProviderConfig
+	// Run reloads and discovers the providers in the given interval until Stop is called.
+	Run(interval time.Duration)
+	Stop()
+}
+
+// New creates a Discoverer for the given providers. Each provider is talked to with a
+// client presenting the credentials of its config. Providers registered later reference
+// their credentials by their name in the given credentials.
+func New(providers provider.Service, configs []config.ProviderConfig, credentials map[string]sdk.AuthConfig) (Discoverer, error) {
+	clients := make(map[string]*http.Client)
+	seeded := make(map[string]sdk.AuthConfig)
+	for _, p := range configs {
+		c, err := providerClient.NewHttpClient(p.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create client for provider '%s': %w", p.Id, err)
+		}
+		clients[p.Id] = c
+		seeded[p.Id] = p.Auth
+	}
+
+	return &discoverer{
+		providers:   providers,
+		credentials: credentials,
+		seeded:      seeded,
+		configs:     append([]config.ProviderConfig{}, configs...),
+		clients:     clients,
+		stop:        make(chan struct{}),
+	}, nil
+}
+
+type discoverer struct {
+	providers   provider.Service
+	credentials map[string]sdk.AuthConfig
+	// seeded holds the credentials given in the config file, which stored definitions
+	// without a credentials reference keep using.
+	seeded map[string]sdk.AuthConfig
+
+	// mu serializes discoveries and changes to the known providers.
+	mu      sync.Mutex
+	configs []config.ProviderConfig
+	clients map[string]*http.Client
+	stop    chan struct{}
+}
+
+func (d *discoverer) Discover() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs []error
+	for _, p := range d.configs {
+		err := d.discover(p)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ErrDiscoveryFailed{Errs: errs}
+	}
+	return nil
+}
+
+func (d *discoverer) Restore() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stored, err := d.providers.ListDefinitions()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for _, definition := range stored {
+		known[definition.Id] = true
+	}
+	for _, p := range d.configs {
+		if known[p.Id] {
+			continue
+		}
+		err := d.providers.SaveDefinition(provider.Definition{Id: p.Id, Name: p.Name, Host: p.Host, Features: p.Features})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = d.reload()
+	var failed *ErrDiscoveryFailed
+	if errors.As(err, &failed) {
+		log.Error().Err(err).Msg("couldn't restore all providers")
+		return nil
+	}
+	return err
+}
+
+func (d *discoverer) Reload() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.reload()
+}
+
+func (d *discoverer) reload() error {
+	stored, err := d.providers.ListDefinitions()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	known := make(map[string]bool)
+	for _, definition := range stored {
+		known[definition.Id] = true
+
+		i := d.index(definition.Id)
+		if i >= 0 {
+			p, err := d.configOf(definition)
+			if err == nil && reflect.DeepEqual(p, d.configs[i]) {
+				continue
+			}
+		}
+
+		_, err := d.register(definition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("couldn't register provider '%s': %w", definition.Id, err))
+		}
+	}
+
+	// unregistering removes the provider from the configs, so iterate over a copy
+	for _, p := range append([]config.ProviderConfig{}, d.configs...) {
+		if known[p.Id] {
+			continue
+		}
+		err := d.unregister(p.Id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("couldn't remove provider '%s': %w", p.Id, err))
+			continue
+		}
+		log.Info().Str("provider", p.Id).Msg("removed provider whose definition was deleted")
+	}
+
+	if len(errs) > 0 {
+		return &ErrDiscoveryFailed{Errs: errs}
+	}
+	return nil
+}
+
+func (d *discoverer) Register(definition provider.Definition) (config.ProviderConfig, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p, err := d.register(definition)
+	if err != nil {
+		return config.ProviderConfig{}, err
+	}
+
+	err = d.discover(p)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", p.Id).Msg("couldn't discover registered provider, retrying later")
+	}
+	return p, nil
+}
+
+// configOf returns the config the provider of the definition is talked to with.
+func (d *discoverer) configOf(definition provider.Definition) (config.ProviderConfig, error) {
+	p := config.ProviderConfig{
+		Id:       definition.Id,
+		Name:     definition.Name,
+		Host:     definition.Host,
+		Auth:     d.seeded[definition.Id],
+		Features: definition.Features,
+	}
+	if definition.Credentials != "" {
+		auth, ok := d.credentials[definition.Credentials]
+		if !ok {
+			return config.ProviderConfig{}, fmt.Errorf("%w: '%s'", ErrUnknownCredentials, definition.Credentials)
+		}
+		p.Auth = auth
+	}
+	return p, nil
+}
+
+func (d *discoverer) register(definition provider.Definition) (config.ProviderConfig, error) {
+	p, err := d.configOf(definition)
+	if err != nil {
+		return config.ProviderConfig{}, err
+	}
+
+	c, err := providerClient.NewHttpClient(p.Auth)
+	if err != nil {
+		return config.ProviderConfig{}, fmt.Errorf("couldn't create client for provider '%s': %w", p.Id, err)
+	}
+
+	i := d.index(p.Id)
+	if i < 0 {
+		d.configs = append(d.configs, p)
+	} else {
+		// the registered clients might talk to the old host or present the old credentials. Only
+		// they are dropped, the shared registrations are added anew by the next discovery.
+		for _, t := range d.providers.Features(p.Id) {
+			d.providers.Forget(p.Id, t)
+		}
+		d.configs[i] = p
+	}
+	d.clients[p.Id] = c
+	return p, nil
+}
+
+func (d *discoverer) Unregister(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.unregister(id)
+}
+
+func (d *discoverer) unregister(id string) error {
+	i := d.index(id)
+	if i < 0 {
+		return provider.ErrNotFound
+	}
+
+	err := d.removeAll(id)
+	if err != nil {
+		return err
+	}
+
+	d.configs = append(d.configs[:i], d.configs[i+1:]...)
+	delete(d.clients, id)
+	return nil
+}
+
+func (d *discoverer) Providers() []config.ProviderConfig {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]config.ProviderConfig{}, d.configs...)
+}
+
+func (d *discoverer) index(id string) int {
+	for i, p := range d.configs {
+		if p.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *discoverer) removeAll(id string) error {
+	for _, t := range d.providers.Features(id) {
+		err := d.remove(id, t)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("provider", id).Str("feature", string(t)).Msg("unregistered provider feature")
+	}
+	return nil
+}
+
+func (d *discoverer) Run(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				err := d.Reload()
+				if err != nil {
+					log.Warn().Err(err).Msg("reloading providers failed")
+				}
+
+				err = d.Discover()
+				if err != nil {
+					log.Warn().Err(err).Msg("provider discovery failed")
+				}
+			}
+		}
+	}()
+}
+
+func (d *discoverer) Stop() {
+	close(d.stop)
+}
+
+func (d *discoverer) discover(p config.ProviderConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), infoTimeout)
+	defer cancel()
+
+	info, err := providerClient.NewInfoClient(p.Host, d.clients[p.Id]).GetInfo(ctx)
+	if errors.Is(err, sdk.ErrNotFound) {
+		err = fmt.Errorf("%w: provider '%s' doesn't serve the info endpoint", sdk.ErrIncompatibleProtocol, p.Id)
+	} else if err != nil {
+		return fmt.Errorf("couldn't get info from provider '%s': %w", p.Id, err)
+	} else {
+		err = info.CheckCompatible()
+	}
+
+	if err != nil {
+		return d.sync(p, nil, nil, err)
+	}
+
+	advertised := make(map[provider.Type]bool)
+	extended := make(map[sdk.Feature]bool)
+	for _, feature := range info.Features {
+		if _, ok := extensions[feature]; ok {
+			extended[feature] = true
+			continue
+		}
+		advertised[provider.Type(feature)] = true
+	}
+
+	warnConfiguredFeatures(p, advertised)
+	return d.sync(p, advertised, extended, nil)
+}
+
+// extensions are features that aren't registered on their own, but change the client
+// registered for the feature they extend.
+var extensions = map[sdk.Feature]provider.Type{
+	sdk.FeatureAppUpdates:      provider.TypeApps,
+	sdk.FeaturePipelineActions: provider.TypePipelines,
+}
+
+// sync registers the advertised features that aren't registered yet and removes
+// registrations for features the provider doesn't advertise anymore. Features are registered
+// anew when the provider starts or stops serving one of their extensions.
+func (d *discoverer) sync(p config.ProviderConfig, advertised map[provider.Type]bool, extended map[sdk.Feature]bool, cause error) error {
+	registered := make(map[provider.Type]bool)
+	for _, t := range d.providers.Features(p.Id) {
+		if !advertised[t] {
+			err := d.remove(p.Id, t)
+			if err != nil {
+				return err
+			}
+			log.Info().Str("provider", p.Id).Str("feature", string(t)).Msg("unregistered provider feature")
+			continue
+		}
+		if !d.clientOutdated(p.Id, t, extended) {
+			registered[t] = true
+			continue
+		}
+		// keeps the shared registration, the client serving the changed extensions is added below
+		d.providers.Forget(p.Id, t)
+	}
+
+	for t := range advertised {
+		if registered[t] {
+			continue
+		}
+		ok, err := d.add(p, t, extended)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			log.Warn().Str("provider", p.Id).Str("feature", string(t)).Msg("provider advertises unknown feature")
+			continue
+		}
+		log.Info().Str("provider", p.Id).Str("feature", string(t)).Msg("registered provider feature")
+	}
+
+	return cause
+}
+
+func (d *discoverer) add(p config.ProviderConfig, t provider.Type, extended map[sdk.Feature]bool) (bool, error) {
+	switch t {
+	case provider.TypeApps:
+		if extended[sdk.FeatureAppUpdates] {
+			return true, d.providers.AddAppProvider(p.Id, p.Name, providerClient.NewAppUpdatesProviderClient(p.Host, d.clients[p.Id]))
+		}
+		return true, d.providers.AddAppProvider(p.Id, p.Name, providerClient.NewAppProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypePipelines:
+		if extended[sdk.FeaturePipelineActions] {
+			return true, d.providers.AddPipelineProvider(p.Id, p.Name, providerClient.NewPipelineActionsProviderClient(p.Host, d.clients[p.Id]))
+		}
+		return true, d.providers.AddPipelineProvider(p.Id, p.Name, providerClient.NewPipelineProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeGroups:
+		return true, d.providers.AddGroupProvider(p.Id, p.Name, providerClient.NewGroupProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeRouting:
+		return true, d.providers.AddRoutingProvider(p.Id, p.Name, providerClient.NewRoutingProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeInstances:
+		return true, d.providers.AddInstancesProvider(p.Id, p.Name, providerClient.NewInstancesProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeLogs:
+		return true, d.providers.AddLogsProvider(p.Id, p.Name, providerClient.NewLogsProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeMetrics:
+		return true, d.providers.AddMetricsProvider(p.Id, p.Name, providerClient.NewMetricsProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeAlerts:
+		return true, d.providers.AddAlertsProvider(p.Id, p.Name, providerClient.NewAlertsProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeTopology:
+		return true, d.providers.AddTopologyProvider(p.Id, p.Name, providerClient.NewTopologyProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeStepLogs:
+		return true, d.providers.AddStepLogsProvider(p.Id, p.Name, providerClient.NewStepLogsProviderClient(p.Host, d.clients[p.Id]))
+	case provider.TypeAppActions:
+		return true, d.providers.AddAppActionsProvider(p.Id, p.Name, providerClient.NewAppActionsProviderClient(p.Host, d.clients[p.Id]))
+	}
+	return false, nil
+}
+
+// clientOutdated reports whether the registered client of the provider's feature doesn't
+// match whether the provider serves the extensions of the feature.
+func (d *discoverer) clientOutdated(id string, t provider.Type, extended map[sdk.Feature]bool) bool {
+	switch t {
+	case provider.TypeApps:
+		p, err := d.providers.GetAppProvider(id)
+		if err != nil {
+			return false
+		}
+		_, ok := p.(sdk.AppUpdatesProvider)
+		return ok != extended[sdk.FeatureAppUpdates]
+	case provider.TypePipelines:
+		p, err := d.providers.GetPipelineProvider(id)
+		if err != nil {
+			return false
+		}
+		_, ok := p.(sdk.PipelineActionsProvider)
+		return ok != extended[sdk.FeaturePipelineActions]
+	}
+	return false
+}
+
+func (d *discoverer) remove(id string, t provider.Type) error {
+	switch t {
+	case provider.TypeApps:
+		return d.providers.DeleteAppProvider(id)
+	case provider.TypePipelines:
+		return d.providers.DeletePipelineProvider(id)
+	case provider.TypeGroups:
+		return d.providers.DeleteGroupProvider(id)
+	case provider.TypeRouting:
+		return d.providers.DeleteRoutingProvider(id)
+	case provider.TypeInstances:
+		return d.providers.DeleteInstancesProvider(id)
+	case provider.TypeLogs:
+		return d.providers.DeleteLogsProvider(id)
+	case provider.TypeMetrics:
+		return d.providers.DeleteMetricsProvider(id)
+	case provider.TypeAlerts:
+		return d.providers.DeleteAlertsProvider(id)
+	case provider.TypeStepLogs:
+		return d.providers.DeleteStepLogsProvider(id)
+	case provider.TypeTopology:
+		return d.providers.DeleteTopologyProvider(id)
+	case provider.TypeAppActions:
+		return d.providers.DeleteAppActionsProvider(id)
+	}
+	return nil
+}
+
+// warnConfiguredFeatures points out configs that still list features which differ from
+// what the provider actually serves.
+func warnConfiguredFeatures(p config.ProviderConfig, advertised map[provider.Type]bool) {
+	if len(p.Features) == 0 {
+		return
+	}
+
+	configured := make(map[provider.Type]bool)
+	for _, t := range p.Features {
+		configured[t] = true
+		if !advertised[t] {
+			log.Warn().Str("provider", p.Id).Str("feature", string(t)).Msg("configured feature is not served by provider, ignoring")
+		}
+	}
+	for t := range advertised {
+		if !configured[t] {
+			log.Warn().Str("provider", p.Id).Str("feature", string(t)).Msg("provider serves feature missing from config, registering anyway")
+		}
+	}
+}
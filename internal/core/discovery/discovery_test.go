@@ -0,0 +1,637 @@
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+
+	tests := []struct {
+		desc        string
+		registered  []provider.Type
+		handler     http.Handler
+		auth        sdk.AuthConfig
+		closed      bool
+		expected    []provider.Type
+		expectedErr error
+	}{
+		{desc: "registers advertised features", handler: sdk.NewHandler(sdk.ProviderConfig{
+			Apps:    p,
+			Routing: p,
+		}), expected: []provider.Type{provider.TypeApps, provider.TypeRouting}},
+		{desc: "removes features no longer advertised", registered: []provider.Type{provider.TypeApps, provider.TypeGroups},
+			handler: sdk.NewHandler(sdk.ProviderConfig{
+				Apps:      p,
+				Instances: p,
+			}), expected: []provider.Type{provider.TypeApps, provider.TypeInstances}},
+		{desc: "registers logs, metrics and alerts", handler: sdk.NewHandler(sdk.ProviderConfig{
+			Logs:    p,
+			Metrics: p,
+			Alerts:  p,
+		}), expected: []provider.Type{provider.TypeAlerts, provider.TypeLogs, provider.TypeMetrics}},
+		{desc: "registers app actions", handler: sdk.NewHandler(sdk.ProviderConfig{
+			Apps:       p,
+			AppActions: p,
+		}), expected: []provider.Type{provider.TypeAppActions, provider.TypeApps}},
+		{desc: "registers step logs", handler: sdk.NewHandler(sdk.ProviderConfig{
+			Pipelines: p,
+			StepLogs:  p,
+		}), expected: []provider.Type{provider.TypePipelines, provider.TypeStepLogs}},
+		{desc: "registers topology", handler: sdk.NewHandler(sdk.ProviderConfig{
+			Apps:     p,
+			Topology: p,
+		}), expected: []provider.Type{provider.TypeApps, provider.TypeTopology}},
+		{desc: "ignores unknown features", handler: infoHandler(sdk.ProviderInfo{
+			ProtocolVersion: sdk.ProtocolVersion,
+			Features:        []sdk.Feature{sdk.FeaturePipelines, "unknown"},
+		}), expected: []provider.Type{provider.TypePipelines}},
+		{desc: "rejects incompatible protocol", registered: []provider.Type{provider.TypeApps}, handler: infoHandler(sdk.ProviderInfo{
+			ProtocolVersion: sdk.ProtocolVersion + 1,
+			Features:        []sdk.Feature{sdk.FeatureApps},
+		}), expected: nil, expectedErr: sdk.ErrIncompatibleProtocol},
+		{desc: "rejects provider without info endpoint", registered: []provider.Type{provider.TypeApps},
+			handler: http.NotFoundHandler(), expected: nil, expectedErr: sdk.ErrIncompatibleProtocol},
+		{desc: "authenticates against provider", auth: sdk.AuthConfig{Token: "token"}, handler: sdk.NewHandler(sdk.ProviderConfig{
+			Apps: p,
+			Auth: sdk.AuthConfig{Token: "token"},
+		}), expected: []provider.Type{provider.TypeApps}},
+		{desc: "keeps features of provider rejecting credentials", registered: []provider.Type{provider.TypeApps}, handler: sdk.NewHandler(sdk.ProviderConfig{
+			Auth: sdk.AuthConfig{Token: "token"},
+		}), expected: []provider.Type{provider.TypeApps}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "keeps features of unreachable provider", registered: []provider.Type{provider.TypeApps},
+			handler: http.NotFoundHandler(), closed: true, expected: []provider.Type{provider.TypeApps}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+			if test.closed {
+				server.Close()
+			}
+
+			s := provider.NewService(&db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}})
+			for _, feature := range test.registered {
+				register(tt, s, feature)
+			}
+
+			d, err := New(s, []config.ProviderConfig{{Id: "provider", Name: "name", Host: server.URL, Auth: test.auth}}, nil)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			err = d.Discover()
+
+			if test.expectedErr != nil && !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+			if test.expectedErr == nil && !test.closed && err != nil {
+				tt.Errorf("\nunexpected err: %v", err)
+			}
+			if test.closed && err == nil {
+				tt.Errorf("\nexpected err for unreachable provider")
+			}
+
+			features := s.Features("provider")
+			if !cmp.Equal(test.expected, features) {
+				tt.Errorf("\ndiff between features: \n%s\n", cmp.Diff(test.expected, features))
+			}
+		})
+	}
+}
+
+func TestDiscoverAppUpdates(t *testing.T) {
+	tests := []struct {
+		desc       string
+		registered bool
+		apps       sdk.AppProviderContext
+		expected   bool
+	}{
+		{desc: "registers app updates client", apps: fakeProvider.AppUpdatesProvider(nil, sdk.AppUpdates{}), expected: true},
+		{desc: "registers plain client", apps: fakeProvider.AppProvider(nil), expected: false},
+		{desc: "replaces client once provider serves app updates", registered: true, apps: fakeProvider.AppUpdatesProvider(nil, sdk.AppUpdates{}), expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			server := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: test.apps}))
+			defer server.Close()
+
+			s := provider.NewService(&db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}})
+			if test.registered {
+				register(tt, s, provider.TypeApps)
+			}
+
+			d, err := New(s, []config.ProviderConfig{{Id: "provider", Name: "name", Host: server.URL}}, nil)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			err = d.Discover()
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			if !cmp.Equal([]provider.Type{provider.TypeApps}, s.Features("provider")) {
+				tt.Errorf("\nunexpected features: %v", s.Features("provider"))
+			}
+
+			p, err := s.GetAppProvider("provider")
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			_, ok := p.(sdk.AppUpdatesProvider)
+			if ok != test.expected {
+				tt.Errorf("\nwanted app updates client: %v, got %v", test.expected, ok)
+			}
+		})
+	}
+}
+
+func TestDiscoverPipelineActions(t *testing.T) {
+	tests := []struct {
+		desc       string
+		registered bool
+		pipelines  sdk.PipelineProviderContext
+		expected   bool
+	}{
+		{desc: "registers pipeline actions client", pipelines: fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{}), expected: true},
+		{desc: "registers plain client", pipelines: plainPipelineProvider{fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{})}, expected: false},
+		{desc: "replaces client once provider serves pipeline actions", registered: true, pipelines: fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{}), expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			server := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Pipelines: test.pipelines}))
+			defer server.Close()
+
+			s := provider.NewService(&db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}})
+			if test.registered {
+				register(tt, s, provider.TypePipelines)
+			}
+
+			d, err := New(s, []config.ProviderConfig{{Id: "provider", Name: "name", Host: server.URL}}, nil)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			err = d.Discover()
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			if !cmp.Equal([]provider.Type{provider.TypePipelines}, s.Features("provider")) {
+				tt.Errorf("\nunexpected features: %v", s.Features("provider"))
+			}
+
+			p, err := s.GetPipelineProvider("provider")
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			_, ok := p.(sdk.PipelineActionsProvider)
+			if ok != test.expected {
+				tt.Errorf("\nwanted pipeline actions client: %v, got %v", test.expected, ok)
+			}
+		})
+	}
+}
+
+// plainPipelineProvider hides the actions of the wrapped provider.
+type plainPipelineProvider struct {
+	sdk.PipelineProviderContext
+}
+
+func register(t *testing.T, s provider.Service, providerType provider.Type) {
+	p := fakeProvider.AppProvider(nil)
+	var err error
+	switch providerType {
+	case provider.TypeApps:
+		err = s.AddAppProvider("provider", "name", p)
+	case provider.TypeGroups:
+		err = s.AddGroupProvider("provider", "name", p)
+	case provider.TypePipelines:
+		err = s.AddPipelineProvider("provider", "name", plainPipelineProvider{p})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func infoHandler(info sdk.ProviderInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": http.StatusOK,
+			"result": info,
+		})
+	})
+}
+
+func TestRestore(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+	seeded := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer seeded.Close()
+	moved := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Groups: p}))
+	defer moved.Close()
+	authenticated := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{
+		Pipelines: p,
+		Auth:      sdk.AuthConfig{Token: "token"},
+	}))
+	defer authenticated.Close()
+
+	s := &definitionStore{
+		Service: provider.NewService(&db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}}),
+		definitions: map[string]provider.Definition{
+			"provider-b": {Id: "provider-b", Host: moved.URL},
+			"provider-c": {Id: "provider-c", Host: authenticated.URL, Credentials: "shared"},
+		},
+	}
+
+	d, err := New(s, []config.ProviderConfig{
+		{Id: "provider-a", Name: "A", Host: seeded.URL},
+		{Id: "provider-b", Host: seeded.URL},
+	}, map[string]sdk.AuthConfig{"shared": {Token: "token"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	err = d.Restore()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = d.Discover()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expectedDefinitions := map[string]provider.Definition{
+		"provider-a": {Id: "provider-a", Name: "A", Host: seeded.URL},
+		"provider-b": {Id: "provider-b", Host: moved.URL},
+		"provider-c": {Id: "provider-c", Host: authenticated.URL, Credentials: "shared"},
+	}
+	if !cmp.Equal(expectedDefinitions, s.definitions) {
+		t.Errorf("\ndiff between definitions: \n%s\n", cmp.Diff(expectedDefinitions, s.definitions))
+	}
+
+	expectedFeatures := map[string][]provider.Type{
+		"provider-a": {provider.TypeApps},
+		"provider-b": {provider.TypeGroups},
+		"provider-c": {provider.TypePipelines},
+	}
+	for id, expected := range expectedFeatures {
+		if !cmp.Equal(expected, s.Features(id)) {
+			t.Errorf("\nunexpected features of %s: %v", id, s.Features(id))
+		}
+	}
+	if len(d.Providers()) != 3 {
+		t.Errorf("\nexpected 3 providers, got %v", d.Providers())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+	apps := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer apps.Close()
+	groups := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{
+		Groups: p,
+		Auth:   sdk.AuthConfig{Token: "token"},
+	}))
+	defer groups.Close()
+
+	s := provider.NewService(&db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}})
+	d, err := New(s, nil, map[string]sdk.AuthConfig{"shared": {Token: "token"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	_, err = d.Register(provider.Definition{Id: "provider", Host: apps.URL, Credentials: "unknown"})
+	if !errors.Is(err, ErrUnknownCredentials) {
+		t.Errorf("\nwanted err: %v\ngot: %v", ErrUnknownCredentials, err)
+	}
+	if len(d.Providers()) != 0 {
+		t.Errorf("\nprovider with unknown credentials was registered")
+	}
+
+	_, err = d.Register(provider.Definition{Id: "provider", Host: apps.URL})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal([]provider.Type{provider.TypeApps}, s.Features("provider")) {
+		t.Errorf("\nunexpected features: %v", s.Features("provider"))
+	}
+
+	c, err := d.Register(provider.Definition{Id: "provider", Host: groups.URL, Credentials: "shared"})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if c.Auth.Token != "token" {
+		t.Errorf("\nregistered provider doesn't present the referenced credentials")
+	}
+	if !cmp.Equal([]provider.Type{provider.TypeGroups}, s.Features("provider")) {
+		t.Errorf("\nunexpected features after replacing provider: %v", s.Features("provider"))
+	}
+
+	err = d.Unregister("provider")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(s.Features("provider")) != 0 || len(d.Providers()) != 0 {
+		t.Errorf("\nprovider is still registered: %v", s.Features("provider"))
+	}
+
+	err = d.Unregister("provider")
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("\nwanted err: %v\ngot: %v", provider.ErrNotFound, err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+	apps := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer apps.Close()
+	groups := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{
+		Groups: p,
+		Auth:   sdk.AuthConfig{Token: "token"},
+	}))
+	defer groups.Close()
+
+	shared := newSharedDatabase()
+	credentials := map[string]sdk.AuthConfig{"shared": {Token: "token"}}
+
+	a := provider.NewService(shared)
+	discoveryA, err := New(a, nil, credentials)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	b := provider.NewService(shared)
+	discoveryB, err := New(b, nil, credentials)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	// saves the definition through replica a, as the admin API does
+	save := func(definition provider.Definition) {
+		_, err := discoveryA.Register(definition)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		err = a.SaveDefinition(definition)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+	}
+	// reloads and discovers the providers on replica b, as its run loop does
+	reload := func() {
+		err := discoveryB.Reload()
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		_ = discoveryB.Discover()
+	}
+
+	save(provider.Definition{Id: "provider", Host: apps.URL})
+	reload()
+	if !cmp.Equal([]provider.Type{provider.TypeApps}, b.Features("provider")) {
+		t.Errorf("\nunexpected features of provider added on other replica: %v", b.Features("provider"))
+	}
+
+	save(provider.Definition{Id: "provider", Host: groups.URL, Credentials: "shared"})
+	reload()
+	if !cmp.Equal([]provider.Type{provider.TypeGroups}, b.Features("provider")) {
+		t.Errorf("\nunexpected features of provider changed on other replica: %v", b.Features("provider"))
+	}
+	providers := discoveryB.Providers()
+	if len(providers) != 1 || providers[0].Auth.Token != "token" {
+		t.Errorf("\nprovider changed on other replica doesn't present the referenced credentials: %v", providers)
+	}
+
+	err = discoveryA.Unregister("provider")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = a.DeleteDefinition("provider")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	reload()
+	if len(b.Features("provider")) != 0 || len(discoveryB.Providers()) != 0 {
+		t.Errorf("\nprovider deleted on other replica is still registered: %v", b.Features("provider"))
+	}
+}
+
+func TestReloadDeletedOnOtherReplica(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+	apps := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer apps.Close()
+	groups := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Groups: p}))
+	defer groups.Close()
+
+	shared := newSharedDatabase()
+	a := provider.NewService(shared)
+	b := provider.NewService(shared)
+
+	var replicas []Discoverer
+	for _, s := range []provider.Service{a, b} {
+		d, err := New(s, []config.ProviderConfig{
+			{Id: "provider-a", Host: apps.URL},
+			{Id: "provider-b", Host: apps.URL},
+		}, nil)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		err = d.Restore()
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		_ = d.Discover()
+		replicas = append(replicas, d)
+	}
+	discoveryA, discoveryB := replicas[0], replicas[1]
+
+	err := discoveryA.Unregister("provider-a")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = a.DeleteDefinition("provider-a")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = a.SaveDefinition(provider.Definition{Id: "provider-b", Host: groups.URL})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	err = discoveryB.Reload()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	_ = discoveryB.Discover()
+
+	if len(b.Features("provider-a")) != 0 {
+		t.Errorf("\nprovider deleted on other replica is still registered: %v", b.Features("provider-a"))
+	}
+	if !cmp.Equal([]provider.Type{provider.TypeGroups}, b.Features("provider-b")) {
+		t.Errorf("\nunexpected features of provider changed in the same reload: %v", b.Features("provider-b"))
+	}
+	if len(discoveryB.Providers()) != 1 {
+		t.Errorf("\nexpected 1 provider, got %v", discoveryB.Providers())
+	}
+}
+
+func TestReloadKeepsSharedState(t *testing.T) {
+	p := fakeProvider.AppProvider(nil)
+	before := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer before.Close()
+	after := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{Apps: p}))
+	defer after.Close()
+
+	shared := newSharedDatabase()
+	var services []provider.Service
+	var replicas []Discoverer
+	for i := 0; i < 2; i++ {
+		s := provider.NewService(shared)
+		d, err := New(s, []config.ProviderConfig{{Id: "provider", Host: before.URL}}, nil)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		err = d.Restore()
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		_ = d.Discover()
+		services = append(services, s)
+		replicas = append(replicas, d)
+	}
+
+	// another replica holds the lease and synced the provider before
+	shared.docs["apps/provider"]["leaseOwner"] = "replica-c"
+	shared.docs["apps/provider"]["lastSynced"] = "2006-01-01T15:00:00Z"
+
+	_, err := replicas[0].Register(provider.Definition{Id: "provider", Host: after.URL})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = services[0].SaveDefinition(provider.Definition{Id: "provider", Host: after.URL})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	err = replicas[1].Reload()
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	_ = replicas[1].Discover()
+
+	doc := shared.docs["apps/provider"]
+	if doc["leaseOwner"] != "replica-c" || doc["lastSynced"] != "2006-01-01T15:00:00Z" {
+		t.Errorf("\nchanging the definition reset the shared state of the provider: %v", doc)
+	}
+	for i, s := range services {
+		if !cmp.Equal([]provider.Type{provider.TypeApps}, s.Features("provider")) {
+			t.Errorf("\nunexpected features on replica %d: %v", i, s.Features("provider"))
+		}
+	}
+	for i, d := range replicas {
+		if d.Providers()[0].Host != after.URL {
+			t.Errorf("\nreplica %d still talks to the old host: %v", i, d.Providers())
+		}
+	}
+}
+
+// sharedDatabase stores provider registrations and definitions in memory and records everything
+// else, so that services sharing it see each other's changes as replicas sharing Mongo do.
+type sharedDatabase struct {
+	*db.RecordingDatabase
+	docs map[string]bson.M
+}
+
+func newSharedDatabase() *sharedDatabase {
+	return &sharedDatabase{
+		RecordingDatabase: &db.RecordingDatabase{Recorder: &db.DatabaseRecorder{}},
+		docs:              make(map[string]bson.M),
+	}
+}
+
+func docKey(filter bson.M) string {
+	return fmt.Sprint(filter["type"]) + "/" + fmt.Sprint(filter["id"])
+}
+
+func (d *sharedDatabase) FindManyWithOptions(coll database.Collection, filter bson.M, each func(dec database.Decodable) error, sort bson.M, limit int) error {
+	if coll != provider.Collection || filter["type"] != "definition" {
+		return d.RecordingDatabase.FindManyWithOptions(coll, filter, each, sort, limit)
+	}
+	for _, doc := range d.docs {
+		if doc["type"] != "definition" {
+			continue
+		}
+		doc := doc
+		err := each(database.DecodableFunc(func(target interface{}) error {
+			b, err := bson.Marshal(doc)
+			if err != nil {
+				return err
+			}
+			return bson.Unmarshal(b, target)
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *sharedDatabase) UpdateOne(coll database.Collection, filter bson.M, createIfMissing bool, update interface{}, res interface{}) error {
+	if coll != provider.Collection {
+		return d.RecordingDatabase.UpdateOne(coll, filter, createIfMissing, update, res)
+	}
+	doc, ok := d.docs[docKey(filter)]
+	if !ok {
+		if !createIfMissing {
+			return database.ErrNotFound
+		}
+		doc = bson.M{"id": filter["id"], "type": fmt.Sprint(filter["type"])}
+	}
+	for k, v := range update.(bson.M) {
+		doc[k] = v
+	}
+	d.docs[docKey(filter)] = doc
+	return nil
+}
+
+func (d *sharedDatabase) DeleteOne(coll database.Collection, filter bson.M) error {
+	if coll != provider.Collection {
+		return d.RecordingDatabase.DeleteOne(coll, filter)
+	}
+	if _, ok := d.docs[docKey(filter)]; !ok {
+		return database.ErrNotFound
+	}
+	delete(d.docs, docKey(filter))
+	return nil
+}
+
+// definitionStore keeps the definitions of the wrapped service in memory.
+type definitionStore struct {
+	provider.Service
+	definitions map[string]provider.Definition
+}
+
+func (s *definitionStore) ListDefinitions() ([]provider.Definition, error) {
+	var res []provider.Definition
+	for _, definition := range s.definitions {
+		res = append(res, definition)
+	}
+	return res, nil
+}
+
+func (s *definitionStore) SaveDefinition(d provider.Definition) error {
+	s.definitions[d.Id] = d
+	return nil
+}
@@ -0,0 +1,32 @@
+package discovery
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnknownCredentials is returned when a provider references credentials that aren't configured.
+var ErrUnknownCredentials = errors.New("unknown provider credentials")
+
+// ErrDiscoveryFailed is returned when one or more providers couldn't be discovered.
+// It matches every error it contains via errors.Is.
+type ErrDiscoveryFailed struct {
+	Errs []error
+}
+
+func (e *ErrDiscoveryFailed) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ErrDiscoveryFailed) Error() string {
+	var msgs []string
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "provider discovery failed: " + strings.Join(msgs, "; ")
+}
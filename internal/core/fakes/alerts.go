@@ -0,0 +1,50 @@
+package fakes
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/alerts"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+type MappingAlertsService struct {
+	Alerts []alerts.Alert
+	Err    error
+}
+
+func (m *MappingAlertsService) UpdateAlerts(providerId string, alertList []sdk.Alert) error {
+	var res []alerts.Alert
+	for _, alert := range m.Alerts {
+		if alert.ProviderId != providerId {
+			res = append(res, alert)
+		}
+	}
+	for _, alert := range alertList {
+		res = append(res, alerts.Alert{Alert: alert, ProviderId: providerId})
+	}
+	m.Alerts = res
+	return nil
+}
+
+func (m *MappingAlertsService) ListAppAlerts(app string) ([]alerts.Alert, error) {
+	return m.filter(func(alert alerts.Alert) bool {
+		return alert.AppId == app
+	})
+}
+
+func (m *MappingAlertsService) ListTeamAlerts(team string) ([]alerts.Alert, error) {
+	return m.filter(func(alert alerts.Alert) bool {
+		return alert.Team == team
+	})
+}
+
+func (m *MappingAlertsService) filter(f func(alert alerts.Alert) bool) ([]alerts.Alert, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	res := []alerts.Alert{}
+	for _, alert := range m.Alerts {
+		if f(alert) {
+			res = append(res, alert)
+		}
+	}
+	return res, nil
+}
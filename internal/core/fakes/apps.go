@@ -20,6 +20,7 @@ type AppsRecorder struct {
 	AppId      string
 	ProviderId string
 	Apps       []sdk.App
+	DeletedIds []string
 }
 
 func (a *RecordingAppsService) ListAppsPaginated(perPage int, page int) (sdk.AppPage, error) {
@@ -59,6 +60,26 @@ func (a *RecordingAppsService) UpdateApp(app sdk.App) error {
 	return nil
 }
 
+func (a *RecordingAppsService) UpsertApps(providerId string, apps []sdk.App) error {
+	a.Record.ProviderId = providerId
+	a.Record.Apps = apps
+
+	if a.Err != nil {
+		return a.Err
+	}
+	return nil
+}
+
+func (a *RecordingAppsService) DeleteApps(providerId string, ids []string) error {
+	a.Record.ProviderId = providerId
+	a.Record.DeletedIds = ids
+
+	if a.Err != nil {
+		return a.Err
+	}
+	return nil
+}
+
 type MappingAppsService struct {
 	Apps map[string]apps.App
 }
@@ -94,3 +115,22 @@ func (m *MappingAppsService) UpdateApp(app sdk.App) error {
 	}
 	return nil
 }
+
+func (m *MappingAppsService) UpsertApps(providerId string, appList []sdk.App) error {
+	for _, app := range appList {
+		m.Apps[app.Id] = apps.App{
+			App:        app,
+			ProviderId: providerId,
+		}
+	}
+	return nil
+}
+
+func (m *MappingAppsService) DeleteApps(providerId string, ids []string) error {
+	for _, id := range ids {
+		if m.Apps[id].ProviderId == providerId {
+			delete(m.Apps, id)
+		}
+	}
+	return nil
+}
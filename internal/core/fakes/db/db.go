@@ -36,6 +36,8 @@ type RecordingDatabase struct {
 	Return           func(target interface{})
 	ReturnEach       func(each func(decodable database.Decodable) error)
 	ReturnPagination func(pagination *sdk.Pagination)
+	ReturnCount      int
+	ReturnMatched    bool
 	Err              error
 }
 
@@ -135,6 +137,17 @@ func (d *RecordingDatabase) UpdateProvided(coll database.Collection, provider st
 	return nil
 }
 
+func (d *RecordingDatabase) DeleteProvided(coll database.Collection, provider string) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	d.Recorder.Record(DatabaseRecord{
+		Collection: coll,
+		Provider:   provider,
+	})
+	return nil
+}
+
 func (d *RecordingDatabase) UpdateMany(coll database.Collection, filters map[string]interface{}, updates map[string]interface{}) error {
 	if d.Err != nil {
 		return d.Err
@@ -163,6 +176,19 @@ func (d *RecordingDatabase) UpdateOne(coll database.Collection, filter bson.M, c
 	return nil
 }
 
+func (d *RecordingDatabase) Upsert(coll database.Collection, filter bson.M, update interface{}) (bool, error) {
+	if d.Err != nil {
+		return false, d.Err
+	}
+	d.Recorder.Record(DatabaseRecord{
+		Collection:      coll,
+		CreateIfMissing: true,
+		Filter:          filter,
+		Update:          update,
+	})
+	return d.ReturnMatched, nil
+}
+
 func (d *RecordingDatabase) UpdateOneById(coll database.Collection, id string, createIfMissing bool, update interface{}, res interface{}) error {
 	if d.Err != nil {
 		return d.Err
@@ -213,6 +239,32 @@ func (d *RecordingDatabase) DeleteOneById(coll database.Collection, id string) e
 	return nil
 }
 
+func (d *RecordingDatabase) Count(coll database.Collection, filter bson.M) (int, error) {
+	if d.Err != nil {
+		return 0, d.Err
+	}
+	d.Recorder.Record(DatabaseRecord{
+		Collection: coll,
+		Filter:     filter,
+	})
+	return d.ReturnCount, nil
+}
+
+func (d *RecordingDatabase) FindOneAndDelete(coll database.Collection, filter bson.M, sort bson.M, res interface{}) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	if d.Return != nil {
+		d.Return(res)
+	}
+	d.Recorder.Record(DatabaseRecord{
+		Collection: coll,
+		Filter:     filter,
+		Sort:       sort,
+	})
+	return nil
+}
+
 func (d *RecordingDatabase) EnsureIndex(coll database.Collection, model mongo.IndexModel) error {
 	if d.Err != nil {
 		return d.Err
@@ -0,0 +1,75 @@
+package fakes
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/config"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"time"
+)
+
+// RecordingDiscoverer records the providers registered and unregistered through it. Registered
+// providers present the credentials of the referenced name in Credentials.
+type RecordingDiscoverer struct {
+	Err          error
+	Credentials  map[string]sdk.AuthConfig
+	Registered   []provider.Definition
+	Unregistered []string
+
+	configs []config.ProviderConfig
+}
+
+func (d *RecordingDiscoverer) Discover() error {
+	return d.Err
+}
+
+func (d *RecordingDiscoverer) Restore() error {
+	return d.Err
+}
+
+func (d *RecordingDiscoverer) Reload() error {
+	return d.Err
+}
+
+func (d *RecordingDiscoverer) Register(definition provider.Definition) (config.ProviderConfig, error) {
+	if d.Err != nil {
+		return config.ProviderConfig{}, d.Err
+	}
+	d.Registered = append(d.Registered, definition)
+
+	p := config.ProviderConfig{
+		Id:       definition.Id,
+		Name:     definition.Name,
+		Host:     definition.Host,
+		Auth:     d.Credentials[definition.Credentials],
+		Features: definition.Features,
+	}
+	d.remove(definition.Id)
+	d.configs = append(d.configs, p)
+	return p, nil
+}
+
+func (d *RecordingDiscoverer) Unregister(id string) error {
+	if d.Err != nil {
+		return d.Err
+	}
+	d.Unregistered = append(d.Unregistered, id)
+	d.remove(id)
+	return nil
+}
+
+func (d *RecordingDiscoverer) Providers() []config.ProviderConfig {
+	return append([]config.ProviderConfig{}, d.configs...)
+}
+
+func (d *RecordingDiscoverer) remove(id string) {
+	for i, p := range d.configs {
+		if p.Id == id {
+			d.configs = append(d.configs[:i], d.configs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (d *RecordingDiscoverer) Run(interval time.Duration) {}
+
+func (d *RecordingDiscoverer) Stop() {}
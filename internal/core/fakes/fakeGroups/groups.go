@@ -1,6 +1,7 @@
 package fakeGroups
 
 import (
+	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/groups"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 )
@@ -8,6 +9,8 @@ import (
 type RecordingGroupsService struct {
 	Err        error
 	ByProvider groups.GroupByProviderMap
+	Members    map[string][]sdk.Member
+	Ancestors  map[string][]string
 	Record     GroupsRecorder
 }
 
@@ -38,5 +41,50 @@ func (r *RecordingGroupsService) UpdateGroups(guid string, groups []sdk.Group) e
 	panic("implement me")
 }
 
+func (r *RecordingGroupsService) UpsertGroups(providerId string, groups []sdk.Group) error {
+	r.Record.ProviderId = providerId
+	r.Record.Groups = groups
+
+	if r.Err != nil {
+		return r.Err
+	}
+	return nil
+}
+
+func (r *RecordingGroupsService) DeleteGroups(providerId string, ids []string) error {
+	r.Record.ProviderId = providerId
+	r.Record.DeletedIds = ids
+
+	if r.Err != nil {
+		return r.Err
+	}
+	return nil
+}
+
+func (r *RecordingGroupsService) ListMembers(id string) ([]sdk.Member, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	members, ok := r.Members[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return members, nil
+}
+
+func (r *RecordingGroupsService) WithAncestors(groupRefs []string) ([]string, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	res := append([]string{}, groupRefs...)
+	for _, group := range groupRefs {
+		res = append(res, r.Ancestors[group]...)
+	}
+	return res, nil
+}
+
 type GroupsRecorder struct {
+	ProviderId string
+	Groups     []sdk.Group
+	DeletedIds []string
 }
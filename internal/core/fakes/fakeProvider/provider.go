@@ -1,7 +1,9 @@
 package fakeProvider
 
 import (
+	"context"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"strconv"
 	"time"
 )
 
@@ -30,6 +32,52 @@ func InstancesProvider(instances map[string]sdk.AppInstances) *Provider {
 	}
 }
 
+// LogsProvider serves the recent logs of apps and sends the tail of an app once tailed.
+func LogsProvider(logs map[string]sdk.AppLogs, tail map[string]sdk.AppLogs) *Provider {
+	return &Provider{
+		Logs: logs,
+		Tail: tail,
+	}
+}
+
+func MetricsProvider(metrics map[string]sdk.AppMetrics) *Provider {
+	return &Provider{
+		Metrics: metrics,
+	}
+}
+
+func AlertsProvider(alerts []sdk.Alert) *Provider {
+	return &Provider{
+		Alerts: alerts,
+	}
+}
+
+func TopologyProvider(topology sdk.Topology) *Provider {
+	return &Provider{
+		Topology: topology,
+	}
+}
+
+// StepLogsProvider pages the lines for every step, recording the requested run in
+// RecordedTime and the pipeline and step in RecordedStep.
+func StepLogsProvider(lines sdk.AppLogs) *Provider {
+	return &Provider{
+		StepLogs: lines,
+	}
+}
+
+// AppActionsProvider records the actions taken on apps and pipelines in RecordedActions.
+func AppActionsProvider() *Provider {
+	return &Provider{}
+}
+
+func AppUpdatesProvider(apps []sdk.App, updates sdk.AppUpdates) *UpdatesProvider {
+	return &UpdatesProvider{
+		Provider:   Provider{Apps: apps},
+		AppUpdates: updates,
+	}
+}
+
 func GroupProvider() *Provider {
 	return &Provider{}
 }
@@ -41,54 +89,172 @@ func NewErrProvider(err error) *Provider {
 }
 
 type Provider struct {
-	Apps         []sdk.App
-	Err          error
-	Pipelines    []sdk.Pipeline
-	Routes       map[string]sdk.AppRouting
-	Instances    map[string]sdk.AppInstances
-	Updates      sdk.PipelineUpdates
-	RecordedTime time.Time
+	Apps          []sdk.App
+	Err           error
+	Pipelines     []sdk.Pipeline
+	Routes        map[string]sdk.AppRouting
+	Instances     map[string]sdk.AppInstances
+	Logs          map[string]sdk.AppLogs
+	Tail          map[string]sdk.AppLogs
+	Metrics       map[string]sdk.AppMetrics
+	RecordedQuery sdk.MetricsQuery
+	Alerts        []sdk.Alert
+	Topology      sdk.Topology
+	Updates       sdk.PipelineUpdates
+	RecordedTime  time.Time
+	StepLogs      sdk.AppLogs
+	RecordedStep  string
+
+	RecordedActions []string
 }
 
-func (f *Provider) ListGroups() ([]sdk.Group, error) {
+func (f *Provider) ListGroups(ctx context.Context) ([]sdk.Group, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (f *Provider) GetGroup(id string) (sdk.Group, error) {
+func (f *Provider) GetGroup(ctx context.Context, id string) (sdk.Group, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (f *Provider) GetAppInstances(id string) (sdk.AppInstances, error) {
+func (f *Provider) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
 	return f.Instances[id], f.Err
 }
 
-func (f *Provider) GetAppRouting(id string) (sdk.AppRouting, error) {
+func (f *Provider) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	return f.Logs[id], f.Err
+}
+
+// TailLogs sends the tail of the app and then blocks until the context is done.
+func (f *Provider) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	for _, line := range f.Tail[id] {
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (f *Provider) GetAppMetrics(ctx context.Context, id string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	f.RecordedQuery = q
+	return f.Metrics[id], f.Err
+}
+
+func (f *Provider) ListAlerts(ctx context.Context) ([]sdk.Alert, error) {
+	return f.Alerts, f.Err
+}
+
+func (f *Provider) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	return f.Topology, f.Err
+}
+
+func (f *Provider) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (sdk.StepLogsPage, error) {
+	f.RecordedTime = runStarted
+	f.RecordedStep = pipelineId + " " + strconv.Itoa(stepId)
+	if f.Err != nil {
+		return sdk.StepLogsPage{}, f.Err
+	}
+	return sdk.PageStepLogs(f.StepLogs, perPage, page)
+}
+
+func (f *Provider) RestartApp(ctx context.Context, id string) error {
+	f.RecordedActions = append(f.RecordedActions, "restart "+id)
+	return f.Err
+}
+
+func (f *Provider) StopApp(ctx context.Context, id string) error {
+	f.RecordedActions = append(f.RecordedActions, "stop "+id)
+	return f.Err
+}
+
+func (f *Provider) StartApp(ctx context.Context, id string) error {
+	f.RecordedActions = append(f.RecordedActions, "start "+id)
+	return f.Err
+}
+
+func (f *Provider) ScaleApp(ctx context.Context, id string, instances int) error {
+	f.RecordedActions = append(f.RecordedActions, "scale "+id+" "+strconv.Itoa(instances))
+	return f.Err
+}
+
+func (f *Provider) RestartAppInstance(ctx context.Context, id string, index int) error {
+	f.RecordedActions = append(f.RecordedActions, "restart "+id+" "+strconv.Itoa(index))
+	return f.Err
+}
+
+func (f *Provider) TriggerPipeline(ctx context.Context, id string) error {
+	f.RecordedActions = append(f.RecordedActions, "trigger "+id)
+	return f.Err
+}
+
+func (f *Provider) RerunStep(ctx context.Context, id string, stepId int) error {
+	f.RecordedActions = append(f.RecordedActions, "rerun "+id+" "+strconv.Itoa(stepId))
+	return f.Err
+}
+
+func (f *Provider) ApproveStep(ctx context.Context, id string, stepId int) error {
+	f.RecordedActions = append(f.RecordedActions, "approve "+id+" "+strconv.Itoa(stepId))
+	return f.Err
+}
+
+func (f *Provider) AbortRun(ctx context.Context, id string) error {
+	f.RecordedActions = append(f.RecordedActions, "abort "+id)
+	return f.Err
+}
+
+func (f *Provider) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
 	return f.Routes[id], f.Err
 }
 
-func (f *Provider) ListUpdates(since time.Time) (sdk.PipelineUpdates, error) {
+func (f *Provider) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
 	f.RecordedTime = since
+	if f.Err != nil {
+		return sdk.PipelineUpdates{}, f.Err
+	}
 	return f.Updates, nil
 }
 
-func (f Provider) ListPipelines() ([]sdk.Pipeline, error) {
+func (f Provider) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
 	return f.Pipelines, nil
 }
 
-func (f Provider) GetPipeline(id string) (sdk.Pipeline, error) {
+func (f Provider) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
 	panic("implement me")
 }
 
-func (f Provider) GetHistory(id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (f Provider) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
 	panic("implement me")
 }
 
-func (f Provider) ListApps() ([]sdk.App, error) {
+func (f Provider) ListApps(ctx context.Context) ([]sdk.App, error) {
 	return f.Apps, f.Err
 }
 
-func (f Provider) GetApp(id string) (sdk.App, error) {
+func (f Provider) GetApp(ctx context.Context, id string) (sdk.App, error) {
 	panic("implement me")
 }
+
+// UpdatesProvider is an app provider that serves pages and updates of its apps.
+type UpdatesProvider struct {
+	Provider
+	AppUpdates sdk.AppUpdates
+}
+
+func (f *UpdatesProvider) ListAppsPage(ctx context.Context, perPage int, page int) (sdk.AppPage, error) {
+	if f.Err != nil {
+		return sdk.AppPage{}, f.Err
+	}
+	return sdk.PageApps(f.Apps, perPage, page)
+}
+
+func (f *UpdatesProvider) ListAppUpdates(ctx context.Context, since time.Time) (sdk.AppUpdates, error) {
+	f.RecordedTime = since
+	return f.AppUpdates, f.Err
+}
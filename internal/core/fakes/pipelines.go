@@ -8,7 +8,7 @@ import (
 
 type RecordingPipelinesService struct {
 	Err       error
-	Pipeline  sdk.Pipeline
+	Pipeline  pipelines.Pipeline
 	Page      sdk.PipelinePage
 	Pipelines []sdk.Pipeline
 	Runs      sdk.PipelineStatusList
@@ -23,6 +23,7 @@ type PipelinesRecorder struct {
 	FromIncl   time.Time
 	ToExcl     time.Time
 	Limit      int
+	Filter     pipelines.RunFilter
 	ProviderId string
 	Pipelines  []sdk.Pipeline
 	Runs       sdk.PipelineStatusList
@@ -43,18 +44,19 @@ func (s *RecordingPipelinesService) ListPipelinesPaginated(perPage int, page int
 	return s.Page, nil
 }
 
-func (s *RecordingPipelinesService) GetPipeline(id string) (sdk.Pipeline, error) {
+func (s *RecordingPipelinesService) GetPipeline(id string) (pipelines.Pipeline, error) {
 	s.Record.PipelineId = id
 	if s.Err != nil {
-		return sdk.Pipeline{}, s.Err
+		return pipelines.Pipeline{}, s.Err
 	}
 	return s.Pipeline, nil
 }
 
-func (s *RecordingPipelinesService) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time) (sdk.PipelineStatusList, error) {
+func (s *RecordingPipelinesService) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time, f pipelines.RunFilter) (sdk.PipelineStatusList, error) {
 	s.Record.PipelineId = id
 	s.Record.FromIncl = fromIncl
 	s.Record.ToExcl = toExcl
+	s.Record.Filter = f
 
 	if s.Err != nil {
 		return nil, s.Err
@@ -62,10 +64,11 @@ func (s *RecordingPipelinesService) ListPipelineRuns(id string, fromIncl time.Ti
 	return s.Runs, nil
 }
 
-func (s *RecordingPipelinesService) ListPipelineRunsLimit(id string, toExcl time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (s *RecordingPipelinesService) ListPipelineRunsLimit(id string, toExcl time.Time, limit int, f pipelines.RunFilter) (sdk.PipelineStatusList, error) {
 	s.Record.PipelineId = id
 	s.Record.ToExcl = toExcl
 	s.Record.Limit = limit
+	s.Record.Filter = f
 
 	if s.Err != nil {
 		return nil, s.Err
@@ -114,6 +117,16 @@ func (s *RecordingPipelinesService) AddPipelineVersions(providerId string, versi
 	return nil
 }
 
+func (s *RecordingPipelinesService) UpsertPipelines(providerId string, pipelines []sdk.Pipeline) error {
+	s.Record.ProviderId = providerId
+	s.Record.Pipelines = pipelines
+
+	if s.Err != nil {
+		return s.Err
+	}
+	return nil
+}
+
 func NewPipelineMapping(p []pipelines.Pipeline, v []sdk.PipelineVersion, r []sdk.PipelineStatus) *MappingPipelinesService {
 	m := &MappingPipelinesService{
 		Pipelines: make(map[string]pipelines.Pipeline),
@@ -148,25 +161,25 @@ func (m *MappingPipelinesService) ListPipelinesPaginated(perPage int, page int)
 	panic("implement me")
 }
 
-func (m *MappingPipelinesService) GetPipeline(id string) (sdk.Pipeline, error) {
-	return m.Pipelines[id].Pipeline, nil
+func (m *MappingPipelinesService) GetPipeline(id string) (pipelines.Pipeline, error) {
+	return m.Pipelines[id], nil
 }
 
-func (m *MappingPipelinesService) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time) (sdk.PipelineStatusList, error) {
+func (m *MappingPipelinesService) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time, f pipelines.RunFilter) (sdk.PipelineStatusList, error) {
 	var res sdk.PipelineStatusList
 	for _, status := range m.Runs[id] {
-		if status.Started.After(fromIncl.Add(-1*time.Second)) && status.Started.Before(toExcl) {
+		if status.Started.After(fromIncl.Add(-1*time.Second)) && status.Started.Before(toExcl) && f.Matches(status) {
 			res = append(res, status)
 		}
 	}
 	return res, nil
 }
 
-func (m *MappingPipelinesService) ListPipelineRunsLimit(id string, toExcl time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (m *MappingPipelinesService) ListPipelineRunsLimit(id string, toExcl time.Time, limit int, f pipelines.RunFilter) (sdk.PipelineStatusList, error) {
 	var res sdk.PipelineStatusList
 	p := m.Runs[id]
 	for i := len(p) - 1; i >= 0; i-- {
-		if p[i].Started.Before(toExcl) {
+		if p[i].Started.Before(toExcl) && f.Matches(p[i]) {
 			res = append(res, p[i])
 		}
 
@@ -219,3 +232,13 @@ func (m *MappingPipelinesService) AddPipelineVersions(providerId string, version
 	}
 	return nil
 }
+
+func (m *MappingPipelinesService) UpsertPipelines(providerId string, pipelineList []sdk.Pipeline) error {
+	for _, pipeline := range pipelineList {
+		m.Pipelines[pipeline.Id] = pipelines.Pipeline{
+			Pipeline:   pipeline,
+			ProviderId: providerId,
+		}
+	}
+	return nil
+}
@@ -2,18 +2,37 @@ package fakes
 
 import (
 	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/internal/queue"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"sort"
 	"time"
 )
 
 type ProviderService struct {
-	Job                *recon.Job
-	AppProviders       map[string]sdk.AppProvider
-	PipelineProviders  map[string]sdk.PipelineProvider
-	RoutingProviders   map[string]sdk.RoutingProvider
-	InstancesProviders map[string]sdk.InstancesProvider
-	GroupProviders     map[string]sdk.GroupProvider
+	Job                 *recon.Job
+	AppProviders        map[string]sdk.AppProviderContext
+	PipelineProviders   map[string]sdk.PipelineProviderContext
+	RoutingProviders    map[string]sdk.RoutingProviderContext
+	InstancesProviders  map[string]sdk.InstancesProviderContext
+	LogsProviders       map[string]sdk.LogsProvider
+	MetricsProviders    map[string]sdk.MetricsProvider
+	AlertsProviders     map[string]sdk.AlertsProvider
+	StepLogsProviders   map[string]sdk.StepLogsProvider
+	TopologyProviders   map[string]sdk.TopologyProvider
+	AppActionsProviders map[string]sdk.AppActionsProvider
+	GroupProviders      map[string]sdk.GroupProviderContext
+	Definitions         map[string]provider.Definition
+	Health              []provider.Health
+	HealthErr           error
+	Syncs               []string
+	Attempts            []string
+	DeadLetters         []recon.Job
+	Requeued            []string
+	Released            []string
+	ReconcileRequests   []string
+	AppUpdateRequests   []string
+	RequestQueue        queue.Metrics
 }
 
 func (s *ProviderService) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool) {
@@ -23,12 +42,51 @@ func (s *ProviderService) AcceptReconcileJob(olderThan time.Duration) (recon.Job
 	return *s.Job, true
 }
 
-func (s *ProviderService) AddAppProvider(id string, name string, p sdk.AppProvider) error {
+func (s *ProviderService) RetryReconcileJob(j recon.Job, at time.Time, cause error) error {
+	s.Attempts = append(s.Attempts, "retry "+string(j.Type)+"/"+j.Guid+": "+cause.Error())
+	return nil
+}
+
+func (s *ProviderService) DeadLetterReconcileJob(j recon.Job, cause error) error {
+	s.Attempts = append(s.Attempts, "dead-letter "+string(j.Type)+"/"+j.Guid+": "+cause.Error())
+	return nil
+}
+
+func (s *ProviderService) SucceedReconcileJob(j recon.Job) error {
+	s.Attempts = append(s.Attempts, "succeed "+string(j.Type)+"/"+j.Guid)
+	return nil
+}
+
+func (s *ProviderService) ReleaseReconcileJob(j recon.Job) error {
+	s.Released = append(s.Released, string(j.Type)+"/"+j.Guid)
+	return nil
+}
+
+func (s *ProviderService) EnsureIndices() error {
+	return nil
+}
+
+func (s *ProviderService) ListDeadLetters() ([]recon.Job, error) {
+	return s.DeadLetters, nil
+}
+
+func (s *ProviderService) RequeueDeadLetter(t recon.Type, guid string) error {
+	for i, j := range s.DeadLetters {
+		if j.Type == t && j.Guid == guid {
+			s.DeadLetters = append(s.DeadLetters[:i], s.DeadLetters[i+1:]...)
+			s.Requeued = append(s.Requeued, string(t)+"/"+guid)
+			return nil
+		}
+	}
+	return recon.ErrJobNotFound
+}
+
+func (s *ProviderService) AddAppProvider(id string, name string, p sdk.AppProviderContext) error {
 	s.AppProviders[id] = p
 	return nil
 }
 
-func (s *ProviderService) GetAppProvider(id string) (sdk.AppProvider, error) {
+func (s *ProviderService) GetAppProvider(id string) (sdk.AppProviderContext, error) {
 	if s.AppProviders[id] == nil {
 		return nil, provider.ErrNotFound
 	}
@@ -41,17 +99,18 @@ func (s *ProviderService) DeleteAppProvider(id string) error {
 	return nil
 }
 
-func (s *ProviderService) RequestAppUpdate(id string) error {
-	panic("implement me")
+func (s *ProviderService) RequestAppUpdate(id string, p queue.Priority) error {
+	s.AppUpdateRequests = append(s.AppUpdateRequests, id)
+	return nil
 }
 
-func (s *ProviderService) AddRoutingProvider(id string, name string, p sdk.RoutingProvider) error {
+func (s *ProviderService) AddRoutingProvider(id string, name string, p sdk.RoutingProviderContext) error {
 	s.RoutingProviders[id] = p
 	return nil
 }
 
-func (s *ProviderService) GetRoutingProviders() ([]sdk.RoutingProvider, error) {
-	var res []sdk.RoutingProvider
+func (s *ProviderService) GetRoutingProviders() ([]sdk.RoutingProviderContext, error) {
+	var res []sdk.RoutingProviderContext
 	for _, routingProvider := range s.RoutingProviders {
 		res = append(res, routingProvider)
 	}
@@ -63,13 +122,13 @@ func (s *ProviderService) DeleteRoutingProvider(id string) error {
 	return nil
 }
 
-func (s *ProviderService) AddInstancesProvider(id string, name string, p sdk.InstancesProvider) error {
+func (s *ProviderService) AddInstancesProvider(id string, name string, p sdk.InstancesProviderContext) error {
 	s.InstancesProviders[id] = p
 	return nil
 }
 
-func (s *ProviderService) GetInstancesProviders() ([]sdk.InstancesProvider, error) {
-	var res []sdk.InstancesProvider
+func (s *ProviderService) GetInstancesProviders() ([]sdk.InstancesProviderContext, error) {
+	var res []sdk.InstancesProviderContext
 	for _, instancesProvider := range s.InstancesProviders {
 		res = append(res, instancesProvider)
 	}
@@ -80,12 +139,116 @@ func (s *ProviderService) DeleteInstancesProvider(id string) error {
 	panic("implement me")
 }
 
-func (s *ProviderService) AddPipelineProvider(id string, name string, p sdk.PipelineProvider) error {
+func (s *ProviderService) AddLogsProvider(id string, name string, p sdk.LogsProvider) error {
+	s.LogsProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetLogsProviders() ([]sdk.LogsProvider, error) {
+	var res []sdk.LogsProvider
+	for _, logsProvider := range s.LogsProviders {
+		res = append(res, logsProvider)
+	}
+	return res, nil
+}
+
+func (s *ProviderService) DeleteLogsProvider(id string) error {
+	delete(s.LogsProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddMetricsProvider(id string, name string, p sdk.MetricsProvider) error {
+	s.MetricsProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetMetricsProviders() ([]sdk.MetricsProvider, error) {
+	var res []sdk.MetricsProvider
+	for _, metricsProvider := range s.MetricsProviders {
+		res = append(res, metricsProvider)
+	}
+	return res, nil
+}
+
+func (s *ProviderService) DeleteMetricsProvider(id string) error {
+	delete(s.MetricsProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddAlertsProvider(id string, name string, p sdk.AlertsProvider) error {
+	s.AlertsProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetAlertsProvider(id string) (sdk.AlertsProvider, error) {
+	if s.AlertsProviders[id] == nil {
+		return nil, provider.ErrNotFound
+	}
+	return s.AlertsProviders[id], nil
+}
+
+func (s *ProviderService) DeleteAlertsProvider(id string) error {
+	delete(s.AlertsProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddTopologyProvider(id string, name string, p sdk.TopologyProvider) error {
+	s.TopologyProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetTopologyProvider(id string) (sdk.TopologyProvider, error) {
+	if s.TopologyProviders[id] == nil {
+		return nil, provider.ErrNotFound
+	}
+	return s.TopologyProviders[id], nil
+}
+
+func (s *ProviderService) DeleteTopologyProvider(id string) error {
+	delete(s.TopologyProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddStepLogsProvider(id string, name string, p sdk.StepLogsProvider) error {
+	s.StepLogsProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetStepLogsProvider(id string) (sdk.StepLogsProvider, error) {
+	if s.StepLogsProviders[id] == nil {
+		return nil, provider.ErrNotFound
+	}
+	return s.StepLogsProviders[id], nil
+}
+
+func (s *ProviderService) DeleteStepLogsProvider(id string) error {
+	delete(s.StepLogsProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddAppActionsProvider(id string, name string, p sdk.AppActionsProvider) error {
+	s.AppActionsProviders[id] = p
+	return nil
+}
+
+func (s *ProviderService) GetAppActionsProvider(id string) (sdk.AppActionsProvider, error) {
+	if s.AppActionsProviders[id] == nil {
+		return nil, provider.ErrNotFound
+	}
+	return s.AppActionsProviders[id], nil
+}
+
+func (s *ProviderService) DeleteAppActionsProvider(id string) error {
+	delete(s.AppActionsProviders, id)
+	return nil
+}
+
+func (s *ProviderService) AddPipelineProvider(id string, name string, p sdk.PipelineProviderContext) error {
 	s.PipelineProviders[id] = p
 	return nil
 }
 
-func (s *ProviderService) GetPipelineProvider(id string) (sdk.PipelineProvider, error) {
+func (s *ProviderService) GetPipelineProvider(id string) (sdk.PipelineProviderContext, error) {
 	if s.PipelineProviders[id] == nil {
 		return nil, provider.ErrNotFound
 	}
@@ -108,12 +271,12 @@ func (s *ProviderService) ListGroupProviders() ([]provider.Data, error) {
 	return res, nil
 }
 
-func (s *ProviderService) AddGroupProvider(id string, name string, p sdk.GroupProvider) error {
+func (s *ProviderService) AddGroupProvider(id string, name string, p sdk.GroupProviderContext) error {
 	s.GroupProviders[id] = p
 	return nil
 }
 
-func (s *ProviderService) GetGroupProvider(id string) (sdk.GroupProvider, error) {
+func (s *ProviderService) GetGroupProvider(id string) (sdk.GroupProviderContext, error) {
 	if s.GroupProviders[id] == nil {
 		return nil, provider.ErrNotFound
 	}
@@ -124,3 +287,133 @@ func (s *ProviderService) DeleteGroupProvider(id string) error {
 	delete(s.PipelineProviders, id)
 	return nil
 }
+
+func (s *ProviderService) Features(id string) []provider.Type {
+	var res []provider.Type
+	if s.AlertsProviders[id] != nil {
+		res = append(res, provider.TypeAlerts)
+	}
+	if s.AppActionsProviders[id] != nil {
+		res = append(res, provider.TypeAppActions)
+	}
+	if s.AppProviders[id] != nil {
+		res = append(res, provider.TypeApps)
+	}
+	if s.GroupProviders[id] != nil {
+		res = append(res, provider.TypeGroups)
+	}
+	if s.InstancesProviders[id] != nil {
+		res = append(res, provider.TypeInstances)
+	}
+	if s.LogsProviders[id] != nil {
+		res = append(res, provider.TypeLogs)
+	}
+	if s.MetricsProviders[id] != nil {
+		res = append(res, provider.TypeMetrics)
+	}
+	if s.PipelineProviders[id] != nil {
+		res = append(res, provider.TypePipelines)
+	}
+	if s.RoutingProviders[id] != nil {
+		res = append(res, provider.TypeRouting)
+	}
+	if s.StepLogsProviders[id] != nil {
+		res = append(res, provider.TypeStepLogs)
+	}
+	if s.TopologyProviders[id] != nil {
+		res = append(res, provider.TypeTopology)
+	}
+	return res
+}
+
+func (s *ProviderService) Forget(id string, providerType provider.Type) {
+	switch providerType {
+	case provider.TypeAlerts:
+		delete(s.AlertsProviders, id)
+	case provider.TypeAppActions:
+		delete(s.AppActionsProviders, id)
+	case provider.TypeApps:
+		delete(s.AppProviders, id)
+	case provider.TypeGroups:
+		delete(s.GroupProviders, id)
+	case provider.TypeInstances:
+		delete(s.InstancesProviders, id)
+	case provider.TypeLogs:
+		delete(s.LogsProviders, id)
+	case provider.TypeMetrics:
+		delete(s.MetricsProviders, id)
+	case provider.TypePipelines:
+		delete(s.PipelineProviders, id)
+	case provider.TypeRouting:
+		delete(s.RoutingProviders, id)
+	case provider.TypeStepLogs:
+		delete(s.StepLogsProviders, id)
+	case provider.TypeTopology:
+		delete(s.TopologyProviders, id)
+	}
+}
+
+func (s *ProviderService) RequestReconcile(id string, providerType provider.Type, p queue.Priority) error {
+	for _, t := range s.Features(id) {
+		if t == providerType {
+			s.ReconcileRequests = append(s.ReconcileRequests, string(providerType)+"/"+id)
+			return nil
+		}
+	}
+	return provider.ErrNotFound
+}
+
+func (s *ProviderService) RequestMetrics() (queue.Metrics, error) {
+	return s.RequestQueue, nil
+}
+
+func (s *ProviderService) RecordSync(id string, providerType provider.Type, latency time.Duration, err error) error {
+	if err != nil {
+		s.Syncs = append(s.Syncs, string(providerType)+"/"+id+": "+err.Error())
+	} else {
+		s.Syncs = append(s.Syncs, string(providerType)+"/"+id+": ok")
+	}
+	return nil
+}
+
+func (s *ProviderService) ListHealth() ([]provider.Health, error) {
+	if s.HealthErr != nil {
+		return nil, s.HealthErr
+	}
+	return s.Health, nil
+}
+
+func (s *ProviderService) ListDefinitions() ([]provider.Definition, error) {
+	res := []provider.Definition{}
+	for _, d := range s.Definitions {
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (s *ProviderService) GetDefinition(id string) (provider.Definition, error) {
+	d, ok := s.Definitions[id]
+	if !ok {
+		return provider.Definition{}, provider.ErrNotFound
+	}
+	return d, nil
+}
+
+func (s *ProviderService) SaveDefinition(d provider.Definition) error {
+	if s.Definitions == nil {
+		s.Definitions = make(map[string]provider.Definition)
+	}
+	s.Definitions[d.Id] = d
+	return nil
+}
+
+func (s *ProviderService) DeleteDefinition(id string) error {
+	if _, ok := s.Definitions[id]; !ok {
+		return provider.ErrNotFound
+	}
+	delete(s.Definitions, id)
+	return nil
+}
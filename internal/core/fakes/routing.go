@@ -1,11 +1,15 @@
 package fakes
 
 import (
+	"github.com/joscha-alisch/dyve/internal/core/routing"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"sort"
+	"time"
 )
 
 type MappingRoutesService struct {
 	Routes map[string]sdk.AppRouting
+	Err    error
 }
 
 func (m *MappingRoutesService) GetRoutes(app string) (sdk.AppRouting, error) {
@@ -16,3 +20,21 @@ func (m *MappingRoutesService) UpdateRoutes(app string, routes sdk.AppRouting) e
 	m.Routes[app] = routes
 	return nil
 }
+
+func (m *MappingRoutesService) ListExpiringRoutes(before time.Time) ([]routing.ExpiringRoute, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var res []routing.ExpiringRoute
+	for app, r := range m.Routes {
+		for _, route := range r.Routes {
+			if route.CertificateExpiresBefore(before) {
+				res = append(res, routing.ExpiringRoute{AppId: app, Route: route})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Route.Tls.Expires.Before(*res[j].Route.Tls.Expires)
+	})
+	return res, nil
+}
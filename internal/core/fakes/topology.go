@@ -0,0 +1,65 @@
+package fakes
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/topology"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+type MappingTopologyService struct {
+	Topology topology.Topology
+	Err      error
+}
+
+func (m *MappingTopologyService) UpdateTopology(providerId string, t sdk.Topology) error {
+	var services []topology.BackingService
+	for _, s := range m.Topology.Services {
+		if s.ProviderId != providerId {
+			services = append(services, s)
+		}
+	}
+	for _, s := range t.Services {
+		services = append(services, topology.BackingService{BackingService: s, ProviderId: providerId})
+	}
+
+	var dependencies []topology.Dependency
+	for _, d := range m.Topology.Dependencies {
+		if d.ProviderId != providerId {
+			dependencies = append(dependencies, d)
+		}
+	}
+	for _, d := range t.Dependencies {
+		dependencies = append(dependencies, topology.Dependency{Dependency: d, ProviderId: providerId})
+	}
+
+	m.Topology = topology.Topology{Services: services, Dependencies: dependencies}
+	return nil
+}
+
+func (m *MappingTopologyService) GetAppDependencies(app string) (topology.Topology, error) {
+	if m.Err != nil {
+		return topology.Topology{}, m.Err
+	}
+	res := topology.Topology{Services: []topology.BackingService{}, Dependencies: []topology.Dependency{}}
+	serviceIds := make(map[string]bool)
+	for _, d := range m.Topology.Dependencies {
+		if d.AppId == app || (d.Target == sdk.DependencyTargetApp && d.TargetId == app) {
+			res.Dependencies = append(res.Dependencies, d)
+			if d.Target == sdk.DependencyTargetService {
+				serviceIds[d.TargetId] = true
+			}
+		}
+	}
+	for _, s := range m.Topology.Services {
+		if serviceIds[s.Id] {
+			res.Services = append(res.Services, s)
+		}
+	}
+	return res, nil
+}
+
+func (m *MappingTopologyService) GetTopology() (topology.Topology, error) {
+	if m.Err != nil {
+		return topology.Topology{}, m.Err
+	}
+	return m.Topology, nil
+}
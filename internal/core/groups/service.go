@@ -1,10 +1,12 @@
 package groups
 
 import (
+	"errors"
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"go.mongodb.org/mongo-driver/bson"
+	"strings"
 )
 
 const Collection = "groups"
@@ -15,6 +17,14 @@ type Service interface {
 	GetGroup(id string) (sdk.Group, error)
 	DeleteGroup(id string) error
 	UpdateGroups(guid string, groups []sdk.Group) error
+	// UpsertGroups creates or updates the given groups of the provider, leaving its other groups untouched.
+	UpsertGroups(providerId string, groups []sdk.Group) error
+	DeleteGroups(providerId string, ids []string) error
+	// ListMembers returns the members of the group including those of the groups nested in it.
+	ListMembers(id string) ([]sdk.Member, error)
+	// WithAncestors returns the given groups together with all groups they are nested in. Groups
+	// are referenced as '<provider>:<group id>', as in the access groups of teams.
+	WithAncestors(groups []string) ([]string, error)
 }
 
 func NewService(db database.Database, providers provider.Service) Service {
@@ -95,3 +105,110 @@ func (s *service) UpdateGroups(providerId string, groups []sdk.Group) error {
 	}
 	return s.db.UpdateProvided(Collection, providerId, groupMap)
 }
+
+func (s *service) UpsertGroups(providerId string, groups []sdk.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	filterMap := make(map[string]interface{}, len(groups))
+	groupMap := make(map[string]interface{}, len(groups))
+	for _, group := range groups {
+		filterMap[group.Id] = bson.M{"provider": providerId, "id": group.Id}
+		groupMap[group.Id] = group
+	}
+	return s.db.UpdateMany(Collection, filterMap, groupMap)
+}
+
+func (s *service) DeleteGroups(providerId string, ids []string) error {
+	for _, id := range ids {
+		err := s.db.DeleteOne(Collection, bson.M{"provider": providerId, "id": id})
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) ListMembers(id string) ([]sdk.Member, error) {
+	group := GroupWithProvider{}
+	err := s.db.FindOneById(Collection, id, &group)
+	if err != nil {
+		return nil, err
+	}
+
+	byProvider, err := s.listProviderGroups([]string{group.Provider})
+	if err != nil {
+		return nil, err
+	}
+
+	return byProvider[group.Provider].TransitiveMembers(id), nil
+}
+
+func (s *service) WithAncestors(groups []string) ([]string, error) {
+	var providers []string
+	seenProviders := make(map[string]bool)
+	for _, group := range groups {
+		providerId, _, ok := splitGroupRef(group)
+		if ok && !seenProviders[providerId] {
+			seenProviders[providerId] = true
+			providers = append(providers, providerId)
+		}
+	}
+	if len(providers) == 0 {
+		return groups, nil
+	}
+
+	byProvider, err := s.listProviderGroups(providers)
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]string{}, groups...)
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		seen[group] = true
+	}
+	for _, group := range groups {
+		providerId, id, ok := splitGroupRef(group)
+		if !ok {
+			continue
+		}
+		for _, ancestor := range byProvider[providerId].Ancestors(id) {
+			ref := providerId + ":" + ancestor
+			if !seen[ref] {
+				seen[ref] = true
+				res = append(res, ref)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *service) listProviderGroups(providers []string) (map[string]sdk.GroupList, error) {
+	res := make(map[string]sdk.GroupList, len(providers))
+	err := s.db.FindMany(Collection, bson.M{"provider": bson.M{"$in": providers}}, func(c database.Decodable) error {
+		group := GroupWithProvider{}
+		err := c.Decode(&group)
+		if err != nil {
+			return err
+		}
+
+		res[group.Provider] = append(res[group.Provider], group.Group)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// splitGroupRef splits a reference of the form '<provider>:<group id>'. Group ids may contain
+// colons themselves, provider ids don't.
+func splitGroupRef(ref string) (string, string, bool) {
+	i := strings.Index(ref, ":")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:i], ref[i+1:], true
+}
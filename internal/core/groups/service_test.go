@@ -268,7 +268,7 @@ func TestService_ListGroupsByProvider(t *testing.T) {
 					}))
 				},
 			},
-			providers: &fakes.ProviderService{GroupProviders: map[string]sdk.GroupProvider{
+			providers: &fakes.ProviderService{GroupProviders: map[string]sdk.GroupProviderContext{
 				"group-provider": nil,
 			}},
 			expected: GroupByProviderMap{
@@ -285,7 +285,7 @@ func TestService_ListGroupsByProvider(t *testing.T) {
 		},
 		{
 			desc: "error while listing groups",
-			providers: &fakes.ProviderService{GroupProviders: map[string]sdk.GroupProvider{
+			providers: &fakes.ProviderService{GroupProviders: map[string]sdk.GroupProviderContext{
 				"group-provider": nil,
 			}},
 			db: &db.RecordingDatabase{
@@ -316,3 +316,143 @@ func TestService_ListGroupsByProvider(t *testing.T) {
 		})
 	}
 }
+
+var nestedGroups = []GroupWithProvider{
+	{Provider: "github", Group: sdk.Group{Id: "org:1", Members: []sdk.Member{{Id: "owner"}}}},
+	{Provider: "github", Group: sdk.Group{Id: "org:2", Parent: "org:1", Members: []sdk.Member{{Id: "lead", Role: "maintainer"}}}},
+	{Provider: "github", Group: sdk.Group{Id: "org:3", Parent: "org:2", Members: []sdk.Member{{Id: "dev"}}}},
+	{Provider: "other", Group: sdk.Group{Id: "org:4", Parent: "org:1"}},
+}
+
+func returnNestedGroups(each func(decodable database.Decodable) error) {
+	for _, group := range nestedGroups {
+		group := group
+		_ = each(DecodableFunc(func(target interface{}) error {
+			*target.(*GroupWithProvider) = group
+			return nil
+		}))
+	}
+}
+
+func TestService_ListMembers(t *testing.T) {
+	tests := []struct {
+		desc        string
+		id          string
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expected    []sdk.Member
+		expectedErr error
+	}{
+		{
+			desc: "lists members of nested groups",
+			id:   "org:2",
+			db: &db.RecordingDatabase{
+				Return: func(target interface{}) {
+					*target.(*GroupWithProvider) = nestedGroups[1]
+				},
+				ReturnEach: returnNestedGroups,
+			},
+			expected: []sdk.Member{{Id: "lead", Role: "maintainer"}, {Id: "dev"}},
+			recorded: []db.DatabaseRecord{
+				{Collection: "groups", Id: "org:2"},
+				{Collection: "groups", Filter: bson.M{"provider": bson.M{"$in": []string{"github"}}}},
+			},
+		},
+		{
+			desc: "error while getting group",
+			id:   "org:2",
+			db: &db.RecordingDatabase{
+				Err: someErr,
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db, nil)
+			res, err := s.ListMembers(test.id)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("result mismatch: %s\n", cmp.Diff(test.expected, res))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
+
+func TestService_WithAncestors(t *testing.T) {
+	tests := []struct {
+		desc        string
+		groups      []string
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expected    []string
+		expectedErr error
+	}{
+		{
+			desc:   "adds ancestors of groups",
+			groups: []string{"github:org:3", "other:org:4"},
+			db:     &db.RecordingDatabase{ReturnEach: returnNestedGroups},
+			expected: []string{
+				"github:org:3", "other:org:4", "github:org:2", "github:org:1", "other:org:1",
+			},
+			recorded: []db.DatabaseRecord{
+				{Collection: "groups", Filter: bson.M{"provider": bson.M{"$in": []string{"github", "other"}}}},
+			},
+		},
+		{
+			desc:   "doesn't duplicate groups",
+			groups: []string{"github:org:2", "github:org:1"},
+			db:     &db.RecordingDatabase{ReturnEach: returnNestedGroups},
+			expected: []string{
+				"github:org:2", "github:org:1",
+			},
+			recorded: []db.DatabaseRecord{
+				{Collection: "groups", Filter: bson.M{"provider": bson.M{"$in": []string{"github"}}}},
+			},
+		},
+		{
+			desc:     "skips malformed groups",
+			groups:   []string{"malformed"},
+			db:       &db.RecordingDatabase{},
+			expected: []string{"malformed"},
+		},
+		{
+			desc:   "error while listing groups",
+			groups: []string{"github:org:3"},
+			db: &db.RecordingDatabase{
+				Err: someErr,
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db, nil)
+			res, err := s.WithAncestors(test.groups)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("result mismatch: %s\n", cmp.Diff(test.expected, res))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
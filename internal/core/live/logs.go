@@ -0,0 +1,93 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// recentLogsLimit is the number of lines sent before tailing the logs of an app.
+const recentLogsLimit = 100
+
+// recentLogsSince is how far back recent logs are requested.
+const recentLogsSince = time.Hour
+
+// LogsUpdate carries log lines of an app, oldest first.
+type LogsUpdate struct {
+	Logs sdk.AppLogs `json:"logs"`
+}
+
+// StreamLogs sends the recent logs of the app from all providers, followed by every line the
+// providers tail until the context is done. Providers that don't know the app are skipped.
+func StreamLogs(ctx context.Context, providers []sdk.LogsProvider, appId string, send func(update LogsUpdate) error) error {
+	recent := recentLogs(ctx, providers, appId)
+	err := send(LogsUpdate{Logs: recent})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan sdk.LogLine)
+	wg := &sync.WaitGroup{}
+	for _, p := range providers {
+		wg.Add(1)
+		go func(p sdk.LogsProvider) {
+			defer wg.Done()
+			err := p.TailLogs(ctx, appId, lines)
+			if err != nil && !errors.Is(err, sdk.ErrNotFound) && ctx.Err() == nil {
+				log.Error().Err(err).Str("app", appId).Msg("error tailing logs")
+			}
+		}(p)
+	}
+
+	tailed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(tailed)
+	}()
+
+	for {
+		select {
+		case line := <-lines:
+			err := send(LogsUpdate{Logs: sdk.AppLogs{line}})
+			if err != nil {
+				cancel()
+				<-tailed
+				return err
+			}
+		case <-tailed:
+			return nil
+		}
+	}
+}
+
+func recentLogs(ctx context.Context, providers []sdk.LogsProvider, appId string) sdk.AppLogs {
+	since := time.Now().Add(-recentLogsSince)
+
+	res := sdk.AppLogs{}
+	for _, p := range providers {
+		logs, err := p.GetRecentLogs(ctx, appId, since, recentLogsLimit)
+		if errors.Is(err, sdk.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Str("app", appId).Msg("error getting recent logs")
+			continue
+		}
+		res = append(res, logs...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+	if len(res) > recentLogsLimit {
+		res = res[len(res)-recentLogsLimit:]
+	}
+	return res
+}
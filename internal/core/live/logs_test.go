@@ -0,0 +1,72 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"testing"
+	"time"
+)
+
+var someTime = time.Now().Add(-time.Minute).UTC()
+
+func TestStreamLogs(t *testing.T) {
+	a := fakeProvider.LogsProvider(map[string]sdk.AppLogs{
+		"app": {
+			{Time: someTime, Message: "a-1"},
+			{Time: someTime.Add(2 * time.Second), Message: "a-2"},
+		},
+	}, map[string]sdk.AppLogs{
+		"app": {{Time: someTime.Add(time.Minute), Message: "a-tail"}},
+	})
+	b := fakeProvider.LogsProvider(map[string]sdk.AppLogs{
+		"app": {{Time: someTime.Add(time.Second), Message: "b-1"}},
+	}, nil)
+	unknown := fakeProvider.NewErrProvider(sdk.ErrNotFound)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var updates []LogsUpdate
+	err := StreamLogs(ctx, []sdk.LogsProvider{a, b, unknown}, "app", func(update LogsUpdate) error {
+		updates = append(updates, update)
+		if len(updates) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []LogsUpdate{
+		{Logs: sdk.AppLogs{
+			{Time: someTime, Message: "a-1"},
+			{Time: someTime.Add(time.Second), Message: "b-1"},
+			{Time: someTime.Add(2 * time.Second), Message: "a-2"},
+		}},
+		{Logs: sdk.AppLogs{{Time: someTime.Add(time.Minute), Message: "a-tail"}}},
+	}
+	if !cmp.Equal(expected, updates) {
+		t.Errorf("\ndiff between updates: \n%s\n", cmp.Diff(expected, updates))
+	}
+}
+
+func TestStreamLogsSendError(t *testing.T) {
+	p := fakeProvider.LogsProvider(nil, map[string]sdk.AppLogs{
+		"app": {{Time: someTime, Message: "tail"}},
+	})
+	someErr := errors.New("closed")
+
+	sent := 0
+	err := StreamLogs(context.Background(), []sdk.LogsProvider{p}, "app", func(update LogsUpdate) error {
+		sent++
+		if sent == 2 {
+			return someErr
+		}
+		return nil
+	})
+	if !errors.Is(err, someErr) {
+		t.Errorf("\nwanted err: %v\ngot: %v", someErr, err)
+	}
+}
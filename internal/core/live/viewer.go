@@ -1,6 +1,8 @@
 package live
 
 import (
+	"context"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/service"
 	"github.com/joscha-alisch/dyve/internal/core/ws"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
@@ -42,26 +44,53 @@ func (v *AppViewer) AddWs(id string, c *ws.Connection) chan error {
 
 	errChan := make(chan error)
 	c.On("update", func() {
-		err := v.core.Providers.RequestAppUpdate(id)
+		err := v.core.Providers.RequestAppUpdate(id, provider.PriorityUser)
 		if err != nil {
 			errChan <- err
 		}
 	})
 
-	go v.wsWorker(wsId, id, c, errChan)
+	// logs are streamed until the websocket closes, no matter how often they are requested
+	ctx, cancel := context.WithCancel(context.Background())
+	logs := &sync.Once{}
+	c.On("logs", func() {
+		logs.Do(func() {
+			go v.logsWorker(ctx, wsId, id, c)
+		})
+	})
+
+	go v.wsWorker(wsId, id, c, errChan, cancel)
 
 	return errChan
 }
 
-func (v *AppViewer) wsWorker(wsId int, appId string, c *ws.Connection, errChan chan error) {
+func (v *AppViewer) wsWorker(wsId int, appId string, c *ws.Connection, errChan chan error, cancel context.CancelFunc) {
 	err := c.Run()
+	cancel()
 	if err != nil {
 		errChan <- err
 	}
+	v.mu.Lock()
 	delete(v.appSubs[appId], wsId)
+	v.mu.Unlock()
 	close(errChan)
 }
 
+func (v *AppViewer) logsWorker(ctx context.Context, wsId int, appId string, c *ws.Connection) {
+	providers, err := v.core.Providers.GetLogsProviders()
+	if err != nil {
+		log.Error().Err(err).Str("app", appId).Msg("no logs providers to stream logs from")
+		return
+	}
+
+	err = StreamLogs(ctx, providers, appId, func(update LogsUpdate) error {
+		return c.Send(update)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("app", appId).Int("ws", wsId).Msg("error streaming logs to websocket")
+	}
+}
+
 func (v *AppViewer) updateWorker() {
 	t := time.NewTicker(2 * time.Second)
 
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/internal/core/provider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"sync"
+)
+
+type Service interface {
+	// GetAppMetrics collects the series of the app from all metrics providers. Providers that
+	// fail are left out, unless all of them fail.
+	GetAppMetrics(ctx context.Context, app string, q sdk.MetricsQuery) (sdk.AppMetrics, error)
+}
+
+func NewService(providers provider.Service) Service {
+	return &service{
+		providers: providers,
+	}
+}
+
+type service struct {
+	providers provider.Service
+}
+
+type providerResult struct {
+	metrics sdk.AppMetrics
+	err     error
+}
+
+func (s *service) GetAppMetrics(ctx context.Context, app string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	providers, err := s.providers.GetMetricsProviders()
+	if errors.Is(err, provider.ErrNotFound) {
+		return sdk.AppMetrics{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]providerResult, len(providers))
+	wg := &sync.WaitGroup{}
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, p sdk.MetricsProvider) {
+			defer wg.Done()
+			metrics, err := p.GetAppMetrics(ctx, app, q)
+			results[i] = providerResult{metrics: metrics, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+
+	res := sdk.AppMetrics{}
+	var lastErr error
+	failed := 0
+	for _, r := range results {
+		if errors.Is(r.err, sdk.ErrNotFound) {
+			continue
+		}
+		if r.err != nil {
+			log.Error().Err(r.err).Str("app", app).Msg("error getting app metrics")
+			lastErr = r.err
+			failed++
+			continue
+		}
+		res = append(res, r.metrics...)
+	}
+
+	if failed > 0 && failed == len(providers) {
+		return nil, lastErr
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Metric < res[j].Metric
+	})
+	return res, nil
+}
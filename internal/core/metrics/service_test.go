@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/fakes"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+var someErr = errors.New("some error")
+
+var cpu = sdk.MetricSeries{Metric: sdk.MetricCpu, Unit: sdk.MetricUnitPercent, Points: []sdk.MetricPoint{{Time: someTime, Value: 50}}}
+var memory = sdk.MetricSeries{Metric: sdk.MetricMemory, Unit: sdk.MetricUnitBytes, Points: []sdk.MetricPoint{{Time: someTime, Value: 1024}}}
+
+func TestGetAppMetrics(t *testing.T) {
+	tests := []struct {
+		desc        string
+		providers   map[string]sdk.MetricsProvider
+		expected    sdk.AppMetrics
+		expectedErr error
+	}{
+		{desc: "merges metrics of all providers", providers: map[string]sdk.MetricsProvider{
+			"a": fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app": {memory}}),
+			"b": fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app": {cpu}}),
+		}, expected: sdk.AppMetrics{cpu, memory}},
+		{desc: "returns no metrics without providers", providers: map[string]sdk.MetricsProvider{}, expected: sdk.AppMetrics{}},
+		{desc: "skips providers not knowing the app", providers: map[string]sdk.MetricsProvider{
+			"a": fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app": {cpu}}),
+			"b": fakeProvider.NewErrProvider(sdk.ErrNotFound),
+		}, expected: sdk.AppMetrics{cpu}},
+		{desc: "skips failing providers", providers: map[string]sdk.MetricsProvider{
+			"a": fakeProvider.MetricsProvider(map[string]sdk.AppMetrics{"app": {cpu}}),
+			"b": fakeProvider.NewErrProvider(someErr),
+		}, expected: sdk.AppMetrics{cpu}},
+		{desc: "returns error if all providers fail", providers: map[string]sdk.MetricsProvider{
+			"a": fakeProvider.NewErrProvider(someErr),
+		}, expectedErr: someErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := NewService(&fakes.ProviderService{MetricsProviders: test.providers})
+			q := sdk.DefaultMetricsQuery(someTime)
+
+			metrics, err := s.GetAppMetrics(context.Background(), "app", q)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, metrics) {
+				tt.Errorf("\ndiff between metrics: \n%s\n", cmp.Diff(test.expected, metrics))
+			}
+
+			for _, p := range test.providers {
+				if recorded := p.(*fakeProvider.Provider).RecordedQuery; !cmp.Equal(q, recorded) {
+					tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(q, recorded))
+				}
+			}
+		})
+	}
+}
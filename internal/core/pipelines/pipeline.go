@@ -1,8 +1,52 @@
 package pipelines
 
-import "github.com/joscha-alisch/dyve/pkg/provider/sdk"
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+)
 
 type Pipeline struct {
 	sdk.Pipeline `json:",inline" bson:",inline"`
-	ProviderId   string `json:"providerId" bson:"providerId"`
+	ProviderId   string `json:"providerId" bson:"provider"`
+}
+
+// RunFilter narrows down the listed runs of a pipeline by what triggered them.
+// Empty fields match every run.
+type RunFilter struct {
+	Type   sdk.TriggerType
+	Commit string
+	Branch string
+	Author string
+}
+
+// Matches reports whether the run passes the filter.
+func (f RunFilter) Matches(run sdk.PipelineStatus) bool {
+	if f == (RunFilter{}) {
+		return true
+	}
+	if run.Trigger == nil {
+		return false
+	}
+
+	t := run.Trigger
+	return matches(string(f.Type), string(t.Type)) && matches(f.Commit, t.Commit) &&
+		matches(f.Branch, t.Branch) && matches(f.Author, t.Author)
+}
+
+func (f RunFilter) apply(filter bson.M) {
+	fields := map[string]string{
+		"trigger.type":   string(f.Type),
+		"trigger.commit": f.Commit,
+		"trigger.branch": f.Branch,
+		"trigger.author": f.Author,
+	}
+	for key, value := range fields {
+		if value != "" {
+			filter[key] = bson.M{"$eq": value}
+		}
+	}
+}
+
+func matches(want string, is string) bool {
+	return want == "" || want == is
 }
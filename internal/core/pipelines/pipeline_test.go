@@ -0,0 +1,38 @@
+package pipelines
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"testing"
+)
+
+func TestRunFilterMatches(t *testing.T) {
+	run := sdk.PipelineStatus{PipelineId: "pipeline-a", Trigger: &sdk.RunTrigger{
+		Type:   sdk.TriggerWebhook,
+		Commit: "abc123",
+		Branch: "main",
+		Author: "jane",
+	}}
+
+	tests := []struct {
+		desc     string
+		filter   RunFilter
+		run      sdk.PipelineStatus
+		expected bool
+	}{
+		{"empty filter matches", RunFilter{}, run, true},
+		{"empty filter matches run without trigger", RunFilter{}, sdk.PipelineStatus{}, true},
+		{"matches all fields", RunFilter{Type: sdk.TriggerWebhook, Commit: "abc123", Branch: "main", Author: "jane"}, run, true},
+		{"matches some fields", RunFilter{Branch: "main"}, run, true},
+		{"doesn't match other branch", RunFilter{Branch: "develop"}, run, false},
+		{"doesn't match other author", RunFilter{Branch: "main", Author: "john"}, run, false},
+		{"doesn't match run without trigger", RunFilter{Branch: "main"}, sdk.PipelineStatus{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			if test.filter.Matches(test.run) != test.expected {
+				tt.Errorf("expected match to be %v", test.expected)
+			}
+		})
+	}
+}
@@ -18,11 +18,13 @@ type Service interface {
 	EnsureIndices() error
 
 	ListPipelinesPaginated(perPage int, page int) (sdk.PipelinePage, error)
-	GetPipeline(id string) (sdk.Pipeline, error)
-	ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time) (sdk.PipelineStatusList, error)
-	ListPipelineRunsLimit(id string, toExcl time.Time, limit int) (sdk.PipelineStatusList, error)
+	GetPipeline(id string) (Pipeline, error)
+	ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time, f RunFilter) (sdk.PipelineStatusList, error)
+	ListPipelineRunsLimit(id string, toExcl time.Time, limit int, f RunFilter) (sdk.PipelineStatusList, error)
 	ListPipelineVersions(id string, fromIncl time.Time, toExcl time.Time) (sdk.PipelineVersionList, error)
 	UpdatePipelines(providerId string, pipelines []sdk.Pipeline) error
+	// UpsertPipelines creates or updates the given pipelines of the provider, leaving its other pipelines untouched.
+	UpsertPipelines(providerId string, pipelines []sdk.Pipeline) error
 	AddPipelineRuns(providerId string, runs sdk.PipelineStatusList) error
 	AddPipelineVersions(providerId string, versions sdk.PipelineVersionList) error
 }
@@ -64,7 +66,7 @@ func (s *service) AddPipelineVersions(providerId string, versions sdk.PipelineVe
 	return s.db.UpdateMany(CollectionVersions, filterMap, idMap)
 }
 
-func (s *service) ListPipelineRunsLimit(id string, toExcl time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (s *service) ListPipelineRunsLimit(id string, toExcl time.Time, limit int, f RunFilter) (sdk.PipelineStatusList, error) {
 	var runs sdk.PipelineStatusList
 
 	filter := bson.M{
@@ -75,6 +77,7 @@ func (s *service) ListPipelineRunsLimit(id string, toExcl time.Time, limit int)
 			"$lt": toExcl,
 		},
 	}
+	f.apply(filter)
 	each := func(c database.Decodable) error {
 		run := sdk.PipelineStatus{}
 		err := c.Decode(&run)
@@ -157,7 +160,7 @@ func (s *service) AddPipelineRuns(providerId string, runs sdk.PipelineStatusList
 	return s.db.UpdateMany(CollectionRuns, filterMap, idMap)
 }
 
-func (s *service) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time) (sdk.PipelineStatusList, error) {
+func (s *service) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Time, f RunFilter) (sdk.PipelineStatusList, error) {
 	var runs sdk.PipelineStatusList
 
 	filter := bson.M{
@@ -169,6 +172,7 @@ func (s *service) ListPipelineRuns(id string, fromIncl time.Time, toExcl time.Ti
 			"$gte": fromIncl,
 		},
 	}
+	f.apply(filter)
 	each := func(c database.Decodable) error {
 		run := sdk.PipelineStatus{}
 		err := c.Decode(&run)
@@ -203,8 +207,8 @@ func (s *service) ListPipelinesPaginated(perPage int, page int) (sdk.PipelinePag
 	return res, err
 }
 
-func (s *service) GetPipeline(id string) (sdk.Pipeline, error) {
-	p := sdk.Pipeline{}
+func (s *service) GetPipeline(id string) (Pipeline, error) {
+	p := Pipeline{}
 	return p, s.db.FindOneById(Collection, id, &p)
 }
 
@@ -215,3 +219,17 @@ func (s *service) UpdatePipelines(providerId string, pipelines []sdk.Pipeline) e
 	}
 	return s.db.UpdateProvided(Collection, providerId, pipelineMap)
 }
+
+func (s *service) UpsertPipelines(providerId string, pipelines []sdk.Pipeline) error {
+	if len(pipelines) == 0 {
+		return nil
+	}
+
+	filterMap := make(map[string]interface{}, len(pipelines))
+	pipelineMap := make(map[string]interface{}, len(pipelines))
+	for _, pipeline := range pipelines {
+		filterMap[pipeline.Id] = bson.M{"provider": providerId, "id": pipeline.Id}
+		pipelineMap[pipeline.Id] = pipeline
+	}
+	return s.db.UpdateMany(Collection, filterMap, pipelineMap)
+}
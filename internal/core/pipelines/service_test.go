@@ -112,7 +112,7 @@ func TestService_GetPipeline(t *testing.T) {
 		id          string
 		db          *db.RecordingDatabase
 		recorded    []db.DatabaseRecord
-		expected    sdk.Pipeline
+		expected    Pipeline
 		expectedErr error
 	}{
 		{
@@ -120,10 +120,10 @@ func TestService_GetPipeline(t *testing.T) {
 			id:   "pipeline-a",
 			db: &db.RecordingDatabase{
 				Return: func(target interface{}) {
-					*target.(*sdk.Pipeline) = somePipeline
+					*target.(*Pipeline) = Pipeline{Pipeline: somePipeline, ProviderId: "provider-a"}
 				},
 			},
-			expected: somePipeline,
+			expected: Pipeline{Pipeline: somePipeline, ProviderId: "provider-a"},
 			recorded: []db.DatabaseRecord{{
 				Collection: "pipelines",
 				Id:         "pipeline-a",
@@ -135,7 +135,7 @@ func TestService_GetPipeline(t *testing.T) {
 			db: &db.RecordingDatabase{
 				Err: someErr,
 			},
-			expected:    sdk.Pipeline{},
+			expected:    Pipeline{},
 			expectedErr: someErr,
 		},
 	}
@@ -168,6 +168,7 @@ func TestService_ListPipelineRuns(t *testing.T) {
 		id          string
 		fromIncl    time.Time
 		toExcl      time.Time
+		filter      RunFilter
 		db          *db.RecordingDatabase
 		recorded    []db.DatabaseRecord
 		expected    sdk.PipelineStatusList
@@ -202,6 +203,30 @@ func TestService_ListPipelineRuns(t *testing.T) {
 				},
 			}},
 		},
+		{
+			desc:     "filters pipeline runs by trigger",
+			id:       "pipeline-a",
+			fromIncl: someTime,
+			toExcl:   someTime.Add(2 * time.Minute),
+			filter:   RunFilter{Branch: "main", Author: "jane"},
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "pipeline_runs",
+				Filter: bson.M{
+					"pipelineId": bson.M{
+						"$eq": "pipeline-a",
+					},
+					"started": bson.M{
+						"$lt":  someTime.Add(2 * time.Minute),
+						"$gte": someTime,
+					},
+					"trigger.branch": bson.M{"$eq": "main"},
+					"trigger.author": bson.M{"$eq": "jane"},
+				},
+			}},
+		},
 		{
 			desc:     "error while getting group",
 			id:       "pipeline-a",
@@ -220,7 +245,7 @@ func TestService_ListPipelineRuns(t *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
 			s := NewService(test.db)
-			res, err := s.ListPipelineRuns(test.id, test.fromIncl, test.toExcl)
+			res, err := s.ListPipelineRuns(test.id, test.fromIncl, test.toExcl, test.filter)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
 			}
@@ -243,6 +268,7 @@ func TestService_ListPipelineRunsLimit(t *testing.T) {
 		id          string
 		toExcl      time.Time
 		limit       int
+		filter      RunFilter
 		db          *db.RecordingDatabase
 		recorded    []db.DatabaseRecord
 		expected    sdk.PipelineStatusList
@@ -280,6 +306,33 @@ func TestService_ListPipelineRunsLimit(t *testing.T) {
 				},
 			}},
 		},
+		{
+			desc:   "filters pipeline runs by trigger",
+			id:     "pipeline-a",
+			toExcl: someTime.Add(2 * time.Minute),
+			limit:  20,
+			filter: RunFilter{Type: sdk.TriggerSchedule, Commit: "abc123"},
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "pipeline_runs",
+				Limit:      20,
+				Sort: bson.M{
+					"started": -1,
+				},
+				Filter: bson.M{
+					"pipelineId": bson.M{
+						"$eq": "pipeline-a",
+					},
+					"started": bson.M{
+						"$lt": someTime.Add(2 * time.Minute),
+					},
+					"trigger.type":   bson.M{"$eq": "schedule"},
+					"trigger.commit": bson.M{"$eq": "abc123"},
+				},
+			}},
+		},
 		{
 			desc:   "error while getting group",
 			id:     "pipeline-a",
@@ -298,7 +351,7 @@ func TestService_ListPipelineRunsLimit(t *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
 			s := NewService(test.db)
-			res, err := s.ListPipelineRunsLimit(test.id, test.toExcl, test.limit)
+			res, err := s.ListPipelineRunsLimit(test.id, test.toExcl, test.limit, test.filter)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
 			}
@@ -418,7 +471,9 @@ func TestService_AddPipelineRuns(t *testing.T) {
 			desc:     "adds pipeline runs",
 			provider: "provider-a",
 			runs:     []sdk.PipelineStatus{somePipelineStatus},
-			db:       &db.RecordingDatabase{},
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {},
+			},
 			recorded: []db.DatabaseRecord{{
 				Collection: "pipeline_runs",
 				Updates: map[string]interface{}{
@@ -474,7 +529,9 @@ func TestService_AddPipelineVersions(t *testing.T) {
 			desc:     "adds pipeline versions",
 			provider: "provider-a",
 			versions: sdk.PipelineVersionList{somePipelineVersion},
-			db:       &db.RecordingDatabase{},
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {},
+			},
 			recorded: []db.DatabaseRecord{{
 				Collection: "pipeline_versions",
 				Updates: map[string]interface{}{
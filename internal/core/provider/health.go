@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"sort"
+	"time"
+)
+
+// Health tells how the reconciliations of a provider's feature went.
+type Health struct {
+	Id                  string     `json:"id" bson:"id"`
+	Name                string     `json:"name" bson:"name"`
+	Feature             Type       `json:"feature" bson:"type"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty" bson:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty" bson:"lastFailure,omitempty"`
+	LastError           string     `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures" bson:"consecutiveFailures"`
+	// LatencyMillis is how long the last reconciliation took.
+	LatencyMillis int64 `json:"latencyMillis" bson:"latencyMillis"`
+}
+
+// Healthy reports whether the last reconciliation didn't fail.
+func (h Health) Healthy() bool {
+	return h.ConsecutiveFailures == 0
+}
+
+// Status is the health of all features of a provider.
+type Status struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Healthy  bool     `json:"healthy"`
+	Features []Health `json:"features"`
+}
+
+// Statuses groups the health of features by their provider, sorted by the provider's id.
+func Statuses(health []Health) []Status {
+	byId := make(map[string]*Status)
+	for _, h := range health {
+		s := byId[h.Id]
+		if s == nil {
+			s = &Status{Id: h.Id, Name: h.Name, Healthy: true}
+			byId[h.Id] = s
+		}
+		s.Features = append(s.Features, h)
+		s.Healthy = s.Healthy && h.Healthy()
+	}
+
+	res := make([]Status, 0, len(byId))
+	for _, s := range byId {
+		sort.Slice(s.Features, func(i, j int) bool {
+			return s.Features[i].Feature < s.Features[j].Feature
+		})
+		res = append(res, *s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res
+}
+
+type FreshnessState string
+
+const (
+	// FreshnessUnknown means the data wasn't reconciled yet, e.g. because it was only pushed by the provider.
+	FreshnessUnknown FreshnessState = "unknown"
+	FreshnessFresh   FreshnessState = "fresh"
+	// FreshnessStale means the last reconciliation failed, so the data might be outdated.
+	FreshnessStale FreshnessState = "stale"
+)
+
+// Freshness tells how current the data synced from providers is.
+type Freshness struct {
+	State       FreshnessState `json:"state"`
+	LastSuccess *time.Time     `json:"lastSuccess,omitempty"`
+}
+
+// FreshnessOf derives the freshness of data synced from all of the given features. The data
+// is stale if any of them is failing and only as current as the oldest successful sync.
+func FreshnessOf(health ...Health) Freshness {
+	res := Freshness{State: FreshnessUnknown}
+	for _, h := range health {
+		if !h.Healthy() {
+			res.State = FreshnessStale
+		} else if h.LastSuccess != nil && res.State == FreshnessUnknown {
+			res.State = FreshnessFresh
+		}
+
+		if h.LastSuccess != nil && (res.LastSuccess == nil || h.LastSuccess.Before(*res.LastSuccess)) {
+			res.LastSuccess = h.LastSuccess
+		}
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+func TestStatuses(t *testing.T) {
+	health := []Health{
+		{Id: "provider-b", Name: "B", Feature: TypePipelines, LastSuccess: &someTime},
+		{Id: "provider-a", Name: "A", Feature: TypePipelines, ConsecutiveFailures: 1},
+		{Id: "provider-a", Name: "A", Feature: TypeApps, LastSuccess: &someTime},
+	}
+
+	expected := []Status{
+		{Id: "provider-a", Name: "A", Healthy: false, Features: []Health{
+			{Id: "provider-a", Name: "A", Feature: TypeApps, LastSuccess: &someTime},
+			{Id: "provider-a", Name: "A", Feature: TypePipelines, ConsecutiveFailures: 1},
+		}},
+		{Id: "provider-b", Name: "B", Healthy: true, Features: []Health{
+			{Id: "provider-b", Name: "B", Feature: TypePipelines, LastSuccess: &someTime},
+		}},
+	}
+
+	res := Statuses(health)
+	if !cmp.Equal(expected, res) {
+		t.Errorf("\ndiff between statuses: \n%s\n", cmp.Diff(expected, res))
+	}
+}
+
+func TestFreshnessOf(t *testing.T) {
+	earlier := someTime.Add(-time.Hour)
+
+	tests := []struct {
+		desc     string
+		health   []Health
+		expected Freshness
+	}{
+		{desc: "is unknown without health", expected: Freshness{State: FreshnessUnknown}},
+		{desc: "is unknown before first sync", health: []Health{{}}, expected: Freshness{State: FreshnessUnknown}},
+		{desc: "is fresh after sync", health: []Health{{LastSuccess: &someTime}},
+			expected: Freshness{State: FreshnessFresh, LastSuccess: &someTime}},
+		{desc: "is stale after failed sync", health: []Health{{LastSuccess: &someTime, ConsecutiveFailures: 1}},
+			expected: Freshness{State: FreshnessStale, LastSuccess: &someTime}},
+		{desc: "is stale if any sync failed", health: []Health{
+			{LastSuccess: &someTime}, {ConsecutiveFailures: 2}, {LastSuccess: &someTime},
+		}, expected: Freshness{State: FreshnessStale, LastSuccess: &someTime}},
+		{desc: "is as current as oldest sync", health: []Health{{LastSuccess: &someTime}, {LastSuccess: &earlier}},
+			expected: Freshness{State: FreshnessFresh, LastSuccess: &earlier}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			res := FreshnessOf(test.health...)
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("\ndiff between freshness: \n%s\n", cmp.Diff(test.expected, res))
+			}
+		})
+	}
+}
@@ -6,9 +6,22 @@ type Provider struct {
 	Data         `bson:",inline"`
 	ProviderType string    `bson:"type"`
 	LastUpdated  time.Time `bson:"lastUpdated"`
+	LastSynced   time.Time `bson:"lastSynced"`
+	Attempts     int       `bson:"attempts"`
+	LastError    string    `bson:"lastError"`
 }
 
 type Data struct {
 	Id   string `bson:"id"`
 	Name string `bson:"name"`
 }
+
+// Definition describes how to reach a provider. Definitions are stored next to the provider's
+// registrations, its credentials are only referenced by name.
+type Definition struct {
+	Id          string `json:"id" bson:"id"`
+	Name        string `json:"name" bson:"name"`
+	Host        string `json:"host" bson:"host"`
+	Features    []Type `json:"features,omitempty" bson:"features,omitempty"`
+	Credentials string `json:"credentials,omitempty" bson:"credentials,omitempty"`
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"errors"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/queue"
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+const RequestsCollection = "reconcileRequests"
+
+const (
+	// PriorityProvider is the priority of reconciliations providers ask for, e.g. because their data changed.
+	PriorityProvider queue.Priority = 1
+	// PriorityUser is the priority of reconciliations users are waiting for, e.g. after they restarted an app.
+	PriorityUser queue.Priority = 2
+)
+
+// requestAging is how long a request waits at most behind later requests of the next higher priority.
+const requestAging = 10 * time.Second
+
+// Requests are keyed by their kind, the type of job and the id of the app or provider.
+const (
+	requestApp      = "app"
+	requestProvider = "provider"
+)
+
+// requestStore holds the reconcile requests of all core replicas in the database, so that
+// requests survive restarts and are popped by whichever replica is free first.
+type requestStore struct {
+	db database.Database
+}
+
+func newRequestQueue(db database.Database) *queue.PriorityQueue {
+	return queue.NewPriorityQueueWithStore(&requestStore{db: db}, requestAging)
+}
+
+// Push coalesces the request with a queued one in a single update, so that replicas pushing the
+// same request at once don't overwrite each other's priority or enqueued time. The rank is computed
+// from the coalesced fields in a second stage of the same update.
+func (s *requestStore) Push(item queue.Item, agingStep time.Duration) (bool, error) {
+	return s.db.Upsert(RequestsCollection, bson.M{"key": item.Key}, bson.A{
+		bson.M{"$set": bson.M{
+			"priority": bson.M{"$max": bson.A{"$priority", item.Priority}},
+			"enqueued": bson.M{"$min": bson.A{"$enqueued", item.Enqueued}},
+		}},
+		bson.M{"$set": bson.M{
+			"rank": bson.M{"$subtract": bson.A{"$enqueued", bson.M{"$multiply": bson.A{"$priority", agingStep.Milliseconds()}}}},
+		}},
+	})
+}
+
+func (s *requestStore) PopFirst() (queue.Item, bool, error) {
+	item := queue.Item{}
+	err := s.db.FindOneAndDelete(RequestsCollection, bson.M{}, bson.M{"rank": 1}, &item)
+	if errors.Is(err, database.ErrNotFound) {
+		return queue.Item{}, false, nil
+	} else if err != nil {
+		return queue.Item{}, false, err
+	}
+	return item, true, nil
+}
+
+func (s *requestStore) Len() (int, error) {
+	return s.db.Count(RequestsCollection, bson.M{})
+}
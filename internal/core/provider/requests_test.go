@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
+	"github.com/joscha-alisch/dyve/internal/queue"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+)
+
+func TestRequestStore(t *testing.T) {
+	item := queue.Item{Key: "app/routing/app-id", Priority: PriorityUser, Enqueued: someTime, Rank: someTime.Add(-2 * requestAging)}
+
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{
+		Recorder:    rec,
+		ReturnCount: 1,
+		Return: func(target interface{}) {
+			if target != nil {
+				*target.(*queue.Item) = item
+			}
+		},
+	}
+	s := &requestStore{db: d}
+
+	coalesced, err := s.Push(queue.Item{Key: item.Key, Priority: PriorityUser, Enqueued: someTime}, requestAging)
+	assertNil(t, "no error", err)
+	assertEqual(t, coalesced, false)
+
+	d.ReturnMatched = true
+	coalesced, err = s.Push(queue.Item{Key: item.Key, Priority: PriorityProvider, Enqueued: someTime}, requestAging)
+	assertNil(t, "no error", err)
+	assertEqual(t, coalesced, true)
+
+	res, ok, err := s.PopFirst()
+	assertNil(t, "no error", err)
+	assertEqual(t, ok, true)
+	assertEqual(t, res, item)
+
+	l, err := s.Len()
+	assertNil(t, "no error", err)
+	assertEqual(t, l, 1)
+
+	push := func(p queue.Priority) db.DatabaseRecord {
+		return db.DatabaseRecord{Collection: RequestsCollection, Filter: bson.M{"key": item.Key}, CreateIfMissing: true, Update: bson.A{
+			bson.M{"$set": bson.M{
+				"priority": bson.M{"$max": bson.A{"$priority", p}},
+				"enqueued": bson.M{"$min": bson.A{"$enqueued", someTime}},
+			}},
+			bson.M{"$set": bson.M{
+				"rank": bson.M{"$subtract": bson.A{"$enqueued", bson.M{"$multiply": bson.A{"$priority", int64(10000)}}}},
+			}},
+		}}
+	}
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		push(PriorityUser),
+		push(PriorityProvider),
+		{Collection: RequestsCollection, Filter: bson.M{}, Sort: bson.M{"rank": 1}},
+		{Collection: RequestsCollection, Filter: bson.M{}},
+	})
+
+	d.Err = database.ErrNotFound
+	_, ok, err = s.PopFirst()
+	assertNil(t, "no error", err)
+	assertEqual(t, ok, false)
+}
@@ -2,13 +2,19 @@ package provider
 
 import (
 	"errors"
+	"github.com/google/uuid"
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/queue"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,6 +28,8 @@ const (
 	ReconcilePipelineProvider   recon.Type = "pipelines"
 	ReconcileGroupProvider      recon.Type = "groups"
 	ReconcileInstancesProviders recon.Type = "instances"
+	ReconcileAlertsProvider     recon.Type = "alerts"
+	ReconcileTopologyProvider   recon.Type = "topology"
 )
 
 const (
@@ -30,61 +38,162 @@ const (
 	TypePipelines Type = "pipelines"
 	TypeRouting   Type = "routing"
 	TypeInstances Type = "instances"
+	TypeLogs      Type = "logs"
+	TypeMetrics   Type = "metrics"
+	TypeAlerts    Type = "alerts"
+	TypeStepLogs  Type = "stepLogs"
+	TypeTopology  Type = "topology"
+
+	TypeAppActions Type = "appActions"
 )
 
+// definitionType marks the documents holding provider definitions rather than registrations.
+const definitionType = "definition"
+
 type Service interface {
-	recon.JobProvider
+	recon.RetryingJobProvider
+	recon.LeasingJobProvider
+	recon.DeadLetterQueue
 
-	AddAppProvider(id string, name string, p sdk.AppProvider) error
-	GetAppProvider(id string) (sdk.AppProvider, error)
+	EnsureIndices() error
+
+	AddAppProvider(id string, name string, p sdk.AppProviderContext) error
+	GetAppProvider(id string) (sdk.AppProviderContext, error)
 	DeleteAppProvider(id string) error
-	RequestAppUpdate(id string) error
+	// RequestAppUpdate schedules an immediate update of the app's routing and instances.
+	RequestAppUpdate(id string, p queue.Priority) error
 
-	AddRoutingProvider(id string, name string, p sdk.RoutingProvider) error
-	GetRoutingProviders() ([]sdk.RoutingProvider, error)
+	AddRoutingProvider(id string, name string, p sdk.RoutingProviderContext) error
+	GetRoutingProviders() ([]sdk.RoutingProviderContext, error)
 	DeleteRoutingProvider(id string) error
 
-	AddInstancesProvider(id string, name string, p sdk.InstancesProvider) error
-	GetInstancesProviders() ([]sdk.InstancesProvider, error)
+	AddInstancesProvider(id string, name string, p sdk.InstancesProviderContext) error
+	GetInstancesProviders() ([]sdk.InstancesProviderContext, error)
 	DeleteInstancesProvider(id string) error
 
-	AddPipelineProvider(id string, name string, p sdk.PipelineProvider) error
-	GetPipelineProvider(id string) (sdk.PipelineProvider, error)
+	AddLogsProvider(id string, name string, p sdk.LogsProvider) error
+	GetLogsProviders() ([]sdk.LogsProvider, error)
+	DeleteLogsProvider(id string) error
+
+	AddMetricsProvider(id string, name string, p sdk.MetricsProvider) error
+	GetMetricsProviders() ([]sdk.MetricsProvider, error)
+	DeleteMetricsProvider(id string) error
+
+	AddAlertsProvider(id string, name string, p sdk.AlertsProvider) error
+	GetAlertsProvider(id string) (sdk.AlertsProvider, error)
+	DeleteAlertsProvider(id string) error
+
+	AddTopologyProvider(id string, name string, p sdk.TopologyProvider) error
+	GetTopologyProvider(id string) (sdk.TopologyProvider, error)
+	DeleteTopologyProvider(id string) error
+
+	AddStepLogsProvider(id string, name string, p sdk.StepLogsProvider) error
+	GetStepLogsProvider(id string) (sdk.StepLogsProvider, error)
+	DeleteStepLogsProvider(id string) error
+
+	AddAppActionsProvider(id string, name string, p sdk.AppActionsProvider) error
+	GetAppActionsProvider(id string) (sdk.AppActionsProvider, error)
+	DeleteAppActionsProvider(id string) error
+
+	AddPipelineProvider(id string, name string, p sdk.PipelineProviderContext) error
+	GetPipelineProvider(id string) (sdk.PipelineProviderContext, error)
 	DeletePipelineProvider(id string) error
 
 	ListGroupProviders() ([]Data, error)
-	AddGroupProvider(id string, name string, p sdk.GroupProvider) error
-	GetGroupProvider(id string) (sdk.GroupProvider, error)
+	AddGroupProvider(id string, name string, p sdk.GroupProviderContext) error
+	GetGroupProvider(id string) (sdk.GroupProviderContext, error)
 	DeleteGroupProvider(id string) error
+
+	// RequestReconcile schedules an immediate reconciliation of the provider for the given type.
+	RequestReconcile(id string, providerType Type, p queue.Priority) error
+	// RequestMetrics tells how the queue of requested reconciliations is doing.
+	RequestMetrics() (queue.Metrics, error)
+
+	// Features returns the types the provider with the given id is currently registered for.
+	Features(id string) []Type
+	// Forget drops the client this replica holds for the provider's feature, but keeps the shared
+	// registration with its lease, retry and health state, e.g. to add a client for a new host.
+	Forget(id string, providerType Type)
+
+	// RecordSync records the outcome of reconciling the provider's feature, err being nil if
+	// it succeeded. Successful syncs become the point in time the next delta sync starts from.
+	// It returns ErrNotFound if the provider isn't registered for the feature.
+	RecordSync(id string, providerType Type, latency time.Duration, err error) error
+	// ListHealth returns the health of all registered features, sorted by provider and feature.
+	ListHealth() ([]Health, error)
+
+	ListDefinitions() ([]Definition, error)
+	GetDefinition(id string) (Definition, error)
+	// SaveDefinition creates the definition or replaces the one with the same id.
+	SaveDefinition(d Definition) error
+	DeleteDefinition(id string) error
 }
 
+// DefaultLeaseDuration is how long a replica may run a job before others may take it over.
+// It has to be longer than reconciliations take.
+const DefaultLeaseDuration = 5 * time.Minute
+
 func NewService(db database.Database) Service {
+	return NewServiceWithLeases(db, LeaseOwner(), DefaultLeaseDuration)
+}
+
+// NewServiceWithLeases creates a service which leases the jobs it accepts to the given owner,
+// so that it can share the jobs with other core replicas.
+func NewServiceWithLeases(db database.Database, owner string, leaseDuration time.Duration) Service {
 	return &service{
-		db:                db,
-		providers:         make(map[Type]map[string]interface{}),
-		appUpdateRequests: queue.NewStringQueue(1000),
+		db:            db,
+		providers:     make(map[Type]map[string]interface{}),
+		requests:      newRequestQueue(db),
+		owner:         owner,
+		leaseDuration: leaseDuration,
 	}
 }
 
+// LeaseOwner identifies this replica by its host name, e.g. the name of its pod.
+func LeaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "core"
+	}
+	return host + "-" + uuid.New().String()[:8]
+}
+
 type service struct {
-	db                database.Database
-	providers         map[Type]map[string]interface{}
-	appUpdateRequests *queue.StringQueue
+	db            database.Database
+	mu            sync.RWMutex
+	providers     map[Type]map[string]interface{}
+	requests      *queue.PriorityQueue
+	owner         string
+	leaseDuration time.Duration
+}
+
+func (s *service) EnsureIndices() error {
+	err := s.db.EnsureIndex(RequestsCollection, mongo.IndexModel{
+		Keys:    bson.D{bson.E{Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.EnsureIndex(RequestsCollection, mongo.IndexModel{
+		Keys: bson.D{bson.E{Key: "rank", Value: 1}},
+	})
 }
 
-func (s *service) AddInstancesProvider(id string, name string, p sdk.InstancesProvider) error {
+func (s *service) AddInstancesProvider(id string, name string, p sdk.InstancesProviderContext) error {
 	return s.add(id, name, TypeInstances, p)
 }
 
-func (s *service) GetInstancesProviders() ([]sdk.InstancesProvider, error) {
+func (s *service) GetInstancesProviders() ([]sdk.InstancesProviderContext, error) {
 	providers, err := s.getAll(TypeInstances)
 	if err != nil {
 		return nil, err
 	}
 
-	var res []sdk.InstancesProvider
+	var res []sdk.InstancesProviderContext
 	for _, provider := range providers.([]interface{}) {
-		res = append(res, provider.(sdk.InstancesProvider))
+		res = append(res, provider.(sdk.InstancesProviderContext))
 	}
 
 	return res, nil
@@ -94,19 +203,127 @@ func (s *service) DeleteInstancesProvider(id string) error {
 	return s.delete(id, TypeInstances)
 }
 
-func (s *service) AddRoutingProvider(id string, name string, p sdk.RoutingProvider) error {
+func (s *service) AddLogsProvider(id string, name string, p sdk.LogsProvider) error {
+	return s.add(id, name, TypeLogs, p)
+}
+
+func (s *service) GetLogsProviders() ([]sdk.LogsProvider, error) {
+	providers, err := s.getAll(TypeLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []sdk.LogsProvider
+	for _, provider := range providers.([]interface{}) {
+		res = append(res, provider.(sdk.LogsProvider))
+	}
+
+	return res, nil
+}
+
+func (s *service) DeleteLogsProvider(id string) error {
+	return s.delete(id, TypeLogs)
+}
+
+func (s *service) AddMetricsProvider(id string, name string, p sdk.MetricsProvider) error {
+	return s.add(id, name, TypeMetrics, p)
+}
+
+func (s *service) GetMetricsProviders() ([]sdk.MetricsProvider, error) {
+	providers, err := s.getAll(TypeMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []sdk.MetricsProvider
+	for _, provider := range providers.([]interface{}) {
+		res = append(res, provider.(sdk.MetricsProvider))
+	}
+
+	return res, nil
+}
+
+func (s *service) DeleteMetricsProvider(id string) error {
+	return s.delete(id, TypeMetrics)
+}
+
+func (s *service) AddAlertsProvider(id string, name string, p sdk.AlertsProvider) error {
+	return s.add(id, name, TypeAlerts, p)
+}
+
+func (s *service) GetAlertsProvider(id string) (sdk.AlertsProvider, error) {
+	p, err := s.get(id, TypeAlerts)
+	if err != nil {
+		return nil, err
+	}
+	return p.(sdk.AlertsProvider), nil
+}
+
+func (s *service) DeleteAlertsProvider(id string) error {
+	return s.delete(id, TypeAlerts)
+}
+
+func (s *service) AddTopologyProvider(id string, name string, p sdk.TopologyProvider) error {
+	return s.add(id, name, TypeTopology, p)
+}
+
+func (s *service) GetTopologyProvider(id string) (sdk.TopologyProvider, error) {
+	p, err := s.get(id, TypeTopology)
+	if err != nil {
+		return nil, err
+	}
+	return p.(sdk.TopologyProvider), nil
+}
+
+func (s *service) DeleteTopologyProvider(id string) error {
+	return s.delete(id, TypeTopology)
+}
+
+func (s *service) AddStepLogsProvider(id string, name string, p sdk.StepLogsProvider) error {
+	return s.add(id, name, TypeStepLogs, p)
+}
+
+func (s *service) GetStepLogsProvider(id string) (sdk.StepLogsProvider, error) {
+	p, err := s.get(id, TypeStepLogs)
+	if err != nil {
+		return nil, err
+	}
+	return p.(sdk.StepLogsProvider), nil
+}
+
+func (s *service) DeleteStepLogsProvider(id string) error {
+	return s.delete(id, TypeStepLogs)
+}
+
+func (s *service) AddAppActionsProvider(id string, name string, p sdk.AppActionsProvider) error {
+	return s.add(id, name, TypeAppActions, p)
+}
+
+func (s *service) GetAppActionsProvider(id string) (sdk.AppActionsProvider, error) {
+	p, err := s.get(id, TypeAppActions)
+	if err != nil {
+		return nil, err
+	}
+	return p.(sdk.AppActionsProvider), nil
+}
+
+func (s *service) DeleteAppActionsProvider(id string) error {
+	return s.delete(id, TypeAppActions)
+}
+
+func (s *service) AddRoutingProvider(id string, name string, p sdk.RoutingProviderContext) error {
 	return s.add(id, name, TypeRouting, p)
 }
 
-func (s *service) GetRoutingProviders() ([]sdk.RoutingProvider, error) {
+func (s *service) GetRoutingProviders() ([]sdk.RoutingProviderContext, error) {
 	providers, err := s.getAll(TypeRouting)
 	if err != nil {
 		return nil, err
 	}
 
-	var res []sdk.RoutingProvider
+	var res []sdk.RoutingProviderContext
 	for _, provider := range providers.([]interface{}) {
-		res = append(res, provider.(sdk.RoutingProvider))
+		res = append(res, provider.(sdk.RoutingProviderContext))
 	}
 
 	return res, nil
@@ -116,44 +333,57 @@ func (s *service) DeleteRoutingProvider(id string) error {
 	return s.delete(id, TypeRouting)
 }
 
-func (s *service) RequestAppUpdate(id string) error {
-	err := s.appUpdateRequests.Push(string(TypeRouting) + "/" + id)
+func (s *service) RequestAppUpdate(id string, p queue.Priority) error {
+	err := s.requests.Push(requestApp+"/"+string(TypeRouting)+"/"+id, p)
 	if err != nil {
 		return err
 	}
-	err = s.appUpdateRequests.Push(string(TypeInstances) + "/" + id)
+	err = s.requests.Push(requestApp+"/"+string(TypeInstances)+"/"+id, p)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *service) AddAppProvider(id string, name string, p sdk.AppProvider) error {
+func (s *service) RequestReconcile(id string, providerType Type, p queue.Priority) error {
+	_, err := s.get(id, providerType)
+	if err != nil {
+		return err
+	}
+
+	return s.requests.Push(requestProvider+"/"+string(providerType)+"/"+id, p)
+}
+
+func (s *service) RequestMetrics() (queue.Metrics, error) {
+	return s.requests.Metrics()
+}
+
+func (s *service) AddAppProvider(id string, name string, p sdk.AppProviderContext) error {
 	return s.add(id, name, TypeApps, p)
 }
 
-func (s *service) GetAppProvider(id string) (sdk.AppProvider, error) {
+func (s *service) GetAppProvider(id string) (sdk.AppProviderContext, error) {
 	p, err := s.get(id, TypeApps)
 	if err != nil {
 		return nil, err
 	}
-	return p.(sdk.AppProvider), nil
+	return p.(sdk.AppProviderContext), nil
 }
 
 func (s *service) DeleteAppProvider(id string) error {
 	return s.delete(id, TypeApps)
 }
 
-func (s *service) AddPipelineProvider(id string, name string, p sdk.PipelineProvider) error {
+func (s *service) AddPipelineProvider(id string, name string, p sdk.PipelineProviderContext) error {
 	return s.add(id, name, TypePipelines, p)
 }
 
-func (s *service) GetPipelineProvider(id string) (sdk.PipelineProvider, error) {
+func (s *service) GetPipelineProvider(id string) (sdk.PipelineProviderContext, error) {
 	p, err := s.get(id, TypePipelines)
 	if err != nil {
 		return nil, err
 	}
-	return p.(sdk.PipelineProvider), nil
+	return p.(sdk.PipelineProviderContext), nil
 }
 
 func (s *service) DeletePipelineProvider(id string) error {
@@ -164,23 +394,137 @@ func (s *service) ListGroupProviders() ([]Data, error) {
 	return s.list(TypeGroups)
 }
 
-func (s *service) AddGroupProvider(id string, name string, p sdk.GroupProvider) error {
+func (s *service) AddGroupProvider(id string, name string, p sdk.GroupProviderContext) error {
 	return s.add(id, name, TypeGroups, p)
 }
 
-func (s *service) GetGroupProvider(id string) (sdk.GroupProvider, error) {
+func (s *service) GetGroupProvider(id string) (sdk.GroupProviderContext, error) {
 	p, err := s.get(id, TypeGroups)
 	if err != nil {
 		return nil, err
 	}
-	return p.(sdk.GroupProvider), nil
+	return p.(sdk.GroupProviderContext), nil
 }
 
 func (s *service) DeleteGroupProvider(id string) error {
 	return s.delete(id, TypeGroups)
 }
 
+func (s *service) RecordSync(id string, providerType Type, latency time.Duration, syncErr error) error {
+	filter := bson.M{"id": id, "type": providerType}
+
+	h := Health{}
+	err := s.db.FindOne(Collection, filter, &h)
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	t := currentTime()
+	update := bson.M{"latencyMillis": latency.Milliseconds()}
+	if syncErr == nil {
+		update["lastSuccess"] = t
+		// the sync started latency ago, so changes made while it ran are part of the next one
+		update["lastSynced"] = t.Add(-latency)
+		update["consecutiveFailures"] = 0
+	} else {
+		update["lastFailure"] = t
+		update["lastError"] = syncErr.Error()
+		update["consecutiveFailures"] = h.ConsecutiveFailures + 1
+	}
+
+	err = s.db.UpdateOne(Collection, filter, false, update, nil)
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *service) ListHealth() ([]Health, error) {
+	res := []Health{}
+	err := s.db.FindManyWithOptions(Collection, bson.M{"type": bson.M{"$ne": definitionType}}, func(c database.Decodable) error {
+		h := Health{}
+		err := c.Decode(&h)
+		if err != nil {
+			return err
+		}
+		res = append(res, h)
+		return nil
+	}, bson.M{"id": 1, "type": 1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) ListDefinitions() ([]Definition, error) {
+	res := []Definition{}
+	err := s.db.FindManyWithOptions(Collection, bson.M{"type": definitionType}, func(c database.Decodable) error {
+		d := Definition{}
+		err := c.Decode(&d)
+		if err != nil {
+			return err
+		}
+		res = append(res, d)
+		return nil
+	}, bson.M{"id": 1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) GetDefinition(id string) (Definition, error) {
+	d := Definition{}
+	err := s.db.FindOne(Collection, bson.M{"type": definitionType, "id": id}, &d)
+	if errors.Is(err, database.ErrNotFound) {
+		return Definition{}, ErrNotFound
+	}
+	if err != nil {
+		return Definition{}, err
+	}
+	return d, nil
+}
+
+func (s *service) SaveDefinition(d Definition) error {
+	return s.db.UpdateOne(Collection, bson.M{"type": definitionType, "id": d.Id}, true, bson.M{
+		"name":        d.Name,
+		"host":        d.Host,
+		"features":    d.Features,
+		"credentials": d.Credentials,
+	}, nil)
+}
+
+func (s *service) DeleteDefinition(id string) error {
+	err := s.db.DeleteOne(Collection, bson.M{"type": definitionType, "id": id})
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *service) Features(id string) []Type {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []Type
+	for providerType, providers := range s.providers {
+		if providers[id] != nil {
+			res = append(res, providerType)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+	return res
+}
+
 func (s *service) add(id string, name string, providerType Type, p interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.providers[providerType] == nil {
 		s.providers[providerType] = make(map[string]interface{})
 	}
@@ -189,9 +533,13 @@ func (s *service) add(id string, name string, providerType Type, p interface{})
 		return ErrExists
 	}
 
-	providerData := bson.M{"id": id, "name": name, "type": string(providerType)}
-
-	err := s.db.UpdateOne(Collection, providerData, true, providerData, nil)
+	// only sets the fields derived from the definition, the lease, retry and health state of a
+	// registration another replica created stays as is
+	err := s.db.UpdateOne(Collection, bson.M{"id": id, "type": string(providerType)}, true, bson.M{
+		"id":   id,
+		"name": name,
+		"type": string(providerType),
+	}, nil)
 	if err != nil {
 		return err
 	}
@@ -216,6 +564,9 @@ func (s *service) list(providerType Type) ([]Data, error) {
 }
 
 func (s *service) get(id string, providerType Type) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	if s.providers[providerType] == nil {
 		return nil, ErrNotFound
 	}
@@ -228,6 +579,9 @@ func (s *service) get(id string, providerType Type) (interface{}, error) {
 }
 
 func (s *service) getAll(providerType Type) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	if s.providers[providerType] == nil {
 		return nil, ErrNotFound
 	}
@@ -240,12 +594,16 @@ func (s *service) getAll(providerType Type) (interface{}, error) {
 }
 
 func (s *service) delete(id string, providerType Type) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.providers[providerType] == nil {
 		return ErrNotFound
 	}
 
+	// another replica might have deleted the shared registration already
 	err := s.db.DeleteOne(Collection, bson.M{"id": id, "type": providerType})
-	if err != nil {
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
 		return err
 	}
 
@@ -256,48 +614,118 @@ func (s *service) delete(id string, providerType Type) error {
 	return nil
 }
 
+func (s *service) Forget(id string, providerType Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.providers[providerType], id)
+	if len(s.providers[providerType]) == 0 {
+		s.providers[providerType] = nil
+	}
+}
+
 var currentTime = time.Now
 
 func (s *service) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool) {
 	t := currentTime()
-	p := Provider{}
 
-	if request, ok := s.appUpdateRequests.Pop(); ok {
-		parts := strings.Split(request, "/")
-		t := Type(parts[0])
-		appId := parts[1]
-
-		switch t {
-		case TypeRouting:
-			return recon.Job{
-				Type:        ReconcileRoutingProviders,
-				Guid:        appId,
-				LastUpdated: time.Time{},
-			}, true
-		case TypeInstances:
-			return recon.Job{
-				Type:        ReconcileInstancesProviders,
-				Guid:        appId,
-				LastUpdated: time.Time{},
-			}, true
-		}
+	j, ok := s.acceptRequested(t)
+	if ok {
+		return j, true
+	}
 
+	local := s.localJobs()
+	if len(local) == 0 {
+		return recon.Job{}, false
 	}
 
-	filter := bson.M{
-		"$or": bson.A{
-			bson.M{
-				"lastUpdated": bson.M{
-					"$lte": t.Add(-olderThan),
-				},
-			},
-			bson.M{
-				"lastUpdated": nil,
+	// retried and requeued jobs go before the regular reconciliations
+	j, ok = s.acceptJob(t, bson.M{"$or": local}, bson.M{"nextAttempt": bson.M{"$lte": t}})
+	if ok {
+		return j, true
+	}
+
+	return s.acceptJob(t, bson.M{"$or": local}, bson.M{"$or": bson.A{
+		bson.M{
+			"nextAttempt": nil,
+			"lastUpdated": bson.M{
+				"$lte": t.Add(-olderThan),
 			},
 		},
+		bson.M{
+			"nextAttempt": nil,
+			"lastUpdated": nil,
+		},
+	}})
+}
+
+// acceptRequested pops requests until one of them can be run by this replica.
+func (s *service) acceptRequested(t time.Time) (recon.Job, bool) {
+	for {
+		item, ok, err := s.requests.Pop()
+		if err != nil {
+			log.Error().Err(err).Msg("error when popping requested job")
+			return recon.Job{}, false
+		}
+		if !ok {
+			return recon.Job{}, false
+		}
+
+		parts := strings.SplitN(item.Key, "/", 3)
+		if len(parts) != 3 {
+			log.Warn().Str("request", item.Key).Msg("dropping malformed request")
+			continue
+		}
+		kind, providerType, id := parts[0], Type(parts[1]), parts[2]
+
+		switch {
+		case kind == requestApp && providerType == TypeRouting:
+			return recon.Job{Type: ReconcileRoutingProviders, Guid: id}, true
+		case kind == requestApp && providerType == TypeInstances:
+			return recon.Job{Type: ReconcileInstancesProviders, Guid: id}, true
+		case kind == requestProvider:
+			if _, err := s.get(id, providerType); err == nil {
+				j, ok := s.acceptJob(t, bson.M{"type": string(providerType), "id": id})
+				if ok {
+					return j, true
+				}
+			}
+
+			// the job is leased by another replica or its provider isn't held by this one, so
+			// it's made due for whichever replica can run it next
+			err := s.db.UpdateOne(Collection, bson.M{"type": string(providerType), "id": id}, false, bson.M{
+				"nextAttempt": t,
+			}, nil)
+			if err != nil && !errors.Is(err, database.ErrNotFound) {
+				log.Error().Err(err).Str("request", item.Key).Msg("error when deferring requested job")
+			}
+		default:
+			log.Warn().Str("request", item.Key).Msg("dropping malformed request")
+		}
+	}
+}
+
+// acceptJob leases a job matching all conditions, which isn't leased by another replica.
+func (s *service) acceptJob(t time.Time, conditions ...bson.M) (recon.Job, bool) {
+	and := bson.A{}
+	for _, c := range conditions {
+		and = append(and, c)
+	}
+	and = append(and, bson.M{"$or": bson.A{
+		bson.M{"leaseExpiry": nil},
+		bson.M{"leaseExpiry": bson.M{"$lte": t}},
+	}})
+
+	p := Provider{}
+	filter := bson.M{
+		"deadLettered": bson.M{"$ne": true},
+		"$and":         and,
 	}
 	update := bson.M{
 		"lastUpdated": t,
+		"nextAttempt": nil,
+		"leaseOwner":  s.owner,
+		"leaseExpiry": t.Add(s.leaseDuration),
 	}
 	err := s.db.UpdateOne(Collection, filter, false, update, &p)
 	if errors.Is(err, database.ErrNotFound) {
@@ -308,9 +736,119 @@ func (s *service) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool)
 		return recon.Job{}, false
 	}
 
+	return p.job(), true
+}
+
+// localJobs matches the registrations of all providers this replica holds clients for.
+func (s *service) localJobs() bson.A {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var types []string
+	for t, providers := range s.providers {
+		if len(providers) > 0 {
+			types = append(types, string(t))
+		}
+	}
+	sort.Strings(types)
+
+	res := bson.A{}
+	for _, t := range types {
+		var ids []string
+		for id := range s.providers[Type(t)] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		res = append(res, bson.M{"type": t, "id": bson.M{"$in": ids}})
+	}
+	return res
+}
+
+func (s *service) ReleaseReconcileJob(j recon.Job) error {
+	filter := bson.M{"type": string(j.Type), "id": j.Guid, "leaseOwner": s.owner}
+	err := s.db.UpdateOne(Collection, filter, false, bson.M{
+		"leaseOwner":  "",
+		"leaseExpiry": nil,
+	}, nil)
+	if errors.Is(err, database.ErrNotFound) {
+		return recon.ErrJobNotFound
+	}
+	return err
+}
+
+// job returns the job reconciling the provider.
+func (p Provider) job() recon.Job {
 	return recon.Job{
 		Type:        recon.Type(p.ProviderType),
 		Guid:        p.Id,
 		LastUpdated: p.LastUpdated,
-	}, true
+		LastSynced:  p.LastSynced,
+		Attempts:    p.Attempts,
+		LastError:   p.LastError,
+	}
+}
+
+func (s *service) RetryReconcileJob(j recon.Job, at time.Time, cause error) error {
+	return s.updateJob(j, bson.M{
+		"attempts":    j.Attempts + 1,
+		"lastError":   cause.Error(),
+		"nextAttempt": at,
+	})
+}
+
+func (s *service) DeadLetterReconcileJob(j recon.Job, cause error) error {
+	return s.updateJob(j, bson.M{
+		"attempts":     j.Attempts + 1,
+		"lastError":    cause.Error(),
+		"nextAttempt":  nil,
+		"deadLettered": true,
+	})
+}
+
+func (s *service) SucceedReconcileJob(j recon.Job) error {
+	return s.updateJob(j, bson.M{
+		"attempts":    0,
+		"lastError":   "",
+		"nextAttempt": nil,
+	})
+}
+
+// updateJob updates the registration the job reconciles. Jobs reconciling single apps
+// aren't stored, so they can't be updated.
+func (s *service) updateJob(j recon.Job, update bson.M) error {
+	err := s.db.UpdateOne(Collection, bson.M{"type": string(j.Type), "id": j.Guid}, false, update, nil)
+	if errors.Is(err, database.ErrNotFound) {
+		return recon.ErrJobNotFound
+	}
+	return err
+}
+
+func (s *service) ListDeadLetters() ([]recon.Job, error) {
+	res := []recon.Job{}
+	err := s.db.FindManyWithOptions(Collection, bson.M{"deadLettered": true}, func(c database.Decodable) error {
+		p := Provider{}
+		err := c.Decode(&p)
+		if err != nil {
+			return err
+		}
+		res = append(res, p.job())
+		return nil
+	}, bson.M{"id": 1, "type": 1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) RequeueDeadLetter(t recon.Type, guid string) error {
+	filter := bson.M{"type": string(t), "id": guid, "deadLettered": true}
+	err := s.db.UpdateOne(Collection, filter, false, bson.M{
+		"attempts":     0,
+		"deadLettered": false,
+		"nextAttempt":  currentTime(),
+	}, nil)
+	if errors.Is(err, database.ErrNotFound) {
+		return recon.ErrJobNotFound
+	}
+	return err
 }
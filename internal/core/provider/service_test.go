@@ -6,13 +6,16 @@ import (
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
+	"github.com/joscha-alisch/dyve/internal/queue"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
 	"testing"
 	"time"
 )
 
 var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+var someErr = errors.New("some error")
 
 func TestService_AppProvider(t *testing.T) {
 	rec := &db.DatabaseRecorder{}
@@ -43,6 +46,25 @@ func TestService_AppProvider(t *testing.T) {
 	assertErr(t, err, ErrNotFound)
 }
 
+func TestService_Forget(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	s := NewService(&db.RecordingDatabase{Recorder: rec})
+
+	err := s.AddAppProvider("provider-a", "A", fakeProvider.AppProvider(nil))
+	assertNil(t, "there should be no error", err)
+
+	s.Forget("provider-a", TypeApps)
+
+	_, err = s.GetAppProvider("provider-a")
+	assertErr(t, err, ErrNotFound)
+	assertEqual(t, rec.Records, []db.DatabaseRecord{{
+		Collection:      Collection,
+		Filter:          bson.M{"id": "provider-a", "type": "apps"},
+		CreateIfMissing: true,
+		Update:          bson.M{"id": "provider-a", "name": "A", "type": "apps"},
+	}})
+}
+
 func TestService_PipelineProvider(t *testing.T) {
 	rec := &db.DatabaseRecorder{}
 	d := &db.RecordingDatabase{Recorder: rec}
@@ -193,14 +215,215 @@ func TestService_InstancesProvider(t *testing.T) {
 	assertErr(t, err, ErrNotFound)
 }
 
-func TestReconcile(t *testing.T) {
+func TestService_LogsProvider(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	p, err := s.GetLogsProviders()
+	if p != nil {
+		assertNil(t, "getProvider should return nil in the beginning", p)
+	}
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.LogsProvider(nil, nil)
+
+	err = s.AddLogsProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddLogsProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetLogsProviders()
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p[0], origProv)
+
+	err = s.DeleteLogsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetLogsProviders()
+	if p != nil {
+		assertNil(t, "getProvider should return nil after deletion", p)
+	}
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_MetricsProvider(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	p, err := s.GetMetricsProviders()
+	if p != nil {
+		assertNil(t, "getProvider should return nil in the beginning", p)
+	}
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.MetricsProvider(nil)
+
+	err = s.AddMetricsProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddMetricsProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetMetricsProviders()
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p[0], origProv)
+
+	err = s.DeleteMetricsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetMetricsProviders()
+	if p != nil {
+		assertNil(t, "getProvider should return nil after deletion", p)
+	}
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_AlertsProvider(t *testing.T) {
 	rec := &db.DatabaseRecorder{}
 	d := &db.RecordingDatabase{Recorder: rec}
 	s := NewService(d)
 
+	p, err := s.GetAlertsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil in the beginning", p)
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.AlertsProvider(nil)
+
+	err = s.AddAlertsProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddAlertsProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetAlertsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p, origProv)
+
+	err = s.DeleteAlertsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetAlertsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil after deletion", p)
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_TopologyProvider(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	p, err := s.GetTopologyProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil in the beginning", p)
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.TopologyProvider(sdk.Topology{})
+
+	err = s.AddTopologyProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddTopologyProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetTopologyProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p, origProv)
+
+	err = s.DeleteTopologyProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetTopologyProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil after deletion", p)
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_StepLogsProvider(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	p, err := s.GetStepLogsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil in the beginning", p)
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.StepLogsProvider(nil)
+
+	err = s.AddStepLogsProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddStepLogsProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetStepLogsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p, origProv)
+
+	err = s.DeleteStepLogsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetStepLogsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil after deletion", p)
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_AppActionsProvider(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	p, err := s.GetAppActionsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil in the beginning", p)
+	assertErr(t, err, ErrNotFound)
+
+	origProv := fakeProvider.AppActionsProvider()
+
+	err = s.AddAppActionsProvider("fakeProvider", "name", origProv)
+	assertNil(t, "there should be no error", err)
+
+	err = s.AddAppActionsProvider("fakeProvider", "name", origProv)
+	assertErr(t, err, ErrExists)
+
+	p, err = s.GetAppActionsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+	assertSame(t, p, origProv)
+
+	err = s.DeleteAppActionsProvider("fakeProvider")
+	assertNil(t, "there should be no error", err)
+
+	p, err = s.GetAppActionsProvider("fakeProvider")
+	assertNil(t, "getProvider should return nil after deletion", p)
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_Features(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{Recorder: rec}
+	s := NewService(d)
+
+	assertEqual(t, []Type(nil), s.Features("fakeProvider"))
+
+	_ = s.AddRoutingProvider("fakeProvider", "name", fakeProvider.RoutesProvider(nil))
+	_ = s.AddAppProvider("fakeProvider", "name", fakeProvider.AppProvider(nil))
+	_ = s.AddAppProvider("otherProvider", "name", fakeProvider.AppProvider(nil))
+
+	assertEqual(t, []Type{TypeApps, TypeRouting}, s.Features("fakeProvider"))
+
+	_ = s.DeleteAppProvider("fakeProvider")
+
+	assertEqual(t, []Type{TypeRouting}, s.Features("fakeProvider"))
+}
+
+func TestReconcile(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := newQueueDatabase(rec)
+	s := NewService(d)
+	_ = s.AddAppProvider("id", "name", fakeProvider.AppProvider(nil))
+
 	d.Return = func(target interface{}) {
 		*target.(*Provider) = Provider{
-			ProviderType: "something",
+			ProviderType: "apps",
 			Data:         Data{Id: "id"},
 			LastUpdated:  someTime,
 		}
@@ -208,13 +431,15 @@ func TestReconcile(t *testing.T) {
 
 	j, ok := s.AcceptReconcileJob(2 * time.Minute)
 	assertEqual(t, j, recon.Job{
-		Type:        "something",
+		Type:        "apps",
 		Guid:        "id",
 		LastUpdated: someTime,
 	})
 	assertEqual(t, ok, true)
 
-	err := s.RequestAppUpdate("app-id")
+	err := s.RequestAppUpdate("app-id", PriorityUser)
+	assertNil(t, "no error", err)
+	err = s.RequestAppUpdate("app-id", PriorityUser)
 	assertNil(t, "no error", err)
 
 	j, ok = s.AcceptReconcileJob(2 * time.Minute)
@@ -233,13 +458,375 @@ func TestReconcile(t *testing.T) {
 
 	j, ok = s.AcceptReconcileJob(2 * time.Minute)
 	assertEqual(t, j, recon.Job{
-		Type:        "something",
+		Type:        "apps",
 		Guid:        "id",
 		LastUpdated: someTime,
 	})
 	assertEqual(t, ok, true)
 }
 
+func TestReconcileWithoutLocalProviders(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	s := NewService(newQueueDatabase(rec))
+
+	_, ok := s.AcceptReconcileJob(2 * time.Minute)
+	assertEqual(t, ok, false)
+	assertEqual(t, len(rec.Records), 0)
+}
+
+func TestService_RequestReconcile(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	rec := &db.DatabaseRecorder{}
+	d := newQueueDatabase(rec)
+	s := NewServiceWithLeases(d, "owner-a", time.Minute)
+
+	err := s.RequestReconcile("fakeProvider", TypePipelines, PriorityUser)
+	assertErr(t, err, ErrNotFound)
+
+	_ = s.AddPipelineProvider("fakeProvider", "name", fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{}))
+	rec.Records = nil
+	err = s.RequestReconcile("fakeProvider", TypePipelines, PriorityUser)
+	assertNil(t, "no error", err)
+
+	d.Return = func(target interface{}) {
+		*target.(*Provider) = Provider{
+			ProviderType: string(TypePipelines),
+			Data:         Data{Id: "fakeProvider"},
+			LastUpdated:  someTime,
+		}
+	}
+
+	j, ok := s.AcceptReconcileJob(2 * time.Minute)
+	assertEqual(t, j, recon.Job{
+		Type:        ReconcilePipelineProvider,
+		Guid:        "fakeProvider",
+		LastUpdated: someTime,
+	})
+	assertEqual(t, ok, true)
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: bson.M{
+			"deadLettered": bson.M{"$ne": true},
+			"$and": bson.A{
+				bson.M{"type": "pipelines", "id": "fakeProvider"},
+				bson.M{"$or": bson.A{bson.M{"leaseExpiry": nil}, bson.M{"leaseExpiry": bson.M{"$lte": someTime}}}},
+			},
+		}, Update: bson.M{
+			"lastUpdated": someTime, "nextAttempt": nil, "leaseOwner": "owner-a", "leaseExpiry": someTime.Add(time.Minute),
+		}},
+	})
+
+	// replicas without the provider make the requested job due for the ones that have it
+	err = s.RequestReconcile("fakeProvider", TypePipelines, PriorityUser)
+	assertNil(t, "no error", err)
+	other := NewServiceWithLeases(d, "owner-b", time.Minute)
+	rec.Records = nil
+	d.Return = nil
+	_, ok = other.AcceptReconcileJob(2 * time.Minute)
+	assertEqual(t, ok, false)
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: bson.M{"type": "pipelines", "id": "fakeProvider"}, Update: bson.M{"nextAttempt": someTime}},
+	})
+
+	rec.Records = nil
+	d.Return = nil
+	err = s.ReleaseReconcileJob(j)
+	assertNil(t, "no error", err)
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: bson.M{"type": "pipelines", "id": "fakeProvider", "leaseOwner": "owner-a"}, Update: bson.M{
+			"leaseOwner": "", "leaseExpiry": nil,
+		}},
+	})
+}
+
+func TestService_RequestPriorities(t *testing.T) {
+	rec := &db.DatabaseRecorder{}
+	d := newQueueDatabase(rec)
+	s := NewService(d)
+	_ = s.AddPipelineProvider("fakeProvider", "name", fakeProvider.PipelineProvider(nil, sdk.PipelineUpdates{}))
+	d.Return = func(target interface{}) {
+		*target.(*Provider) = Provider{ProviderType: string(TypePipelines), Data: Data{Id: "fakeProvider"}}
+	}
+
+	err := s.RequestReconcile("fakeProvider", TypePipelines, PriorityProvider)
+	assertNil(t, "no error", err)
+	err = s.RequestAppUpdate("app-id", PriorityUser)
+	assertNil(t, "no error", err)
+	err = s.RequestAppUpdate("app-id", PriorityProvider)
+	assertNil(t, "no error", err)
+
+	m, err := s.RequestMetrics()
+	assertNil(t, "no error", err)
+	assertEqual(t, m.Length, 3)
+	assertEqual(t, m.Pushed, 5)
+	assertEqual(t, m.Coalesced, 2)
+
+	var jobs []recon.Job
+	for i := 0; i < 3; i++ {
+		j, ok := s.AcceptReconcileJob(2 * time.Minute)
+		assertEqual(t, ok, true)
+		jobs = append(jobs, recon.Job{Type: j.Type, Guid: j.Guid})
+	}
+	assertEqual(t, jobs, []recon.Job{
+		{Type: ReconcileRoutingProviders, Guid: "app-id"},
+		{Type: ReconcileInstancesProviders, Guid: "app-id"},
+		{Type: ReconcilePipelineProvider, Guid: "fakeProvider"},
+	})
+}
+
+func TestService_LastSynced(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	d := &documentDatabase{queueDatabase: newQueueDatabase(&db.DatabaseRecorder{}), doc: bson.M{}}
+	s := NewService(d)
+	_ = s.AddAppProvider("provider-a", "A", fakeProvider.AppProvider(nil))
+
+	j, ok := s.AcceptReconcileJob(time.Minute)
+	assertEqual(t, ok, true)
+	assertEqual(t, j, recon.Job{Type: ReconcileAppProvider, Guid: "provider-a", LastUpdated: someTime})
+
+	err := s.RecordSync("provider-a", TypeApps, 20*time.Millisecond, nil)
+	assertNil(t, "no error", err)
+
+	later := someTime.Add(2 * time.Minute)
+	currentTime = func() time.Time {
+		return later
+	}
+	j, ok = s.AcceptReconcileJob(time.Minute)
+	assertEqual(t, ok, true)
+	assertEqual(t, j, recon.Job{
+		Type:        ReconcileAppProvider,
+		Guid:        "provider-a",
+		LastUpdated: later,
+		LastSynced:  someTime.Add(-20 * time.Millisecond),
+	})
+}
+
+func TestService_Definitions(t *testing.T) {
+	definition := Definition{Id: "provider-a", Name: "Provider A", Host: "https://provider-a.com", Credentials: "shared"}
+
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{
+		Recorder: rec,
+		ReturnEach: func(each func(decodable database.Decodable) error) {
+			_ = each(database.DecodableFunc(func(target interface{}) error {
+				*target.(*Definition) = definition
+				return nil
+			}))
+		},
+		Return: func(target interface{}) {
+			if target != nil {
+				*target.(*Definition) = definition
+			}
+		},
+	}
+	s := NewService(d)
+
+	err := s.SaveDefinition(definition)
+	assertNil(t, "there should be no error", err)
+
+	list, err := s.ListDefinitions()
+	assertNil(t, "there should be no error", err)
+	assertEqual(t, list, []Definition{definition})
+
+	res, err := s.GetDefinition("provider-a")
+	assertNil(t, "there should be no error", err)
+	assertEqual(t, res, definition)
+
+	err = s.DeleteDefinition("provider-a")
+	assertNil(t, "there should be no error", err)
+
+	filter := bson.M{"type": "definition", "id": "provider-a"}
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: filter, CreateIfMissing: true, Update: bson.M{
+			"name": "Provider A", "host": "https://provider-a.com", "features": []Type(nil), "credentials": "shared",
+		}},
+		{Collection: Collection, Filter: bson.M{"type": "definition"}, Sort: bson.M{"id": 1}},
+		{Collection: Collection, Filter: filter},
+		{Collection: Collection, Filter: filter},
+	})
+
+	d.Err = database.ErrNotFound
+	_, err = s.GetDefinition("provider-b")
+	assertErr(t, err, ErrNotFound)
+	err = s.DeleteDefinition("provider-b")
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_AcceptReconcileJobFilter(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	rec := &db.DatabaseRecorder{}
+	d := newQueueDatabase(rec)
+	s := NewServiceWithLeases(d, "owner-a", time.Minute)
+	_ = s.AddAppProvider("provider-b", "B", fakeProvider.AppProvider(nil))
+	_ = s.AddAppProvider("provider-a", "A", fakeProvider.AppProvider(nil))
+	_ = s.AddAlertsProvider("provider-a", "A", nil)
+	rec.Records = nil
+
+	// nothing is accepted if no job is due
+	d.Err = database.ErrNotFound
+	_, ok := s.AcceptReconcileJob(2 * time.Minute)
+	assertEqual(t, ok, false)
+
+	d.Err = nil
+	d.Return = func(res interface{}) {
+		*res.(*Provider) = Provider{Data: Data{Id: "provider-a"}, ProviderType: "apps", Attempts: 2}
+	}
+	j, ok := s.(*service).acceptJob(someTime, bson.M{"$or": s.(*service).localJobs()}, bson.M{"$or": bson.A{
+		bson.M{"nextAttempt": nil, "lastUpdated": bson.M{"$lte": someTime.Add(-2 * time.Minute)}},
+		bson.M{"nextAttempt": nil, "lastUpdated": nil},
+	}})
+	assertEqual(t, ok, true)
+	assertEqual(t, j, recon.Job{Type: ReconcileAppProvider, Guid: "provider-a", Attempts: 2})
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: bson.M{
+			"deadLettered": bson.M{"$ne": true},
+			"$and": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"type": "alerts", "id": bson.M{"$in": []string{"provider-a"}}},
+					bson.M{"type": "apps", "id": bson.M{"$in": []string{"provider-a", "provider-b"}}},
+				}},
+				bson.M{"$or": bson.A{
+					bson.M{"nextAttempt": nil, "lastUpdated": bson.M{"$lte": someTime.Add(-2 * time.Minute)}},
+					bson.M{"nextAttempt": nil, "lastUpdated": nil},
+				}},
+				bson.M{"$or": bson.A{bson.M{"leaseExpiry": nil}, bson.M{"leaseExpiry": bson.M{"$lte": someTime}}}},
+			},
+		}, Update: bson.M{
+			"lastUpdated": someTime, "nextAttempt": nil, "leaseOwner": "owner-a", "leaseExpiry": someTime.Add(time.Minute),
+		}},
+	})
+}
+
+func TestService_Retries(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{
+		Recorder: rec,
+		ReturnEach: func(each func(decodable database.Decodable) error) {
+			_ = each(database.DecodableFunc(func(target interface{}) error {
+				*target.(*Provider) = Provider{Data: Data{Id: "provider-a"}, ProviderType: "apps", Attempts: 8, LastError: "some error"}
+				return nil
+			}))
+		},
+	}
+	s := NewService(d)
+	j := recon.Job{Type: ReconcileAppProvider, Guid: "provider-a", Attempts: 2}
+
+	err := s.RetryReconcileJob(j, someTime.Add(time.Minute), someErr)
+	assertNil(t, "there should be no error", err)
+	err = s.DeadLetterReconcileJob(j, someErr)
+	assertNil(t, "there should be no error", err)
+	err = s.SucceedReconcileJob(j)
+	assertNil(t, "there should be no error", err)
+
+	dead, err := s.ListDeadLetters()
+	assertNil(t, "there should be no error", err)
+	assertEqual(t, dead, []recon.Job{{Type: ReconcileAppProvider, Guid: "provider-a", Attempts: 8, LastError: "some error"}})
+
+	err = s.RequeueDeadLetter(ReconcileAppProvider, "provider-a")
+	assertNil(t, "there should be no error", err)
+
+	filter := bson.M{"type": "apps", "id": "provider-a"}
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: filter, Update: bson.M{
+			"attempts": 3, "lastError": "some error", "nextAttempt": someTime.Add(time.Minute),
+		}},
+		{Collection: Collection, Filter: filter, Update: bson.M{
+			"attempts": 3, "lastError": "some error", "nextAttempt": nil, "deadLettered": true,
+		}},
+		{Collection: Collection, Filter: filter, Update: bson.M{
+			"attempts": 0, "lastError": "", "nextAttempt": nil,
+		}},
+		{Collection: Collection, Filter: bson.M{"deadLettered": true}, Sort: bson.M{"id": 1, "type": 1}},
+		{Collection: Collection, Filter: bson.M{"type": "apps", "id": "provider-a", "deadLettered": true}, Update: bson.M{
+			"attempts": 0, "deadLettered": false, "nextAttempt": someTime,
+		}},
+	})
+
+	d.Err = database.ErrNotFound
+	err = s.RetryReconcileJob(recon.Job{Type: ReconcileRoutingProviders, Guid: "app-a"}, someTime, someErr)
+	assertErr(t, err, recon.ErrJobNotFound)
+	err = s.RequeueDeadLetter(ReconcileAppProvider, "provider-b")
+	assertErr(t, err, recon.ErrJobNotFound)
+}
+
+func TestService_RecordSync(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	rec := &db.DatabaseRecorder{}
+	d := &db.RecordingDatabase{
+		Recorder: rec,
+		Return: func(target interface{}) {
+			if h, ok := target.(*Health); ok {
+				*h = Health{Id: "provider-a", Feature: TypeApps, ConsecutiveFailures: 2}
+			}
+		},
+	}
+	s := NewService(d)
+
+	err := s.RecordSync("provider-a", TypeApps, 1500*time.Millisecond, someErr)
+	assertNil(t, "there should be no error", err)
+	err = s.RecordSync("provider-a", TypeApps, 20*time.Millisecond, nil)
+	assertNil(t, "there should be no error", err)
+
+	filter := bson.M{"id": "provider-a", "type": TypeApps}
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: filter},
+		{Collection: Collection, Filter: filter, Update: bson.M{
+			"latencyMillis": int64(1500), "lastFailure": someTime, "lastError": someErr.Error(), "consecutiveFailures": 3,
+		}},
+		{Collection: Collection, Filter: filter},
+		{Collection: Collection, Filter: filter, Update: bson.M{
+			"latencyMillis": int64(20), "lastSuccess": someTime, "consecutiveFailures": 0,
+			"lastSynced": someTime.Add(-20 * time.Millisecond),
+		}},
+	})
+
+	d.Err = database.ErrNotFound
+	err = s.RecordSync("provider-b", TypeApps, 0, nil)
+	assertErr(t, err, ErrNotFound)
+}
+
+func TestService_ListHealth(t *testing.T) {
+	health := Health{Id: "provider-a", Feature: TypeApps, LastSuccess: &someTime}
+
+	rec := &db.DatabaseRecorder{}
+	s := NewService(&db.RecordingDatabase{
+		Recorder: rec,
+		ReturnEach: func(each func(decodable database.Decodable) error) {
+			_ = each(database.DecodableFunc(func(target interface{}) error {
+				*target.(*Health) = health
+				return nil
+			}))
+		},
+	})
+
+	res, err := s.ListHealth()
+	assertNil(t, "there should be no error", err)
+	assertEqual(t, res, []Health{health})
+	assertEqual(t, rec.Records, []db.DatabaseRecord{
+		{Collection: Collection, Filter: bson.M{"type": bson.M{"$ne": "definition"}}, Sort: bson.M{"id": 1, "type": 1}},
+	})
+}
+
 func assertNil(t *testing.T, desc string, a interface{}) {
 	if a != nil {
 		t.Fatal(desc)
@@ -263,3 +850,87 @@ func assertEqual(t *testing.T, a, b interface{}) {
 		t.Fatalf("the two objects mismatch: %s\n", cmp.Diff(a, b))
 	}
 }
+
+// queueDatabase keeps the requests pushed to the request queue in memory, which the recording database can't.
+type queueDatabase struct {
+	*db.RecordingDatabase
+	requests queue.Store
+}
+
+func newQueueDatabase(rec *db.DatabaseRecorder) *queueDatabase {
+	return &queueDatabase{
+		RecordingDatabase: &db.RecordingDatabase{Recorder: rec},
+		requests:          queue.NewMemoryStore(),
+	}
+}
+
+// Upsert pushes the request whose priority and enqueued time the request store passes to the
+// first stage of its update.
+func (d *queueDatabase) Upsert(coll database.Collection, filter bson.M, update interface{}) (bool, error) {
+	if coll != RequestsCollection {
+		return d.RecordingDatabase.Upsert(coll, filter, update)
+	}
+	set := update.(bson.A)[0].(bson.M)["$set"].(bson.M)
+	return d.requests.Push(queue.Item{
+		Key:      filter["key"].(string),
+		Priority: set["priority"].(bson.M)["$max"].(bson.A)[1].(queue.Priority),
+		Enqueued: set["enqueued"].(bson.M)["$min"].(bson.A)[1].(time.Time),
+	}, requestAging)
+}
+
+func (d *queueDatabase) FindOneAndDelete(coll database.Collection, filter bson.M, sort bson.M, res interface{}) error {
+	if coll != RequestsCollection {
+		return d.RecordingDatabase.FindOneAndDelete(coll, filter, sort, res)
+	}
+	item, ok, err := d.requests.PopFirst()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return database.ErrNotFound
+	}
+	*res.(*queue.Item) = item
+	return nil
+}
+
+func (d *queueDatabase) Count(coll database.Collection, filter bson.M) (int, error) {
+	if coll != RequestsCollection {
+		return d.RecordingDatabase.Count(coll, filter)
+	}
+	return d.requests.Len()
+}
+
+// documentDatabase holds a single provider registration, which it returns after applying
+// updates to it, as Mongo does.
+type documentDatabase struct {
+	*queueDatabase
+	doc bson.M
+}
+
+func (d *documentDatabase) FindOne(coll database.Collection, filter interface{}, res interface{}) error {
+	if coll != Collection {
+		return d.queueDatabase.FindOne(coll, filter, res)
+	}
+	return d.decode(res)
+}
+
+func (d *documentDatabase) UpdateOne(coll database.Collection, filter bson.M, createIfMissing bool, update interface{}, res interface{}) error {
+	if coll != Collection {
+		return d.queueDatabase.UpdateOne(coll, filter, createIfMissing, update, res)
+	}
+	for k, v := range update.(bson.M) {
+		d.doc[k] = v
+	}
+	if res == nil {
+		return nil
+	}
+	return d.decode(res)
+}
+
+func (d *documentDatabase) decode(res interface{}) error {
+	b, err := bson.Marshal(d.doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(b, res)
+}
@@ -1,40 +1,74 @@
 package reconciler
 
 import (
+	"context"
 	"errors"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/service"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/rs/zerolog/log"
 	"time"
 )
 
-func NewReconciler(core service.Core, olderThan time.Duration) recon.Reconciler {
+// NewReconciler creates the reconciler of the core. Every job is cancelled once it
+// runs longer than the given timeout, which is passed on to the providers as deadline.
+func NewReconciler(core service.Core, olderThan time.Duration, timeout time.Duration) recon.Reconciler {
 	if olderThan == 0 {
 		olderThan = time.Minute
 	}
+	if timeout == 0 {
+		timeout = time.Minute
+	}
 
 	r := &reconciler{
 		Reconciler: recon.NewReconciler(core.Providers, olderThan),
 		core:       core,
+		timeout:    timeout,
 	}
 
-	r.Handler(provider.ReconcileAppProvider, r.reconcileAppProvider)
-	r.Handler(provider.ReconcileRoutingProviders, r.reconcileAppRouting)
-	r.Handler(provider.ReconcileInstancesProviders, r.reconcileAppInstances)
+	r.Handler(provider.ReconcileAppProvider, r.withHealth(r.withTimeout(r.reconcileAppProvider)))
+	r.Handler(provider.ReconcileRoutingProviders, r.withTimeout(r.reconcileAppRouting))
+	r.Handler(provider.ReconcileInstancesProviders, r.withTimeout(r.reconcileAppInstances))
 
-	r.Handler(provider.ReconcilePipelineProvider, r.reconcilePipelineProvider)
-	r.Handler(provider.ReconcileGroupProvider, r.reconcileGroupProvider)
+	r.Handler(provider.ReconcilePipelineProvider, r.withHealth(r.withTimeout(r.reconcilePipelineProvider)))
+	r.Handler(provider.ReconcileGroupProvider, r.withHealth(r.withTimeout(r.reconcileGroupProvider)))
+	r.Handler(provider.ReconcileAlertsProvider, r.withHealth(r.withTimeout(r.reconcileAlertsProvider)))
+	r.Handler(provider.ReconcileTopologyProvider, r.withHealth(r.withTimeout(r.reconcileTopologyProvider)))
 
 	return r
 }
 
 type reconciler struct {
 	recon.Reconciler
-	core service.Core
+	core    service.Core
+	timeout time.Duration
+}
+
+func (r *reconciler) withTimeout(f func(ctx context.Context, j recon.Job) error) recon.ReconcileHandler {
+	return func(j recon.Job) error {
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+		return f(ctx, j)
+	}
 }
 
-func (r *reconciler) reconcileAppProvider(j recon.Job) error {
+// withHealth records the outcome and latency of jobs reconciling a single provider, whose
+// type is the feature they reconcile.
+func (r *reconciler) withHealth(f recon.ReconcileHandler) recon.ReconcileHandler {
+	return func(j recon.Job) error {
+		start := time.Now()
+		err := f(j)
+
+		recordErr := r.core.Providers.RecordSync(j.Guid, provider.Type(j.Type), time.Since(start), err)
+		if recordErr != nil && !errors.Is(recordErr, provider.ErrNotFound) {
+			log.Error().Err(recordErr).Str("provider", j.Guid).Msg("error recording provider health")
+		}
+		return err
+	}
+}
+
+func (r *reconciler) reconcileAppProvider(ctx context.Context, j recon.Job) error {
 	p, err := r.core.Providers.GetAppProvider(j.Guid)
 	if errors.Is(err, provider.ErrNotFound) {
 		return r.core.Providers.DeleteAppProvider(j.Guid)
@@ -43,15 +77,61 @@ func (r *reconciler) reconcileAppProvider(j recon.Job) error {
 		return err
 	}
 
-	apps, err := p.ListApps()
+	u, ok := p.(sdk.AppUpdatesProvider)
+	if !ok {
+		apps, err := p.ListApps(ctx)
+		if err != nil {
+			return err
+		}
+		return r.core.Apps.UpdateApps(j.Guid, apps)
+	}
+
+	// providers drop their tombstones after a while, so deletions of older updates might be missed
+	if j.LastSynced.IsZero() || currentTime().Sub(j.LastSynced) > sdk.MaxUpdatesAge {
+		apps, err := listAllApps(ctx, u)
+		if err != nil {
+			return err
+		}
+		return r.core.Apps.UpdateApps(j.Guid, apps)
+	}
+
+	updates, err := u.ListAppUpdates(ctx, j.LastSynced)
+	if err != nil {
+		return err
+	}
+
+	err = r.core.Apps.UpsertApps(j.Guid, updates.Apps)
 	if err != nil {
 		return err
 	}
 
-	return r.core.Apps.UpdateApps(j.Guid, apps)
+	deleted := make([]string, 0, len(updates.Deleted))
+	for _, tombstone := range updates.Deleted {
+		deleted = append(deleted, tombstone.Id)
+	}
+	return r.core.Apps.DeleteApps(j.Guid, deleted)
+}
+
+var currentTime = time.Now
+
+// appsPerPage is the page size in which apps are fetched from providers serving app updates.
+const appsPerPage = 500
+
+func listAllApps(ctx context.Context, p sdk.AppUpdatesProvider) ([]sdk.App, error) {
+	var res []sdk.App
+	for page := 0; ; page++ {
+		appPage, err := p.ListAppsPage(ctx, appsPerPage, page)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, appPage.Apps...)
+		if page+1 >= appPage.TotalPages {
+			return res, nil
+		}
+	}
 }
 
-func (r *reconciler) reconcileAppRouting(j recon.Job) error {
+func (r *reconciler) reconcileAppRouting(ctx context.Context, j recon.Job) error {
 	p, err := r.core.Providers.GetRoutingProviders()
 	if err != nil {
 		return err
@@ -59,7 +139,7 @@ func (r *reconciler) reconcileAppRouting(j recon.Job) error {
 
 	routing := sdk.AppRouting{}
 	for _, routingProvider := range p {
-		result, err := routingProvider.GetAppRouting(j.Guid)
+		result, err := routingProvider.GetAppRouting(ctx, j.Guid)
 		if err != nil {
 			return err
 		}
@@ -74,7 +154,7 @@ func (r *reconciler) reconcileAppRouting(j recon.Job) error {
 	return nil
 }
 
-func (r *reconciler) reconcileAppInstances(j recon.Job) error {
+func (r *reconciler) reconcileAppInstances(ctx context.Context, j recon.Job) error {
 	p, err := r.core.Providers.GetInstancesProviders()
 	if err != nil {
 		return err
@@ -82,7 +162,7 @@ func (r *reconciler) reconcileAppInstances(j recon.Job) error {
 
 	instances := sdk.AppInstances{}
 	for _, routingProvider := range p {
-		result, err := routingProvider.GetAppInstances(j.Guid)
+		result, err := routingProvider.GetAppInstances(ctx, j.Guid)
 		if err != nil {
 			return err
 		}
@@ -97,7 +177,7 @@ func (r *reconciler) reconcileAppInstances(j recon.Job) error {
 	return nil
 }
 
-func (r *reconciler) reconcileGroupProvider(j recon.Job) error {
+func (r *reconciler) reconcileGroupProvider(ctx context.Context, j recon.Job) error {
 	p, err := r.core.Providers.GetGroupProvider(j.Guid)
 	if errors.Is(err, provider.ErrNotFound) {
 		return r.core.Providers.DeleteGroupProvider(j.Guid)
@@ -106,7 +186,7 @@ func (r *reconciler) reconcileGroupProvider(j recon.Job) error {
 		return err
 	}
 
-	groups, err := p.ListGroups()
+	groups, err := p.ListGroups(ctx)
 	if err != nil {
 		return err
 	}
@@ -114,7 +194,7 @@ func (r *reconciler) reconcileGroupProvider(j recon.Job) error {
 	return r.core.Groups.UpdateGroups(j.Guid, groups)
 }
 
-func (r *reconciler) reconcilePipelineProvider(j recon.Job) error {
+func (r *reconciler) reconcilePipelineProvider(ctx context.Context, j recon.Job) error {
 	p, err := r.core.Providers.GetPipelineProvider(j.Guid)
 	if errors.Is(err, provider.ErrNotFound) {
 		return r.core.Providers.DeletePipelineProvider(j.Guid)
@@ -123,12 +203,15 @@ func (r *reconciler) reconcilePipelineProvider(j recon.Job) error {
 		return err
 	}
 
-	pipelines, err := p.ListPipelines()
+	pipelines, err := p.ListPipelines(ctx)
 	if err != nil {
 		return err
 	}
 
-	updates, err := p.ListUpdates(j.LastUpdated)
+	updates, err := p.ListUpdates(ctx, j.LastSynced)
+	if err != nil {
+		return err
+	}
 
 	err = r.core.Pipelines.UpdatePipelines(j.Guid, pipelines)
 	if err != nil {
@@ -146,3 +229,37 @@ func (r *reconciler) reconcilePipelineProvider(j recon.Job) error {
 
 	return nil
 }
+
+func (r *reconciler) reconcileAlertsProvider(ctx context.Context, j recon.Job) error {
+	p, err := r.core.Providers.GetAlertsProvider(j.Guid)
+	if errors.Is(err, provider.ErrNotFound) {
+		return r.core.Providers.DeleteAlertsProvider(j.Guid)
+	}
+	if err != nil {
+		return err
+	}
+
+	alerts, err := p.ListAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.core.Alerts.UpdateAlerts(j.Guid, alerts)
+}
+
+func (r *reconciler) reconcileTopologyProvider(ctx context.Context, j recon.Job) error {
+	p, err := r.core.Providers.GetTopologyProvider(j.Guid)
+	if errors.Is(err, provider.ErrNotFound) {
+		return r.core.Providers.DeleteTopologyProvider(j.Guid)
+	}
+	if err != nil {
+		return err
+	}
+
+	topology, err := p.GetTopology(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.core.Topology.UpdateTopology(j.Guid, topology)
+}
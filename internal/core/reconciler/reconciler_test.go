@@ -1,14 +1,18 @@
 package reconciler
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/alerts"
 	"github.com/joscha-alisch/dyve/internal/core/apps"
 	"github.com/joscha-alisch/dyve/internal/core/fakes"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeProvider"
 	"github.com/joscha-alisch/dyve/internal/core/pipelines"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/service"
+	"github.com/joscha-alisch/dyve/internal/core/topology"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"testing"
@@ -16,6 +20,7 @@ import (
 )
 
 var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+var someErr = errors.New("some error")
 
 func TestName(t *testing.T) {
 	tests := []struct {
@@ -54,7 +59,8 @@ func TestName(t *testing.T) {
 			desc: "adds pipelines, runs and versions", job: recon.Job{
 				Type:        provider.ReconcilePipelineProvider,
 				Guid:        "pipeline-provider",
-				LastUpdated: someTime,
+				LastUpdated: someTime.Add(time.Minute),
+				LastSynced:  someTime,
 			}, providerId: "pipeline-provider", pipelineProvider: fakeProvider.PipelineProvider([]sdk.Pipeline{
 				{Id: "pipeline-a", Name: "pipeline-a"},
 				{Id: "pipeline-b", Name: "pipeline-b"},
@@ -71,6 +77,21 @@ func TestName(t *testing.T) {
 			},
 			}, expectedWorked: true, recordedTime: someTime,
 		},
+		{
+			desc: "keeps pipelines if updates can't be listed", job: recon.Job{
+				Type:        provider.ReconcilePipelineProvider,
+				Guid:        "pipeline-provider",
+				LastUpdated: someTime.Add(time.Minute),
+				LastSynced:  someTime,
+			}, providerId: "pipeline-provider", pipelineProvider: &fakeProvider.Provider{
+				Pipelines: []sdk.Pipeline{{Id: "pipeline-b", Name: "pipeline-b"}},
+				Err:       someErr,
+			}, pipelinesBefore: &fakes.MappingPipelinesService{Pipelines: map[string]pipelines.Pipeline{
+				"pipeline-a": {ProviderId: "pipeline-provider", Pipeline: sdk.Pipeline{Id: "pipeline-a", Name: "pipeline-a"}},
+			}}, pipelinesAfter: &fakes.MappingPipelinesService{Pipelines: map[string]pipelines.Pipeline{
+				"pipeline-a": {ProviderId: "pipeline-provider", Pipeline: sdk.Pipeline{Id: "pipeline-a", Name: "pipeline-a"}},
+			}}, expectedErr: someErr, expectedWorked: true, recordedTime: someTime,
+		},
 		{
 			desc: "adds routes", job: recon.Job{
 				Type:        provider.ReconcileRoutingProviders,
@@ -117,10 +138,10 @@ func TestName(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			providers := &fakes.ProviderService{
 				Job:                &test.job,
-				AppProviders:       map[string]sdk.AppProvider{},
-				PipelineProviders:  map[string]sdk.PipelineProvider{},
-				RoutingProviders:   map[string]sdk.RoutingProvider{},
-				InstancesProviders: map[string]sdk.InstancesProvider{},
+				AppProviders:       map[string]sdk.AppProviderContext{},
+				PipelineProviders:  map[string]sdk.PipelineProviderContext{},
+				RoutingProviders:   map[string]sdk.RoutingProviderContext{},
+				InstancesProviders: map[string]sdk.InstancesProviderContext{},
 			}
 			if test.appProvider != nil {
 				providers.AppProviders[test.providerId] = test.appProvider
@@ -154,7 +175,7 @@ func TestName(t *testing.T) {
 				Routing:   test.routesBefore,
 				Instances: test.instancesBefore,
 				Pipelines: test.pipelinesBefore,
-			}, 1*time.Minute)
+			}, 1*time.Minute, 1*time.Minute)
 			worked, err := r.Run()
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err %v\n   got err %v", test.expectedErr, err)
@@ -187,3 +208,304 @@ func TestName(t *testing.T) {
 	}
 
 }
+
+func TestReconcileAppUpdates(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime.Add(time.Minute)
+	}
+	defer func() { currentTime = time.Now }()
+
+	var manyApps []sdk.App
+	after := &fakes.MappingAppsService{Apps: map[string]apps.App{}}
+	for i := 0; i < 1200; i++ {
+		app := sdk.App{Id: fmt.Sprintf("app-%d", i)}
+		manyApps = append(manyApps, app)
+		after.Apps[app.Id] = apps.App{ProviderId: "app-provider", App: app}
+	}
+
+	tests := []struct {
+		desc         string
+		job          recon.Job
+		provider     *fakeProvider.UpdatesProvider
+		appsBefore   *fakes.MappingAppsService
+		appsAfter    *fakes.MappingAppsService
+		recordedTime time.Time
+		expectedErr  error
+	}{
+		{
+			desc:       "lists all pages on first reconciliation",
+			job:        recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider", LastUpdated: someTime},
+			provider:   fakeProvider.AppUpdatesProvider(manyApps, sdk.AppUpdates{}),
+			appsBefore: &fakes.MappingAppsService{Apps: map[string]apps.App{}},
+			appsAfter:  after,
+		},
+		{
+			desc:     "lists all pages if last reconciliation is too long ago",
+			job:      recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider", LastUpdated: someTime, LastSynced: someTime.Add(-sdk.MaxUpdatesAge)},
+			provider: fakeProvider.AppUpdatesProvider(manyApps, sdk.AppUpdates{}),
+			appsBefore: &fakes.MappingAppsService{Apps: map[string]apps.App{
+				"app-gone": {ProviderId: "app-provider", App: sdk.App{Id: "app-gone"}},
+			}},
+			appsAfter: after,
+		},
+		{
+			desc: "applies updates since last reconciliation",
+			job:  recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider", LastUpdated: someTime.Add(time.Minute), LastSynced: someTime},
+			provider: fakeProvider.AppUpdatesProvider(nil, sdk.AppUpdates{
+				Apps:    []sdk.App{{Id: "app-a", Name: "changed"}, {Id: "app-c", Name: "new"}},
+				Deleted: []sdk.AppTombstone{{Id: "app-b", Deleted: someTime}},
+			}),
+			appsBefore: &fakes.MappingAppsService{Apps: map[string]apps.App{
+				"app-a": {ProviderId: "app-provider", App: sdk.App{Id: "app-a", Name: "app-a"}},
+				"app-b": {ProviderId: "app-provider", App: sdk.App{Id: "app-b", Name: "app-b"}},
+				"app-d": {ProviderId: "app-provider", App: sdk.App{Id: "app-d", Name: "app-d"}},
+			}},
+			appsAfter: &fakes.MappingAppsService{Apps: map[string]apps.App{
+				"app-a": {ProviderId: "app-provider", App: sdk.App{Id: "app-a", Name: "changed"}},
+				"app-c": {ProviderId: "app-provider", App: sdk.App{Id: "app-c", Name: "new"}},
+				"app-d": {ProviderId: "app-provider", App: sdk.App{Id: "app-d", Name: "app-d"}},
+			}},
+			recordedTime: someTime,
+		},
+		{
+			desc: "keeps apps on error",
+			job:  recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider", LastUpdated: someTime.Add(time.Minute), LastSynced: someTime},
+			provider: &fakeProvider.UpdatesProvider{
+				Provider: fakeProvider.Provider{Err: someErr},
+			},
+			appsBefore: &fakes.MappingAppsService{Apps: map[string]apps.App{
+				"app-a": {ProviderId: "app-provider", App: sdk.App{Id: "app-a", Name: "app-a"}},
+			}},
+			appsAfter: &fakes.MappingAppsService{Apps: map[string]apps.App{
+				"app-a": {ProviderId: "app-provider", App: sdk.App{Id: "app-a", Name: "app-a"}},
+			}},
+			recordedTime: someTime,
+			expectedErr:  someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{
+				Job:          &test.job,
+				AppProviders: map[string]sdk.AppProviderContext{"app-provider": test.provider},
+			}
+
+			r := NewReconciler(service.Core{
+				Apps:      test.appsBefore,
+				Providers: providers,
+			}, 1*time.Minute, 1*time.Minute)
+
+			_, err := r.Run()
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err %v\n   got err %v", test.expectedErr, err)
+			}
+
+			if !test.provider.RecordedTime.Equal(test.recordedTime) {
+				tt.Errorf("\nwanted time: %v\n   got time: %v", test.recordedTime, test.provider.RecordedTime)
+			}
+
+			if !cmp.Equal(test.appsAfter.Apps, test.appsBefore.Apps) {
+				tt.Errorf("\napp service states don't match: \n%s\n", cmp.Diff(test.appsAfter.Apps, test.appsBefore.Apps))
+			}
+		})
+	}
+}
+
+func TestReconcileAlerts(t *testing.T) {
+	otherAlert := alerts.Alert{ProviderId: "other-provider", Alert: sdk.Alert{Id: "alert-c", AppId: "app-b"}}
+
+	tests := []struct {
+		desc         string
+		provider     *fakeProvider.Provider
+		alertsBefore []alerts.Alert
+		alertsAfter  []alerts.Alert
+		expectedErr  error
+	}{
+		{
+			desc: "replaces alerts of provider",
+			provider: fakeProvider.AlertsProvider([]sdk.Alert{
+				{Id: "alert-b", AppId: "app-a", Severity: sdk.AlertSeverityCritical, Started: someTime},
+			}),
+			alertsBefore: []alerts.Alert{
+				{ProviderId: "alerts-provider", Alert: sdk.Alert{Id: "alert-a", AppId: "app-a"}},
+				otherAlert,
+			},
+			alertsAfter: []alerts.Alert{
+				otherAlert,
+				{ProviderId: "alerts-provider", Alert: sdk.Alert{Id: "alert-b", AppId: "app-a", Severity: sdk.AlertSeverityCritical, Started: someTime}},
+			},
+		},
+		{
+			desc:     "keeps alerts on error",
+			provider: fakeProvider.NewErrProvider(someErr),
+			alertsBefore: []alerts.Alert{
+				{ProviderId: "alerts-provider", Alert: sdk.Alert{Id: "alert-a", AppId: "app-a"}},
+			},
+			alertsAfter: []alerts.Alert{
+				{ProviderId: "alerts-provider", Alert: sdk.Alert{Id: "alert-a", AppId: "app-a"}},
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{
+				Job:             &recon.Job{Type: provider.ReconcileAlertsProvider, Guid: "alerts-provider"},
+				AlertsProviders: map[string]sdk.AlertsProvider{"alerts-provider": test.provider},
+			}
+			alertService := &fakes.MappingAlertsService{Alerts: test.alertsBefore}
+
+			r := NewReconciler(service.Core{
+				Alerts:    alertService,
+				Providers: providers,
+			}, 1*time.Minute, 1*time.Minute)
+
+			_, err := r.Run()
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err %v\n   got err %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.alertsAfter, alertService.Alerts) {
+				tt.Errorf("\nalert service states don't match: \n%s\n", cmp.Diff(test.alertsAfter, alertService.Alerts))
+			}
+		})
+	}
+}
+
+func TestReconcileTopology(t *testing.T) {
+	otherDependency := topology.Dependency{ProviderId: "other-provider", Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetApp, TargetId: "app-a"}}
+
+	tests := []struct {
+		desc           string
+		provider       *fakeProvider.Provider
+		topologyBefore topology.Topology
+		topologyAfter  topology.Topology
+		expectedErr    error
+	}{
+		{
+			desc: "replaces topology of provider",
+			provider: fakeProvider.TopologyProvider(sdk.Topology{
+				Services:     []sdk.BackingService{{Id: "service-b", Name: "redis"}},
+				Dependencies: []sdk.Dependency{{AppId: "app-a", Target: sdk.DependencyTargetService, TargetId: "service-b"}},
+			}),
+			topologyBefore: topology.Topology{
+				Services: []topology.BackingService{
+					{ProviderId: "topology-provider", BackingService: sdk.BackingService{Id: "service-a", Name: "postgres"}},
+				},
+				Dependencies: []topology.Dependency{
+					{ProviderId: "topology-provider", Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetService, TargetId: "service-a"}},
+					otherDependency,
+				},
+			},
+			topologyAfter: topology.Topology{
+				Services: []topology.BackingService{
+					{ProviderId: "topology-provider", BackingService: sdk.BackingService{Id: "service-b", Name: "redis"}},
+				},
+				Dependencies: []topology.Dependency{
+					otherDependency,
+					{ProviderId: "topology-provider", Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetService, TargetId: "service-b"}},
+				},
+			},
+		},
+		{
+			desc:     "keeps topology on error",
+			provider: fakeProvider.NewErrProvider(someErr),
+			topologyBefore: topology.Topology{
+				Dependencies: []topology.Dependency{otherDependency},
+			},
+			topologyAfter: topology.Topology{
+				Dependencies: []topology.Dependency{otherDependency},
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{
+				Job:               &recon.Job{Type: provider.ReconcileTopologyProvider, Guid: "topology-provider"},
+				TopologyProviders: map[string]sdk.TopologyProvider{"topology-provider": test.provider},
+			}
+			topologyService := &fakes.MappingTopologyService{Topology: test.topologyBefore}
+
+			r := NewReconciler(service.Core{
+				Topology:  topologyService,
+				Providers: providers,
+			}, 1*time.Minute, 1*time.Minute)
+
+			_, err := r.Run()
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err %v\n   got err %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.topologyAfter, topologyService.Topology) {
+				tt.Errorf("\ntopology service states don't match: \n%s\n", cmp.Diff(test.topologyAfter, topologyService.Topology))
+			}
+		})
+	}
+}
+
+func TestReconcilerTimeout(t *testing.T) {
+	providers := &fakes.ProviderService{
+		Job:          &recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider"},
+		AppProviders: map[string]sdk.AppProviderContext{"app-provider": blockingAppProvider{}},
+	}
+
+	r := NewReconciler(service.Core{
+		Apps:      &fakes.MappingAppsService{Apps: map[string]apps.App{}},
+		Providers: providers,
+	}, 1*time.Minute, 10*time.Millisecond)
+
+	_, err := r.Run()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("\nwanted err %v\n   got err %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestReconcileHealth(t *testing.T) {
+	tests := []struct {
+		desc          string
+		job           recon.Job
+		app           sdk.AppProviderContext
+		expectedSyncs []string
+	}{
+		{desc: "records successful sync", job: recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider"},
+			app: fakeProvider.AppProvider(nil), expectedSyncs: []string{"apps/app-provider: ok"}},
+		{desc: "records failed sync", job: recon.Job{Type: provider.ReconcileAppProvider, Guid: "app-provider"},
+			app: blockingAppProvider{}, expectedSyncs: []string{"apps/app-provider: context deadline exceeded"}},
+		{desc: "doesn't record per app jobs", job: recon.Job{Type: provider.ReconcileRoutingProviders, Guid: "app-a"},
+			app: fakeProvider.AppProvider(nil)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			providers := &fakes.ProviderService{
+				Job:              &test.job,
+				AppProviders:     map[string]sdk.AppProviderContext{"app-provider": test.app},
+				RoutingProviders: map[string]sdk.RoutingProviderContext{},
+			}
+
+			r := NewReconciler(service.Core{
+				Apps:      &fakes.MappingAppsService{Apps: map[string]apps.App{}},
+				Routing:   &fakes.MappingRoutesService{Routes: map[string]sdk.AppRouting{}},
+				Providers: providers,
+			}, 1*time.Minute, 10*time.Millisecond)
+
+			_, _ = r.Run()
+			if !cmp.Equal(test.expectedSyncs, providers.Syncs) {
+				tt.Errorf("\ndiff between syncs: \n%s\n", cmp.Diff(test.expectedSyncs, providers.Syncs))
+			}
+		})
+	}
+}
+
+type blockingAppProvider struct {
+	sdk.AppProviderContext
+}
+
+func (blockingAppProvider) ListApps(ctx context.Context) ([]sdk.App, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
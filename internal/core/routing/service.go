@@ -4,11 +4,21 @@ import (
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"go.mongodb.org/mongo-driver/bson"
+	"sort"
+	"time"
 )
 
 type Service interface {
 	GetRoutes(app string) (sdk.AppRouting, error)
 	UpdateRoutes(app string, routes sdk.AppRouting) error
+	// ListExpiringRoutes returns the routes of all apps whose certificates expire before the
+	// given time, the ones expiring first at the top.
+	ListExpiringRoutes(before time.Time) ([]ExpiringRoute, error)
+}
+
+type ExpiringRoute struct {
+	AppId string       `json:"appId"`
+	Route sdk.AppRoute `json:"route"`
 }
 
 const Collection = "routing"
@@ -41,3 +51,31 @@ func (s *service) UpdateRoutes(app string, routes sdk.AppRouting) error {
 		RouteData: routes,
 	}, nil)
 }
+
+func (s *service) ListExpiringRoutes(before time.Time) ([]ExpiringRoute, error) {
+	var res []ExpiringRoute
+	err := s.db.FindMany(Collection, bson.M{
+		"routedata.routes.tls.expires": bson.M{"$lt": before},
+	}, func(c database.Decodable) error {
+		d := routeData{}
+		err := c.Decode(&d)
+		if err != nil {
+			return err
+		}
+
+		for _, route := range d.RouteData.Routes {
+			if route.CertificateExpiresBefore(before) {
+				res = append(res, ExpiringRoute{AppId: d.Id, Route: route})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Route.Tls.Expires.Before(*res[j].Route.Tls.Expires)
+	})
+	return res, nil
+}
@@ -3,10 +3,12 @@ package routing
 import (
 	"errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"go.mongodb.org/mongo-driver/bson"
 	"testing"
+	"time"
 )
 
 var someRouting = sdk.AppRouting{
@@ -19,6 +21,7 @@ var someRouting = sdk.AppRouting{
 	},
 }
 var someErr = errors.New("some error")
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
 
 func TestService_GetRouting(t *testing.T) {
 	tests := []struct {
@@ -129,3 +132,77 @@ func TestService_UpdateRouting(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ListExpiringRoutes(t *testing.T) {
+	soon := someTime.Add(24 * time.Hour)
+	sooner := someTime.Add(time.Hour)
+	later := someTime.Add(90 * 24 * time.Hour)
+
+	tests := []struct {
+		desc        string
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expected    []ExpiringRoute
+		expectedErr error
+	}{
+		{
+			desc: "lists routes with expiring certificates",
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {
+					data := []routeData{
+						{Id: "app-a", RouteData: sdk.AppRouting{Routes: sdk.AppRoutes{
+							{Host: "a", Tls: &sdk.RouteTls{Enabled: true, Expires: &soon}},
+							{Host: "b", Tls: &sdk.RouteTls{Enabled: true, Expires: &later}},
+							{Host: "c"},
+						}}},
+						{Id: "app-b", RouteData: sdk.AppRouting{Routes: sdk.AppRoutes{
+							{Host: "d", Tls: &sdk.RouteTls{Enabled: true, Expires: &sooner}},
+						}}},
+					}
+					for _, d := range data {
+						d := d
+						_ = each(database.DecodableFunc(func(target interface{}) error {
+							*target.(*routeData) = d
+							return nil
+						}))
+					}
+				},
+			},
+			expected: []ExpiringRoute{
+				{AppId: "app-b", Route: sdk.AppRoute{Host: "d", Tls: &sdk.RouteTls{Enabled: true, Expires: &sooner}}},
+				{AppId: "app-a", Route: sdk.AppRoute{Host: "a", Tls: &sdk.RouteTls{Enabled: true, Expires: &soon}}},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "routing",
+				Filter:     bson.M{"routedata.routes.tls.expires": bson.M{"$lt": someTime.Add(30 * 24 * time.Hour)}},
+			}},
+		},
+		{
+			desc: "error while listing routes",
+			db: &db.RecordingDatabase{
+				Err: someErr,
+			},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db)
+			res, err := s.ListExpiringRoutes(someTime.Add(30 * 24 * time.Hour))
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("results mismatch: %s\n", cmp.Diff(test.expected, res))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
@@ -1,13 +1,16 @@
 package service
 
 import (
+	"github.com/joscha-alisch/dyve/internal/core/alerts"
 	"github.com/joscha-alisch/dyve/internal/core/apps"
 	"github.com/joscha-alisch/dyve/internal/core/groups"
 	"github.com/joscha-alisch/dyve/internal/core/instances"
+	"github.com/joscha-alisch/dyve/internal/core/metrics"
 	"github.com/joscha-alisch/dyve/internal/core/pipelines"
 	"github.com/joscha-alisch/dyve/internal/core/provider"
 	"github.com/joscha-alisch/dyve/internal/core/routing"
 	"github.com/joscha-alisch/dyve/internal/core/teams"
+	"github.com/joscha-alisch/dyve/internal/core/topology"
 )
 
 type Core struct {
@@ -18,4 +21,7 @@ type Core struct {
 	Pipelines pipelines.Service
 	Routing   routing.Service
 	Instances instances.Service
+	Metrics   metrics.Service
+	Alerts    alerts.Service
+	Topology  topology.Service
 }
@@ -17,23 +17,38 @@ type Service interface {
 	DeleteTeam(id string) error
 	CreateTeam(id string, data TeamSettings) error
 	UpdateTeam(id string, data TeamSettings) error
+	// TeamsForGroups returns the teams the groups have access to, including the access of the
+	// groups they are nested in.
 	TeamsForGroups(groups []string) (ByAccess, error)
 }
 
-func NewService(db database.Database) Service {
+// GroupHierarchy resolves the groups that groups are nested in. It is implemented by the
+// groups service.
+type GroupHierarchy interface {
+	WithAncestors(groups []string) ([]string, error)
+}
+
+func NewService(db database.Database, groups GroupHierarchy) Service {
 	return &service{
-		db: db,
+		db:     db,
+		groups: groups,
 	}
 }
 
 type service struct {
-	db database.Database
+	db     database.Database
+	groups GroupHierarchy
 }
 
 func (s *service) TeamsForGroups(groups []string) (ByAccess, error) {
 	res := ByAccess{}
 
-	err := s.db.FindMany(Collection, bson.M{
+	groups, err := s.groups.WithAncestors(groups)
+	if err != nil {
+		return res, err
+	}
+
+	err = s.db.FindMany(Collection, bson.M{
 		"$or": []bson.M{
 			{"access.admin": bson.M{"$in": groups}},
 			{"access.member": bson.M{"$in": groups}},
@@ -5,6 +5,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/internal/core/database"
 	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/fakeGroups"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"go.mongodb.org/mongo-driver/bson"
 	"testing"
@@ -68,7 +69,7 @@ func TestService_GetTeam(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, nil)
 			res, err := s.GetTeam(test.team)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -121,7 +122,7 @@ func TestService_UpdateTeam(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, nil)
 			err := s.UpdateTeam(test.team, test.data)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -168,7 +169,7 @@ func TestService_DeleteTeam(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, nil)
 			err := s.DeleteTeam(test.team)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -216,7 +217,7 @@ func TestService_CreateTeam(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, nil)
 			err := s.CreateTeam(test.team, test.data)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -280,7 +281,7 @@ func TestService_ListTeamsPaginated(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, nil)
 			res, err := s.ListTeamsPaginated(test.perPage, test.page)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -302,6 +303,7 @@ func TestService_TeamsForGroups(t *testing.T) {
 	tests := []struct {
 		desc        string
 		groups      []string
+		ancestors   map[string][]string
 		db          *db.RecordingDatabase
 		recorded    []db.DatabaseRecord
 		expected    ByAccess
@@ -348,6 +350,34 @@ func TestService_TeamsForGroups(t *testing.T) {
 				},
 			}},
 		},
+		{
+			desc:      "lists teams of parent groups",
+			groups:    []string{"child"},
+			ancestors: map[string][]string{"child": {"parent"}},
+			db: &db.RecordingDatabase{
+				ReturnEach: func(each func(decodable database.Decodable) error) {
+					_ = each(database.DecodableFunc(func(target interface{}) error {
+						*target.(*Team) = Team{Id: "a", TeamSettings: TeamSettings{Access: AccessGroups{Member: []string{"parent"}}}}
+						return nil
+					}))
+				},
+			},
+			expected: ByAccess{
+				Member: []Team{
+					{Id: "a", TeamSettings: TeamSettings{Access: AccessGroups{Member: []string{"parent"}}}},
+				},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "teams",
+				Filter: bson.M{
+					"$or": []bson.M{
+						{"access.admin": bson.M{"$in": []string{"child", "parent"}}},
+						{"access.member": bson.M{"$in": []string{"child", "parent"}}},
+						{"access.viewer": bson.M{"$in": []string{"child", "parent"}}},
+					},
+				},
+			}},
+		},
 		{
 			desc:   "error while listing teams",
 			groups: []string{"a"},
@@ -363,7 +393,7 @@ func TestService_TeamsForGroups(t *testing.T) {
 		t.Run(test.desc, func(tt *testing.T) {
 			recorder := &db.DatabaseRecorder{}
 			test.db.Recorder = recorder
-			s := NewService(test.db)
+			s := NewService(test.db, &fakeGroups.RecordingGroupsService{Ancestors: test.ancestors})
 			res, err := s.TeamsForGroups(test.groups)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
@@ -380,3 +410,17 @@ func TestService_TeamsForGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestByAccess_CanOperate(t *testing.T) {
+	b := ByAccess{
+		Admin:  []Team{{Id: "a"}},
+		Member: []Team{{Id: "b"}},
+		Viewer: []Team{{Id: "c"}},
+	}
+
+	for team, expected := range map[string]bool{"a": true, "b": true, "c": false, "d": false, "": false} {
+		if b.CanOperate(team) != expected {
+			t.Errorf("team '%s': wanted %v", team, expected)
+		}
+	}
+}
@@ -31,3 +31,19 @@ type ByAccess struct {
 	Member []Team
 	Viewer []Team
 }
+
+// CanOperate reports whether the teams include the team with admin or member access,
+// which is needed to change the state of its apps and pipelines.
+func (b ByAccess) CanOperate(teamId string) bool {
+	for _, t := range b.Admin {
+		if t.Id == teamId {
+			return true
+		}
+	}
+	for _, t := range b.Member {
+		if t.Id == teamId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package topology
+
+import (
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	ServicesCollection     database.Collection = "services"
+	DependenciesCollection database.Collection = "dependencies"
+)
+
+type Service interface {
+	// UpdateTopology replaces the backing services and dependencies reported by the provider.
+	UpdateTopology(providerId string, topology sdk.Topology) error
+	// GetAppDependencies returns the dependencies from and to the app together with the backing services it uses.
+	GetAppDependencies(app string) (Topology, error)
+	// GetTopology returns the whole dependency graph.
+	GetTopology() (Topology, error)
+}
+
+func NewService(db database.Database) Service {
+	return &service{
+		db: db,
+	}
+}
+
+type service struct {
+	db database.Database
+}
+
+func (s *service) UpdateTopology(providerId string, topology sdk.Topology) error {
+	serviceMap := make(map[string]interface{}, len(topology.Services))
+	for _, backingService := range topology.Services {
+		serviceMap[backingService.Id] = backingService
+	}
+	err := s.updateProvided(ServicesCollection, providerId, serviceMap)
+	if err != nil {
+		return err
+	}
+
+	dependencyMap := make(map[string]interface{}, len(topology.Dependencies))
+	for _, dependency := range topology.Dependencies {
+		dependencyMap[dependency.Id()] = dependency
+	}
+	return s.updateProvided(DependenciesCollection, providerId, dependencyMap)
+}
+
+// updateProvided replaces the items of the provider, which may be none, e.g. for apps without dependencies.
+func (s *service) updateProvided(coll database.Collection, providerId string, items map[string]interface{}) error {
+	if len(items) == 0 {
+		return s.db.DeleteProvided(coll, providerId)
+	}
+	return s.db.UpdateProvided(coll, providerId, items)
+}
+
+func (s *service) GetAppDependencies(app string) (Topology, error) {
+	dependencies, err := s.listDependencies(bson.M{"$or": bson.A{
+		bson.M{"appId": app},
+		bson.M{"target": sdk.DependencyTargetApp, "targetId": app},
+	}})
+	if err != nil {
+		return Topology{}, err
+	}
+
+	var serviceIds []string
+	for _, dependency := range dependencies {
+		if dependency.Target == sdk.DependencyTargetService {
+			serviceIds = append(serviceIds, dependency.TargetId)
+		}
+	}
+
+	services := []BackingService{}
+	if len(serviceIds) > 0 {
+		services, err = s.listServices(bson.M{"id": bson.M{"$in": serviceIds}})
+		if err != nil {
+			return Topology{}, err
+		}
+	}
+
+	return Topology{Services: services, Dependencies: dependencies}, nil
+}
+
+func (s *service) GetTopology() (Topology, error) {
+	services, err := s.listServices(bson.M{})
+	if err != nil {
+		return Topology{}, err
+	}
+	dependencies, err := s.listDependencies(bson.M{})
+	if err != nil {
+		return Topology{}, err
+	}
+	return Topology{Services: services, Dependencies: dependencies}, nil
+}
+
+func (s *service) listServices(filter bson.M) ([]BackingService, error) {
+	res := []BackingService{}
+	err := s.db.FindManyWithOptions(ServicesCollection, filter, func(c database.Decodable) error {
+		backingService := BackingService{}
+		err := c.Decode(&backingService)
+		if err != nil {
+			return err
+		}
+		res = append(res, backingService)
+		return nil
+	}, bson.M{"id": 1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *service) listDependencies(filter bson.M) ([]Dependency, error) {
+	res := []Dependency{}
+	err := s.db.FindManyWithOptions(DependenciesCollection, filter, func(c database.Decodable) error {
+		dependency := Dependency{}
+		err := c.Decode(&dependency)
+		if err != nil {
+			return err
+		}
+		res = append(res, dependency)
+		return nil
+	}, bson.M{"id": 1}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,212 @@
+package topology
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/internal/core/database"
+	"github.com/joscha-alisch/dyve/internal/core/fakes/db"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"testing"
+)
+
+var someService = BackingService{
+	BackingService: sdk.BackingService{Id: "service-a", Name: "postgres", Kind: "postgresql"},
+	ProviderId:     "provider-a",
+}
+var someServiceDependency = Dependency{
+	Dependency: sdk.Dependency{AppId: "app-a", Target: sdk.DependencyTargetService, TargetId: "service-a"},
+	ProviderId: "provider-a",
+}
+var someAppDependency = Dependency{
+	Dependency: sdk.Dependency{AppId: "app-b", Target: sdk.DependencyTargetApp, TargetId: "app-a"},
+	ProviderId: "provider-a",
+}
+var someErr = errors.New("some error")
+
+func TestService_UpdateTopology(t *testing.T) {
+	tests := []struct {
+		desc        string
+		topology    sdk.Topology
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expectedErr error
+	}{
+		{
+			desc: "updates services and dependencies",
+			topology: sdk.Topology{
+				Services:     []sdk.BackingService{someService.BackingService},
+				Dependencies: []sdk.Dependency{someServiceDependency.Dependency},
+			},
+			db: &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "services",
+				Provider:   "provider-a",
+				Updates:    map[string]interface{}{"service-a": someService.BackingService},
+			}, {
+				Collection: "dependencies",
+				Provider:   "provider-a",
+				Updates:    map[string]interface{}{"app-a/service/service-a": someServiceDependency.Dependency},
+			}},
+		},
+		{
+			desc: "removes all services and dependencies",
+			db:   &db.RecordingDatabase{},
+			recorded: []db.DatabaseRecord{{
+				Collection: "services",
+				Provider:   "provider-a",
+			}, {
+				Collection: "dependencies",
+				Provider:   "provider-a",
+			}},
+		},
+		{
+			desc:        "error while updating topology",
+			topology:    sdk.Topology{Services: []sdk.BackingService{someService.BackingService}},
+			db:          &db.RecordingDatabase{Err: someErr},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			s := NewService(test.db)
+			err := s.UpdateTopology("provider-a", test.topology)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
+
+func TestService_GetTopology(t *testing.T) {
+	returnTopology := func(each func(decodable database.Decodable) error) {
+		_ = each(database.DecodableFunc(func(target interface{}) error {
+			switch v := target.(type) {
+			case *BackingService:
+				*v = someService
+			case *Dependency:
+				*v = someServiceDependency
+			}
+			return nil
+		}))
+	}
+
+	tests := []struct {
+		desc        string
+		get         func(s Service) (Topology, error)
+		db          *db.RecordingDatabase
+		recorded    []db.DatabaseRecord
+		expected    Topology
+		expectedErr error
+	}{
+		{
+			desc: "gets whole topology",
+			get: func(s Service) (Topology, error) {
+				return s.GetTopology()
+			},
+			db: &db.RecordingDatabase{ReturnEach: returnTopology},
+			expected: Topology{
+				Services:     []BackingService{someService},
+				Dependencies: []Dependency{someServiceDependency},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "services",
+				Filter:     bson.M{},
+				Sort:       bson.M{"id": 1},
+			}, {
+				Collection: "dependencies",
+				Filter:     bson.M{},
+				Sort:       bson.M{"id": 1},
+			}},
+		},
+		{
+			desc: "gets app dependencies",
+			get: func(s Service) (Topology, error) {
+				return s.GetAppDependencies("app-a")
+			},
+			db: &db.RecordingDatabase{ReturnEach: returnTopology},
+			expected: Topology{
+				Services:     []BackingService{someService},
+				Dependencies: []Dependency{someServiceDependency},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "dependencies",
+				Filter: bson.M{"$or": bson.A{
+					bson.M{"appId": "app-a"},
+					bson.M{"target": sdk.DependencyTargetApp, "targetId": "app-a"},
+				}},
+				Sort: bson.M{"id": 1},
+			}, {
+				Collection: "services",
+				Filter:     bson.M{"id": bson.M{"$in": []string{"service-a"}}},
+				Sort:       bson.M{"id": 1},
+			}},
+		},
+		{
+			desc: "does not look up services without service dependencies",
+			get: func(s Service) (Topology, error) {
+				return s.GetAppDependencies("app-a")
+			},
+			db: &db.RecordingDatabase{ReturnEach: func(each func(decodable database.Decodable) error) {
+				_ = each(database.DecodableFunc(func(target interface{}) error {
+					*target.(*Dependency) = someAppDependency
+					return nil
+				}))
+			}},
+			expected: Topology{
+				Services:     []BackingService{},
+				Dependencies: []Dependency{someAppDependency},
+			},
+			recorded: []db.DatabaseRecord{{
+				Collection: "dependencies",
+				Filter: bson.M{"$or": bson.A{
+					bson.M{"appId": "app-a"},
+					bson.M{"target": sdk.DependencyTargetApp, "targetId": "app-a"},
+				}},
+				Sort: bson.M{"id": 1},
+			}},
+		},
+		{
+			desc: "error while getting topology",
+			get: func(s Service) (Topology, error) {
+				return s.GetTopology()
+			},
+			db:          &db.RecordingDatabase{Err: someErr},
+			expectedErr: someErr,
+		},
+		{
+			desc: "error while getting app dependencies",
+			get: func(s Service) (Topology, error) {
+				return s.GetAppDependencies("app-a")
+			},
+			db:          &db.RecordingDatabase{Err: someErr},
+			expectedErr: someErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			recorder := &db.DatabaseRecorder{}
+			test.db.Recorder = recorder
+			res, err := test.get(NewService(test.db))
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("errors mismatch: %s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("results mismatch: %s\n", cmp.Diff(test.expected, res))
+			}
+
+			if !cmp.Equal(test.recorded, recorder.Records) {
+				tt.Errorf("recorded mismatch: %s\n", cmp.Diff(test.recorded, recorder.Records))
+			}
+		})
+	}
+}
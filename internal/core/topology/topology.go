@@ -0,0 +1,19 @@
+package topology
+
+import "github.com/joscha-alisch/dyve/pkg/provider/sdk"
+
+type BackingService struct {
+	sdk.BackingService `json:",inline" bson:",inline"`
+	ProviderId         string `json:"providerId" bson:"provider"`
+}
+
+type Dependency struct {
+	sdk.Dependency `json:",inline" bson:",inline"`
+	ProviderId     string `json:"providerId" bson:"provider"`
+}
+
+// Topology is a graph of apps and backing services connected by dependencies.
+type Topology struct {
+	Services     []BackingService `json:"services"`
+	Dependencies []Dependency     `json:"dependencies"`
+}
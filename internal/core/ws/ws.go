@@ -4,6 +4,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 	"net/http"
+	"sync"
 )
 
 type Handler interface {
@@ -36,6 +37,8 @@ type Connection struct {
 	conn     *websocket.Conn
 	errCount int
 	handlers map[string]func()
+	// mu guards writes, as messages can be sent from several goroutines.
+	mu sync.Mutex
 }
 
 type OnCommand func(cmd string, c *Connection)
@@ -75,6 +78,8 @@ func (c *Connection) Run() error {
 }
 
 func (c *Connection) Send(msg interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.conn.WriteJSON(msg)
 }
 
@@ -0,0 +1,89 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errRequestFailed = errors.New("alertmanager request failed")
+
+// Login configures how to reach the Alertmanager API.
+type Login struct {
+	Url string `yaml:"url"`
+	// BearerToken is sent with every request, if set.
+	BearerToken string `yaml:"bearerToken"`
+}
+
+// API is an abstraction around the Alertmanager v2 API.
+type API interface {
+	// ListActiveAlerts returns the alerts that are neither silenced nor inhibited.
+	ListActiveAlerts(ctx context.Context) ([]Alert, error)
+}
+
+type Alert struct {
+	Fingerprint  string            `json:"fingerprint"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+}
+
+func NewDefaultApi(l Login) API {
+	c := http.DefaultClient
+	if l.BearerToken != "" {
+		c = &http.Client{Transport: &bearerTransport{token: l.BearerToken, next: http.DefaultTransport}}
+	}
+	return NewApi(l.Url, c)
+}
+
+func NewApi(uri string, c *http.Client) API {
+	return &api{
+		uri: strings.TrimSuffix(uri, "/"),
+		c:   c,
+	}
+}
+
+type api struct {
+	uri string
+	c   *http.Client
+}
+
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (b *bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.token)
+	return b.next.RoundTrip(r)
+}
+
+func (a *api) ListActiveAlerts(ctx context.Context) ([]Alert, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", a.uri+"/api/v2/alerts?active=true&silenced=false&inhibited=false", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", errRequestFailed, res.StatusCode)
+	}
+
+	var alerts []Alert
+	err = json.NewDecoder(res.Body).Decode(&alerts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errRequestFailed, err)
+	}
+	return alerts, nil
+}
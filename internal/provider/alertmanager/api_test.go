@@ -0,0 +1,90 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+func TestApiListActiveAlerts(t *testing.T) {
+	tests := []struct {
+		desc        string
+		status      int
+		body        string
+		expected    []Alert
+		expectedErr error
+	}{
+		{desc: "lists alerts", status: http.StatusOK, body: `[{
+			"fingerprint": "1a2b3c",
+			"labels": {"alertname": "HighErrorRate", "app_id": "app-a"},
+			"annotations": {"summary": "errors above 5%"},
+			"startsAt": "2006-01-01T15:00:00Z",
+			"endsAt": "2006-01-01T16:00:00Z",
+			"updatedAt": "2006-01-01T15:00:00Z",
+			"generatorURL": "http://prometheus/graph",
+			"receivers": [{"name": "default"}],
+			"status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+		}]`, expected: []Alert{{
+			Fingerprint:  "1a2b3c",
+			Labels:       map[string]string{"alertname": "HighErrorRate", "app_id": "app-a"},
+			Annotations:  map[string]string{"summary": "errors above 5%"},
+			StartsAt:     someTime,
+			GeneratorURL: "http://prometheus/graph",
+		}}},
+		{desc: "lists no alerts", status: http.StatusOK, body: `[]`, expected: []Alert{}},
+		{desc: "returns error for failed request", status: http.StatusInternalServerError, body: `"error"`, expectedErr: errRequestFailed},
+		{desc: "returns error for malformed response", status: http.StatusOK, body: `{"data": []}`, expectedErr: errRequestFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			var query url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/alerts" {
+					tt.Errorf("unexpected path %s", r.URL.Path)
+				}
+				query = r.URL.Query()
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			alerts, err := NewApi(server.URL+"/", server.Client()).ListActiveAlerts(context.Background())
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, alerts) {
+				tt.Errorf("\ndiff between alerts: \n%s\n", cmp.Diff(test.expected, alerts))
+			}
+
+			expectedQuery := url.Values{"active": {"true"}, "silenced": {"false"}, "inhibited": {"false"}}
+			if !cmp.Equal(expectedQuery, query) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(expectedQuery, query))
+			}
+		})
+	}
+}
+
+func TestApiBearerToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	_, err := NewDefaultApi(Login{Url: server.URL, BearerToken: "token"}).ListActiveAlerts(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("wanted bearer token, got %s", auth)
+	}
+}
@@ -0,0 +1,83 @@
+package alertmanager
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"strings"
+)
+
+// Labels configures which labels of an alert name its app, team and severity.
+type Labels struct {
+	App      string `yaml:"app"`
+	Team     string `yaml:"team"`
+	Severity string `yaml:"severity"`
+}
+
+var DefaultLabels = Labels{
+	App:      "app_id",
+	Team:     "team",
+	Severity: "severity",
+}
+
+func NewProvider(am API, labels Labels) *Provider {
+	if labels.App == "" {
+		labels.App = DefaultLabels.App
+	}
+	if labels.Team == "" {
+		labels.Team = DefaultLabels.Team
+	}
+	if labels.Severity == "" {
+		labels.Severity = DefaultLabels.Severity
+	}
+	return &Provider{
+		am:     am,
+		labels: labels,
+	}
+}
+
+type Provider struct {
+	am     API
+	labels Labels
+}
+
+// ListAlerts returns the active alerts of Alertmanager. Alerts without an app label can't be
+// shown for an app and are left out.
+func (p *Provider) ListAlerts(ctx context.Context) ([]sdk.Alert, error) {
+	alerts, err := p.am.ListActiveAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []sdk.Alert{}
+	for _, alert := range alerts {
+		app := alert.Labels[p.labels.App]
+		if app == "" {
+			continue
+		}
+
+		res = append(res, sdk.Alert{
+			Id:       alert.Fingerprint,
+			AppId:    app,
+			Team:     alert.Labels[p.labels.Team],
+			Kind:     sdk.AlertKindAlert,
+			Name:     alert.Labels["alertname"],
+			Summary:  alert.Annotations["summary"],
+			Severity: toSeverity(alert.Labels[p.labels.Severity]),
+			Started:  alert.StartsAt,
+			Labels:   alert.Labels,
+			Link:     alert.GeneratorURL,
+		})
+	}
+	return res, nil
+}
+
+// toSeverity maps the severities commonly used in alerting rules. Unknown ones become info.
+func toSeverity(severity string) sdk.AlertSeverity {
+	switch strings.ToLower(severity) {
+	case "critical", "error", "page":
+		return sdk.AlertSeverityCritical
+	case "warning", "warn":
+		return sdk.AlertSeverityWarning
+	}
+	return sdk.AlertSeverityInfo
+}
@@ -0,0 +1,75 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk/sdktest"
+	"testing"
+)
+
+var someErr = errors.New("error")
+
+var someAlerts = []Alert{
+	{Fingerprint: "a1", Labels: map[string]string{"alertname": "HighErrorRate", "app_id": "app-a", "team": "team-a", "severity": "critical"},
+		Annotations: map[string]string{"summary": "errors above 5%"}, StartsAt: someTime, GeneratorURL: "http://prometheus/graph"},
+	{Fingerprint: "b2", Labels: map[string]string{"alertname": "HighLatency", "app_id": "app-b", "severity": "warn"}, StartsAt: someTime},
+	{Fingerprint: "c3", Labels: map[string]string{"alertname": "DiskFull", "service": "app-c", "owner": "team-c", "level": "Page"}, StartsAt: someTime},
+	{Fingerprint: "d4", Labels: map[string]string{"alertname": "Watchdog"}, StartsAt: someTime},
+}
+
+func TestListAlerts(t *testing.T) {
+	tests := []struct {
+		desc        string
+		am          API
+		labels      Labels
+		expected    []sdk.Alert
+		expectedErr error
+	}{
+		{desc: "lists alerts of apps", am: &fakeAlertmanager{alerts: someAlerts}, expected: []sdk.Alert{
+			{Id: "a1", AppId: "app-a", Team: "team-a", Kind: sdk.AlertKindAlert, Name: "HighErrorRate", Summary: "errors above 5%",
+				Severity: sdk.AlertSeverityCritical, Started: someTime, Labels: someAlerts[0].Labels, Link: "http://prometheus/graph"},
+			{Id: "b2", AppId: "app-b", Kind: sdk.AlertKindAlert, Name: "HighLatency",
+				Severity: sdk.AlertSeverityWarning, Started: someTime, Labels: someAlerts[1].Labels},
+		}},
+		{desc: "uses configured labels", am: &fakeAlertmanager{alerts: someAlerts}, labels: Labels{App: "service", Team: "owner", Severity: "level"}, expected: []sdk.Alert{
+			{Id: "c3", AppId: "app-c", Team: "team-c", Kind: sdk.AlertKindAlert, Name: "DiskFull",
+				Severity: sdk.AlertSeverityCritical, Started: someTime, Labels: someAlerts[2].Labels},
+		}},
+		{desc: "lists no alerts", am: &fakeAlertmanager{}, expected: []sdk.Alert{}},
+		{desc: "returns error", am: &fakeAlertmanager{err: someErr}, expectedErr: someErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.am, test.labels)
+			alerts, err := p.ListAlerts(context.Background())
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, alerts) {
+				tt.Errorf("\ndiff between returned alerts: \n%s\n", cmp.Diff(test.expected, alerts))
+			}
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	sdktest.RunConfig(t, sdk.ProviderConfig{
+		Name:   "alertmanager",
+		Alerts: NewProvider(&fakeAlertmanager{alerts: someAlerts}, Labels{}),
+	})
+}
+
+type fakeAlertmanager struct {
+	alerts []Alert
+	err    error
+}
+
+func (f *fakeAlertmanager) ListActiveAlerts(ctx context.Context) ([]Alert, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.alerts, nil
+}
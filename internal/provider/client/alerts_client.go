@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+)
+
+func NewAlertsProviderClient(uri string, c *http.Client) sdk.AlertsProvider {
+	return &alertsProviderClient{
+		baseClient: newBaseClient(uri+"/alerts", c),
+	}
+}
+
+type listAlertsResponse struct {
+	Status int
+	Err    string
+	Result []sdk.Alert
+}
+
+type alertsProviderClient struct {
+	baseClient
+}
+
+func (p *alertsProviderClient) ListAlerts(ctx context.Context) ([]sdk.Alert, error) {
+	r := listAlertsResponse{}
+	err := p.get(ctx, &r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
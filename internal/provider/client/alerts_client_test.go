@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListAlerts(t *testing.T) {
+	tests := []struct {
+		desc     string
+		state    fakeAlertsProvider
+		expected []sdk.Alert
+	}{
+		{desc: "returns alerts", state: fakeAlertsProvider{alerts: []sdk.Alert{
+			{Id: "alert-a", AppId: "app-a", Kind: sdk.AlertKindAlert, Name: "HighLatency", Severity: sdk.AlertSeverityWarning,
+				Started: someTime, Labels: map[string]string{"env": "prod"}, Link: "http://alerts/a"},
+		}}, expected: []sdk.Alert{
+			{Id: "alert-a", AppId: "app-a", Kind: sdk.AlertKindAlert, Name: "HighLatency", Severity: sdk.AlertSeverityWarning,
+				Started: someTime, Labels: map[string]string{"env": "prod"}, Link: "http://alerts/a"},
+		}},
+		{desc: "returns no alerts", state: fakeAlertsProvider{alerts: []sdk.Alert{}}, expected: []sdk.Alert{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewAlertsProviderHandler(&test.state))
+			defer s.Close()
+
+			c := NewAlertsProviderClient(s.URL, nil)
+
+			alerts, err := c.ListAlerts(context.Background())
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			if !cmp.Equal(test.expected, alerts) {
+				tt.Errorf("\ndiff between alerts\n%s\n", cmp.Diff(test.expected, alerts))
+			}
+		})
+	}
+}
+
+type fakeAlertsProvider struct {
+	alerts []sdk.Alert
+}
+
+func (f *fakeAlertsProvider) ListAlerts(ctx context.Context) ([]sdk.Alert, error) {
+	return f.alerts, nil
+}
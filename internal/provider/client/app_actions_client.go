@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"strconv"
+)
+
+func NewAppActionsProviderClient(uri string, c *http.Client) sdk.AppActionsProvider {
+	return &appActionsProviderClient{
+		baseClient: newBaseClient(uri+"/actions", c),
+	}
+}
+
+type appActionsProviderClient struct {
+	baseClient
+}
+
+func (p *appActionsProviderClient) RestartApp(ctx context.Context, id string) error {
+	return p.post(ctx, nil, id, "restart")
+}
+
+func (p *appActionsProviderClient) StopApp(ctx context.Context, id string) error {
+	return p.post(ctx, nil, id, "stop")
+}
+
+func (p *appActionsProviderClient) StartApp(ctx context.Context, id string) error {
+	return p.post(ctx, nil, id, "start")
+}
+
+func (p *appActionsProviderClient) ScaleApp(ctx context.Context, id string, instances int) error {
+	return p.post(ctx, sdk.AppScaleRequest{Instances: instances}, id, "scale")
+}
+
+func (p *appActionsProviderClient) RestartAppInstance(ctx context.Context, id string, index int) error {
+	return p.post(ctx, nil, id, "instances", strconv.Itoa(index), "restart")
+}
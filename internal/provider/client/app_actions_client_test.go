@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestAppActions(t *testing.T) {
+	tests := []struct {
+		desc          string
+		err           error
+		action        func(ctx context.Context, p sdk.AppActionsProvider) error
+		expectedErr   error
+		expectedCalls []string
+	}{
+		{desc: "restarts app", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.RestartApp(ctx, "a")
+		}, expectedCalls: []string{"restart a"}},
+		{desc: "stops app", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.StopApp(ctx, "a")
+		}, expectedCalls: []string{"stop a"}},
+		{desc: "starts app", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.StartApp(ctx, "a")
+		}, expectedCalls: []string{"start a"}},
+		{desc: "scales app", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.ScaleApp(ctx, "a", 3)
+		}, expectedCalls: []string{"scale a 3"}},
+		{desc: "restarts app instance", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.RestartAppInstance(ctx, "a", 2)
+		}, expectedCalls: []string{"restart a 2"}},
+		{desc: "returns not found", err: sdk.ErrNotFound, action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.RestartApp(ctx, "a")
+		}, expectedErr: sdk.ErrNotFound, expectedCalls: []string{"restart a"}},
+		{desc: "returns invalid scale", action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.ScaleApp(ctx, "a", -1)
+		}, expectedErr: sdk.ErrScaleInvalid},
+		{desc: "returns other errors", err: sdk.ErrInternal, action: func(ctx context.Context, p sdk.AppActionsProvider) error {
+			return p.StopApp(ctx, "a")
+		}, expectedErr: sdk.ErrInternal, expectedCalls: []string{"stop a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := &fakeAppActionsProvider{err: test.err}
+			s := httptest.NewServer(sdk.NewAppActionsProviderHandler(p))
+			defer s.Close()
+
+			err := test.action(context.Background(), NewAppActionsProviderClient(s.URL, nil))
+			// errors other than not found only keep their message when crossing http
+			if fmt.Sprint(test.expectedErr) != fmt.Sprint(err) {
+				tt.Errorf("\nwanted error: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.expectedCalls, p.calls) {
+				tt.Errorf("\ndiff between calls\n%s\n", cmp.Diff(test.expectedCalls, p.calls))
+			}
+		})
+	}
+}
+
+type fakeAppActionsProvider struct {
+	err   error
+	calls []string
+}
+
+func (f *fakeAppActionsProvider) RestartApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "restart "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) StopApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "stop "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) StartApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "start "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) ScaleApp(ctx context.Context, id string, instances int) error {
+	f.calls = append(f.calls, "scale "+id+" "+strconv.Itoa(instances))
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) RestartAppInstance(ctx context.Context, id string, index int) error {
+	f.calls = append(f.calls, "restart "+id+" "+strconv.Itoa(index))
+	return f.err
+}
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"context"
+	"fmt"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
+	"time"
 )
 
 type listAppsResponse struct {
@@ -17,30 +20,80 @@ type getAppResponse struct {
 	Result sdk.App
 }
 
-func NewAppProviderClient(uri string, c *http.Client) sdk.AppProvider {
+type listAppsPageResponse struct {
+	Status int
+	Err    string
+	Result sdk.AppPage
+}
+
+type listAppUpdatesResponse struct {
+	Status int
+	Err    string
+	Result sdk.AppUpdates
+}
+
+func NewAppProviderClient(uri string, c *http.Client) sdk.AppProviderContext {
 	return &appProviderClient{
 		baseClient: newBaseClient(uri+"/apps", c),
 	}
 }
 
+// NewAppUpdatesProviderClient creates a client for app providers advertising sdk.FeatureAppUpdates.
+func NewAppUpdatesProviderClient(uri string, c *http.Client) sdk.AppUpdatesProvider {
+	return &appUpdatesProviderClient{
+		appProviderClient{
+			baseClient: newBaseClient(uri+"/apps", c),
+		},
+	}
+}
+
 type appProviderClient struct {
 	baseClient
 }
 
-func (a *appProviderClient) ListApps() ([]sdk.App, error) {
+// appUpdatesProviderClient is kept apart from appProviderClient, so the core can tell
+// by its type whether a provider serves app updates.
+type appUpdatesProviderClient struct {
+	appProviderClient
+}
+
+func (a *appProviderClient) ListApps(ctx context.Context) ([]sdk.App, error) {
 	r := listAppsResponse{}
-	err := a.get(&r, nil)
+	err := a.get(ctx, &r, nil)
 	if err != nil {
 		return nil, err
 	}
 	return r.Result, nil
 }
 
-func (a *appProviderClient) GetApp(id string) (sdk.App, error) {
+func (a *appProviderClient) GetApp(ctx context.Context, id string) (sdk.App, error) {
 	r := getAppResponse{}
-	err := a.get(&r, nil, id)
+	err := a.get(ctx, &r, nil, id)
 	if err != nil {
 		return sdk.App{}, err
 	}
 	return r.Result, nil
 }
+
+func (a *appUpdatesProviderClient) ListAppsPage(ctx context.Context, perPage int, page int) (sdk.AppPage, error) {
+	r := listAppsPageResponse{}
+	err := a.get(ctx, &r, map[string]string{
+		"perPage": fmt.Sprintf("%d", perPage),
+		"page":    fmt.Sprintf("%d", page),
+	})
+	if err != nil {
+		return sdk.AppPage{}, err
+	}
+	return r.Result, nil
+}
+
+func (a *appUpdatesProviderClient) ListAppUpdates(ctx context.Context, since time.Time) (sdk.AppUpdates, error) {
+	r := listAppUpdatesResponse{}
+	err := a.get(ctx, &r, map[string]string{
+		"since": since.Format(time.RFC3339),
+	}, "updates")
+	if err != nil {
+		return sdk.AppUpdates{}, err
+	}
+	return r.Result, nil
+}
@@ -1,11 +1,13 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetApp(t *testing.T) {
@@ -30,7 +32,7 @@ func TestGetApp(t *testing.T) {
 
 			c := NewAppProviderClient(s.URL, nil)
 
-			apps, err := c.GetApp(test.id)
+			apps, err := c.GetApp(context.Background(), test.id)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -66,7 +68,7 @@ func TestListApps(t *testing.T) {
 
 			c := NewAppProviderClient(s.URL, nil)
 
-			apps, err := c.ListApps()
+			apps, err := c.ListApps(context.Background())
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -78,17 +80,115 @@ func TestListApps(t *testing.T) {
 	}
 }
 
+func TestListAppsPage(t *testing.T) {
+	f := &fakeAppProvider{apps: []sdk.App{
+		{Id: "id-a", Name: "name-a"},
+		{Id: "id-b", Name: "name-b"},
+		{Id: "id-c", Name: "name-c"},
+	}}
+	s := httptest.NewServer(sdk.NewAppProviderHandler(f))
+	defer s.Close()
+
+	c := NewAppUpdatesProviderClient(s.URL, nil)
+
+	page, err := c.ListAppsPage(context.Background(), 2, 1)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := sdk.AppPage{
+		Pagination: sdk.Pagination{TotalResults: 3, TotalPages: 2, PerPage: 2, Page: 1},
+		Apps:       []sdk.App{{Id: "id-c", Name: "name-c"}},
+	}
+	if !cmp.Equal(expected, page) {
+		t.Errorf("\ndiff between pages\n%s\n", cmp.Diff(expected, page))
+	}
+}
+
+func TestListAppUpdates(t *testing.T) {
+	since, _ := time.Parse(time.RFC3339, "2021-01-01T15:00:00Z")
+	f := &fakeAppUpdatesProvider{updates: sdk.AppUpdates{
+		Apps:    []sdk.App{{Id: "id-a", Name: "name-a"}},
+		Deleted: []sdk.AppTombstone{{Id: "id-b", Deleted: since.Add(time.Hour)}},
+	}}
+	s := httptest.NewServer(sdk.NewAppProviderHandler(f))
+	defer s.Close()
+
+	c := NewAppUpdatesProviderClient(s.URL, nil)
+
+	updates, err := c.ListAppUpdates(context.Background(), since)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if !cmp.Equal(f.updates, updates) {
+		t.Errorf("\ndiff between updates\n%s\n", cmp.Diff(f.updates, updates))
+	}
+	if !f.since.Equal(since) {
+		t.Errorf("\nwanted since %v\n   got %v", since, f.since)
+	}
+}
+
+func TestListAppsDeadline(t *testing.T) {
+	cancelled := make(chan struct{})
+	handler := sdk.NewAppProviderHandler(&blockingAppProvider{cancelled: cancelled})
+	s := httptest.NewServer(handler)
+	defer s.Close()
+
+	c := NewAppProviderClient(s.URL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := c.ListApps(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("\nwanted err: %v\ngot: %v", context.DeadlineExceeded, err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("request context of the provider wasn't cancelled")
+	}
+}
+
+type blockingAppProvider struct {
+	fakeAppProvider
+	cancelled chan struct{}
+}
+
+func (b *blockingAppProvider) ListApps(ctx context.Context) ([]sdk.App, error) {
+	<-ctx.Done()
+	close(b.cancelled)
+	return nil, ctx.Err()
+}
+
+type fakeAppUpdatesProvider struct {
+	fakeAppProvider
+	updates sdk.AppUpdates
+	since   time.Time
+}
+
+func (f *fakeAppUpdatesProvider) ListAppsPage(ctx context.Context, perPage int, page int) (sdk.AppPage, error) {
+	return sdk.PageApps(f.apps, perPage, page)
+}
+
+func (f *fakeAppUpdatesProvider) ListAppUpdates(ctx context.Context, since time.Time) (sdk.AppUpdates, error) {
+	f.since = since
+	return f.updates, nil
+}
+
 type fakeAppProvider struct {
 	apps    []sdk.App
 	app     sdk.App
 	appPage sdk.AppPage
 }
 
-func (f fakeAppProvider) ListApps() ([]sdk.App, error) {
+func (f fakeAppProvider) ListApps(ctx context.Context) ([]sdk.App, error) {
 	return f.apps, nil
 }
 
-func (f fakeAppProvider) GetApp(id string) (sdk.App, error) {
+func (f fakeAppProvider) GetApp(ctx context.Context, id string) (sdk.App, error) {
 	if f.app.Id == id {
 		return f.app, nil
 	}
@@ -1,7 +1,10 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
@@ -28,7 +31,62 @@ type baseClient struct {
 	basePath string
 }
 
-func (a *baseClient) get(resp interface{}, query map[string]string, path ...string) error {
+func (a *baseClient) get(ctx context.Context, resp interface{}, query map[string]string, path ...string) error {
+	res, err := a.open(ctx, query, path...)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// post sends body as json to the path. Responses with an error status are returned as error.
+func (a *baseClient) post(ctx context.Context, body interface{}, path ...string) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.basePath+strings.Join(path, "/"), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	res, err := a.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return sdk.ErrNotFound
+	case http.StatusConflict:
+		return sdk.ErrConflict
+	case http.StatusUnauthorized:
+		return sdk.ErrUnauthorized
+	}
+
+	resp := struct {
+		Err string `json:"error"`
+	}{}
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil || resp.Err == "" {
+		return fmt.Errorf("request failed with status %d", res.StatusCode)
+	}
+	return errors.New(resp.Err)
+}
+
+// open requests the path and returns the response for the caller to read and close.
+func (a *baseClient) open(ctx context.Context, query map[string]string, path ...string) (*http.Response, error) {
 	fullPath := a.basePath
 	for _, s := range path {
 		fullPath = fullPath + "/" + s
@@ -43,24 +101,25 @@ func (a *baseClient) get(resp interface{}, query map[string]string, path ...stri
 		fullPath += strings.Join(queries, "&")
 	}
 
-	req, err := http.NewRequest("GET", fullPath, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullPath, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	res, err := a.c.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if res.StatusCode == http.StatusNotFound {
-		return sdk.ErrNotFound
+		res.Body.Close()
+		return nil, sdk.ErrNotFound
 	}
 
-	err = json.NewDecoder(res.Body).Decode(&resp)
-	if err != nil {
-		return err
+	if res.StatusCode == http.StatusUnauthorized {
+		res.Body.Close()
+		return nil, sdk.ErrUnauthorized
 	}
 
-	return nil
+	return res, nil
 }
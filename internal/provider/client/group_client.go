@@ -1,11 +1,12 @@
 package client
 
 import (
+	"context"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
 )
 
-func NewGroupProviderClient(uri string, c *http.Client) sdk.GroupProvider {
+func NewGroupProviderClient(uri string, c *http.Client) sdk.GroupProviderContext {
 	return &groupProviderClient{
 		baseClient: newBaseClient(uri+"/groups", c),
 	}
@@ -27,18 +28,18 @@ type groupProviderClient struct {
 	baseClient
 }
 
-func (p *groupProviderClient) ListGroups() ([]sdk.Group, error) {
+func (p *groupProviderClient) ListGroups(ctx context.Context) ([]sdk.Group, error) {
 	r := listGroupsResponse{}
-	err := p.get(&r, nil)
+	err := p.get(ctx, &r, nil)
 	if err != nil {
 		return nil, err
 	}
 	return r.Result, nil
 }
 
-func (p *groupProviderClient) GetGroup(id string) (sdk.Group, error) {
+func (p *groupProviderClient) GetGroup(ctx context.Context, id string) (sdk.Group, error) {
 	r := getGroupResponse{}
-	err := p.get(&r, nil, id)
+	err := p.get(ctx, &r, nil, id)
 	if err != nil {
 		return sdk.Group{}, err
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
@@ -31,7 +32,7 @@ func TestGetGroup(t *testing.T) {
 
 			c := NewGroupProviderClient(s.URL, nil)
 
-			group, err := c.GetGroup(test.id)
+			group, err := c.GetGroup(context.Background(), test.id)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -71,7 +72,7 @@ func TestListGroups(t *testing.T) {
 
 			c := NewGroupProviderClient(s.URL, nil)
 
-			groups, err := c.ListGroups()
+			groups, err := c.ListGroups(context.Background())
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -90,14 +91,14 @@ type fakeGroupProvider struct {
 	recordedId string
 }
 
-func (f *fakeGroupProvider) ListGroups() ([]sdk.Group, error) {
+func (f *fakeGroupProvider) ListGroups(ctx context.Context) ([]sdk.Group, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
 	return f.groups, nil
 }
 
-func (f *fakeGroupProvider) GetGroup(id string) (sdk.Group, error) {
+func (f *fakeGroupProvider) GetGroup(ctx context.Context, id string) (sdk.Group, error) {
 	f.recordedId = id
 	if f.err != nil {
 		return sdk.Group{}, f.err
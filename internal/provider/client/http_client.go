@@ -0,0 +1,46 @@
+package client
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"time"
+)
+
+// NewHttpClient creates a client presenting the configured credentials on every request.
+func NewHttpClient(a sdk.AuthConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if a.TLS.Enabled() {
+		tlsConfig, err := a.TLS.ClientTLS()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: &authTransport{
+			auth: a,
+			next: transport,
+		},
+	}, nil
+}
+
+type authTransport struct {
+	auth sdk.AuthConfig
+	next http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the original request
+	r = r.Clone(r.Context())
+
+	err := t.auth.AuthorizeRequest(r, currentTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(r)
+}
+
+var currentTime = time.Now
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHttpClientAuth(t *testing.T) {
+	tests := []struct {
+		desc        string
+		server      sdk.AuthConfig
+		client      sdk.AuthConfig
+		expectedErr error
+	}{
+		{desc: "no auth", server: sdk.AuthConfig{}, client: sdk.AuthConfig{}},
+		{desc: "bearer token", server: sdk.AuthConfig{Token: "token"}, client: sdk.AuthConfig{Token: "token"}},
+		{desc: "wrong bearer token", server: sdk.AuthConfig{Token: "token"}, client: sdk.AuthConfig{Token: "other"}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "missing bearer token", server: sdk.AuthConfig{Token: "token"}, client: sdk.AuthConfig{}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "hmac", server: sdk.AuthConfig{HmacSecret: "secret"}, client: sdk.AuthConfig{HmacSecret: "secret"}},
+		{desc: "wrong hmac secret", server: sdk.AuthConfig{HmacSecret: "secret"}, client: sdk.AuthConfig{HmacSecret: "other"}, expectedErr: sdk.ErrUnauthorized},
+		{desc: "token and hmac", server: sdk.AuthConfig{Token: "token", HmacSecret: "secret"}, client: sdk.AuthConfig{Token: "token", HmacSecret: "secret"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{
+				Apps: &fakeAppProvider{},
+				Auth: test.server,
+			}))
+			defer s.Close()
+
+			c, err := NewHttpClient(test.client)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			_, err = NewAppProviderClient(s.URL, c).ListApps(context.Background())
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+)
+
+func NewInfoClient(uri string, c *http.Client) sdk.InfoProvider {
+	return &infoClient{
+		baseClient: newBaseClient(uri+"/info", c),
+	}
+}
+
+type getInfoResponse struct {
+	Status int
+	Err    string
+	Result sdk.ProviderInfo
+}
+
+type infoClient struct {
+	baseClient
+}
+
+func (p *infoClient) GetInfo(ctx context.Context) (sdk.ProviderInfo, error) {
+	r := getInfoResponse{}
+	err := p.get(ctx, &r, nil)
+	if err != nil {
+		return sdk.ProviderInfo{}, err
+	}
+	return r.Result, nil
+}
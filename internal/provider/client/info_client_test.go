@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetInfo(t *testing.T) {
+	s := httptest.NewServer(sdk.NewHandler(sdk.ProviderConfig{
+		Name:   "groups",
+		Groups: &fakeGroupProvider{},
+	}))
+	defer s.Close()
+
+	info, err := NewInfoClient(s.URL, nil).GetInfo(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := sdk.ProviderInfo{
+		Name:            "groups",
+		ProtocolVersion: sdk.ProtocolVersion,
+		Features:        []sdk.Feature{sdk.FeatureGroups},
+	}
+	if !cmp.Equal(expected, info) {
+		t.Errorf("\ndiff between infos\n%s\n", cmp.Diff(expected, info))
+	}
+}
+
+func TestGetInfoNotSupported(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	_, err := NewInfoClient(s.URL, nil).GetInfo(context.Background())
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("\nwanted err: %v\ngot: %v", sdk.ErrNotFound, err)
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
 )
@@ -11,7 +12,7 @@ type getInstancesResponse struct {
 	Result sdk.AppInstances
 }
 
-func NewInstancesProviderClient(uri string, c *http.Client) sdk.InstancesProvider {
+func NewInstancesProviderClient(uri string, c *http.Client) sdk.InstancesProviderContext {
 	return &instancesProviderClient{
 		baseClient: newBaseClient(uri+"/instances", c),
 	}
@@ -21,9 +22,9 @@ type instancesProviderClient struct {
 	baseClient
 }
 
-func (c *instancesProviderClient) GetAppInstances(id string) (sdk.AppInstances, error) {
+func (c *instancesProviderClient) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
 	r := getInstancesResponse{}
-	err := c.get(&r, nil, id)
+	err := c.get(ctx, &r, nil, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
@@ -33,7 +34,7 @@ func TestGetAppInstances(t *testing.T) {
 
 			c := NewInstancesProviderClient(s.URL, nil)
 
-			instances, err := c.GetAppInstances(test.id)
+			instances, err := c.GetAppInstances(context.Background(), test.id)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -56,7 +57,7 @@ type fakeInstancesProvider struct {
 	recordedId string
 }
 
-func (f *fakeInstancesProvider) GetAppInstances(id string) (sdk.AppInstances, error) {
+func (f *fakeInstancesProvider) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
 	f.recordedId = id
 	if f.err != nil {
 		return sdk.AppInstances{}, f.err
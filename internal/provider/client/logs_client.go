@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"io"
+	"net/http"
+	"time"
+)
+
+type getRecentLogsResponse struct {
+	Status int
+	Err    string
+	Result sdk.AppLogs
+}
+
+func NewLogsProviderClient(uri string, c *http.Client) sdk.LogsProvider {
+	return &logsProviderClient{
+		baseClient: newBaseClient(uri+"/logs", c),
+	}
+}
+
+type logsProviderClient struct {
+	baseClient
+}
+
+func (l *logsProviderClient) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	r := getRecentLogsResponse{}
+	err := l.get(ctx, &r, map[string]string{
+		"since": since.UTC().Format(time.RFC3339),
+		"limit": fmt.Sprintf("%d", limit),
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
+
+// TailLogs reads the stream of lines until the context is done or the provider closes it.
+func (l *logsProviderClient) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	res, err := l.open(ctx, nil, id, "tail")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		r := getRecentLogsResponse{}
+		_ = json.NewDecoder(res.Body).Decode(&r)
+		return fmt.Errorf("tailing logs of app %s failed with status %d: %s", id, res.StatusCode, r.Err)
+	}
+
+	dec := json.NewDecoder(res.Body)
+	for {
+		line := sdk.LogLine{}
+		err := dec.Decode(&line)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
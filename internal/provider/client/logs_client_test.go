@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var someLogs = sdk.AppLogs{
+	{Time: someTime, Instance: "0", Source: "APP", Stream: sdk.LogStreamOut, Message: "first"},
+	{Time: someTime.Add(time.Second), Stream: sdk.LogStreamErr, Message: "second"},
+}
+
+func TestGetRecentLogs(t *testing.T) {
+	tests := []struct {
+		desc        string
+		id          string
+		state       fakeLogsProvider
+		expected    sdk.AppLogs
+		expectedErr error
+	}{
+		{desc: "returns logs", id: "id-a", state: fakeLogsProvider{logs: someLogs}, expected: someLogs},
+		{desc: "returns not found err", id: "not-exist", state: fakeLogsProvider{
+			err: sdk.ErrNotFound,
+		}, expectedErr: sdk.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewLogsProviderHandler(&test.state))
+			defer s.Close()
+
+			c := NewLogsProviderClient(s.URL, nil)
+
+			logs, err := c.GetRecentLogs(context.Background(), test.id, someTime, 10)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if test.state.recordedId != test.id || !test.state.recordedSince.Equal(someTime) || test.state.recordedLimit != 10 {
+				tt.Errorf("\nwanted id %s, since %v and limit 10\ngot: %s, %v and %d", test.id, someTime, test.state.recordedId, test.state.recordedSince, test.state.recordedLimit)
+			}
+
+			if !cmp.Equal(test.expected, logs) {
+				tt.Errorf("\ndiff between logs\n%s\n", cmp.Diff(test.expected, logs))
+			}
+		})
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	tests := []struct {
+		desc        string
+		id          string
+		state       fakeLogsProvider
+		expected    sdk.AppLogs
+		expectedErr error
+	}{
+		{desc: "streams logs", id: "id-a", state: fakeLogsProvider{logs: someLogs}, expected: someLogs},
+		{desc: "returns not found err", id: "not-exist", state: fakeLogsProvider{
+			err: sdk.ErrNotFound,
+		}, expectedErr: sdk.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewLogsProviderHandler(&test.state))
+			defer s.Close()
+
+			c := NewLogsProviderClient(s.URL, nil)
+
+			lines := make(chan sdk.LogLine)
+			errs := make(chan error)
+			go func() {
+				errs <- c.TailLogs(context.Background(), test.id, lines)
+			}()
+
+			var logs sdk.AppLogs
+			for {
+				select {
+				case line := <-lines:
+					logs = append(logs, line)
+					continue
+				case err := <-errs:
+					if !errors.Is(err, test.expectedErr) {
+						tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+					}
+				}
+				break
+			}
+
+			if !cmp.Equal(test.expected, logs) {
+				tt.Errorf("\ndiff between logs\n%s\n", cmp.Diff(test.expected, logs))
+			}
+		})
+	}
+}
+
+func TestTailLogsCancel(t *testing.T) {
+	p := &blockingLogsProvider{cancelled: make(chan struct{})}
+	s := httptest.NewServer(sdk.NewLogsProviderHandler(p))
+	defer s.Close()
+
+	c := NewLogsProviderClient(s.URL, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan sdk.LogLine)
+	errs := make(chan error)
+	go func() {
+		errs <- c.TailLogs(ctx, "id-a", lines)
+	}()
+
+	<-lines
+	cancel()
+
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-p.cancelled:
+	case <-time.After(time.Second):
+		t.Error("expected the provider to stop tailing")
+	}
+}
+
+type fakeLogsProvider struct {
+	err           error
+	logs          sdk.AppLogs
+	recordedId    string
+	recordedSince time.Time
+	recordedLimit int
+}
+
+func (f *fakeLogsProvider) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	f.recordedId = id
+	f.recordedSince = since
+	f.recordedLimit = limit
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.logs, nil
+}
+
+func (f *fakeLogsProvider) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, line := range f.logs {
+		lines <- line
+	}
+	return nil
+}
+
+// blockingLogsProvider logs a line and then tails until the request is cancelled.
+type blockingLogsProvider struct {
+	cancelled chan struct{}
+}
+
+func (b *blockingLogsProvider) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	return nil, nil
+}
+
+func (b *blockingLogsProvider) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	lines <- sdk.LogLine{Message: "line"}
+	<-ctx.Done()
+	close(b.cancelled)
+	return nil
+}
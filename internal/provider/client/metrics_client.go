@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"time"
+)
+
+type getMetricsResponse struct {
+	Status int
+	Err    string
+	Result sdk.AppMetrics
+}
+
+func NewMetricsProviderClient(uri string, c *http.Client) sdk.MetricsProvider {
+	return &metricsProviderClient{
+		baseClient: newBaseClient(uri+"/metrics", c),
+	}
+}
+
+type metricsProviderClient struct {
+	baseClient
+}
+
+func (m *metricsProviderClient) GetAppMetrics(ctx context.Context, id string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	r := getMetricsResponse{}
+	err := m.get(ctx, &r, map[string]string{
+		"from": q.From.UTC().Format(time.RFC3339),
+		"to":   q.To.UTC().Format(time.RFC3339),
+		"step": q.Step.String(),
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAppMetrics(t *testing.T) {
+	q := sdk.MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: 30 * time.Second}
+
+	tests := []struct {
+		desc        string
+		id          string
+		state       fakeMetricsProvider
+		expected    sdk.AppMetrics
+		expectedErr error
+	}{
+		{desc: "returns metrics", id: "id-a", state: fakeMetricsProvider{metrics: sdk.AppMetrics{
+			{Metric: sdk.MetricMemory, Unit: sdk.MetricUnitBytes, Points: []sdk.MetricPoint{{Time: someTime, Value: 1024}}},
+		}}, expected: sdk.AppMetrics{
+			{Metric: sdk.MetricMemory, Unit: sdk.MetricUnitBytes, Points: []sdk.MetricPoint{{Time: someTime, Value: 1024}}},
+		}},
+		{desc: "returns not found err", id: "not-exist", state: fakeMetricsProvider{
+			err: sdk.ErrNotFound,
+		}, expectedErr: sdk.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewMetricsProviderHandler(&test.state))
+			defer s.Close()
+
+			c := NewMetricsProviderClient(s.URL, nil)
+
+			metrics, err := c.GetAppMetrics(context.Background(), test.id, q)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if test.state.recordedId != test.id || !cmp.Equal(q, test.state.recordedQuery) {
+				tt.Errorf("\nwanted id %s and query %v\ngot: %s and %v", test.id, q, test.state.recordedId, test.state.recordedQuery)
+			}
+
+			if !cmp.Equal(test.expected, metrics) {
+				tt.Errorf("\ndiff between metrics\n%s\n", cmp.Diff(test.expected, metrics))
+			}
+		})
+	}
+}
+
+type fakeMetricsProvider struct {
+	err           error
+	metrics       sdk.AppMetrics
+	recordedId    string
+	recordedQuery sdk.MetricsQuery
+}
+
+func (f *fakeMetricsProvider) GetAppMetrics(ctx context.Context, id string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	f.recordedId = id
+	f.recordedQuery = q
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.metrics, nil
+}
@@ -1,18 +1,30 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-func NewPipelineProviderClient(uri string, c *http.Client) sdk.PipelineProvider {
+func NewPipelineProviderClient(uri string, c *http.Client) sdk.PipelineProviderContext {
 	return &pipelineProviderClient{
 		baseClient: newBaseClient(uri+"/pipelines", c),
 	}
 }
 
+// NewPipelineActionsProviderClient creates a client for pipeline providers advertising
+// sdk.FeaturePipelineActions.
+func NewPipelineActionsProviderClient(uri string, c *http.Client) sdk.PipelineActionsProvider {
+	return &pipelineActionsProviderClient{
+		pipelineProviderClient{
+			baseClient: newBaseClient(uri+"/pipelines", c),
+		},
+	}
+}
+
 type listPipelinesResponse struct {
 	Status int
 	Err    string
@@ -41,9 +53,15 @@ type pipelineProviderClient struct {
 	baseClient
 }
 
-func (p *pipelineProviderClient) ListUpdates(since time.Time) (sdk.PipelineUpdates, error) {
+// pipelineActionsProviderClient is kept apart from pipelineProviderClient, so the core can
+// tell by its type whether a provider can act on pipelines.
+type pipelineActionsProviderClient struct {
+	pipelineProviderClient
+}
+
+func (p *pipelineProviderClient) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
 	r := listPipelineUpdatesResponse{}
-	err := p.get(&r, map[string]string{
+	err := p.get(ctx, &r, map[string]string{
 		"since": since.Format(time.RFC3339),
 	}, "updates")
 	if err != nil {
@@ -52,27 +70,27 @@ func (p *pipelineProviderClient) ListUpdates(since time.Time) (sdk.PipelineUpdat
 	return r.Result, nil
 }
 
-func (p *pipelineProviderClient) ListPipelines() ([]sdk.Pipeline, error) {
+func (p *pipelineProviderClient) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
 	r := listPipelinesResponse{}
-	err := p.get(&r, nil)
+	err := p.get(ctx, &r, nil)
 	if err != nil {
 		return nil, err
 	}
 	return r.Result, nil
 }
 
-func (p *pipelineProviderClient) GetPipeline(id string) (sdk.Pipeline, error) {
+func (p *pipelineProviderClient) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
 	r := getPipelineResponse{}
-	err := p.get(&r, nil, id)
+	err := p.get(ctx, &r, nil, id)
 	if err != nil {
 		return sdk.Pipeline{}, err
 	}
 	return r.Result, nil
 }
 
-func (p *pipelineProviderClient) GetHistory(id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (p *pipelineProviderClient) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
 	r := getHistoryResponse{}
-	err := p.get(&r, map[string]string{
+	err := p.get(ctx, &r, map[string]string{
 		"before": before.Format(time.RFC3339),
 		"limit":  fmt.Sprintf("%d", limit),
 	}, id, "history")
@@ -81,3 +99,19 @@ func (p *pipelineProviderClient) GetHistory(id string, before time.Time, limit i
 	}
 	return r.Result, nil
 }
+
+func (p *pipelineActionsProviderClient) TriggerPipeline(ctx context.Context, id string) error {
+	return p.post(ctx, nil, id, "trigger")
+}
+
+func (p *pipelineActionsProviderClient) RerunStep(ctx context.Context, id string, stepId int) error {
+	return p.post(ctx, nil, id, "steps", strconv.Itoa(stepId), "rerun")
+}
+
+func (p *pipelineActionsProviderClient) ApproveStep(ctx context.Context, id string, stepId int) error {
+	return p.post(ctx, nil, id, "steps", strconv.Itoa(stepId), "approve")
+}
+
+func (p *pipelineActionsProviderClient) AbortRun(ctx context.Context, id string) error {
+	return p.post(ctx, nil, id, "abort")
+}
@@ -1,10 +1,13 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -34,7 +37,7 @@ func TestGetPipeline(t *testing.T) {
 
 			c := NewPipelineProviderClient(s.URL, nil)
 
-			pipeline, err := c.GetPipeline(test.id)
+			pipeline, err := c.GetPipeline(context.Background(), test.id)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -74,7 +77,7 @@ func TestListPipelines(t *testing.T) {
 
 			c := NewPipelineProviderClient(s.URL, nil)
 
-			pipelines, err := c.ListPipelines()
+			pipelines, err := c.ListPipelines(context.Background())
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -121,7 +124,7 @@ func TestGetHistory(t *testing.T) {
 
 			c := NewPipelineProviderClient(s.URL, nil)
 
-			pipelines, err := c.GetHistory(test.id, test.before, test.limit)
+			pipelines, err := c.GetHistory(context.Background(), test.id, test.before, test.limit)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -240,7 +243,7 @@ func TestListUpdates(t *testing.T) {
 
 			c := NewPipelineProviderClient(s.URL, nil)
 
-			updates, err := c.ListUpdates(test.since)
+			updates, err := c.ListUpdates(context.Background(), test.since)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -252,6 +255,81 @@ func TestListUpdates(t *testing.T) {
 	}
 }
 
+func TestPipelineActions(t *testing.T) {
+	tests := []struct {
+		desc          string
+		err           error
+		action        func(ctx context.Context, p sdk.PipelineActionsProvider) error
+		expectedErr   error
+		expectedCalls []string
+	}{
+		{desc: "triggers pipeline", action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.TriggerPipeline(ctx, "a")
+		}, expectedCalls: []string{"trigger a"}},
+		{desc: "reruns step", action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.RerunStep(ctx, "a", 1)
+		}, expectedCalls: []string{"rerun a 1"}},
+		{desc: "approves step", action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.ApproveStep(ctx, "a", 2)
+		}, expectedCalls: []string{"approve a 2"}},
+		{desc: "aborts run", action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.AbortRun(ctx, "a")
+		}, expectedCalls: []string{"abort a"}},
+		{desc: "returns not found", err: sdk.ErrNotFound, action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.TriggerPipeline(ctx, "a")
+		}, expectedErr: sdk.ErrNotFound, expectedCalls: []string{"trigger a"}},
+		{desc: "returns conflict", err: sdk.ErrConflict, action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.ApproveStep(ctx, "a", 0)
+		}, expectedErr: sdk.ErrConflict, expectedCalls: []string{"approve a 0"}},
+		{desc: "returns other errors", err: errors.New("some error"), action: func(ctx context.Context, p sdk.PipelineActionsProvider) error {
+			return p.AbortRun(ctx, "a")
+		}, expectedErr: sdk.ErrInternal, expectedCalls: []string{"abort a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := &fakePipelineActionsProvider{fakePipelineProvider: fakePipelineProvider{err: test.err}}
+			s := httptest.NewServer(sdk.NewPipelineProviderHandler(p))
+			defer s.Close()
+
+			err := test.action(context.Background(), NewPipelineActionsProviderClient(s.URL, nil))
+			// errors other than not found and conflicts only keep their message when crossing http
+			if fmt.Sprint(test.expectedErr) != fmt.Sprint(err) {
+				tt.Errorf("\nwanted error: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.expectedCalls, p.calls) {
+				tt.Errorf("\ndiff between calls\n%s\n", cmp.Diff(test.expectedCalls, p.calls))
+			}
+		})
+	}
+}
+
+type fakePipelineActionsProvider struct {
+	fakePipelineProvider
+	calls []string
+}
+
+func (f *fakePipelineActionsProvider) TriggerPipeline(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "trigger "+id)
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) RerunStep(ctx context.Context, id string, stepId int) error {
+	f.calls = append(f.calls, "rerun "+id+" "+strconv.Itoa(stepId))
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) ApproveStep(ctx context.Context, id string, stepId int) error {
+	f.calls = append(f.calls, "approve "+id+" "+strconv.Itoa(stepId))
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) AbortRun(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "abort "+id)
+	return f.err
+}
+
 type fakePipelineProvider struct {
 	err            error
 	pipelines      []sdk.Pipeline
@@ -263,7 +341,7 @@ type fakePipelineProvider struct {
 	versions       sdk.PipelineVersionList
 }
 
-func (f *fakePipelineProvider) ListUpdates(since time.Time) (sdk.PipelineUpdates, error) {
+func (f *fakePipelineProvider) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
 	updates := sdk.PipelineUpdates{}
 
 	for _, version := range f.versions {
@@ -289,14 +367,14 @@ func (f *fakePipelineProvider) ListUpdates(since time.Time) (sdk.PipelineUpdates
 	return updates, nil
 }
 
-func (f *fakePipelineProvider) ListPipelines() ([]sdk.Pipeline, error) {
+func (f *fakePipelineProvider) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
 	return f.pipelines, nil
 }
 
-func (f *fakePipelineProvider) GetPipeline(id string) (sdk.Pipeline, error) {
+func (f *fakePipelineProvider) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
 	f.recordedId = id
 	if f.err != nil {
 		return sdk.Pipeline{}, f.err
@@ -305,7 +383,7 @@ func (f *fakePipelineProvider) GetPipeline(id string) (sdk.Pipeline, error) {
 	return f.pipeline, nil
 }
 
-func (f *fakePipelineProvider) GetHistory(id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+func (f *fakePipelineProvider) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
 	f.recordedId = id
 	f.recordedBefore = before
 	f.recordedLimit = limit
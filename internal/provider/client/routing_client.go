@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"net/http"
 )
@@ -11,7 +12,7 @@ type getRoutingResponse struct {
 	Result sdk.AppRouting
 }
 
-func NewRoutingProviderClient(uri string, c *http.Client) sdk.RoutingProvider {
+func NewRoutingProviderClient(uri string, c *http.Client) sdk.RoutingProviderContext {
 	return &routingProviderClient{
 		baseClient: newBaseClient(uri+"/routing", c),
 	}
@@ -21,9 +22,9 @@ type routingProviderClient struct {
 	baseClient
 }
 
-func (c *routingProviderClient) GetAppRouting(id string) (sdk.AppRouting, error) {
+func (c *routingProviderClient) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
 	r := getRoutingResponse{}
-	err := c.get(&r, nil, id)
+	err := c.get(ctx, &r, nil, id)
 	if err != nil {
 		return sdk.AppRouting{}, err
 	}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
@@ -37,7 +38,7 @@ func TestGetAppRouting(t *testing.T) {
 
 			c := NewRoutingProviderClient(s.URL, nil)
 
-			routing, err := c.GetAppRouting(test.id)
+			routing, err := c.GetAppRouting(context.Background(), test.id)
 			if !errors.Is(err, test.expectedErr) {
 				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
 			}
@@ -60,7 +61,7 @@ type fakeRoutingProvider struct {
 	recordedId string
 }
 
-func (f *fakeRoutingProvider) GetAppRouting(id string) (sdk.AppRouting, error) {
+func (f *fakeRoutingProvider) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
 	f.recordedId = id
 	if f.err != nil {
 		return sdk.AppRouting{}, f.err
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type getStepLogsResponse struct {
+	Status int
+	Err    string `json:"error"`
+	Result sdk.StepLogsPage
+}
+
+func NewStepLogsProviderClient(uri string, c *http.Client) sdk.StepLogsProvider {
+	return &stepLogsProviderClient{
+		baseClient: newBaseClient(uri+"/steplogs", c),
+	}
+}
+
+type stepLogsProviderClient struct {
+	baseClient
+}
+
+func (s *stepLogsProviderClient) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (sdk.StepLogsPage, error) {
+	r := getStepLogsResponse{}
+	err := s.get(ctx, &r, map[string]string{
+		"perPage": strconv.Itoa(perPage),
+		"page":    strconv.Itoa(page),
+	}, pipelineId, "runs", runStarted.UTC().Format(time.RFC3339Nano), "steps", strconv.Itoa(stepId))
+	if err != nil {
+		return sdk.StepLogsPage{}, err
+	}
+	// the page is the only thing the caller can get wrong
+	if r.Status == http.StatusBadRequest && r.Err == sdk.ErrPageExceeded.Error() {
+		return sdk.StepLogsPage{}, sdk.ErrPageExceeded
+	} else if r.Status != http.StatusOK {
+		return sdk.StepLogsPage{}, errors.New(r.Err)
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetStepLogs(t *testing.T) {
+	lines := []sdk.LogLine{
+		{Time: someTime, Stream: sdk.LogStreamOut, Message: "first"},
+		{Time: someTime.Add(time.Second), Stream: sdk.LogStreamErr, Message: "second"},
+		{Time: someTime.Add(2 * time.Second), Stream: sdk.LogStreamOut, Message: "third"},
+	}
+
+	tests := []struct {
+		desc            string
+		err             error
+		run             time.Time
+		page            int
+		expected        sdk.StepLogsPage
+		expectedErr     error
+		expectedRequest string
+	}{
+		{desc: "returns step logs", run: someTime, expected: sdk.StepLogsPage{
+			Pagination: sdk.Pagination{TotalResults: 3, TotalPages: 2, PerPage: 2, Page: 1},
+			Lines:      lines[2:],
+		}, page: 1, expectedRequest: "a 2006-01-01T15:00:00Z 1 2 1"},
+		{desc: "keeps fractions of the run's start", run: someTime.Add(time.Millisecond).In(time.FixedZone("other", 3600)), expected: sdk.StepLogsPage{
+			Pagination: sdk.Pagination{TotalResults: 3, TotalPages: 2, PerPage: 2},
+			Lines:      lines[:2],
+		}, expectedRequest: "a 2006-01-01T15:00:00.001Z 1 2 0"},
+		{desc: "returns not found", err: sdk.ErrNotFound, run: someTime, expectedErr: sdk.ErrNotFound,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 2 0"},
+		{desc: "returns page exceeded", run: someTime, page: 2, expectedErr: sdk.ErrPageExceeded,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 2 2"},
+		{desc: "returns other errors", err: errors.New("some error"), run: someTime, expectedErr: sdk.ErrInternal,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 2 0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := &fakeStepLogsProvider{lines: lines, err: test.err}
+			s := httptest.NewServer(sdk.NewStepLogsProviderHandler(p))
+			defer s.Close()
+
+			c := NewStepLogsProviderClient(s.URL, nil)
+
+			page, err := c.GetStepLogs(context.Background(), "a", test.run, 1, 2, test.page)
+			// errors other than not found and exceeded pages only keep their message when crossing http
+			if fmt.Sprint(test.expectedErr) != fmt.Sprint(err) {
+				tt.Errorf("\nwanted error: %v\ngot: %v", test.expectedErr, err)
+			}
+
+			if !cmp.Equal(test.expected, page) {
+				tt.Errorf("\ndiff between pages\n%s\n", cmp.Diff(test.expected, page))
+			}
+
+			if test.expectedRequest != p.request {
+				tt.Errorf("\nwanted request '%s'\ngot '%s'", test.expectedRequest, p.request)
+			}
+		})
+	}
+}
+
+type fakeStepLogsProvider struct {
+	lines   []sdk.LogLine
+	err     error
+	request string
+}
+
+func (f *fakeStepLogsProvider) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (sdk.StepLogsPage, error) {
+	f.request = fmt.Sprintf("%s %s %d %d %d", pipelineId, runStarted.Format(time.RFC3339Nano), stepId, perPage, page)
+	if f.err != nil {
+		return sdk.StepLogsPage{}, f.err
+	}
+	return sdk.PageStepLogs(f.lines, perPage, page)
+}
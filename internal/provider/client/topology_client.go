@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+)
+
+func NewTopologyProviderClient(uri string, c *http.Client) sdk.TopologyProvider {
+	return &topologyProviderClient{
+		baseClient: newBaseClient(uri+"/topology", c),
+	}
+}
+
+type getTopologyResponse struct {
+	Status int
+	Err    string
+	Result sdk.Topology
+}
+
+type topologyProviderClient struct {
+	baseClient
+}
+
+func (p *topologyProviderClient) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	r := getTopologyResponse{}
+	err := p.get(ctx, &r, nil)
+	if err != nil {
+		return sdk.Topology{}, err
+	}
+	return r.Result, nil
+}
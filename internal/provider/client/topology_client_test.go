@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetTopology(t *testing.T) {
+	tests := []struct {
+		desc     string
+		state    fakeTopologyProvider
+		expected sdk.Topology
+	}{
+		{desc: "returns topology", state: fakeTopologyProvider{topology: sdk.Topology{
+			Services: []sdk.BackingService{{Id: "db", Name: "orders-db", Kind: "postgres"}},
+			Dependencies: []sdk.Dependency{
+				{AppId: "app-a", Target: sdk.DependencyTargetApp, TargetId: "app-b"},
+				{AppId: "app-b", Target: sdk.DependencyTargetService, TargetId: "db", Name: "binding"},
+			},
+		}}, expected: sdk.Topology{
+			Services: []sdk.BackingService{{Id: "db", Name: "orders-db", Kind: "postgres"}},
+			Dependencies: []sdk.Dependency{
+				{AppId: "app-a", Target: sdk.DependencyTargetApp, TargetId: "app-b"},
+				{AppId: "app-b", Target: sdk.DependencyTargetService, TargetId: "db", Name: "binding"},
+			},
+		}},
+		{desc: "returns empty topology", state: fakeTopologyProvider{topology: sdk.Topology{
+			Services:     []sdk.BackingService{},
+			Dependencies: []sdk.Dependency{},
+		}}, expected: sdk.Topology{
+			Services:     []sdk.BackingService{},
+			Dependencies: []sdk.Dependency{},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			s := httptest.NewServer(sdk.NewTopologyProviderHandler(&test.state))
+			defer s.Close()
+
+			c := NewTopologyProviderClient(s.URL, nil)
+
+			topology, err := c.GetTopology(context.Background())
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			if !cmp.Equal(test.expected, topology) {
+				tt.Errorf("\ndiff between topologies\n%s\n", cmp.Diff(test.expected, topology))
+			}
+		})
+	}
+}
+
+type fakeTopologyProvider struct {
+	topology sdk.Topology
+}
+
+func (f *fakeTopologyProvider) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	return f.topology, nil
+}
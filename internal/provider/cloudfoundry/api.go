@@ -1,24 +1,42 @@
 package cloudfoundry
 
 import (
+	"context"
 	cf "github.com/cloudfoundry-community/go-cfclient"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
 )
 
 const CFGuid = "main"
 
+// crashWindow is how far back the crashes of instances are counted.
+const crashWindow = 24 * time.Hour
+
 type CFLogin struct {
 	Api  string
 	User string
 	Pass string
 }
 
-// API is an abstraction around the CloudFoundry functionality.
+// API is an abstraction around the CloudFoundry functionality. The calls serving requests
+// take the context of the request, the ones of the reconciler run without.
 type API interface {
 	ListOrgs() ([]Org, error)
 	ListSpaces(orgGuid string) ([]Space, error)
 	ListApps(spaceGuid string) ([]App, error)
-	GetRoutes(appId string) (Routes, error)
-	GetInstances(appId string) (Instances, error)
+	GetRoutes(ctx context.Context, appId string) (Routes, error)
+	GetInstances(ctx context.Context, appId string) (Instances, error)
+	ListServiceBindings(ctx context.Context) ([]ServiceBinding, error)
+
+	StartApp(ctx context.Context, appId string) error
+	StopApp(ctx context.Context, appId string) error
+	RestartApp(ctx context.Context, appId string) error
+	ScaleApp(ctx context.Context, appId string, instances int) error
+	RestartInstance(ctx context.Context, appId string, index int) error
 }
 
 // CfCli is a wrapper interface for the official cloudfoundry client extracting the needed functions.
@@ -29,7 +47,20 @@ type CfCli interface {
 	ListSpacesByOrgGuid(orgGuid string) ([]cf.Space, error)
 	ListAppsBySpaceGuid(spaceGuid string) ([]cf.App, error)
 	GetAppRoutes(appGuid string) ([]cf.Route, error)
+	GetSharedDomainByGuid(guid string) (cf.SharedDomain, error)
+	GetDomainByGuid(guid string) (cf.Domain, error)
 	GetAppInstances(guid string) (map[string]cf.AppInstance, error)
+	GetAppStats(guid string) (map[string]cf.AppStats, error)
+	ListEventsByQuery(query url.Values) ([]cf.Event, error)
+	StartApp(guid string) error
+	StopApp(guid string) error
+	RestartApp(guid string) error
+	UpdateApp(guid string, aur cf.AppUpdateResource) (cf.UpdateResponse, error)
+	KillAppInstance(guid string, index string) error
+	ListServiceBindings() ([]cf.ServiceBinding, error)
+	ListServiceInstances() ([]cf.ServiceInstance, error)
+	ListUserProvidedServiceInstances() ([]cf.UserProvidedServiceInstance, error)
+	ListServices() ([]cf.Service, error)
 }
 
 func NewDefaultApi(l CFLogin) (API, error) {
@@ -42,62 +73,302 @@ func NewDefaultApi(l CFLogin) (API, error) {
 		return nil, err
 	}
 
-	return &api{cli: cli}, nil
+	return &api{
+		cli: cli,
+		withContext: func(ctx context.Context) CfCli {
+			return clientWithContext(ctx, cli)
+		},
+	}, nil
 }
 
+// NewApi creates an API for the given client. As the client can't be bound to a context,
+// calls aren't cancelled with their context.
 func NewApi(cli CfCli) API {
 	return &api{
 		cli: cli,
+		withContext: func(ctx context.Context) CfCli {
+			return cli
+		},
 	}
 }
 
 type api struct {
 	cli CfCli
+	// withContext returns the client to use for calls bound to the context.
+	withContext func(ctx context.Context) CfCli
+}
+
+// clientWithContext copies the client so that all of its requests are bound to the context.
+// The copy shares the connections and the token of the client.
+func clientWithContext(ctx context.Context, cli *cf.Client) CfCli {
+	c := *cli
+	httpClient := *cli.Config.HttpClient
+	httpClient.Transport = &contextTransport{ctx: ctx, base: httpClient.Transport}
+	c.Config.HttpClient = &httpClient
+	return &c
+}
+
+// contextTransport binds the requests to the context, as the CloudFoundry client creates
+// its requests without one.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(r.WithContext(t.ctx))
 }
 
-func (a *api) GetInstances(appId string) (Instances, error) {
-	instances, err := a.cli.GetAppInstances(appId)
+// GetInstances combines the instances of the app with their stats and their recent crashes.
+// Stats and crashes are looked up on a best-effort basis and left empty if that fails.
+func (a *api) GetInstances(ctx context.Context, appId string) (Instances, error) {
+	cli := a.withContext(ctx)
+	instances, err := cli.GetAppInstances(appId)
 	if err != nil {
 		return nil, err
 	}
+	stats, err := cli.GetAppStats(appId)
+	if err != nil {
+		log.Warn().Err(err).Str("app", appId).Msg("couldn't get stats of app instances")
+	}
+	crashes, err := recentCrashes(cli, appId)
+	if err != nil {
+		log.Warn().Err(err).Str("app", appId).Msg("couldn't get crashes of app instances")
+	}
 
 	var res Instances
-	for _, instance := range instances {
+	for i, instance := range instances {
+		index, err := strconv.Atoi(i)
+		if err != nil {
+			continue
+		}
+
+		crash := crashes[index]
 		res = append(res, Instance{
-			State: instance.State,
-			Since: instance.Since.Time,
+			Index:     index,
+			State:     instance.State,
+			Since:     instance.Since.Time,
+			Stats:     toInstanceStats(stats[i]),
+			Crashes:   crash.count,
+			LastCrash: crash.reason,
 		})
 	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Index < res[j].Index
+	})
 	return res, nil
 }
 
-func (a *api) GetRoutes(appId string) (Routes, error) {
-	routes, err := a.cli.GetAppRoutes(appId)
+type crash struct {
+	count  int
+	reason string
+}
+
+// recentCrashes counts the crash events of the app's instances within the crash window and
+// keeps the reason of the latest crash per instance.
+func recentCrashes(cli CfCli, appId string) (map[int]crash, error) {
+	events, err := cli.ListEventsByQuery(url.Values{
+		"q": []string{
+			"type:app.crash",
+			"actee:" + appId,
+			"timestamp>" + time.Now().Add(-crashWindow).UTC().Format(time.RFC3339),
+		},
+		"order-direction": []string{"desc"},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	domains := map[string]string{}
+	res := make(map[int]crash)
+	for _, event := range events {
+		index, ok := event.Metadata["index"].(float64)
+		if !ok {
+			continue
+		}
+		c, seen := res[int(index)]
+		if !seen {
+			c.reason, _ = event.Metadata["exit_description"].(string)
+		}
+		c.count++
+		res[int(index)] = c
+	}
+	return res, nil
+}
+
+func toInstanceStats(s cf.AppStats) *InstanceStats {
+	if s.Stats.Host == "" {
+		return nil
+	}
+	return &InstanceStats{
+		Cell:      s.Stats.Host,
+		Uptime:    int64(s.Stats.Uptime),
+		Cpu:       s.Stats.Usage.CPU,
+		Mem:       int64(s.Stats.Usage.Mem),
+		MemQuota:  int64(s.Stats.MemQuota),
+		Disk:      int64(s.Stats.Usage.Disk),
+		DiskQuota: int64(s.Stats.DiskQuota),
+	}
+}
+
+func (a *api) StartApp(ctx context.Context, appId string) error {
+	return a.withContext(ctx).StartApp(appId)
+}
+
+func (a *api) StopApp(ctx context.Context, appId string) error {
+	return a.withContext(ctx).StopApp(appId)
+}
+
+func (a *api) RestartApp(ctx context.Context, appId string) error {
+	return a.withContext(ctx).RestartApp(appId)
+}
+
+// ScaleApp stops the app when scaling to zero, as the client omits zero instances from updates.
+func (a *api) ScaleApp(ctx context.Context, appId string, instances int) error {
+	cli := a.withContext(ctx)
+	if instances == 0 {
+		return cli.StopApp(appId)
+	}
+
+	_, err := cli.UpdateApp(appId, cf.AppUpdateResource{Instances: instances})
+	return err
+}
+
+// RestartInstance kills the instance, which CloudFoundry then replaces with a new one.
+func (a *api) RestartInstance(ctx context.Context, appId string, index int) error {
+	cli := a.withContext(ctx)
+	instances, err := cli.GetAppInstances(appId)
+	if err != nil {
+		return err
+	}
+
+	i := strconv.Itoa(index)
+	if _, ok := instances[i]; !ok {
+		return errNotFound
+	}
+
+	return cli.KillAppInstance(appId, i)
+}
+
+func (a *api) GetRoutes(ctx context.Context, appId string) (Routes, error) {
+	cli := a.withContext(ctx)
+	routes, err := cli.GetAppRoutes(appId)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := map[string]domain{}
 
 	var res Routes
 	for _, route := range routes {
-		if domains[route.DomainGuid] == "" {
-			domain, err := route.Domain()
+		d, ok := domains[route.DomainGuid]
+		if !ok {
+			d, err = getDomain(cli, route.DomainGuid)
 			if err != nil {
 				return nil, err
 			}
-			domains[route.DomainGuid] = domain.Name
+			domains[route.DomainGuid] = d
+		}
+
+		host := d.name
+		if route.Host != "" {
+			host = route.Host + "." + d.name
 		}
 
 		res = append(res, Route{
-			Host: route.Host + "." + domains[route.DomainGuid],
-			Path: route.Path,
-			Port: route.Port,
+			Host:     host,
+			Domain:   d.name,
+			Path:     route.Path,
+			Port:     route.Port,
+			Tcp:      d.tcp,
+			Internal: d.internal,
 		})
 	}
 	return res, nil
 }
 
+// ListServiceBindings resolves the service instances the bindings point to. User-provided
+// instances aren't offered by a service and are labelled as such.
+func (a *api) ListServiceBindings(ctx context.Context) ([]ServiceBinding, error) {
+	cli := a.withContext(ctx)
+	bindings, err := cli.ListServiceBindings()
+	if err != nil {
+		return nil, err
+	}
+	services, err := cli.ListServices()
+	if err != nil {
+		return nil, err
+	}
+	managed, err := cli.ListServiceInstances()
+	if err != nil {
+		return nil, err
+	}
+	userProvided, err := cli.ListUserProvidedServiceInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(services))
+	for _, service := range services {
+		labels[service.Guid] = service.Label
+	}
+	instances := make(map[string]ServiceBinding, len(managed)+len(userProvided))
+	for _, instance := range managed {
+		instances[instance.Guid] = ServiceBinding{
+			InstanceGuid: instance.Guid,
+			InstanceName: instance.Name,
+			Service:      labels[instance.ServiceGuid],
+		}
+	}
+	for _, instance := range userProvided {
+		instances[instance.Guid] = ServiceBinding{
+			InstanceGuid: instance.Guid,
+			InstanceName: instance.Name,
+			Service:      userProvidedService,
+		}
+	}
+
+	var res []ServiceBinding
+	for _, binding := range bindings {
+		instance, ok := instances[binding.ServiceInstanceGuid]
+		if !ok {
+			continue
+		}
+		instance.AppGuid = binding.AppGuid
+		res = append(res, instance)
+	}
+	return res, nil
+}
+
+type domain struct {
+	name     string
+	tcp      bool
+	internal bool
+}
+
+// getDomain looks the domain up among the shared domains first. Only these can be internal
+// or route tcp traffic, the others are private domains of an org.
+func getDomain(cli CfCli, guid string) (domain, error) {
+	shared, err := cli.GetSharedDomainByGuid(guid)
+	if err == nil {
+		return domain{
+			name:     shared.Name,
+			tcp:      shared.RouterGroupType == "tcp",
+			internal: shared.Internal,
+		}, nil
+	}
+
+	private, err := cli.GetDomainByGuid(guid)
+	if err != nil {
+		return domain{}, err
+	}
+	return domain{name: private.Name}, nil
+}
+
 func (a *api) ListOrgs() ([]Org, error) {
 	orgs, err := a.cli.ListOrgs()
 	if err != nil {
@@ -1,8 +1,14 @@
 package cloudfoundry
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	cf "github.com/cloudfoundry-community/go-cfclient"
 	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -25,7 +31,7 @@ func TestGetOrg(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			api := NewApi(&fakeCfClient{test.state})
+			api := NewApi(&fakeCfClient{b: test.state})
 
 			spaces, _ := api.ListSpaces(test.guid)
 			if !cmp.Equal(test.expected, spaces) {
@@ -52,7 +58,7 @@ func TestGetSpace(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			api := NewApi(&fakeCfClient{test.state})
+			api := NewApi(&fakeCfClient{b: test.state})
 
 			apps, _ := api.ListApps(test.guid)
 			if !cmp.Equal(test.expectedApps, apps) {
@@ -77,7 +83,7 @@ func TestGetCfInfo(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			api := NewApi(&fakeCfClient{test.state})
+			api := NewApi(&fakeCfClient{b: test.state})
 
 			orgs, _ := api.ListOrgs()
 			if !cmp.Equal(test.expected, orgs) {
@@ -104,13 +110,56 @@ func TestGetInstances(t *testing.T) {
 				State: "STOPPED",
 			},
 		}},
+		{desc: "gets instances with stats and crashes", id: "app-a", state: cfBackend{
+			instances: map[string]map[string]cf.AppInstance{
+				"app-a": {"1": {State: "CRASHED"}, "0": {State: "RUNNING"}},
+			},
+			stats: map[string]map[string]cf.AppStats{
+				"app-a": {"0": runningStats("cell-a", 120, 0.5, 256, 1024)},
+			},
+			events: []cf.Event{
+				crashEvent("app-a", 1, "APP/PROC/WEB: Exited with status 137 (out of memory)"),
+				crashEvent("app-a", 1, "APP/PROC/WEB: Exited with status 1"),
+				crashEvent("app-b", 0, "APP/PROC/WEB: Exited with status 1"),
+			},
+		}, expected: Instances{
+			{
+				Index: 0,
+				State: "RUNNING",
+				Stats: &InstanceStats{Cell: "cell-a", Uptime: 120, Cpu: 0.5, Mem: 256, MemQuota: 1024},
+			},
+			{
+				Index:     1,
+				State:     "CRASHED",
+				Crashes:   2,
+				LastCrash: "APP/PROC/WEB: Exited with status 137 (out of memory)",
+			},
+		}},
+		{desc: "gets instances without stats and crashes if they fail", id: "app-a", state: cfBackend{
+			instances: map[string]map[string]cf.AppInstance{
+				"app-a": {"0": {State: "RUNNING"}},
+			},
+			stats: map[string]map[string]cf.AppStats{
+				"app-a": {"0": runningStats("cell-a", 120, 0.5, 256, 1024)},
+			},
+			events: []cf.Event{
+				crashEvent("app-a", 0, "APP/PROC/WEB: Exited with status 1"),
+			},
+			statsErr:  errors.New("stats failed"),
+			eventsErr: errors.New("events failed"),
+		}, expected: Instances{
+			{
+				Index: 0,
+				State: "RUNNING",
+			},
+		}},
 	}
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			api := NewApi(&fakeCfClient{test.state})
+			api := NewApi(&fakeCfClient{b: test.state})
 
-			res, _ := api.GetInstances(test.id)
+			res, _ := api.GetInstances(context.Background(), test.id)
 			if !cmp.Equal(test.expected, res) {
 				tt.Errorf("\nresult mismatch: \n%s\n", cmp.Diff(test.expected, res))
 			}
@@ -133,16 +182,34 @@ func TestGetRoutes(t *testing.T) {
 		}, expected: nil},
 		{desc: "gets routes", id: "app-a", state: cfBackend{
 			routes: map[string][]cf.Route{
-				"app-a": {},
+				"app-a": {
+					{Host: "app", Path: "/api", DomainGuid: "shared-a"},
+					{Port: 1025, DomainGuid: "tcp-a"},
+					{Host: "app", DomainGuid: "internal-a"},
+					{Host: "www", DomainGuid: "private-a"},
+				},
 			},
-		}, expected: nil},
+			sharedDomains: map[string]cf.SharedDomain{
+				"shared-a":   {Name: "example.com"},
+				"tcp-a":      {Name: "tcp.example.com", RouterGroupType: "tcp"},
+				"internal-a": {Name: "apps.internal", Internal: true},
+			},
+			domains: map[string]cf.Domain{
+				"private-a": {Name: "org.com"},
+			},
+		}, expected: Routes{
+			{Host: "app.example.com", Domain: "example.com", Path: "/api"},
+			{Host: "tcp.example.com", Domain: "tcp.example.com", Port: 1025, Tcp: true},
+			{Host: "app.apps.internal", Domain: "apps.internal", Internal: true},
+			{Host: "www.org.com", Domain: "org.com"},
+		}},
 	}
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			api := NewApi(&fakeCfClient{test.state})
+			api := NewApi(&fakeCfClient{b: test.state})
 
-			res, _ := api.GetRoutes(test.id)
+			res, _ := api.GetRoutes(context.Background(), test.id)
 			if !cmp.Equal(test.expected, res) {
 				tt.Errorf("\nresult mismatch: \n%s\n", cmp.Diff(test.expected, res))
 			}
@@ -150,8 +217,91 @@ func TestGetRoutes(t *testing.T) {
 	}
 }
 
+func TestApiAppActions(t *testing.T) {
+	state := cfBackend{instances: map[string]map[string]cf.AppInstance{
+		"app-a": {"0": {State: "RUNNING"}, "1": {State: "CRASHED"}},
+	}}
+
+	tests := []struct {
+		desc          string
+		action        func(a API) error
+		expectedErr   error
+		expectedCalls []string
+	}{
+		{desc: "starts app", action: func(a API) error { return a.StartApp(context.Background(), "app-a") }, expectedCalls: []string{"start app-a"}},
+		{desc: "stops app", action: func(a API) error { return a.StopApp(context.Background(), "app-a") }, expectedCalls: []string{"stop app-a"}},
+		{desc: "restarts app", action: func(a API) error { return a.RestartApp(context.Background(), "app-a") }, expectedCalls: []string{"restart app-a"}},
+		{desc: "scales app", action: func(a API) error { return a.ScaleApp(context.Background(), "app-a", 3) }, expectedCalls: []string{"update app-a instances=3"}},
+		{desc: "stops app scaled to zero", action: func(a API) error { return a.ScaleApp(context.Background(), "app-a", 0) }, expectedCalls: []string{"stop app-a"}},
+		{desc: "restarts instance", action: func(a API) error { return a.RestartInstance(context.Background(), "app-a", 1) }, expectedCalls: []string{"kill app-a 1"}},
+		{desc: "doesn't restart unknown instance", action: func(a API) error { return a.RestartInstance(context.Background(), "app-a", 2) }, expectedErr: errNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			cli := &fakeCfClient{b: state}
+			err := test.action(NewApi(cli))
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expectedCalls, cli.calls) {
+				tt.Errorf("\ndiff between calls: \n%s\n", cmp.Diff(test.expectedCalls, cli.calls))
+			}
+		})
+	}
+}
+
+func TestClientWithContext(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	cli := &cf.Client{Config: cf.Config{ApiAddress: s.URL, HttpClient: &http.Client{}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+
+	err := clientWithContext(ctx, cli).StartApp("app-a")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("\nwanted error: %v, got %v\n", context.Canceled, err)
+	}
+	if cli.Config.HttpClient.Transport != nil {
+		t.Error("expected the original client to stay unbound")
+	}
+}
+
+func TestListServiceBindings(t *testing.T) {
+	state := cfBackend{
+		bindings: []cf.ServiceBinding{
+			{AppGuid: "app-a", ServiceInstanceGuid: "instance-a"},
+			{AppGuid: "app-b", ServiceInstanceGuid: "instance-a"},
+			{AppGuid: "app-b", ServiceInstanceGuid: "instance-b"},
+			{AppGuid: "app-b", ServiceInstanceGuid: "instance-gone"},
+		},
+		serviceInstances: []cf.ServiceInstance{{Guid: "instance-a", Name: "db", ServiceGuid: "service-a"}},
+		userProvided:     []cf.UserProvidedServiceInstance{{Guid: "instance-b", Name: "logs"}},
+		services:         []cf.Service{{Guid: "service-a", Label: "postgresql"}},
+	}
+
+	api := NewApi(&fakeCfClient{b: state})
+	bindings, err := api.ListServiceBindings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ServiceBinding{
+		{AppGuid: "app-a", InstanceGuid: "instance-a", InstanceName: "db", Service: "postgresql"},
+		{AppGuid: "app-b", InstanceGuid: "instance-a", InstanceName: "db", Service: "postgresql"},
+		{AppGuid: "app-b", InstanceGuid: "instance-b", InstanceName: "logs", Service: "user-provided"},
+	}
+	if !cmp.Equal(expected, bindings) {
+		t.Errorf("\ndiff between bindings: \n%s\n", cmp.Diff(expected, bindings))
+	}
+}
+
 type fakeCfClient struct {
-	b cfBackend
+	b     cfBackend
+	calls []string
 }
 
 func (f *fakeCfClient) GetAppRoutes(appGuid string) ([]cf.Route, error) {
@@ -168,12 +318,122 @@ func (f *fakeCfClient) GetAppInstances(guid string) (map[string]cf.AppInstance,
 	return f.b.instances[guid], nil
 }
 
+func (f *fakeCfClient) GetSharedDomainByGuid(guid string) (cf.SharedDomain, error) {
+	d, ok := f.b.sharedDomains[guid]
+	if !ok {
+		return cf.SharedDomain{}, errNotFound
+	}
+	return d, nil
+}
+
+func (f *fakeCfClient) GetDomainByGuid(guid string) (cf.Domain, error) {
+	d, ok := f.b.domains[guid]
+	if !ok {
+		return cf.Domain{}, errNotFound
+	}
+	return d, nil
+}
+
+func (f *fakeCfClient) GetAppStats(guid string) (map[string]cf.AppStats, error) {
+	if f.b.statsErr != nil {
+		return nil, f.b.statsErr
+	}
+	return f.b.stats[guid], nil
+}
+
+// ListEventsByQuery only filters by actee, the events are expected to be ordered newest first.
+func (f *fakeCfClient) ListEventsByQuery(query url.Values) ([]cf.Event, error) {
+	if f.b.eventsErr != nil {
+		return nil, f.b.eventsErr
+	}
+
+	var res []cf.Event
+	for _, event := range f.b.events {
+		for _, q := range query["q"] {
+			if q == "actee:"+event.Actee {
+				res = append(res, event)
+			}
+		}
+	}
+	return res, nil
+}
+
+func runningStats(cell string, uptime int, cpu float64, mem int, memQuota int) cf.AppStats {
+	s := cf.AppStats{State: "RUNNING"}
+	s.Stats.Host = cell
+	s.Stats.Uptime = uptime
+	s.Stats.Usage.CPU = cpu
+	s.Stats.Usage.Mem = mem
+	s.Stats.MemQuota = memQuota
+	return s
+}
+
+func crashEvent(app string, index int, reason string) cf.Event {
+	return cf.Event{Type: "app.crash", Actee: app, Metadata: map[string]interface{}{
+		"index":            float64(index),
+		"exit_description": reason,
+	}}
+}
+
+func (f *fakeCfClient) StartApp(guid string) error {
+	f.calls = append(f.calls, "start "+guid)
+	return nil
+}
+
+func (f *fakeCfClient) StopApp(guid string) error {
+	f.calls = append(f.calls, "stop "+guid)
+	return nil
+}
+
+func (f *fakeCfClient) RestartApp(guid string) error {
+	f.calls = append(f.calls, "restart "+guid)
+	return nil
+}
+
+func (f *fakeCfClient) UpdateApp(guid string, aur cf.AppUpdateResource) (cf.UpdateResponse, error) {
+	f.calls = append(f.calls, fmt.Sprintf("update %s instances=%d", guid, aur.Instances))
+	return cf.UpdateResponse{}, nil
+}
+
+func (f *fakeCfClient) KillAppInstance(guid string, index string) error {
+	f.calls = append(f.calls, "kill "+guid+" "+index)
+	return nil
+}
+
+func (f *fakeCfClient) ListServiceBindings() ([]cf.ServiceBinding, error) {
+	return f.b.bindings, nil
+}
+
+func (f *fakeCfClient) ListServiceInstances() ([]cf.ServiceInstance, error) {
+	return f.b.serviceInstances, nil
+}
+
+func (f *fakeCfClient) ListUserProvidedServiceInstances() ([]cf.UserProvidedServiceInstance, error) {
+	return f.b.userProvided, nil
+}
+
+func (f *fakeCfClient) ListServices() ([]cf.Service, error) {
+	return f.b.services, nil
+}
+
 type cfBackend struct {
 	orgs      map[string]*cf.Org
 	spaces    map[string]*cf.Space
 	apps      map[string]*cf.App
 	routes    map[string][]cf.Route
 	instances map[string]map[string]cf.AppInstance
+	stats     map[string]map[string]cf.AppStats
+	events    []cf.Event
+	statsErr  error
+	eventsErr error
+
+	bindings         []cf.ServiceBinding
+	serviceInstances []cf.ServiceInstance
+	userProvided     []cf.UserProvidedServiceInstance
+	services         []cf.Service
+
+	sharedDomains map[string]cf.SharedDomain
+	domains       map[string]cf.Domain
 }
 
 func (f *fakeCfClient) ListOrgs() ([]cf.Org, error) {
@@ -51,18 +51,59 @@ type App struct {
 
 type Routes []Route
 type Route struct {
-	Host string `bson:"host"`
-	Path string `bson:"path"`
-	Port int    `bson:"port"`
+	Host     string `bson:"host"`
+	Domain   string `bson:"domain"`
+	Path     string `bson:"path"`
+	Port     int    `bson:"port"`
+	Tcp      bool   `bson:"tcp"`
+	Internal bool   `bson:"internal"`
+}
+
+// userProvidedService labels bindings to instances not offered by a service broker.
+const userProvidedService = "user-provided"
+
+type ServiceBinding struct {
+	AppGuid      string
+	InstanceGuid string
+	InstanceName string
+	Service      string
 }
 
 type Instances []Instance
 type Instance struct {
+	Index int
 	State string
 	Since time.Time
+	Stats *InstanceStats
+	// Crashes counts the crashes of the instance within the crash window, CloudFoundry doesn't
+	// keep track of how often instances were restarted.
+	Crashes   int
+	LastCrash string
 }
 
-func (a App) toSdkApp() sdk.App {
+// InstanceStats are only reported for running instances.
+type InstanceStats struct {
+	Cell      string
+	Uptime    int64
+	Cpu       float64
+	Mem       int64
+	MemQuota  int64
+	Disk      int64
+	DiskQuota int64
+}
+
+// Teams maps org names, or space names qualified as 'org/space', to the id of the team owning
+// their apps. Spaces take precedence over their org.
+type Teams map[string]string
+
+func (t Teams) owner(space SpaceInfo) string {
+	if team, ok := t[space.Org.Name+"/"+space.Name]; ok {
+		return team
+	}
+	return t[space.Org.Name]
+}
+
+func (a App) toSdkApp(teams Teams) sdk.App {
 	app := sdk.App{
 		Id:       a.Guid,
 		Name:     a.Name,
@@ -80,6 +121,10 @@ func (a App) toSdkApp() sdk.App {
 		app.Position = append(app.Position, a.Space.Name)
 	}
 
+	if team := teams.owner(a.Space); team != "" {
+		app.Labels["team"] = team
+	}
+
 	if len(app.Labels) == 0 {
 		app.Labels = nil
 	}
@@ -0,0 +1,144 @@
+package cloudfoundry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	cf "github.com/cloudfoundry-community/go-cfclient"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLogCacheLimit is the most envelopes the log cache returns per request.
+const maxLogCacheLimit = 1000
+
+// LogCache is an abstraction around the log cache of CloudFoundry, which keeps the recent
+// logs of apps that loggregator collected.
+type LogCache interface {
+	ReadLogs(ctx context.Context, appGuid string, q LogQuery) ([]Log, error)
+}
+
+type LogQuery struct {
+	// Start is the time of the oldest log to return.
+	Start time.Time
+	Limit int
+	// Descending returns the newest logs first, which combined with Limit returns the latest logs.
+	Descending bool
+}
+
+type Log struct {
+	Time     time.Time
+	Instance string
+	Source   string
+	Err      bool
+	Message  string
+}
+
+// NewDefaultLogCache logs in to CloudFoundry and reads from the log cache at the given uri.
+// If uri is empty, it is derived from the api address.
+func NewDefaultLogCache(l CFLogin, uri string) (LogCache, error) {
+	cli, err := cf.NewClient(&cf.Config{
+		ApiAddress: l.Api,
+		Username:   l.User,
+		Password:   l.Pass,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if uri == "" {
+		uri = strings.Replace(cli.Config.ApiAddress, "://api.", "://log-cache.", 1)
+	}
+
+	return NewLogCache(uri, cli.Config.HttpClient), nil
+}
+
+// NewLogCache reads from the log cache at the given uri. The client has to authenticate
+// against CloudFoundry.
+func NewLogCache(uri string, c *http.Client) LogCache {
+	return &logCache{
+		uri: strings.TrimSuffix(uri, "/"),
+		c:   c,
+	}
+}
+
+type logCache struct {
+	uri string
+	c   *http.Client
+}
+
+type logCacheResponse struct {
+	Envelopes struct {
+		Batch []logCacheEnvelope `json:"batch"`
+	} `json:"envelopes"`
+}
+
+type logCacheEnvelope struct {
+	Timestamp  string            `json:"timestamp"`
+	InstanceId string            `json:"instance_id"`
+	Tags       map[string]string `json:"tags"`
+	Log        struct {
+		Payload []byte `json:"payload"`
+		Type    string `json:"type"`
+	} `json:"log"`
+}
+
+func (l *logCache) ReadLogs(ctx context.Context, appGuid string, q LogQuery) ([]Log, error) {
+	limit := q.Limit
+	if limit <= 0 || limit > maxLogCacheLimit {
+		limit = maxLogCacheLimit
+	}
+
+	query := url.Values{}
+	query.Set("envelope_types", "LOG")
+	query.Set("limit", strconv.Itoa(limit))
+	if !q.Start.IsZero() {
+		query.Set("start_time", strconv.FormatInt(q.Start.UnixNano(), 10))
+	}
+	if q.Descending {
+		query.Set("descending", "true")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", l.uri+"/api/v1/read/"+url.PathEscape(appGuid)+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := l.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading logs of app '%s' failed with status %d", appGuid, res.StatusCode)
+	}
+
+	r := logCacheResponse{}
+	err = json.NewDecoder(res.Body).Decode(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []Log
+	for _, e := range r.Envelopes.Batch {
+		nanos, err := strconv.ParseInt(e.Timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed timestamp '%s' in logs of app '%s': %w", e.Timestamp, appGuid, err)
+		}
+		logs = append(logs, Log{
+			Time:     time.Unix(0, nanos).UTC(),
+			Instance: e.InstanceId,
+			Source:   e.Tags["source_type"],
+			Err:      e.Log.Type == "ERR",
+			Message:  strings.TrimRight(string(e.Log.Payload), "\n"),
+		})
+	}
+	return logs, nil
+}
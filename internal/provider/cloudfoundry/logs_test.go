@@ -0,0 +1,126 @@
+package cloudfoundry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var logTime = time.Unix(0, 1600000000123456789).UTC()
+
+func TestLogCacheReadLogs(t *testing.T) {
+	tests := []struct {
+		desc          string
+		app           string
+		q             LogQuery
+		status        int
+		body          interface{}
+		expected      []Log
+		expectedQuery url.Values
+		expectedErr   error
+	}{
+		{desc: "reads logs", app: "app-guid", q: LogQuery{Start: logTime, Limit: 10, Descending: true}, status: http.StatusOK, body: map[string]interface{}{
+			"envelopes": map[string]interface{}{"batch": []interface{}{
+				map[string]interface{}{
+					"timestamp":   "1600000000123456789",
+					"instance_id": "0",
+					"tags":        map[string]string{"source_type": "APP/PROC/WEB"},
+					"log":         map[string]interface{}{"payload": []byte("hello\n"), "type": "OUT"},
+				},
+				map[string]interface{}{
+					"timestamp": "1600000000123456790",
+					"log":       map[string]interface{}{"payload": []byte("failed"), "type": "ERR"},
+				},
+			}},
+		}, expected: []Log{
+			{Time: logTime, Instance: "0", Source: "APP/PROC/WEB", Message: "hello"},
+			{Time: logTime.Add(time.Nanosecond), Err: true, Message: "failed"},
+		}, expectedQuery: url.Values{
+			"envelope_types": {"LOG"},
+			"start_time":     {"1600000000123456789"},
+			"limit":          {"10"},
+			"descending":     {"true"},
+		}},
+		{desc: "caps limit", app: "app-guid", status: http.StatusOK, body: map[string]interface{}{}, expectedQuery: url.Values{
+			"envelope_types": {"LOG"},
+			"limit":          {"1000"},
+		}},
+		{desc: "returns not found", app: "app-guid", status: http.StatusNotFound, expectedErr: errNotFound, expectedQuery: url.Values{
+			"envelope_types": {"LOG"},
+			"limit":          {"1000"},
+		}},
+		{desc: "returns error for malformed timestamp", app: "app-guid", status: http.StatusOK, body: map[string]interface{}{
+			"envelopes": map[string]interface{}{"batch": []interface{}{
+				map[string]interface{}{"timestamp": "yesterday"},
+			}},
+		}, expectedErr: strconv.ErrSyntax, expectedQuery: url.Values{
+			"envelope_types": {"LOG"},
+			"limit":          {"1000"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			var path string
+			var query url.Values
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				query = r.URL.Query()
+				w.WriteHeader(test.status)
+				_ = json.NewEncoder(w).Encode(test.body)
+			}))
+			defer s.Close()
+
+			logs, err := NewLogCache(s.URL, s.Client()).ReadLogs(context.Background(), test.app, test.q)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+			if path != "/api/v1/read/"+test.app {
+				tt.Errorf("\nwanted path /api/v1/read/%s\ngot: %s", test.app, path)
+			}
+			if !cmp.Equal(test.expectedQuery, query) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(test.expectedQuery, query))
+			}
+			if !cmp.Equal(test.expected, logs) {
+				tt.Errorf("\ndiff between logs: \n%s\n", cmp.Diff(test.expected, logs))
+			}
+		})
+	}
+}
+
+type fakeLogCache struct {
+	logs    []Log
+	err     error
+	queries []LogQuery
+}
+
+// ReadLogs returns the logs starting at the query, in the requested order.
+func (f *fakeLogCache) ReadLogs(ctx context.Context, appGuid string, q LogQuery) ([]Log, error) {
+	f.queries = append(f.queries, q)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var res []Log
+	for _, l := range f.logs {
+		if !l.Time.Before(q.Start) {
+			res = append(res, l)
+		}
+	}
+	if q.Descending {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+	if q.Limit > 0 && len(res) > q.Limit {
+		res = res[:q.Limit]
+	}
+	return res, nil
+}
@@ -41,12 +41,18 @@ func NewMongoDatabase(l MongoLogin) (Database, error) {
 		apps:    db.Collection("apps"),
 		cache:   db.Collection("cache"),
 	}
+	m.MongoJobStore = recon.NewMongoJobStore(
+		recon.JobCollection{Type: ReconcileOrganizations, Collection: m.cfInfos},
+		recon.JobCollection{Type: ReconcileSpaces, Collection: m.orgs},
+		recon.JobCollection{Type: ReconcileApps, Collection: m.spaces},
+	)
 
 	err = m.setupBaseJob()
 	return m, err
 }
 
 type mongoDatabase struct {
+	*recon.MongoJobStore
 	cli     *mongo.Client
 	db      *mongo.Database
 	orgs    *mongo.Collection
@@ -86,6 +92,9 @@ func (d *mongoDatabase) GetApp(id string) (App, error) {
 			"$eq": id,
 		},
 	})
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return App{}, errNotFound
+	}
 	if res.Err() != nil {
 		return App{}, res.Err()
 	}
@@ -269,15 +278,24 @@ func (d *mongoDatabase) AcceptReconcileJob(olderThan time.Duration) (recon.Job,
 func (d *mongoDatabase) acceptCollectionReconcileJob(typ recon.Type, coll *mongo.Collection, t time.Time, olderThan time.Duration) (recon.Job, bool) {
 	lessThanTime := t.Add(-olderThan)
 	res := coll.FindOneAndUpdate(d.ctx, bson.M{
+		"deadLettered": bson.M{"$ne": true},
 		"$or": bson.A{
 			bson.M{
+				"nextAttempt": bson.M{"$lte": t},
+			},
+			bson.M{
+				"nextAttempt": nil,
 				"lastUpdated": bson.M{"$lte": lessThanTime},
 			},
-			bson.M{"lastUpdated": nil},
+			bson.M{
+				"nextAttempt": nil,
+				"lastUpdated": nil,
+			},
 		},
 	}, bson.M{
 		"$set": bson.M{
 			"lastUpdated": t,
+			"nextAttempt": nil,
 		},
 	}, options.FindOneAndUpdate().SetSort(bson.D{{"lastUpdated", 1}}))
 
@@ -1,23 +1,33 @@
 package cloudfoundry
 
 import (
+	"context"
+	"errors"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"time"
 )
 
-func NewProvider(db Database, cf API) *Provider {
+// logsPollInterval is how often the log cache is asked for new lines while tailing.
+var logsPollInterval = time.Second
+
+func NewProvider(db Database, cf API, logs LogCache, teams Teams) *Provider {
 	return &Provider{
-		db: db,
-		cf: cf,
+		db:    db,
+		cf:    cf,
+		logs:  logs,
+		teams: teams,
 	}
 }
 
 type Provider struct {
-	db Database
-	cf API
+	db    Database
+	cf    API
+	logs  LogCache
+	teams Teams
 }
 
-func (p *Provider) ListApps() ([]sdk.App, error) {
+func (p *Provider) ListApps(ctx context.Context) ([]sdk.App, error) {
 	cfApps, err := p.db.ListApps()
 	if err != nil {
 		return nil, err
@@ -25,35 +35,31 @@ func (p *Provider) ListApps() ([]sdk.App, error) {
 
 	var res []sdk.App
 	for _, app := range cfApps {
-		res = append(res, app.toSdkApp())
+		res = append(res, app.toSdkApp(p.teams))
 	}
 	return res, nil
 }
 
-func (p *Provider) GetApp(id string) (sdk.App, error) {
+func (p *Provider) GetApp(ctx context.Context, id string) (sdk.App, error) {
 	app, err := p.db.GetApp(id)
 	if err != nil {
 		return sdk.App{}, err
 	}
 
-	return app.toSdkApp(), nil
+	return app.toSdkApp(p.teams), nil
 }
 
-func (p *Provider) GetAppRouting(id string) (sdk.AppRouting, error) {
+func (p *Provider) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
 	cached := sdk.AppRouting{}
 
 	res, err := p.db.Cached(id+"/routing", 5*time.Second, &cached, func() (interface{}, error) {
-		routes, err := p.cf.GetRoutes(id)
+		routes, err := p.cf.GetRoutes(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		appRouting := sdk.AppRouting{}
 		for _, route := range routes {
-			appRouting.Routes = append(appRouting.Routes, sdk.AppRoute{
-				Host:    route.Host,
-				Path:    route.Path,
-				AppPort: route.Port,
-			})
+			appRouting.Routes = append(appRouting.Routes, route.toSdkRoute())
 		}
 		return appRouting, nil
 	})
@@ -67,19 +73,16 @@ func (p *Provider) GetAppRouting(id string) (sdk.AppRouting, error) {
 	return cached, nil
 }
 
-func (p *Provider) GetAppInstances(id string) (sdk.AppInstances, error) {
+func (p *Provider) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
 	cached := sdk.AppInstances{}
 	res, err := p.db.Cached(id+"/instances", 5*time.Second, &cached, func() (interface{}, error) {
-		instances, err := p.cf.GetInstances(id)
+		instances, err := p.cf.GetInstances(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		appInstances := sdk.AppInstances{}
 		for _, instance := range instances {
-			appInstances = append(appInstances, sdk.AppInstance{
-				State: cfStateToSdkState(instance.State),
-				Since: instance.Since,
-			})
+			appInstances = append(appInstances, instance.toSdkInstance())
 		}
 		return appInstances, nil
 	})
@@ -92,12 +95,252 @@ func (p *Provider) GetAppInstances(id string) (sdk.AppInstances, error) {
 	return cached, nil
 }
 
+// GetTopology reports the service instances bound to the known apps. CloudFoundry doesn't know
+// about apps calling each other, so there are no dependencies between apps.
+func (p *Provider) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	cached := sdk.Topology{}
+	res, err := p.db.Cached("topology", 30*time.Second, &cached, func() (interface{}, error) {
+		apps, err := p.db.ListApps()
+		if err != nil {
+			return nil, err
+		}
+		bindings, err := p.cf.ListServiceBindings(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		known := make(map[string]bool, len(apps))
+		for _, app := range apps {
+			known[app.Guid] = true
+		}
+
+		topology := sdk.Topology{}
+		services := make(map[string]bool)
+		for _, binding := range bindings {
+			if !known[binding.AppGuid] {
+				continue
+			}
+			if !services[binding.InstanceGuid] {
+				services[binding.InstanceGuid] = true
+				topology.Services = append(topology.Services, sdk.BackingService{
+					Id:   binding.InstanceGuid,
+					Name: binding.InstanceName,
+					Kind: binding.Service,
+				})
+			}
+			topology.Dependencies = append(topology.Dependencies, sdk.Dependency{
+				AppId:    binding.AppGuid,
+				Target:   sdk.DependencyTargetService,
+				TargetId: binding.InstanceGuid,
+			})
+		}
+		return topology, nil
+	})
+	if err != nil {
+		return sdk.Topology{}, err
+	}
+	if res != nil {
+		return res.(sdk.Topology), nil
+	}
+	return cached, nil
+}
+
+func (p *Provider) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	err := p.checkAppExists(id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := p.logs.ReadLogs(ctx, id, LogQuery{Start: since, Limit: limit, Descending: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := sdk.AppLogs{}
+	for i := len(logs) - 1; i >= 0; i-- {
+		res = append(res, logs[i].toSdkLogLine())
+	}
+	return res, nil
+}
+
+// TailLogs polls the log cache for lines logged after the call, as the log cache only
+// serves what loggregator already collected.
+func (p *Provider) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	err := p.checkAppExists(id)
+	if err != nil {
+		return err
+	}
+
+	start := currentTime()
+	t := time.NewTicker(logsPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+
+		logs, err := p.logs.ReadLogs(ctx, id, LogQuery{Start: start})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, l := range logs {
+			select {
+			case lines <- l.toSdkLogLine():
+			case <-ctx.Done():
+				return nil
+			}
+			start = l.Time.Add(time.Nanosecond)
+		}
+	}
+}
+
+func (p *Provider) RestartApp(ctx context.Context, id string) error {
+	return p.takeAction(ctx, id, p.cf.RestartApp)
+}
+
+func (p *Provider) StopApp(ctx context.Context, id string) error {
+	return p.takeAction(ctx, id, p.cf.StopApp)
+}
+
+func (p *Provider) StartApp(ctx context.Context, id string) error {
+	return p.takeAction(ctx, id, p.cf.StartApp)
+}
+
+func (p *Provider) ScaleApp(ctx context.Context, id string, instances int) error {
+	return p.takeAction(ctx, id, func(ctx context.Context, appId string) error {
+		return p.cf.ScaleApp(ctx, appId, instances)
+	})
+}
+
+func (p *Provider) RestartAppInstance(ctx context.Context, id string, index int) error {
+	return p.takeAction(ctx, id, func(ctx context.Context, appId string) error {
+		return p.cf.RestartInstance(ctx, appId, index)
+	})
+}
+
+// takeAction runs the action against CloudFoundry for known apps only.
+func (p *Provider) takeAction(ctx context.Context, id string, action func(ctx context.Context, appId string) error) error {
+	err := p.checkAppExists(id)
+	if err != nil {
+		return err
+	}
+
+	err = action(ctx, id)
+	if errors.Is(err, errNotFound) {
+		return sdk.ErrNotFound
+	}
+	return err
+}
+
+func (p *Provider) checkAppExists(id string) error {
+	_, err := p.db.GetApp(id)
+	if errors.Is(err, errNotFound) {
+		return sdk.ErrNotFound
+	}
+	return err
+}
+
+func (p *Provider) ListDeadLetters(ctx context.Context) ([]sdk.DeadLetter, error) {
+	jobs, err := p.db.ListDeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.DeadLetter, 0, len(jobs))
+	for _, j := range jobs {
+		res = append(res, sdk.DeadLetter{
+			Type:        string(j.Type),
+			Guid:        j.Guid,
+			Attempts:    j.Attempts,
+			LastError:   j.LastError,
+			LastAttempt: j.LastUpdated,
+		})
+	}
+	return res, nil
+}
+
+func (p *Provider) RequeueDeadLetter(ctx context.Context, jobType string, guid string) error {
+	err := p.db.RequeueDeadLetter(recon.Type(jobType), guid)
+	if errors.Is(err, recon.ErrJobNotFound) {
+		return sdk.ErrNotFound
+	}
+	return err
+}
+
+func (l Log) toSdkLogLine() sdk.LogLine {
+	stream := sdk.LogStreamOut
+	if l.Err {
+		stream = sdk.LogStreamErr
+	}
+	return sdk.LogLine{
+		Time:     l.Time,
+		Instance: l.Instance,
+		Source:   l.Source,
+		Stream:   stream,
+		Message:  l.Message,
+	}
+}
+
+// toSdkRoute leaves the TLS of the route unknown, as certificates are terminated in front of
+// CloudFoundry's routers.
+func (r Route) toSdkRoute() sdk.AppRoute {
+	protocol := sdk.RouteProtocolHttp
+	if r.Tcp {
+		protocol = sdk.RouteProtocolTcp
+	}
+	return sdk.AppRoute{
+		Host:     r.Host,
+		Path:     r.Path,
+		AppPort:  r.Port,
+		Domain:   r.Domain,
+		Protocol: protocol,
+		Internal: r.Internal,
+	}
+}
+
+// toSdkInstance reports the recent crashes as restarts, as CloudFoundry restarts crashed
+// instances and doesn't count restarts itself.
+func (i Instance) toSdkInstance() sdk.AppInstance {
+	instance := sdk.AppInstance{
+		Index:     i.Index,
+		State:     cfStateToSdkState(i.State),
+		Since:     i.Since,
+		Restarts:  i.Crashes,
+		LastCrash: i.LastCrash,
+	}
+	if i.Stats != nil {
+		instance.Host = i.Stats.Cell
+		instance.Uptime = i.Stats.Uptime
+		instance.Usage = &sdk.InstanceUsage{
+			Cpu:         i.Stats.Cpu,
+			Memory:      i.Stats.Mem,
+			MemoryQuota: i.Stats.MemQuota,
+			Disk:        i.Stats.Disk,
+			DiskQuota:   i.Stats.DiskQuota,
+		}
+	}
+	return instance
+}
+
+// cfStateToSdkState maps the states of both apps and their instances. Flapping instances
+// crash repeatedly, down instances are gone, e.g. with the cell they ran on.
 func cfStateToSdkState(state string) sdk.AppState {
 	switch state {
-	case "STOPPED":
+	case "STOPPED", "DOWN":
 		return sdk.AppStateStopped
-	case "STARTED":
+	case "STARTED", "RUNNING":
 		return sdk.AppStateRunning
+	case "STARTING":
+		return sdk.AppStateStarting
+	case "CRASHED", "FLAPPING":
+		return sdk.AppStateCrashed
 	default:
 		return sdk.AppStateUnknown
 	}
@@ -1,10 +1,13 @@
 package cloudfoundry
 
 import (
+	"context"
 	"errors"
 	"github.com/google/go-cmp/cmp"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"testing"
+	"time"
 )
 
 var someErr = errors.New("error")
@@ -28,8 +31,8 @@ func TestListApps(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			p := NewProvider(test.db, nil)
-			apps, err := p.ListApps()
+			p := NewProvider(test.db, nil, nil, nil)
+			apps, err := p.ListApps(context.Background())
 
 			if err != test.expectedErr {
 				tt.Errorf("\ndiff between errors: \n%s\n", cmp.Diff(test.expectedErr, err))
@@ -42,6 +45,29 @@ func TestListApps(t *testing.T) {
 	}
 }
 
+func TestListAppsWithTeams(t *testing.T) {
+	db := &fakeDb{b: backend{Apps: map[string]*App{
+		"app-guid-a": {AppInfo: AppInfo{Guid: "app-guid-a", Name: "app-name-a", Space: SpaceInfo{Name: "dev", Org: OrgInfo{Name: "shop"}}}},
+		"app-guid-b": {AppInfo: AppInfo{Guid: "app-guid-b", Name: "app-name-b", Space: SpaceInfo{Name: "prod", Org: OrgInfo{Name: "shop"}}}},
+		"app-guid-c": {AppInfo: AppInfo{Guid: "app-guid-c", Name: "app-name-c", Space: SpaceInfo{Name: "dev", Org: OrgInfo{Name: "blog"}}}},
+	}}}
+
+	p := NewProvider(db, nil, nil, Teams{"shop": "team-shop", "shop/prod": "team-ops"})
+	apps, err := p.ListApps(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []sdk.App{
+		{Id: "app-guid-a", Name: "app-name-a", Labels: sdk.AppLabels{"org": "shop", "space": "dev", "team": "team-shop"}, Position: sdk.AppPosition{"shop", "dev"}},
+		{Id: "app-guid-b", Name: "app-name-b", Labels: sdk.AppLabels{"org": "shop", "space": "prod", "team": "team-ops"}, Position: sdk.AppPosition{"shop", "prod"}},
+		{Id: "app-guid-c", Name: "app-name-c", Labels: sdk.AppLabels{"org": "blog", "space": "dev"}, Position: sdk.AppPosition{"blog", "dev"}},
+	}
+	if !cmp.Equal(expected, apps) {
+		t.Errorf("\ndiff between returned apps: \n%s\n", cmp.Diff(expected, apps))
+	}
+}
+
 func TestGetApp(t *testing.T) {
 	tests := []struct {
 		desc        string
@@ -59,8 +85,8 @@ func TestGetApp(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			p := NewProvider(test.db, nil)
-			app, err := p.GetApp(test.id)
+			p := NewProvider(test.db, nil, nil, nil)
+			app, err := p.GetApp(context.Background(), test.id)
 			if !errors.Is(test.expectedErr, err) {
 				tt.Errorf("\nwanted error: \n%s, got %s\n", test.expectedErr.Error(), err.Error())
 			}
@@ -113,12 +139,49 @@ func TestGetAppInstances(t *testing.T) {
 			State: "unknown",
 			Since: someTime,
 		}}},
+		{desc: "gets instance details from CF", db: &fakeDb{}, cf: &fakeCf{b: backend{
+			AppInstances: map[string]Instances{
+				"app-guid-a": {{
+					Index: 0,
+					State: "RUNNING",
+					Since: someTime,
+					Stats: &InstanceStats{Cell: "cell-a", Uptime: 60, Cpu: 0.25, Mem: 128, MemQuota: 256, Disk: 512, DiskQuota: 1024},
+				}, {
+					Index:     1,
+					State:     "CRASHED",
+					Since:     someTime,
+					Crashes:   2,
+					LastCrash: "out of memory",
+				}, {
+					Index: 2,
+					State: "STARTING",
+					Since: someTime,
+				}},
+			}},
+		}, id: "app-guid-a", expected: sdk.AppInstances{{
+			Index:  0,
+			State:  "running",
+			Since:  someTime,
+			Host:   "cell-a",
+			Uptime: 60,
+			Usage:  &sdk.InstanceUsage{Cpu: 0.25, Memory: 128, MemoryQuota: 256, Disk: 512, DiskQuota: 1024},
+		}, {
+			Index:     1,
+			State:     "crashed",
+			Since:     someTime,
+			Restarts:  2,
+			LastCrash: "out of memory",
+		}, {
+			Index: 2,
+			State: "starting",
+			Since: someTime,
+		}}},
 	}
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			p := NewProvider(test.db, test.cf)
-			app, err := p.GetAppInstances(test.id)
+			p := NewProvider(test.db, test.cf, nil, nil)
+			app, err := p.GetAppInstances(context.Background(), test.id)
 			if !errors.Is(test.expectedErr, err) {
 				tt.Errorf("\nwanted error: \n%s, got %s\n", test.expectedErr.Error(), err.Error())
 			}
@@ -160,20 +223,34 @@ func TestGetAppRoutes(t *testing.T) {
 					Host: "host",
 					Path: "path",
 					Port: 4223,
+				}, {
+					Host:     "tcp.example.com",
+					Domain:   "tcp.example.com",
+					Port:     1025,
+					Tcp:      true,
+					Internal: true,
 				}}}},
 		}, id: "app-guid-a", expected: sdk.AppRouting{Routes: sdk.AppRoutes{
 			{
-				Host:    "host",
-				Path:    "path",
-				AppPort: 4223,
+				Host:     "host",
+				Path:     "path",
+				AppPort:  4223,
+				Protocol: sdk.RouteProtocolHttp,
+			},
+			{
+				Host:     "tcp.example.com",
+				Domain:   "tcp.example.com",
+				AppPort:  1025,
+				Protocol: sdk.RouteProtocolTcp,
+				Internal: true,
 			},
 		}}},
 	}
 
 	for _, test := range tests {
 		t.Run(test.desc, func(tt *testing.T) {
-			p := NewProvider(test.db, test.cf)
-			app, err := p.GetAppRouting(test.id)
+			p := NewProvider(test.db, test.cf, nil, nil)
+			app, err := p.GetAppRouting(context.Background(), test.id)
 			if !errors.Is(test.expectedErr, err) {
 				tt.Errorf("\nwanted error: \n%s, got %s\n", test.expectedErr.Error(), err.Error())
 			}
@@ -183,3 +260,224 @@ func TestGetAppRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTopology(t *testing.T) {
+	apps := map[string]*App{
+		"app-guid-a": {AppInfo: AppInfo{Guid: "app-guid-a"}},
+		"app-guid-b": {AppInfo: AppInfo{Guid: "app-guid-b"}},
+	}
+
+	tests := []struct {
+		desc        string
+		db          *fakeDb
+		cf          *fakeCf
+		expected    sdk.Topology
+		expectedErr error
+	}{
+		{desc: "gets topology cached", db: &fakeDb{b: backend{Cache: map[string]interface{}{
+			"topology": sdk.Topology{Services: []sdk.BackingService{{Id: "instance-a", Name: "db"}}},
+		}}}, expected: sdk.Topology{Services: []sdk.BackingService{{Id: "instance-a", Name: "db"}}}},
+		{desc: "returns error", db: &fakeDb{err: someErr}, expectedErr: someErr},
+		{desc: "returns cf error", db: &fakeDb{}, cf: &fakeCf{err: someErr}, expectedErr: someErr},
+		{desc: "gets service bindings of known apps from CF", db: &fakeDb{b: backend{Apps: apps}}, cf: &fakeCf{b: backend{
+			ServiceBindings: []ServiceBinding{
+				{AppGuid: "app-guid-a", InstanceGuid: "instance-a", InstanceName: "db", Service: "postgresql"},
+				{AppGuid: "app-guid-b", InstanceGuid: "instance-a", InstanceName: "db", Service: "postgresql"},
+				{AppGuid: "app-guid-b", InstanceGuid: "instance-b", InstanceName: "logs", Service: "user-provided"},
+				{AppGuid: "app-guid-unknown", InstanceGuid: "instance-c", InstanceName: "cache", Service: "redis"},
+			},
+		}}, expected: sdk.Topology{
+			Services: []sdk.BackingService{
+				{Id: "instance-a", Name: "db", Kind: "postgresql"},
+				{Id: "instance-b", Name: "logs", Kind: "user-provided"},
+			},
+			Dependencies: []sdk.Dependency{
+				{AppId: "app-guid-a", Target: sdk.DependencyTargetService, TargetId: "instance-a"},
+				{AppId: "app-guid-b", Target: sdk.DependencyTargetService, TargetId: "instance-a"},
+				{AppId: "app-guid-b", Target: sdk.DependencyTargetService, TargetId: "instance-b"},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.db, test.cf, nil, nil)
+			topology, err := p.GetTopology(context.Background())
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, topology) {
+				tt.Errorf("\ndiff between returned topology: \n%s\n", cmp.Diff(test.expected, topology))
+			}
+		})
+	}
+}
+
+var someLogs = []Log{
+	{Time: logTime, Instance: "0", Source: "APP/PROC/WEB", Message: "first"},
+	{Time: logTime.Add(time.Second), Instance: "0", Source: "APP/PROC/WEB", Err: true, Message: "second"},
+	{Time: logTime.Add(2 * time.Second), Instance: "1", Source: "RTR", Message: "third"},
+}
+
+var appDb = &fakeDb{b: backend{Apps: map[string]*App{
+	"app-guid-a": {AppInfo: AppInfo{Guid: "app-guid-a", Name: "app-name-a"}},
+}}}
+
+func TestGetRecentLogs(t *testing.T) {
+	tests := []struct {
+		desc        string
+		id          string
+		logs        *fakeLogCache
+		expected    sdk.AppLogs
+		expectedErr error
+	}{
+		{desc: "returns latest logs oldest first", id: "app-guid-a", logs: &fakeLogCache{logs: someLogs}, expected: sdk.AppLogs{
+			{Time: logTime.Add(time.Second), Instance: "0", Source: "APP/PROC/WEB", Stream: sdk.LogStreamErr, Message: "second"},
+			{Time: logTime.Add(2 * time.Second), Instance: "1", Source: "RTR", Stream: sdk.LogStreamOut, Message: "third"},
+		}},
+		{desc: "returns not found for unknown app", id: "not-exist", logs: &fakeLogCache{logs: someLogs}, expectedErr: sdk.ErrNotFound},
+		{desc: "returns error", id: "app-guid-a", logs: &fakeLogCache{err: someErr}, expectedErr: someErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(appDb, nil, test.logs, nil)
+			logs, err := p.GetRecentLogs(context.Background(), test.id, logTime, 2)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, logs) {
+				tt.Errorf("\ndiff between returned logs: \n%s\n", cmp.Diff(test.expected, logs))
+			}
+		})
+	}
+}
+
+func TestAppActions(t *testing.T) {
+	tests := []struct {
+		desc          string
+		id            string
+		cf            *fakeCf
+		action        func(ctx context.Context, p *Provider, id string) error
+		expectedErr   error
+		expectedCalls []string
+	}{
+		{desc: "restarts app", id: "app-guid-a", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.RestartApp(ctx, id)
+		}, expectedCalls: []string{"restart app-guid-a"}},
+		{desc: "stops app", id: "app-guid-a", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.StopApp(ctx, id)
+		}, expectedCalls: []string{"stop app-guid-a"}},
+		{desc: "starts app", id: "app-guid-a", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.StartApp(ctx, id)
+		}, expectedCalls: []string{"start app-guid-a"}},
+		{desc: "scales app", id: "app-guid-a", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.ScaleApp(ctx, id, 2)
+		}, expectedCalls: []string{"scale app-guid-a 2"}},
+		{desc: "restarts app instance", id: "app-guid-a", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.RestartAppInstance(ctx, id, 1)
+		}, expectedCalls: []string{"restart app-guid-a 1"}},
+		{desc: "returns not found for unknown app", id: "not-exist", cf: &fakeCf{}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.RestartApp(ctx, id)
+		}, expectedErr: sdk.ErrNotFound},
+		{desc: "returns not found for unknown instance", id: "app-guid-a", cf: &fakeCf{err: errNotFound}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.RestartAppInstance(ctx, id, 5)
+		}, expectedErr: sdk.ErrNotFound, expectedCalls: []string{"restart app-guid-a 5"}},
+		{desc: "returns error", id: "app-guid-a", cf: &fakeCf{err: someErr}, action: func(ctx context.Context, p *Provider, id string) error {
+			return p.StopApp(ctx, id)
+		}, expectedErr: someErr, expectedCalls: []string{"stop app-guid-a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(appDb, test.cf, nil, nil)
+			err := test.action(context.Background(), p, test.id)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expectedCalls, test.cf.calls) {
+				tt.Errorf("\ndiff between calls: \n%s\n", cmp.Diff(test.expectedCalls, test.cf.calls))
+			}
+		})
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	logsPollInterval = time.Millisecond
+	currentTime = func() time.Time {
+		return logTime.Add(time.Second)
+	}
+	defer func() {
+		logsPollInterval = time.Second
+		currentTime = time.Now
+	}()
+
+	cache := &fakeLogCache{logs: someLogs}
+	p := NewProvider(appDb, nil, cache, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan sdk.LogLine)
+	errs := make(chan error)
+	go func() {
+		errs <- p.TailLogs(ctx, "app-guid-a", lines)
+	}()
+
+	var logs sdk.AppLogs
+	logs = append(logs, <-lines, <-lines)
+
+	// later polls continue after the last line instead of sending it again
+	select {
+	case line := <-lines:
+		t.Errorf("unexpected line sent again: %v", line)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	expected := sdk.AppLogs{
+		{Time: logTime.Add(time.Second), Instance: "0", Source: "APP/PROC/WEB", Stream: sdk.LogStreamErr, Message: "second"},
+		{Time: logTime.Add(2 * time.Second), Instance: "1", Source: "RTR", Stream: sdk.LogStreamOut, Message: "third"},
+	}
+	if !cmp.Equal(expected, logs) {
+		t.Errorf("\ndiff between tailed logs: \n%s\n", cmp.Diff(expected, logs))
+	}
+
+	err := p.TailLogs(context.Background(), "not-exist", lines)
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("\nwanted error: %v, got %v\n", sdk.ErrNotFound, err)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	lastAttempt := time.Date(2006, 1, 1, 15, 0, 0, 0, time.UTC)
+	db := &fakeDb{deadLetters: []recon.Job{
+		{Type: ReconcileSpaces, Guid: "org-a", LastUpdated: lastAttempt, Attempts: 8, LastError: "some error"},
+	}}
+	p := NewProvider(db, nil, nil, nil)
+
+	dead, err := p.ListDeadLetters(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []sdk.DeadLetter{
+		{Type: "spaces", Guid: "org-a", Attempts: 8, LastError: "some error", LastAttempt: lastAttempt},
+	}
+	if !cmp.Equal(expected, dead) {
+		t.Errorf("\ndiff between dead letters: \n%s\n", cmp.Diff(expected, dead))
+	}
+
+	err = p.RequeueDeadLetter(context.Background(), "spaces", "org-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal([]string{"spaces/org-a"}, db.requeued) {
+		t.Errorf("\ndiff between requeued: \n%s\n", cmp.Diff([]string{"spaces/org-a"}, db.requeued))
+	}
+
+	err = p.RequeueDeadLetter(context.Background(), "spaces", "org-b")
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("\nwanted error: %v, got %v\n", sdk.ErrNotFound, err)
+	}
+}
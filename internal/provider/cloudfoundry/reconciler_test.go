@@ -1,6 +1,8 @@
 package cloudfoundry
 
 import (
+	"context"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
@@ -122,10 +124,37 @@ func TestReconciler(t *testing.T) {
 }
 
 type fakeCf struct {
-	b backend
+	b     backend
+	calls []string
+	err   error
 }
 
-func (f *fakeCf) GetRoutes(appId string) (Routes, error) {
+func (f *fakeCf) StartApp(ctx context.Context, appId string) error {
+	f.calls = append(f.calls, "start "+appId)
+	return f.err
+}
+
+func (f *fakeCf) StopApp(ctx context.Context, appId string) error {
+	f.calls = append(f.calls, "stop "+appId)
+	return f.err
+}
+
+func (f *fakeCf) RestartApp(ctx context.Context, appId string) error {
+	f.calls = append(f.calls, "restart "+appId)
+	return f.err
+}
+
+func (f *fakeCf) ScaleApp(ctx context.Context, appId string, instances int) error {
+	f.calls = append(f.calls, fmt.Sprintf("scale %s %d", appId, instances))
+	return f.err
+}
+
+func (f *fakeCf) RestartInstance(ctx context.Context, appId string, index int) error {
+	f.calls = append(f.calls, fmt.Sprintf("restart %s %d", appId, index))
+	return f.err
+}
+
+func (f *fakeCf) GetRoutes(ctx context.Context, appId string) (Routes, error) {
 	if f.b.AppRoutes[appId] == nil {
 		return nil, errNotFound
 	}
@@ -133,7 +162,7 @@ func (f *fakeCf) GetRoutes(appId string) (Routes, error) {
 	return f.b.AppRoutes[appId], nil
 }
 
-func (f *fakeCf) GetInstances(appId string) (Instances, error) {
+func (f *fakeCf) GetInstances(ctx context.Context, appId string) (Instances, error) {
 	if f.b.AppInstances[appId] == nil {
 		return nil, errNotFound
 	}
@@ -141,6 +170,10 @@ func (f *fakeCf) GetInstances(appId string) (Instances, error) {
 	return f.b.AppInstances[appId], nil
 }
 
+func (f *fakeCf) ListServiceBindings(ctx context.Context) ([]ServiceBinding, error) {
+	return f.b.ServiceBindings, f.err
+}
+
 func (f *fakeCf) ListOrgs() ([]Org, error) {
 	var res []Org
 	for _, org := range f.b.Orgs {
@@ -186,9 +219,11 @@ func (f *fakeCf) GetApp(guid string) (App, error) {
 }
 
 type fakeDb struct {
-	job *recon.Job
-	b   backend
-	err error
+	job         *recon.Job
+	b           backend
+	err         error
+	deadLetters []recon.Job
+	requeued    []string
 }
 
 func (f *fakeDb) Cached(id string, duration time.Duration, res interface{}, fun func() (interface{}, error)) (interface{}, error) {
@@ -205,6 +240,10 @@ func (f *fakeDb) Cached(id string, duration time.Duration, res interface{}, fun
 			*res = f.b.Cache[id].(sdk.AppRouting)
 			return nil, nil
 		}
+		if res, ok := res.(*sdk.Topology); ok {
+			*res = f.b.Cache[id].(sdk.Topology)
+			return nil, nil
+		}
 	}
 	return fun()
 }
@@ -341,12 +380,45 @@ func (f *fakeDb) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool) {
 	return *f.job, true
 }
 
+func (f *fakeDb) RetryReconcileJob(j recon.Job, at time.Time, cause error) error {
+	return nil
+}
+
+func (f *fakeDb) DeadLetterReconcileJob(j recon.Job, cause error) error {
+	return nil
+}
+
+func (f *fakeDb) SucceedReconcileJob(j recon.Job) error {
+	return nil
+}
+
+func (f *fakeDb) ListDeadLetters() ([]recon.Job, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.deadLetters, nil
+}
+
+func (f *fakeDb) RequeueDeadLetter(t recon.Type, guid string) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, j := range f.deadLetters {
+		if j.Type == t && j.Guid == guid {
+			f.requeued = append(f.requeued, string(t)+"/"+guid)
+			return nil
+		}
+	}
+	return recon.ErrJobNotFound
+}
+
 type backend struct {
-	CfApis       map[string]*CF
-	Orgs         map[string]*Org
-	Spaces       map[string]*Space
-	Apps         map[string]*App
-	Cache        map[string]interface{}
-	AppInstances map[string]Instances
-	AppRoutes    map[string]Routes
+	CfApis          map[string]*CF
+	Orgs            map[string]*Org
+	Spaces          map[string]*Space
+	Apps            map[string]*App
+	Cache           map[string]interface{}
+	AppInstances    map[string]Instances
+	AppRoutes       map[string]Routes
+	ServiceBindings []ServiceBinding
 }
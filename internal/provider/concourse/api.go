@@ -0,0 +1,266 @@
+package concourse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// API is a simplified wrapper around the Concourse ATC REST API.
+type API interface {
+	ListTeams(ctx context.Context) ([]Team, error)
+	ListPipelines(ctx context.Context, team string) ([]Pipeline, error)
+	ListJobs(ctx context.Context, team string, pipeline string) ([]Job, error)
+	// ListBuilds returns one page of builds of a pipeline, newest first. The returned page
+	// points to the next (older) page and is nil if there are no more builds.
+	ListBuilds(ctx context.Context, team string, pipeline string, page Page) ([]Build, *Page, error)
+}
+
+// Page addresses a page of builds the same way the ATC does: Until is the exclusive
+// upper bound of the build ids, zero meaning the newest build.
+type Page struct {
+	Until int
+	Limit int
+}
+
+type Login struct {
+	Url      string `yaml:"url"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"`
+}
+
+func NewDefaultApi(l Login) (API, error) {
+	if l.Url == "" {
+		return nil, errNoUrl
+	}
+
+	c := &http.Client{Transport: &tokenTransport{
+		l:     l,
+		base:  http.DefaultTransport,
+		token: l.Token,
+	}}
+
+	return NewApi(l.Url, c), nil
+}
+
+func NewApi(uri string, c *http.Client) API {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	return &api{
+		c:       c,
+		baseUrl: strings.TrimSuffix(uri, "/"),
+	}
+}
+
+type api struct {
+	c       *http.Client
+	baseUrl string
+}
+
+func (a *api) ListTeams(ctx context.Context) ([]Team, error) {
+	var teams []Team
+	_, err := a.get(ctx, &teams, nil, "teams")
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (a *api) ListPipelines(ctx context.Context, team string) ([]Pipeline, error) {
+	var pipelines []Pipeline
+	_, err := a.get(ctx, &pipelines, nil, "teams", team, "pipelines")
+	if err != nil {
+		return nil, err
+	}
+	return pipelines, nil
+}
+
+func (a *api) ListJobs(ctx context.Context, team string, pipeline string) ([]Job, error) {
+	var jobs []Job
+	_, err := a.get(ctx, &jobs, nil, "teams", team, "pipelines", pipeline, "jobs")
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (a *api) ListBuilds(ctx context.Context, team string, pipeline string, page Page) ([]Build, *Page, error) {
+	query := url.Values{}
+	if page.Limit > 0 {
+		query.Set("limit", strconv.Itoa(page.Limit))
+	}
+	if page.Until > 0 {
+		query.Set("until", strconv.Itoa(page.Until))
+	}
+
+	var builds []Build
+	header, err := a.get(ctx, &builds, query, "teams", team, "pipelines", pipeline, "builds")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return builds, nextPage(header), nil
+}
+
+func (a *api) get(ctx context.Context, res interface{}, query url.Values, path ...string) (http.Header, error) {
+	fullPath := a.baseUrl + "/api/v1"
+	for _, s := range path {
+		fullPath += "/" + url.PathEscape(s)
+	}
+	if len(query) != 0 {
+		fullPath += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &errUnexpectedStatus{Status: resp.StatusCode, Path: fullPath}
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Header, nil
+}
+
+// nextPage parses the Link header the ATC sets on paginated responses, e.g.
+// <http://ci/api/v1/teams/main/pipelines/p/builds?until=12&limit=100>; rel="next"
+func nextPage(h http.Header) *Page {
+	for _, links := range h.Values("Link") {
+		for _, link := range strings.Split(links, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 || !strings.Contains(parts[1], `rel="next"`) {
+				continue
+			}
+
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+			if err != nil {
+				return nil
+			}
+
+			until, _ := strconv.Atoi(u.Query().Get("until"))
+			limit, _ := strconv.Atoi(u.Query().Get("limit"))
+			if until == 0 {
+				return nil
+			}
+			return &Page{Until: until, Limit: limit}
+		}
+	}
+	return nil
+}
+
+// tokenTransport authenticates requests against the ATC. If user credentials are configured
+// it fetches a token via the password grant of the integrated identity provider and renews it
+// once the ATC rejects it.
+type tokenTransport struct {
+	l     Login
+	base  http.RoundTripper
+	mu    sync.Mutex
+	token string
+}
+
+func (t *tokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.currentToken(r.Context(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withToken(r, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.l.User == "" {
+		return resp, err
+	}
+	_ = resp.Body.Close()
+
+	token, err = t.currentToken(r.Context(), true)
+	if err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(withToken(r, token))
+}
+
+func (t *tokenTransport) currentToken(ctx context.Context, renew bool) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.l.User == "" || (t.token != "" && !renew) {
+		return t.token, nil
+	}
+
+	token, err := fetchToken(ctx, &http.Client{Transport: t.base}, t.l)
+	if err != nil {
+		return "", err
+	}
+	t.token = token
+	return token, nil
+}
+
+func withToken(r *http.Request, token string) *http.Request {
+	if token == "" {
+		return r
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+func fetchToken(ctx context.Context, c *http.Client, l Login) (string, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {l.User},
+		"password":   {l.Password},
+		"scope":      {"openid profile email federated:id groups"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(l.Url, "/")+"/sky/issuer/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// these are the public client credentials fly uses as well
+	req.SetBasicAuth("fly", "Zmx5")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: status %d", errLoginFailed, resp.StatusCode)
+	}
+
+	t := tokenResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&t)
+	if err != nil {
+		return "", err
+	}
+
+	return t.AccessToken, nil
+}
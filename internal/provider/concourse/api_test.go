@@ -0,0 +1,238 @@
+package concourse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestListBuildsPagination(t *testing.T) {
+	atc := &fakeAtc{builds: map[string][]Build{
+		"main/p": {{Id: 5}, {Id: 4}, {Id: 3}, {Id: 2}, {Id: 1}},
+	}}
+	s := httptest.NewServer(atc.handler())
+	defer s.Close()
+
+	api := NewApi(s.URL, nil)
+
+	var ids []int
+	var pages int
+	page := &Page{Limit: 2}
+	for page != nil {
+		builds, next, err := api.ListBuilds(context.Background(), "main", "p", *page)
+		if err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		for _, build := range builds {
+			ids = append(ids, build.Id)
+		}
+		page = next
+		pages++
+	}
+
+	if pages != 3 {
+		t.Errorf("expected 3 pages, got %d", pages)
+	}
+	if !cmp.Equal([]int{5, 4, 3, 2, 1}, ids) {
+		t.Errorf("build ids mismatch: \n%s\n", cmp.Diff([]int{5, 4, 3, 2, 1}, ids))
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		desc     string
+		link     string
+		expected *Page
+	}{
+		{"no link", "", nil},
+		{"only previous", `<http://ci/api/v1/builds?since=5&limit=2>; rel="previous"`, nil},
+		{"next", `<http://ci/api/v1/builds?since=5&limit=2>; rel="previous", <http://ci/api/v1/builds?until=3&limit=2>; rel="next"`, &Page{Until: 3, Limit: 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			h := http.Header{}
+			if test.link != "" {
+				h.Set("Link", test.link)
+			}
+			page := nextPage(h)
+			if !cmp.Equal(test.expected, page) {
+				tt.Errorf("page mismatch: \n%s\n", cmp.Diff(test.expected, page))
+			}
+		})
+	}
+}
+
+func TestApiErrors(t *testing.T) {
+	atc := &fakeAtc{}
+	s := httptest.NewServer(atc.handler())
+	defer s.Close()
+
+	api := NewApi(s.URL, nil)
+
+	_, err := api.ListJobs(context.Background(), "main", "not-exist")
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	atc.status = http.StatusInternalServerError
+	_, err = api.ListTeams(context.Background())
+	var statusErr *errUnexpectedStatus
+	if !errors.As(err, &statusErr) || statusErr.Status != http.StatusInternalServerError {
+		t.Errorf("expected unexpected status error, got %v", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	atc := &fakeAtc{
+		user:     "user",
+		password: "pass",
+		teams:    []Team{{Id: 1, Name: "main"}},
+	}
+	s := httptest.NewServer(atc.handler())
+	defer s.Close()
+
+	api, err := NewDefaultApi(Login{Url: s.URL, User: "user", Password: "pass"})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	teams, err := api.ListTeams(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(atc.teams, teams) {
+		t.Errorf("teams mismatch: \n%s\n", cmp.Diff(atc.teams, teams))
+	}
+
+	_, err = api.ListTeams(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if atc.logins != 1 {
+		t.Errorf("expected token to be reused, but logged in %d times", atc.logins)
+	}
+
+	atc.revoke()
+	_, err = api.ListTeams(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if atc.logins != 2 {
+		t.Errorf("expected token to be renewed, but logged in %d times", atc.logins)
+	}
+
+	_, err = NewDefaultApi(Login{})
+	if !errors.Is(err, errNoUrl) {
+		t.Errorf("expected no url error, got %v", err)
+	}
+}
+
+type fakeAtc struct {
+	teams     []Team
+	pipelines map[string][]Pipeline
+	jobs      map[string][]Job
+	builds    map[string][]Build
+	status    int
+
+	user     string
+	password string
+	token    string
+	logins   int
+}
+
+func (f *fakeAtc) handler() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/sky/issuer/token", f.login)
+
+	api := r.PathPrefix("/api/v1").Subrouter()
+	api.Use(f.authenticate)
+	api.HandleFunc("/teams", func(w http.ResponseWriter, r *http.Request) {
+		f.respond(w, f.teams)
+	})
+	api.HandleFunc("/teams/{team}/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		f.respond(w, f.pipelines[mux.Vars(r)["team"]])
+	})
+	api.HandleFunc("/teams/{team}/pipelines/{pipeline}/jobs", func(w http.ResponseWriter, r *http.Request) {
+		jobs, ok := f.jobs[key(r)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.respond(w, jobs)
+	})
+	api.HandleFunc("/teams/{team}/pipelines/{pipeline}/builds", f.listBuilds)
+	return r
+}
+
+func (f *fakeAtc) listBuilds(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+	until, _ := strconv.Atoi(r.FormValue("until"))
+	if limit == 0 {
+		limit = 100
+	}
+
+	var remaining []Build
+	for _, build := range f.builds[key(r)] {
+		if until == 0 || build.Id < until {
+			remaining = append(remaining, build)
+		}
+	}
+
+	page := remaining
+	if len(remaining) > limit {
+		page = remaining[:limit]
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?until=%d&limit=%d>; rel="next"`, r.Host, r.URL.Path, page[limit-1].Id, limit))
+	}
+	if page == nil {
+		page = []Build{}
+	}
+
+	f.respond(w, page)
+}
+
+func (f *fakeAtc) login(w http.ResponseWriter, r *http.Request) {
+	client, secret, _ := r.BasicAuth()
+	if client != "fly" || secret != "Zmx5" || r.FormValue("grant_type") != "password" ||
+		r.FormValue("username") != f.user || r.FormValue("password") != f.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.logins++
+	f.token = fmt.Sprintf("token-%d", f.logins)
+	_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: f.token, TokenType: "bearer"})
+}
+
+func (f *fakeAtc) revoke() {
+	f.token = "revoked"
+}
+
+func (f *fakeAtc) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.user != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeAtc) respond(w http.ResponseWriter, v interface{}) {
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func key(r *http.Request) string {
+	return mux.Vars(r)["team"] + "/" + mux.Vars(r)["pipeline"]
+}
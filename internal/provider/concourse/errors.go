@@ -0,0 +1,19 @@
+package concourse
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errNotFound = errors.New("not found")
+var errNoUrl = errors.New("no concourse url configured")
+var errLoginFailed = errors.New("could not log in to concourse")
+
+type errUnexpectedStatus struct {
+	Status int
+	Path   string
+}
+
+func (e *errUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected status %d from concourse for '%s'", e.Status, e.Path)
+}
@@ -0,0 +1,44 @@
+package concourse
+
+type Team struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Pipeline struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	TeamName    string `json:"team_name"`
+	Paused      bool   `json:"paused"`
+	LastUpdated int64  `json:"last_updated"`
+}
+
+type Job struct {
+	Id           int        `json:"id"`
+	Name         string     `json:"name"`
+	PipelineName string     `json:"pipeline_name"`
+	TeamName     string     `json:"team_name"`
+	Inputs       []JobInput `json:"inputs"`
+}
+
+type JobInput struct {
+	Name     string   `json:"name"`
+	Resource string   `json:"resource"`
+	Passed   []string `json:"passed"`
+	Trigger  bool     `json:"trigger"`
+}
+
+type Build struct {
+	Id           int    `json:"id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	TeamName     string `json:"team_name"`
+	JobName      string `json:"job_name"`
+	PipelineId   int    `json:"pipeline_id"`
+	PipelineName string `json:"pipeline_name"`
+	StartTime    int64  `json:"start_time"`
+	EndTime      int64  `json:"end_time"`
+	// CreatedBy is the user who triggered the build by hand. It is empty for builds
+	// triggered by new versions of their inputs.
+	CreatedBy string `json:"created_by,omitempty"`
+}
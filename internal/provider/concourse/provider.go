@@ -1,29 +1,313 @@
 package concourse
 
 import (
+	"context"
+	"errors"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"strconv"
 	"time"
 )
 
-func NewProvider() sdk.PipelineProvider {
-	return &provider{}
+const buildsPerPage = 100
+
+// maxBuildDuration is how long builds are expected to run at most. Builds are listed by the
+// order they were created in, so updates look at the builds started this long before the given
+// time as well, as they might have been running since.
+const maxBuildDuration = 24 * time.Hour
+
+// NewProvider creates a pipeline provider backed by the given Concourse API. If teams is empty,
+// the pipelines of all teams visible to the configured user are provided.
+func NewProvider(cc API, teams []string) sdk.PipelineProviderContext {
+	return &provider{
+		cc:    cc,
+		teams: teams,
+	}
 }
 
 type provider struct {
+	cc    API
+	teams []string
+}
+
+func (p *provider) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
+	pipelines, err := p.listPipelines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []sdk.Pipeline
+	for _, pipeline := range pipelines {
+		jobs, err := p.cc.ListJobs(ctx, pipeline.TeamName, pipeline.Name)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, toSdkPipeline(pipeline, jobs))
+	}
+	return res, nil
+}
+
+func (p *provider) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
+	pipelines, err := p.listPipelines(ctx)
+	if err != nil {
+		return sdk.PipelineUpdates{}, err
+	}
+
+	updates := sdk.PipelineUpdates{}
+	for _, pipeline := range pipelines {
+		jobs, err := p.cc.ListJobs(ctx, pipeline.TeamName, pipeline.Name)
+		if err != nil {
+			return sdk.PipelineUpdates{}, err
+		}
+
+		if unixTime(pipeline.LastUpdated).After(since) {
+			updates.Versions = append(updates.Versions, toSdkVersion(pipeline, jobs))
+		}
+
+		steps := stepIds(jobs)
+		err = p.eachBuild(ctx, pipeline, func(b Build) bool {
+			started, ended := unixTime(b.StartTime), unixTime(b.EndTime)
+			if started.IsZero() {
+				return true
+			}
+			if started.Before(since.Add(-maxBuildDuration)) {
+				return false
+			}
+			if started.Before(since) && !ended.IsZero() && ended.Before(since) {
+				return true
+			}
+
+			if run, ok := toSdkRun(pipeline, b, steps); ok {
+				updates.Runs = append(updates.Runs, run)
+			}
+			return true
+		})
+		if err != nil {
+			return sdk.PipelineUpdates{}, err
+		}
+	}
+
+	return updates, nil
+}
+
+func (p *provider) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
+	pipeline, err := p.findPipeline(ctx, id)
+	if err != nil {
+		return sdk.Pipeline{}, err
+	}
+
+	jobs, err := p.cc.ListJobs(ctx, pipeline.TeamName, pipeline.Name)
+	if err != nil {
+		return sdk.Pipeline{}, err
+	}
+
+	return toSdkPipeline(pipeline, jobs), nil
+}
+
+func (p *provider) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+	pipeline, err := p.findPipeline(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := p.cc.ListJobs(ctx, pipeline.TeamName, pipeline.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := stepIds(jobs)
+	res := sdk.PipelineStatusList{}
+	err = p.eachBuild(ctx, pipeline, func(b Build) bool {
+		started := unixTime(b.StartTime)
+		if started.IsZero() || !started.Before(before) {
+			return true
+		}
+
+		if run, ok := toSdkRun(pipeline, b, steps); ok {
+			res = append(res, run)
+		}
+		return limit <= 0 || len(res) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
-func (p *provider) ListPipelines() ([]sdk.Pipeline, error) {
-	panic("implement me")
+func (p *provider) listPipelines(ctx context.Context) ([]Pipeline, error) {
+	teams := p.teams
+	if len(teams) == 0 {
+		all, err := p.cc.ListTeams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range all {
+			teams = append(teams, team.Name)
+		}
+	}
+
+	var res []Pipeline
+	for _, team := range teams {
+		pipelines, err := p.cc.ListPipelines(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pipelines...)
+	}
+	return res, nil
+}
+
+func (p *provider) findPipeline(ctx context.Context, id string) (Pipeline, error) {
+	pipelines, err := p.listPipelines(ctx)
+	if err != nil {
+		return Pipeline{}, err
+	}
+
+	for _, pipeline := range pipelines {
+		if pipelineId(pipeline) == id {
+			return pipeline, nil
+		}
+	}
+	return Pipeline{}, sdk.ErrNotFound
+}
+
+// eachBuild pages through the builds of the pipeline, newest first, until f returns false.
+func (p *provider) eachBuild(ctx context.Context, pipeline Pipeline, f func(b Build) bool) error {
+	page := &Page{Limit: buildsPerPage}
+	for page != nil {
+		builds, next, err := p.cc.ListBuilds(ctx, pipeline.TeamName, pipeline.Name, *page)
+		if errors.Is(err, errNotFound) {
+			return sdk.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, build := range builds {
+			if !f(build) {
+				return nil
+			}
+		}
+		page = next
+	}
+	return nil
+}
+
+func pipelineId(p Pipeline) string {
+	return strconv.Itoa(p.Id)
+}
+
+func stepIds(jobs []Job) map[string]int {
+	ids := make(map[string]int, len(jobs))
+	for _, job := range jobs {
+		ids[job.Name] = job.Id
+	}
+	return ids
+}
+
+func toSdkPipeline(p Pipeline, jobs []Job) sdk.Pipeline {
+	return sdk.Pipeline{
+		Id:      pipelineId(p),
+		Name:    p.TeamName + "/" + p.Name,
+		Current: toSdkVersion(p, jobs),
+	}
+}
+
+// toSdkVersion converts the jobs of a pipeline into steps. Every job that is listed in the
+// 'passed' constraint of another job's input becomes a connection to it, which is manual
+// unless at least one of these inputs triggers the job automatically.
+func toSdkVersion(p Pipeline, jobs []Job) sdk.PipelineVersion {
+	ids := stepIds(jobs)
+
+	def := sdk.PipelineDefinition{}
+	for _, job := range jobs {
+		def.Steps = append(def.Steps, sdk.PipelineStep{
+			Name: job.Name,
+			Id:   job.Id,
+		})
+
+		var order []int
+		triggers := make(map[int]bool)
+		for _, input := range job.Inputs {
+			for _, passed := range input.Passed {
+				from, ok := ids[passed]
+				if !ok {
+					continue
+				}
+				if _, seen := triggers[from]; !seen {
+					order = append(order, from)
+				}
+				triggers[from] = triggers[from] || input.Trigger
+			}
+		}
+
+		for _, from := range order {
+			def.Connections = append(def.Connections, sdk.PipelineConnection{
+				From:   from,
+				To:     job.Id,
+				Manual: !triggers[from],
+			})
+		}
+	}
+
+	return sdk.PipelineVersion{
+		PipelineId: pipelineId(p),
+		Created:    unixTime(p.LastUpdated),
+		Definition: def,
+	}
+}
+
+func toSdkRun(p Pipeline, b Build, steps map[string]int) (sdk.PipelineStatus, bool) {
+	stepId, ok := steps[b.JobName]
+	if !ok {
+		return sdk.PipelineStatus{}, false
+	}
+
+	return sdk.PipelineStatus{
+		PipelineId: pipelineId(p),
+		Started:    unixTime(b.StartTime),
+		Trigger:    toSdkTrigger(b),
+		Steps: []sdk.StepRun{
+			{
+				StepId:  stepId,
+				Status:  toSdkStatus(b.Status),
+				Started: unixTime(b.StartTime),
+				Ended:   unixTime(b.EndTime),
+			},
+		},
+	}, true
 }
 
-func (p *provider) ListUpdates(since time.Time) (sdk.PipelineUpdates, error) {
-	panic("implement me")
+// toSdkTrigger only knows about manually triggered builds. The commits a build runs for are
+// versions of its inputs, which the build listing doesn't include.
+func toSdkTrigger(b Build) *sdk.RunTrigger {
+	if b.CreatedBy == "" {
+		return nil
+	}
+	return &sdk.RunTrigger{
+		Type:   sdk.TriggerManual,
+		Author: b.CreatedBy,
+	}
 }
 
-func (p *provider) GetPipeline(id string) (sdk.Pipeline, error) {
-	panic("implement me")
+func toSdkStatus(status string) sdk.StepStatus {
+	switch status {
+	case "succeeded":
+		return sdk.StatusSuccess
+	case "failed", "errored":
+		return sdk.StatusFailure
+	case "started":
+		return sdk.StatusRunning
+	case "aborted":
+		return sdk.StatusAborted
+	default:
+		return sdk.StatusPending
+	}
 }
 
-func (p *provider) GetHistory(id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
-	panic("implement me")
+func unixTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t, 0).UTC()
 }
@@ -0,0 +1,270 @@
+package concourse
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+func minutes(n int) int64 {
+	return someTime.Add(time.Duration(n) * time.Minute).Unix()
+}
+
+func at(n int) time.Time {
+	return someTime.Add(time.Duration(n) * time.Minute).UTC()
+}
+
+var jobs = []Job{
+	{Id: 10, Name: "test", Inputs: []JobInput{{Name: "src", Trigger: true}}},
+	{Id: 11, Name: "build", Inputs: []JobInput{
+		{Name: "src", Passed: []string{"test"}, Trigger: true},
+		{Name: "other", Passed: []string{"test"}},
+	}},
+	{Id: 12, Name: "deploy", Inputs: []JobInput{{Name: "image", Passed: []string{"build", "removed"}}}},
+}
+
+var expectedDefinition = sdk.PipelineDefinition{
+	Steps: []sdk.PipelineStep{
+		{Name: "test", Id: 10},
+		{Name: "build", Id: 11},
+		{Name: "deploy", Id: 12},
+	},
+	Connections: []sdk.PipelineConnection{
+		{From: 10, To: 11, Manual: false},
+		{From: 11, To: 12, Manual: true},
+	},
+}
+
+func newFakeAtc() *fakeAtc {
+	return &fakeAtc{
+		teams: []Team{{Id: 1, Name: "main"}, {Id: 2, Name: "other"}},
+		pipelines: map[string][]Pipeline{
+			"main":  {{Id: 1, Name: "app", TeamName: "main", LastUpdated: minutes(0)}},
+			"other": {{Id: 2, Name: "lib", TeamName: "other", LastUpdated: minutes(-60)}},
+		},
+		jobs: map[string][]Job{
+			"main/app":  jobs,
+			"other/lib": {},
+		},
+		builds: map[string][]Build{
+			"main/app": {
+				{Id: 7, JobName: "build", Status: "pending"},
+				{Id: 6, JobName: "build", Status: "started", StartTime: minutes(50)},
+				{Id: 5, JobName: "test", Status: "succeeded", StartTime: minutes(40), EndTime: minutes(45), CreatedBy: "jane"},
+				{Id: 4, JobName: "gone", Status: "succeeded", StartTime: minutes(30), EndTime: minutes(31)},
+				{Id: 3, JobName: "deploy", Status: "errored", StartTime: minutes(20), EndTime: minutes(21)},
+				{Id: 2, JobName: "build", Status: "aborted", StartTime: minutes(10), EndTime: minutes(12)},
+				{Id: 1, JobName: "test", Status: "failed", StartTime: minutes(1), EndTime: minutes(2)},
+			},
+		},
+	}
+}
+
+func newTestProvider(atc *fakeAtc, teams []string) (sdk.PipelineProviderContext, func()) {
+	s := httptest.NewServer(atc.handler())
+	return NewProvider(NewApi(s.URL, nil), teams), s.Close
+}
+
+func TestListPipelines(t *testing.T) {
+	p, closeFn := newTestProvider(newFakeAtc(), nil)
+	defer closeFn()
+
+	pipelines, err := p.ListPipelines(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []sdk.Pipeline{
+		{Id: "1", Name: "main/app", Current: sdk.PipelineVersion{
+			PipelineId: "1", Created: at(0), Definition: expectedDefinition,
+		}},
+		{Id: "2", Name: "other/lib", Current: sdk.PipelineVersion{
+			PipelineId: "2", Created: at(-60),
+		}},
+	}
+	if !cmp.Equal(expected, pipelines) {
+		t.Errorf("pipelines mismatch: \n%s\n", cmp.Diff(expected, pipelines))
+	}
+}
+
+func TestListPipelinesOfTeams(t *testing.T) {
+	p, closeFn := newTestProvider(newFakeAtc(), []string{"other"})
+	defer closeFn()
+
+	pipelines, err := p.ListPipelines(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	if len(pipelines) != 1 || pipelines[0].Id != "2" {
+		t.Errorf("expected only pipeline of team 'other', got %v", pipelines)
+	}
+}
+
+func TestGetPipeline(t *testing.T) {
+	p, closeFn := newTestProvider(newFakeAtc(), nil)
+	defer closeFn()
+
+	pipeline, err := p.GetPipeline(context.Background(), "1")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(expectedDefinition, pipeline.Current.Definition) {
+		t.Errorf("definition mismatch: \n%s\n", cmp.Diff(expectedDefinition, pipeline.Current.Definition))
+	}
+
+	_, err = p.GetPipeline(context.Background(), "3")
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	tests := []struct {
+		desc     string
+		id       string
+		before   time.Time
+		limit    int
+		expected sdk.PipelineStatusList
+	}{
+		{desc: "skips pending and unknown jobs", id: "1", before: at(60), limit: 4, expected: sdk.PipelineStatusList{
+			run("1", 11, sdk.StatusRunning, 50, 0),
+			manualRun(run("1", 10, sdk.StatusSuccess, 40, 45), "jane"),
+			run("1", 12, sdk.StatusFailure, 20, 21),
+			run("1", 11, sdk.StatusAborted, 10, 12),
+		}},
+		{desc: "respects before", id: "1", before: at(20), limit: 10, expected: sdk.PipelineStatusList{
+			run("1", 11, sdk.StatusAborted, 10, 12),
+			run("1", 10, sdk.StatusFailure, 1, 2),
+		}},
+		{desc: "empty history", id: "2", before: at(60), limit: 10, expected: sdk.PipelineStatusList{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p, closeFn := newTestProvider(newFakeAtc(), nil)
+			defer closeFn()
+
+			// small pages make sure the provider follows the pagination links of the ATC
+			p.(*provider).cc = &pageLimitApi{API: p.(*provider).cc, limit: 2}
+
+			history, err := p.GetHistory(context.Background(), test.id, test.before, test.limit)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			if !cmp.Equal(test.expected, history) {
+				tt.Errorf("history mismatch: \n%s\n", cmp.Diff(test.expected, history))
+			}
+		})
+	}
+}
+
+func TestListUpdates(t *testing.T) {
+	p, closeFn := newTestProvider(newFakeAtc(), nil)
+	defer closeFn()
+
+	updates, err := p.ListUpdates(context.Background(), at(30))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := sdk.PipelineUpdates{
+		Runs: sdk.PipelineStatusList{
+			run("1", 11, sdk.StatusRunning, 50, 0),
+			manualRun(run("1", 10, sdk.StatusSuccess, 40, 45), "jane"),
+		},
+	}
+	if !cmp.Equal(expected, updates) {
+		t.Errorf("updates mismatch: \n%s\n", cmp.Diff(expected, updates))
+	}
+
+	updates, err = p.ListUpdates(context.Background(), at(-1))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(updates.Versions) != 1 || updates.Versions[0].PipelineId != "1" {
+		t.Errorf("expected new version of pipeline '1', got %v", updates.Versions)
+	}
+	if len(updates.Runs) != 5 {
+		t.Errorf("expected 5 runs, got %d", len(updates.Runs))
+	}
+}
+
+func TestListUpdatesOfLongRunningBuilds(t *testing.T) {
+	atc := newFakeAtc()
+	atc.builds["main/app"] = []Build{
+		{Id: 4, JobName: "build", Status: "succeeded", StartTime: minutes(20), EndTime: minutes(21)},
+		{Id: 3, JobName: "test", Status: "started", StartTime: minutes(10)},
+		{Id: 2, JobName: "deploy", Status: "succeeded", StartTime: minutes(-60), EndTime: minutes(40)},
+		{Id: 1, JobName: "test", Status: "started", StartTime: minutes(-2 * 24 * 60)},
+	}
+	p, closeFn := newTestProvider(atc, []string{"main"})
+	defer closeFn()
+
+	updates, err := p.ListUpdates(context.Background(), at(30))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := sdk.PipelineUpdates{
+		Runs: sdk.PipelineStatusList{
+			run("1", 10, sdk.StatusRunning, 10, 0),
+			run("1", 12, sdk.StatusSuccess, -60, 40),
+		},
+	}
+	if !cmp.Equal(expected, updates) {
+		t.Errorf("updates mismatch: \n%s\n", cmp.Diff(expected, updates))
+	}
+}
+
+func TestToSdkStatus(t *testing.T) {
+	tests := map[string]sdk.StepStatus{
+		"succeeded": sdk.StatusSuccess,
+		"failed":    sdk.StatusFailure,
+		"errored":   sdk.StatusFailure,
+		"started":   sdk.StatusRunning,
+		"aborted":   sdk.StatusAborted,
+		"pending":   sdk.StatusPending,
+	}
+
+	for status, expected := range tests {
+		if toSdkStatus(status) != expected {
+			t.Errorf("expected '%s' to map to '%s', got '%s'", status, expected, toSdkStatus(status))
+		}
+	}
+}
+
+func run(pipeline string, step int, status sdk.StepStatus, started int, ended int) sdk.PipelineStatus {
+	end := time.Time{}
+	if ended != 0 {
+		end = at(ended)
+	}
+	return sdk.PipelineStatus{
+		PipelineId: pipeline,
+		Started:    at(started),
+		Steps: []sdk.StepRun{
+			{StepId: step, Status: status, Started: at(started), Ended: end},
+		},
+	}
+}
+
+type pageLimitApi struct {
+	API
+	limit int
+}
+
+func (a *pageLimitApi) ListBuilds(ctx context.Context, team string, pipeline string, page Page) ([]Build, *Page, error) {
+	page.Limit = a.limit
+	return a.API.ListBuilds(ctx, team, pipeline, page)
+}
+
+func manualRun(run sdk.PipelineStatus, author string) sdk.PipelineStatus {
+	run.Trigger = &sdk.RunTrigger{Type: sdk.TriggerManual, Author: author}
+	return run
+}
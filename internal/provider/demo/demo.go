@@ -1,6 +1,8 @@
 package demo
 
 import (
+	"context"
+	"fmt"
 	"github.com/Pallinder/go-randomdata"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 	"time"
@@ -13,6 +15,7 @@ func NewProvider() *Provider {
 	p.GenerateHistory()
 	p.GenerateAppInstances()
 	p.GenerateAppRouting()
+	p.GenerateTopology()
 
 	return p
 }
@@ -24,6 +27,7 @@ type Provider struct {
 	versions  map[string]sdk.PipelineVersionList
 	routing   map[string]sdk.AppRouting
 	instances map[string]sdk.AppInstances
+	topology  sdk.Topology
 }
 
 func (d *Provider) GetAppInstances(id string) (sdk.AppInstances, error) {
@@ -34,6 +38,10 @@ func (d *Provider) GetAppRouting(id string) (sdk.AppRouting, error) {
 	return d.routing[id], nil
 }
 
+func (d *Provider) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	return d.topology, nil
+}
+
 func (d *Provider) GenerateApps() {
 	var apps []sdk.App
 	for i := 0; i < 1000; i++ {
@@ -83,7 +91,8 @@ func (d *Provider) GenerateHistory() {
 	versions := make(map[string]sdk.PipelineVersionList)
 
 	for i, pipeline := range d.pipelines {
-		now := time.Now()
+		// whole seconds, so runs can still be found after a round trip through the core
+		now := time.Now().Truncate(time.Second)
 		start := now.Add(-(time.Hour * 24) * time.Duration(randomdata.Number(1, 365)))
 
 		pVersion := sdk.PipelineVersion{
@@ -98,15 +107,18 @@ func (d *Provider) GenerateHistory() {
 			run := sdk.PipelineStatus{
 				Started:    start,
 				PipelineId: pipeline.Id,
+				Trigger:    runTrigger(),
 			}
 			for _, step := range pipeline.Current.Definition.Steps {
 				end := start.Add(time.Second * time.Duration(randomdata.Number(20, 180)))
 
 				run.Steps = append(run.Steps, sdk.StepRun{
-					StepId:  step.Id,
-					Status:  sdk.StatusSuccess,
-					Started: start,
-					Ended:   end,
+					StepId:    step.Id,
+					Status:    sdk.StatusSuccess,
+					Started:   start,
+					Ended:     end,
+					Url:       fmt.Sprintf("https://ci.example.com/pipelines/%s/runs/%d/steps/%d", pipeline.Id, run.Started.Unix(), step.Id),
+					Artifacts: stepArtifacts(pipeline.Id, run.Started, step.Id),
 				})
 
 				start = end.Add(time.Second * time.Duration(randomdata.Number(5, 20)))
@@ -177,7 +189,10 @@ func (d *Provider) GetPipeline(id string) (sdk.Pipeline, error) {
 }
 
 func (d *Provider) GetHistory(id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
-	runs := d.history[id]
+	runs, ok := d.history[id]
+	if !ok {
+		return nil, sdk.ErrNotFound
+	}
 
 	var res []sdk.PipelineStatus
 	for i := len(runs) - 1; i > 0; i-- {
@@ -192,6 +207,22 @@ func (d *Provider) GetHistory(id string, before time.Time, limit int) (sdk.Pipel
 	return res, nil
 }
 
+// GetStepLogs makes up the logs of a step run. They are derived from the step run, so they
+// stay the same across requests.
+func (d *Provider) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (sdk.StepLogsPage, error) {
+	for _, run := range d.history[pipelineId] {
+		if !run.Started.Equal(runStarted) {
+			continue
+		}
+		for _, step := range run.Steps {
+			if step.StepId == stepId {
+				return sdk.PageStepLogs(stepLogs(step), perPage, page)
+			}
+		}
+	}
+	return sdk.StepLogsPage{}, sdk.ErrNotFound
+}
+
 func (d *Provider) ListApps() ([]sdk.App, error) {
 	return d.apps, nil
 }
@@ -242,10 +273,7 @@ func (d *Provider) GenerateAppInstances() {
 		for i := 0; i < n; i++ {
 			now := time.Now()
 			since := now.Add(-(time.Minute) * time.Duration(randomdata.Number(1, 365)))
-			d.instances[app.Id] = append(d.instances[app.Id], sdk.AppInstance{
-				State: appState(),
-				Since: since,
-			})
+			d.instances[app.Id] = append(d.instances[app.Id], instance(i, appState(), since))
 		}
 	}
 }
@@ -256,12 +284,37 @@ func (d *Provider) GenerateAppRouting() {
 		var routes sdk.AppRoutes
 		n := randomdata.Number(1, 10)
 		for i := 0; i < n; i++ {
-			routes = append(routes, sdk.AppRoute{
-				Host:    host(),
-				Path:    path(),
-				AppPort: port(),
-			})
+			routes = append(routes, route())
 		}
 		d.routing[app.Id] = sdk.AppRouting{Routes: routes}
 	}
 }
+
+// GenerateTopology lets apps call apps generated before them only, so the graph stays acyclic.
+func (d *Provider) GenerateTopology() {
+	var services []sdk.BackingService
+	for i := 0; i < 50; i++ {
+		services = append(services, backingService())
+	}
+
+	var dependencies []sdk.Dependency
+	for i, app := range d.apps {
+		targets := map[string]bool{}
+		for j := randomdata.Number(0, 3); j > 0; j-- {
+			service := services[randomdata.Number(0, len(services))]
+			if !targets[service.Id] {
+				targets[service.Id] = true
+				dependencies = append(dependencies, sdk.Dependency{AppId: app.Id, Target: sdk.DependencyTargetService, TargetId: service.Id})
+			}
+		}
+		for j := randomdata.Number(0, 3); j > 0 && i > 0; j-- {
+			target := d.apps[randomdata.Number(0, i)]
+			if !targets[target.Id] {
+				targets[target.Id] = true
+				dependencies = append(dependencies, sdk.Dependency{AppId: app.Id, Target: sdk.DependencyTargetApp, TargetId: target.Id})
+			}
+		}
+	}
+
+	d.topology = sdk.Topology{Services: services, Dependencies: dependencies}
+}
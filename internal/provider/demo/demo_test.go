@@ -0,0 +1,20 @@
+package demo
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk/sdktest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	p := NewProvider()
+	sdktest.RunConfig(t, sdk.ProviderConfig{
+		Name:      "demo",
+		Apps:      sdk.AppProviderWithContext(p),
+		Pipelines: sdk.PipelineProviderWithContext(p),
+		Instances: sdk.InstancesProviderWithContext(p),
+		Routing:   sdk.RoutingProviderWithContext(p),
+		StepLogs:  p,
+		Topology:  p,
+	})
+}
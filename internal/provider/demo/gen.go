@@ -5,7 +5,9 @@ import (
 	"github.com/Pallinder/go-randomdata"
 	"github.com/google/uuid"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"math/rand"
 	"strings"
+	"time"
 )
 
 func id() string {
@@ -20,6 +22,34 @@ func appState() sdk.AppState {
 	return appStates[randomdata.Number(0, len(appStates)-1)]
 }
 
+// instance makes up the details of an instance. Only running instances report their usage,
+// crashed ones were restarted a few times.
+func instance(index int, state sdk.AppState, since time.Time) sdk.AppInstance {
+	i := sdk.AppInstance{
+		Index: index,
+		State: state,
+		Since: since,
+		Host:  fmt.Sprintf("cell-%d", randomdata.Number(1, 20)),
+	}
+
+	switch state {
+	case sdk.AppStateRunning:
+		memQuota := int64(256 << (20 + randomdata.Number(0, 4)))
+		i.Uptime = int64(time.Since(since).Seconds())
+		i.Usage = &sdk.InstanceUsage{
+			Cpu:         float64(randomdata.Number(1, 100)) / 100,
+			Memory:      memQuota * int64(randomdata.Number(20, 95)) / 100,
+			MemoryQuota: memQuota,
+			Disk:        int64(randomdata.Number(50, 900)) << 20,
+			DiskQuota:   1 << 30,
+		}
+	case sdk.AppStateCrashed:
+		i.Restarts = randomdata.Number(1, 10)
+		i.LastCrash = crashReasons[randomdata.Number(0, len(crashReasons))]
+	}
+	return i
+}
+
 func pipelineName() string {
 	return pipelineWords[randomdata.Number(0, len(pipelineWords))] + "-" + randomdata.Adjective() + "-" + randomdata.Noun()
 }
@@ -36,6 +66,41 @@ func host() string {
 	return randomdata.Noun() + ".com"
 }
 
+func route() sdk.AppRoute {
+	r := sdk.AppRoute{
+		Host:     host(),
+		Path:     path(),
+		AppPort:  port(),
+		Protocol: sdk.RouteProtocolHttp,
+	}
+	r.Domain = r.Host
+
+	switch n := randomdata.Number(0, 100); {
+	case n > 95:
+		r.Protocol = sdk.RouteProtocolTcp
+		r.Path = ""
+	case n > 90:
+		r.Protocol = sdk.RouteProtocolGrpc
+	}
+
+	if randomdata.Number(0, 100) > 85 {
+		r.Internal = true
+		r.Domain = "apps.internal"
+		r.Host = strings.TrimSuffix(r.Host, ".com") + "." + r.Domain
+	}
+
+	if randomdata.Number(0, 100) > 90 {
+		r.Weight = randomdata.Number(1, 100)
+	}
+
+	if !r.Internal && r.Protocol != sdk.RouteProtocolTcp {
+		expires := time.Now().Add(time.Duration(randomdata.Number(1, 365*24)) * time.Hour)
+		r.Tls = &sdk.RouteTls{Enabled: true, Expires: &expires}
+	}
+
+	return r
+}
+
 func path() string {
 	path := "/"
 
@@ -60,10 +125,68 @@ func team() string {
 	return strings.ToLower(randomdata.FirstName(randomdata.RandomGender)) + "s-" + randomdata.Adjective() + "-" + strings.ToLower(randomdata.Noun()) + "s"
 }
 
+func backingService() sdk.BackingService {
+	kind := serviceKinds[randomdata.Number(0, len(serviceKinds))]
+	return sdk.BackingService{
+		Id:   id(),
+		Name: randomdata.Adjective() + "-" + kind,
+		Kind: kind,
+	}
+}
+
 func pipelineStep() string {
 	return pipelineStepNames[randomdata.Number(0, len(pipelineStepNames)-1)]
 }
 
+func stepArtifacts(pipelineId string, runStarted time.Time, stepId int) []sdk.StepArtifact {
+	if randomdata.Number(0, 100) > 30 {
+		return nil
+	}
+	name := artifactNames[randomdata.Number(0, len(artifactNames))]
+	return []sdk.StepArtifact{{
+		Name: name,
+		Url:  fmt.Sprintf("https://ci.example.com/pipelines/%s/runs/%d/steps/%d/artifacts/%s", pipelineId, runStarted.Unix(), stepId, name),
+	}}
+}
+
+// runTrigger makes up a commit on one of a few branches. Most runs are triggered by a push,
+// some by hand or by the nightly schedule.
+func runTrigger() *sdk.RunTrigger {
+	t := &sdk.RunTrigger{
+		Type:    sdk.TriggerWebhook,
+		Commit:  fmt.Sprintf("%040x", rand.Uint64()),
+		Branch:  branchNames[randomdata.Number(0, len(branchNames))],
+		Author:  strings.ToLower(randomdata.FirstName(randomdata.RandomGender)),
+		Message: commitMessages[randomdata.Number(0, len(commitMessages))],
+	}
+
+	switch n := randomdata.Number(0, 100); {
+	case n < 10:
+		t.Type = sdk.TriggerSchedule
+		t.Branch = "main"
+	case n < 25:
+		t.Type = sdk.TriggerManual
+	}
+	return t
+}
+
+// stepLogs returns a line every few seconds of the step run. The lines are seeded by the
+// step run, so they are the same every time.
+func stepLogs(step sdk.StepRun) []sdk.LogLine {
+	r := rand.New(rand.NewSource(step.Started.Unix() + int64(step.StepId)))
+
+	lines := []sdk.LogLine{{Time: step.Started, Stream: sdk.LogStreamOut, Message: "starting step"}}
+	for t := step.Started.Add(time.Second); t.Before(step.Ended); t = t.Add(time.Duration(r.Intn(5)+1) * time.Second) {
+		line := sdk.LogLine{Time: t, Stream: sdk.LogStreamOut, Message: stepLogMessages[r.Intn(len(stepLogMessages))]}
+		if r.Intn(20) == 0 {
+			line.Stream = sdk.LogStreamErr
+			line.Message = "warning: " + line.Message + " took longer than expected"
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, sdk.LogLine{Time: step.Ended, Stream: sdk.LogStreamOut, Message: "step " + string(step.Status)})
+}
+
 var appWords = []string{
 	"generator",
 	"service",
@@ -164,3 +287,58 @@ var pipelineStepNames = []string{
 	"build",
 	"verify",
 }
+
+var crashReasons = []string{
+	"out of memory",
+	"exited with status 1",
+	"health check failed",
+	"exited with status 137",
+}
+
+var artifactNames = []string{
+	"test-report.html",
+	"coverage.xml",
+	"app.jar",
+	"image-digest.txt",
+	"changelog.md",
+}
+
+var stepLogMessages = []string{
+	"fetching dependencies",
+	"compiling sources",
+	"running unit tests",
+	"running integration tests",
+	"uploading cache",
+	"pushing image",
+	"waiting for rollout",
+	"checking health endpoint",
+	"collecting artifacts",
+	"cleaning up workspace",
+}
+
+var branchNames = []string{
+	"main",
+	"main",
+	"main",
+	"develop",
+	"feature/login",
+	"fix/timeouts",
+}
+
+var commitMessages = []string{
+	"Fix flaky test",
+	"Bump dependencies",
+	"Add health endpoint",
+	"Refactor configuration loading",
+	"Update README",
+	"Improve error messages",
+}
+
+var serviceKinds = []string{
+	"postgresql",
+	"mysql",
+	"redis",
+	"rabbitmq",
+	"elasticsearch",
+	"s3",
+}
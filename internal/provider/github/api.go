@@ -2,10 +2,12 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/bradleyfalzon/ghinstallation"
 	"github.com/google/go-github/v39/github"
 	"net/http"
+	"time"
 )
 
 /**
@@ -14,6 +16,13 @@ API is a simplified wrapper around the github api
 type API interface {
 	ListTeams(org string) ([]Team, error)
 	ListMembers(org string, team string) ([]Member, error)
+
+	ListRepositories(ctx context.Context, org string) ([]string, error)
+	ListWorkflows(ctx context.Context, org string, repo string) ([]Workflow, error)
+	// ListWorkflowRuns returns one page of runs of the workflow created before the given time,
+	// newest first. The returned page number is zero if there are no more runs.
+	ListWorkflowRuns(ctx context.Context, org string, repo string, workflow int64, before time.Time, page int) ([]WorkflowRun, int, error)
+	ListJobRuns(ctx context.Context, org string, repo string, run int64) ([]JobRun, error)
 }
 
 type Login struct {
@@ -49,21 +58,18 @@ type api struct {
 }
 
 func (a *api) ListMembers(org string, team string) ([]Member, error) {
-	opt := &github.TeamListTeamMembersOptions{
-		ListOptions: github.ListOptions{PerPage: 10},
+	maintainers, err := a.listTeamMembers(org, team, "maintainer")
+	if err != nil {
+		return nil, err
+	}
+	isMaintainer := make(map[int64]bool, len(maintainers))
+	for _, user := range maintainers {
+		isMaintainer[user.GetID()] = true
 	}
 
-	var allUsers []*github.User
-	for {
-		users, resp, err := a.c.ListTeamMembersBySlug(context.Background(), org, team, opt)
-		if err != nil {
-			return nil, err
-		}
-		allUsers = append(allUsers, users...)
-		if resp.NextPage == 0 {
-			break
-		}
-		opt.Page = resp.NextPage
+	allUsers, err := a.listTeamMembers(org, team, "all")
+	if err != nil {
+		return nil, err
 	}
 
 	var res []Member
@@ -72,15 +78,44 @@ func (a *api) ListMembers(org string, team string) ([]Member, error) {
 		if name == "" {
 			name = user.GetLogin()
 		}
+		role := "member"
+		if isMaintainer[user.GetID()] {
+			role = "maintainer"
+		}
 		res = append(res, Member{
-			Guid: fmt.Sprintf("%d", user.ID),
-			Name: name,
+			Guid:      fmt.Sprintf("%d", user.GetID()),
+			Name:      name,
+			Login:     user.GetLogin(),
+			Email:     user.GetEmail(),
+			AvatarUrl: user.GetAvatarURL(),
+			Role:      role,
 		})
 	}
 
 	return res, nil
 }
 
+func (a *api) listTeamMembers(org string, team string, role string) ([]*github.User, error) {
+	opt := &github.TeamListTeamMembersOptions{
+		Role:        role,
+		ListOptions: github.ListOptions{PerPage: 10},
+	}
+
+	var res []*github.User
+	for {
+		users, resp, err := a.c.ListTeamMembersBySlug(context.Background(), org, team, opt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, users...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return res, nil
+}
+
 func (a *api) ListTeams(org string) ([]Team, error) {
 	opt := &github.ListOptions{PerPage: 10}
 
@@ -99,14 +134,169 @@ func (a *api) ListTeams(org string) ([]Team, error) {
 
 	var res []Team
 	for _, team := range allTeams {
-		res = append(res, Team{
-			TeamInfo: TeamInfo{
-				Guid: fmt.Sprintf("%d", team.GetID()),
-				Slug: team.GetSlug(),
-				Name: team.GetName(),
-			},
+		info := TeamInfo{
+			Guid: fmt.Sprintf("%d", team.GetID()),
+			Slug: team.GetSlug(),
+			Name: team.GetName(),
+		}
+		if team.Parent != nil {
+			info.Parent = fmt.Sprintf("%d", team.Parent.GetID())
+		}
+		res = append(res, Team{TeamInfo: info})
+	}
+
+	return res, nil
+}
+
+func (a *api) ListRepositories(ctx context.Context, org string) ([]string, error) {
+	opt := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var res []string
+	for {
+		repos, resp, err := a.c.ListByOrg(ctx, org, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			res = append(res, repo.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return res, nil
+}
+
+func (a *api) ListWorkflows(ctx context.Context, org string, repo string) ([]Workflow, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	var allWorkflows []*github.Workflow
+	for {
+		workflows, resp, err := a.c.ListWorkflows(ctx, org, repo, opt)
+		if isNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		allWorkflows = append(allWorkflows, workflows.Workflows...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	var res []Workflow
+	for _, workflow := range allWorkflows {
+		jobs, err := a.workflowJobs(ctx, org, repo, workflow.GetPath())
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, Workflow{
+			Guid:      workflow.GetID(),
+			Repo:      repo,
+			Name:      workflow.GetName(),
+			Path:      workflow.GetPath(),
+			UpdatedAt: workflow.GetUpdatedAt().Time,
+			Jobs:      jobs,
+		})
+	}
+
+	return res, nil
+}
+
+// workflowJobs reads the jobs from the workflow file on the default branch. Workflows without
+// a readable file (e.g. the ones GitHub creates dynamically) have no jobs.
+func (a *api) workflowJobs(ctx context.Context, org string, repo string, path string) ([]WorkflowJob, error) {
+	file, _, _, err := a.c.GetContents(ctx, org, repo, path, nil)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := parseWorkflowJobs([]byte(content))
+	if err != nil {
+		return nil, nil
+	}
+	return jobs, nil
+}
+
+func (a *api) ListWorkflowRuns(ctx context.Context, org string, repo string, workflow int64, before time.Time, page int) ([]WorkflowRun, int, error) {
+	opt := &github.ListWorkflowRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 50, Page: page},
+	}
+	if !before.IsZero() {
+		opt.Created = "<" + before.UTC().Format(time.RFC3339)
+	}
+
+	runs, resp, err := a.c.ListWorkflowRunsByID(ctx, org, repo, workflow, opt)
+	if isNotFound(err) {
+		return nil, 0, errNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var res []WorkflowRun
+	for _, run := range runs.WorkflowRuns {
+		res = append(res, WorkflowRun{
+			Guid:          run.GetID(),
+			WorkflowGuid:  run.GetWorkflowID(),
+			CreatedAt:     run.GetCreatedAt().Time,
+			UpdatedAt:     run.GetUpdatedAt().Time,
+			Event:         run.GetEvent(),
+			HeadSha:       run.GetHeadSHA(),
+			HeadBranch:    run.GetHeadBranch(),
+			CommitAuthor:  run.GetHeadCommit().GetAuthor().GetName(),
+			CommitMessage: run.GetHeadCommit().GetMessage(),
 		})
 	}
 
+	return res, resp.NextPage, nil
+}
+
+func (a *api) ListJobRuns(ctx context.Context, org string, repo string, run int64) ([]JobRun, error) {
+	opt := &github.ListWorkflowJobsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var res []JobRun
+	for {
+		jobs, resp, err := a.c.ListWorkflowJobs(ctx, org, repo, run, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs.Jobs {
+			res = append(res, JobRun{
+				Name:        job.GetName(),
+				Status:      job.GetStatus(),
+				Conclusion:  job.GetConclusion(),
+				StartedAt:   job.GetStartedAt().Time,
+				CompletedAt: job.GetCompletedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
 	return res, nil
 }
+
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
@@ -12,6 +12,13 @@ Cli is an interface wrapping the methods we need from the official client
 type Cli interface {
 	ListTeams(ctx context.Context, org string, opts *github.ListOptions) ([]*github.Team, *github.Response, error)
 	ListTeamMembersBySlug(ctx context.Context, org string, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error)
+
+	ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error)
+	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
+
+	ListWorkflows(ctx context.Context, owner, repo string, opts *github.ListOptions) (*github.Workflows, *github.Response, error)
+	ListWorkflowRunsByID(ctx context.Context, owner, repo string, workflowID int64, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error)
+	ListWorkflowJobs(ctx context.Context, owner, repo string, runID int64, opts *github.ListWorkflowJobsOptions) (*github.Jobs, *github.Response, error)
 }
 
 func NewClient(c *http.Client) Cli {
@@ -22,10 +29,24 @@ func NewClient(c *http.Client) Cli {
 	ghCli := github.NewClient(c)
 
 	return gitHubCli{
-		ghCli.Teams,
+		TeamsService:   ghCli.Teams,
+		ActionsService: ghCli.Actions,
+		repos:          ghCli.Repositories,
 	}
 }
 
 type gitHubCli struct {
 	*github.TeamsService
+	*github.ActionsService
+
+	// not embedded, as the repositories service has a ListTeams method as well
+	repos *github.RepositoriesService
+}
+
+func (c gitHubCli) ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	return c.repos.ListByOrg(ctx, org, opts)
+}
+
+func (c gitHubCli) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	return c.repos.GetContents(ctx, owner, repo, path, opts)
 }
@@ -7,6 +7,11 @@ import (
 
 type Database interface {
 	AcceptReconcileJob(olderThan time.Duration) (reconciliation.Job, bool)
+	RetryReconcileJob(j reconciliation.Job, at time.Time, cause error) error
+	DeadLetterReconcileJob(j reconciliation.Job, cause error) error
+	SucceedReconcileJob(j reconciliation.Job) error
+	ListDeadLetters() ([]reconciliation.Job, error)
+	RequeueDeadLetter(t reconciliation.Type, guid string) error
 
 	ListTeams() ([]Team, error)
 	UpsertOrgTeams(org string, teams []Team) error
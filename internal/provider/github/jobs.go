@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"errors"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+)
+
+func NewJobsProvider(db Database) sdk.JobsProvider {
+	return &jobsProvider{
+		db: db,
+	}
+}
+
+type jobsProvider struct {
+	db Database
+}
+
+func (p *jobsProvider) ListDeadLetters(ctx context.Context) ([]sdk.DeadLetter, error) {
+	jobs, err := p.db.ListDeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sdk.DeadLetter, 0, len(jobs))
+	for _, j := range jobs {
+		res = append(res, sdk.DeadLetter{
+			Type:        string(j.Type),
+			Guid:        j.Guid,
+			Attempts:    j.Attempts,
+			LastError:   j.LastError,
+			LastAttempt: j.LastUpdated,
+		})
+	}
+	return res, nil
+}
+
+func (p *jobsProvider) RequeueDeadLetter(ctx context.Context, jobType string, guid string) error {
+	err := p.db.RequeueDeadLetter(recon.Type(jobType), guid)
+	if errors.Is(err, recon.ErrJobNotFound) {
+		return sdk.ErrNotFound
+	}
+	return err
+}
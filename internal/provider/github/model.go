@@ -23,9 +23,53 @@ type TeamInfo struct {
 	Guid string
 	Name string
 	Slug string
+	// Parent is the guid of the team this team is nested in, if any.
+	Parent string
 }
 
 type Member struct {
-	Guid string `bson:"guid"`
-	Name string `bson:"name"`
+	Guid      string `bson:"guid"`
+	Name      string `bson:"name"`
+	Login     string `bson:"login"`
+	Email     string `bson:"email"`
+	AvatarUrl string `bson:"avatarUrl"`
+	Role      string `bson:"role"`
+}
+
+type Workflow struct {
+	Guid      int64
+	Repo      string
+	Name      string
+	Path      string
+	UpdatedAt time.Time
+	Jobs      []WorkflowJob
+}
+
+// WorkflowJob is a job as defined in the workflow file. Key is the id of the job within
+// the file, which other jobs reference in their 'needs'.
+type WorkflowJob struct {
+	Key   string
+	Name  string
+	Needs []string
+}
+
+type WorkflowRun struct {
+	Guid         int64
+	WorkflowGuid int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	// Event is the name of the event that triggered the run, e.g. 'push' or 'schedule'.
+	Event         string
+	HeadSha       string
+	HeadBranch    string
+	CommitAuthor  string
+	CommitMessage string
+}
+
+type JobRun struct {
+	Name        string
+	Status      string
+	Conclusion  string
+	StartedAt   time.Time
+	CompletedAt time.Time
 }
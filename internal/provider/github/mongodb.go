@@ -35,6 +35,10 @@ func NewMongoDatabase(l MongoLogin, org string) (Database, error) {
 		orgs:  db.Collection("orgs"),
 		teams: db.Collection("teams"),
 	}
+	m.MongoJobStore = recon.NewMongoJobStore(
+		recon.JobCollection{Type: ReconcileTeams, Collection: m.orgs},
+		recon.JobCollection{Type: ReconcileMembers, Collection: m.teams},
+	)
 
 	err = m.setupBaseJob(org)
 
@@ -42,6 +46,7 @@ func NewMongoDatabase(l MongoLogin, org string) (Database, error) {
 }
 
 type mongoDatabase struct {
+	*recon.MongoJobStore
 	cli   *mongo.Client
 	db    *mongo.Database
 	teams *mongo.Collection
@@ -134,15 +139,24 @@ func (d *mongoDatabase) AcceptReconcileJob(olderThan time.Duration) (recon.Job,
 func (d *mongoDatabase) acceptCollectionReconcileJob(typ recon.Type, coll *mongo.Collection, t time.Time, olderThan time.Duration) (recon.Job, bool) {
 	lessThanTime := t.Add(-olderThan)
 	res := coll.FindOneAndUpdate(context.Background(), bson.M{
+		"deadLettered": bson.M{"$ne": true},
 		"$or": bson.A{
 			bson.M{
+				"nextAttempt": bson.M{"$lte": t},
+			},
+			bson.M{
+				"nextAttempt": nil,
 				"lastUpdated": bson.M{"$lte": lessThanTime},
 			},
-			bson.M{"lastUpdated": nil},
+			bson.M{
+				"nextAttempt": nil,
+				"lastUpdated": nil,
+			},
 		},
 	}, bson.M{
 		"$set": bson.M{
 			"lastUpdated": t,
+			"nextAttempt": nil,
 		},
 	}, options.FindOneAndUpdate().SetSort(bson.D{{"lastUpdated", 1}}))
 
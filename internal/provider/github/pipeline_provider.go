@@ -0,0 +1,352 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewPipelineProvider creates a pipeline provider for the GitHub Actions workflows of the
+// given repositories of an org. If repos is empty, all repositories of the org are used.
+func NewPipelineProvider(gh API, org string, repos []string) sdk.PipelineProviderContext {
+	return &pipelineProvider{
+		gh:    gh,
+		org:   org,
+		repos: repos,
+	}
+}
+
+type pipelineProvider struct {
+	gh    API
+	org   string
+	repos []string
+}
+
+func (p *pipelineProvider) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
+	workflows, err := p.listWorkflows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []sdk.Pipeline
+	for _, workflow := range workflows {
+		res = append(res, workflow.toSdkPipeline())
+	}
+	return res, nil
+}
+
+func (p *pipelineProvider) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
+	workflows, err := p.listWorkflows(ctx)
+	if err != nil {
+		return sdk.PipelineUpdates{}, err
+	}
+
+	updates := sdk.PipelineUpdates{}
+	for _, workflow := range workflows {
+		if workflow.UpdatedAt.After(since) {
+			updates.Versions = append(updates.Versions, workflow.toSdkVersion())
+		}
+
+		err = p.eachRun(ctx, workflow, time.Time{}, func(run WorkflowRun) (bool, error) {
+			if run.CreatedAt.Before(since) && run.UpdatedAt.Before(since) {
+				return false, nil
+			}
+
+			status, err := p.toSdkRun(ctx, workflow, run)
+			if err != nil {
+				return false, err
+			}
+			updates.Runs = append(updates.Runs, status)
+			return true, nil
+		})
+		if err != nil {
+			return sdk.PipelineUpdates{}, err
+		}
+	}
+
+	return updates, nil
+}
+
+func (p *pipelineProvider) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
+	workflow, err := p.findWorkflow(ctx, id)
+	if err != nil {
+		return sdk.Pipeline{}, err
+	}
+
+	return workflow.toSdkPipeline(), nil
+}
+
+func (p *pipelineProvider) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+	workflow, err := p.findWorkflow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := sdk.PipelineStatusList{}
+	err = p.eachRun(ctx, workflow, before, func(run WorkflowRun) (bool, error) {
+		if !run.CreatedAt.Before(before) {
+			return true, nil
+		}
+
+		status, err := p.toSdkRun(ctx, workflow, run)
+		if err != nil {
+			return false, err
+		}
+		res = append(res, status)
+		return limit <= 0 || len(res) < limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *pipelineProvider) listWorkflows(ctx context.Context) ([]Workflow, error) {
+	repos := p.repos
+	if len(repos) == 0 {
+		all, err := p.gh.ListRepositories(ctx, p.org)
+		if err != nil {
+			return nil, err
+		}
+		repos = all
+	}
+
+	var res []Workflow
+	for _, repo := range repos {
+		workflows, err := p.gh.ListWorkflows(ctx, p.org, repo)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, workflows...)
+	}
+	return res, nil
+}
+
+func (p *pipelineProvider) findWorkflow(ctx context.Context, id string) (Workflow, error) {
+	workflows, err := p.listWorkflows(ctx)
+	if err != nil {
+		return Workflow{}, err
+	}
+
+	for _, workflow := range workflows {
+		if workflow.pipelineId() == id {
+			return workflow, nil
+		}
+	}
+	return Workflow{}, sdk.ErrNotFound
+}
+
+// eachRun pages through the runs of the workflow, newest first, until f returns false.
+func (p *pipelineProvider) eachRun(ctx context.Context, w Workflow, before time.Time, f func(run WorkflowRun) (bool, error)) error {
+	page := 1
+	for page != 0 {
+		runs, next, err := p.gh.ListWorkflowRuns(ctx, p.org, w.Repo, w.Guid, before, page)
+		if errors.Is(err, errNotFound) {
+			return sdk.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, run := range runs {
+			ok, err := f(run)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+		page = next
+	}
+	return nil
+}
+
+func (p *pipelineProvider) toSdkRun(ctx context.Context, w Workflow, run WorkflowRun) (sdk.PipelineStatus, error) {
+	jobs, err := p.gh.ListJobRuns(ctx, p.org, w.Repo, run.Guid)
+	if err != nil {
+		return sdk.PipelineStatus{}, err
+	}
+
+	return sdk.PipelineStatus{
+		PipelineId: w.pipelineId(),
+		Started:    run.CreatedAt,
+		Trigger:    run.toSdkTrigger(),
+		Steps:      w.toSdkStepRuns(jobs),
+	}, nil
+}
+
+func (run WorkflowRun) toSdkTrigger() *sdk.RunTrigger {
+	t := sdk.RunTrigger{
+		Type:    toSdkTriggerType(run.Event),
+		Commit:  run.HeadSha,
+		Branch:  run.HeadBranch,
+		Author:  run.CommitAuthor,
+		Message: run.CommitMessage,
+	}
+	if t == (sdk.RunTrigger{}) {
+		return nil
+	}
+	return &t
+}
+
+// toSdkTriggerType maps the event that triggered a workflow run. Events that aren't started
+// by hand or by the schedule are sent as webhooks by GitHub.
+func toSdkTriggerType(event string) sdk.TriggerType {
+	switch event {
+	case "":
+		return ""
+	case "workflow_dispatch":
+		return sdk.TriggerManual
+	case "schedule":
+		return sdk.TriggerSchedule
+	default:
+		return sdk.TriggerWebhook
+	}
+}
+
+func (w Workflow) pipelineId() string {
+	return strconv.FormatInt(w.Guid, 10)
+}
+
+func (w Workflow) toSdkPipeline() sdk.Pipeline {
+	return sdk.Pipeline{
+		Id:      w.pipelineId(),
+		Name:    w.Repo + "/" + w.Name,
+		Current: w.toSdkVersion(),
+	}
+}
+
+// toSdkVersion converts the jobs of the workflow into steps, numbered in the order they are
+// defined in. The 'needs' of a job become connections to it.
+func (w Workflow) toSdkVersion() sdk.PipelineVersion {
+	ids := make(map[string]int, len(w.Jobs))
+	for i, job := range w.Jobs {
+		ids[job.Key] = i + 1
+	}
+
+	def := sdk.PipelineDefinition{}
+	for i, job := range w.Jobs {
+		def.Steps = append(def.Steps, sdk.PipelineStep{
+			Name: job.Name,
+			Id:   i + 1,
+		})
+
+		for _, need := range job.Needs {
+			from, ok := ids[need]
+			if !ok {
+				continue
+			}
+			def.Connections = append(def.Connections, sdk.PipelineConnection{
+				From: from,
+				To:   i + 1,
+			})
+		}
+	}
+
+	return sdk.PipelineVersion{
+		PipelineId: w.pipelineId(),
+		Created:    w.UpdatedAt,
+		Definition: def,
+	}
+}
+
+// toSdkStepRuns matches the job runs to the steps of the workflow by their name. Runs of a
+// matrix job are named like 'name (a, b)' and are merged into a single run of the step.
+func (w Workflow) toSdkStepRuns(jobs []JobRun) []sdk.StepRun {
+	ids := make(map[string]int, len(w.Jobs))
+	for i, job := range w.Jobs {
+		ids[job.Name] = i + 1
+	}
+
+	runs := make(map[int]sdk.StepRun)
+	for _, job := range jobs {
+		id, ok := ids[job.Name]
+		if !ok {
+			if idx := strings.Index(job.Name, " ("); idx != -1 {
+				id, ok = ids[job.Name[:idx]]
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		status, ok := toSdkStatus(job.Status, job.Conclusion)
+		if !ok {
+			continue
+		}
+
+		run := sdk.StepRun{
+			StepId:  id,
+			Status:  status,
+			Started: job.StartedAt,
+			Ended:   job.CompletedAt,
+		}
+		if existing, ok := runs[id]; ok {
+			run = mergeStepRuns(existing, run)
+		}
+		runs[id] = run
+	}
+
+	res := make([]sdk.StepRun, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, run)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StepId < res[j].StepId
+	})
+	return res
+}
+
+var statusPriority = map[sdk.StepStatus]int{
+	sdk.StatusSuccess: 0,
+	sdk.StatusPending: 1,
+	sdk.StatusAborted: 2,
+	sdk.StatusFailure: 3,
+	sdk.StatusRunning: 4,
+}
+
+func mergeStepRuns(a sdk.StepRun, b sdk.StepRun) sdk.StepRun {
+	res := a
+	if statusPriority[b.Status] > statusPriority[a.Status] {
+		res.Status = b.Status
+	}
+	if res.Started.IsZero() || (!b.Started.IsZero() && b.Started.Before(res.Started)) {
+		res.Started = b.Started
+	}
+	if a.Ended.IsZero() || b.Ended.IsZero() {
+		res.Ended = time.Time{}
+	} else if b.Ended.After(a.Ended) {
+		res.Ended = b.Ended
+	}
+	return res
+}
+
+// toSdkStatus maps the status and conclusion of a job run. Skipped jobs did not run at all
+// and are not reported.
+func toSdkStatus(status string, conclusion string) (sdk.StepStatus, bool) {
+	switch status {
+	case "queued":
+		return sdk.StatusPending, true
+	case "in_progress":
+		return sdk.StatusRunning, true
+	}
+
+	switch conclusion {
+	case "success", "neutral":
+		return sdk.StatusSuccess, true
+	case "failure", "timed_out", "action_required":
+		return sdk.StatusFailure, true
+	case "cancelled":
+		return sdk.StatusAborted, true
+	case "skipped":
+		return "", false
+	default:
+		return sdk.StatusPending, true
+	}
+}
@@ -0,0 +1,322 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+func at(n int) time.Time {
+	return someTime.Add(time.Duration(n) * time.Minute)
+}
+
+func ts(n int) *github.Timestamp {
+	return &github.Timestamp{Time: at(n)}
+}
+
+const someWorkflowFile = `
+name: build
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        go: [1.16, 1.17]
+  lint:
+    name: Lint Code
+    runs-on: ubuntu-latest
+  build:
+    needs: [test, lint]
+    runs-on: ubuntu-latest
+  release:
+    needs: build
+    runs-on: ubuntu-latest
+`
+
+var expectedDefinition = sdk.PipelineDefinition{
+	Steps: []sdk.PipelineStep{
+		{Name: "test", Id: 1},
+		{Name: "Lint Code", Id: 2},
+		{Name: "build", Id: 3},
+		{Name: "release", Id: 4},
+	},
+	Connections: []sdk.PipelineConnection{
+		{From: 1, To: 3},
+		{From: 2, To: 3},
+		{From: 3, To: 4},
+	},
+}
+
+func newFakeCli() *fakeCli {
+	return &fakeCli{
+		repos: []string{"app", "lib"},
+		workflows: map[string][]*github.Workflow{
+			"app": {
+				{ID: github.Int64(1), Name: github.String("build"), Path: github.String(".github/workflows/build.yml"), UpdatedAt: ts(0)},
+			},
+			"lib": {
+				{ID: github.Int64(2), Name: github.String("pages"), Path: github.String("dynamic/pages"), UpdatedAt: ts(-60)},
+			},
+		},
+		files: map[string]string{
+			"app/.github/workflows/build.yml": someWorkflowFile,
+		},
+		runs: map[int64][]*github.WorkflowRun{
+			1: {
+				{ID: github.Int64(13), CreatedAt: ts(50), UpdatedAt: ts(51), Event: github.String("push"),
+					HeadSHA: github.String("abc123"), HeadBranch: github.String("main"), HeadCommit: &github.HeadCommit{
+						Message: github.String("fix build"), Author: &github.CommitAuthor{Name: github.String("jane")},
+					}},
+				{ID: github.Int64(12), CreatedAt: ts(40), UpdatedAt: ts(45), Event: github.String("workflow_dispatch"),
+					HeadSHA: github.String("def456"), HeadBranch: github.String("develop")},
+				{ID: github.Int64(11), CreatedAt: ts(10), UpdatedAt: ts(20)},
+			},
+		},
+		jobs: map[int64][]*github.WorkflowJob{
+			13: {
+				{Name: github.String("test (1.16)"), Status: github.String("completed"), Conclusion: github.String("success"), StartedAt: ts(50), CompletedAt: ts(51)},
+				{Name: github.String("test (1.17)"), Status: github.String("in_progress"), StartedAt: ts(50)},
+				{Name: github.String("Lint Code"), Status: github.String("queued")},
+			},
+			12: {
+				{Name: github.String("test (1.16)"), Status: github.String("completed"), Conclusion: github.String("success"), StartedAt: ts(41), CompletedAt: ts(42)},
+				{Name: github.String("test (1.17)"), Status: github.String("completed"), Conclusion: github.String("failure"), StartedAt: ts(40), CompletedAt: ts(43)},
+				{Name: github.String("Lint Code"), Status: github.String("completed"), Conclusion: github.String("cancelled"), StartedAt: ts(40), CompletedAt: ts(41)},
+				{Name: github.String("build"), Status: github.String("completed"), Conclusion: github.String("skipped")},
+			},
+			11: {
+				{Name: github.String("build"), Status: github.String("completed"), Conclusion: github.String("success"), StartedAt: ts(10), CompletedAt: ts(20)},
+			},
+		},
+	}
+}
+
+func newTestPipelineProvider(cli Cli, repos []string) sdk.PipelineProviderContext {
+	return NewPipelineProvider(NewApi(cli), "org", repos)
+}
+
+func TestListPipelines(t *testing.T) {
+	p := newTestPipelineProvider(newFakeCli(), nil)
+
+	pipelines, err := p.ListPipelines(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []sdk.Pipeline{
+		{Id: "1", Name: "app/build", Current: sdk.PipelineVersion{
+			PipelineId: "1", Created: at(0), Definition: expectedDefinition,
+		}},
+		{Id: "2", Name: "lib/pages", Current: sdk.PipelineVersion{
+			PipelineId: "2", Created: at(-60),
+		}},
+	}
+	if !cmp.Equal(expected, pipelines) {
+		t.Errorf("pipelines mismatch: \n%s\n", cmp.Diff(expected, pipelines))
+	}
+}
+
+func TestListPipelinesOfRepos(t *testing.T) {
+	p := newTestPipelineProvider(newFakeCli(), []string{"lib"})
+
+	pipelines, err := p.ListPipelines(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(pipelines) != 1 || pipelines[0].Id != "2" {
+		t.Errorf("expected only pipeline of repo 'lib', got %v", pipelines)
+	}
+}
+
+func TestGetPipeline(t *testing.T) {
+	p := newTestPipelineProvider(newFakeCli(), nil)
+
+	pipeline, err := p.GetPipeline(context.Background(), "1")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(expectedDefinition, pipeline.Current.Definition) {
+		t.Errorf("definition mismatch: \n%s\n", cmp.Diff(expectedDefinition, pipeline.Current.Definition))
+	}
+
+	_, err = p.GetPipeline(context.Background(), "3")
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	run13 := sdk.PipelineStatus{PipelineId: "1", Started: at(50), Trigger: &sdk.RunTrigger{
+		Type: sdk.TriggerWebhook, Commit: "abc123", Branch: "main", Author: "jane", Message: "fix build",
+	}, Steps: []sdk.StepRun{
+		{StepId: 1, Status: sdk.StatusRunning, Started: at(50)},
+		{StepId: 2, Status: sdk.StatusPending},
+	}}
+	run12 := sdk.PipelineStatus{PipelineId: "1", Started: at(40), Trigger: &sdk.RunTrigger{
+		Type: sdk.TriggerManual, Commit: "def456", Branch: "develop",
+	}, Steps: []sdk.StepRun{
+		{StepId: 1, Status: sdk.StatusFailure, Started: at(40), Ended: at(43)},
+		{StepId: 2, Status: sdk.StatusAborted, Started: at(40), Ended: at(41)},
+	}}
+	run11 := sdk.PipelineStatus{PipelineId: "1", Started: at(10), Steps: []sdk.StepRun{
+		{StepId: 3, Status: sdk.StatusSuccess, Started: at(10), Ended: at(20)},
+	}}
+
+	tests := []struct {
+		desc     string
+		id       string
+		before   time.Time
+		limit    int
+		expected sdk.PipelineStatusList
+	}{
+		{"merges matrix jobs", "1", at(60), 2, sdk.PipelineStatusList{run13, run12}},
+		{"respects before", "1", at(40), 10, sdk.PipelineStatusList{run11}},
+		{"empty history", "2", at(60), 10, sdk.PipelineStatusList{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := newTestPipelineProvider(newFakeCli(), nil)
+
+			history, err := p.GetHistory(context.Background(), test.id, test.before, test.limit)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+			if !cmp.Equal(test.expected, history) {
+				tt.Errorf("history mismatch: \n%s\n", cmp.Diff(test.expected, history))
+			}
+		})
+	}
+}
+
+func TestListUpdates(t *testing.T) {
+	p := newTestPipelineProvider(newFakeCli(), nil)
+
+	updates, err := p.ListUpdates(context.Background(), at(30))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(updates.Versions) != 0 {
+		t.Errorf("expected no new versions, got %v", updates.Versions)
+	}
+	if len(updates.Runs) != 2 {
+		t.Errorf("expected 2 runs, got %d", len(updates.Runs))
+	}
+
+	updates, err = p.ListUpdates(context.Background(), at(-1))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if len(updates.Versions) != 1 || updates.Versions[0].PipelineId != "1" {
+		t.Errorf("expected new version of pipeline '1', got %v", updates.Versions)
+	}
+	if len(updates.Runs) != 3 {
+		t.Errorf("expected 3 runs, got %d", len(updates.Runs))
+	}
+}
+
+func TestParseWorkflowJobs(t *testing.T) {
+	jobs, err := parseWorkflowJobs([]byte(someWorkflowFile))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []WorkflowJob{
+		{Key: "test", Name: "test"},
+		{Key: "lint", Name: "Lint Code"},
+		{Key: "build", Name: "build", Needs: []string{"test", "lint"}},
+		{Key: "release", Name: "release", Needs: []string{"build"}},
+	}
+	if !cmp.Equal(expected, jobs) {
+		t.Errorf("jobs mismatch: \n%s\n", cmp.Diff(expected, jobs))
+	}
+}
+
+// fakeCli serves a single page per request, so runs are paged one by one.
+type fakeCli struct {
+	repos     []string
+	workflows map[string][]*github.Workflow
+	files     map[string]string
+	runs      map[int64][]*github.WorkflowRun
+	jobs      map[int64][]*github.WorkflowJob
+}
+
+func notFound() error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
+
+func (f *fakeCli) ListTeams(ctx context.Context, org string, opts *github.ListOptions) ([]*github.Team, *github.Response, error) {
+	return nil, &github.Response{}, nil
+}
+
+func (f *fakeCli) ListTeamMembersBySlug(ctx context.Context, org string, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error) {
+	return nil, &github.Response{}, nil
+}
+
+func (f *fakeCli) ListByOrg(ctx context.Context, org string, opts *github.RepositoryListByOrgOptions) ([]*github.Repository, *github.Response, error) {
+	var res []*github.Repository
+	for _, repo := range f.repos {
+		res = append(res, &github.Repository{Name: github.String(repo)})
+	}
+	return res, &github.Response{}, nil
+}
+
+func (f *fakeCli) GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
+	content, ok := f.files[repo+"/"+path]
+	if !ok {
+		return nil, nil, nil, notFound()
+	}
+	return &github.RepositoryContent{
+		Encoding: github.String("base64"),
+		Content:  github.String(base64.StdEncoding.EncodeToString([]byte(content))),
+	}, nil, &github.Response{}, nil
+}
+
+func (f *fakeCli) ListWorkflows(ctx context.Context, owner, repo string, opts *github.ListOptions) (*github.Workflows, *github.Response, error) {
+	workflows, ok := f.workflows[repo]
+	if !ok {
+		return nil, nil, notFound()
+	}
+	return &github.Workflows{Workflows: workflows}, &github.Response{}, nil
+}
+
+func (f *fakeCli) ListWorkflowRunsByID(ctx context.Context, owner, repo string, workflowID int64, opts *github.ListWorkflowRunsOptions) (*github.WorkflowRuns, *github.Response, error) {
+	var runs []*github.WorkflowRun
+	for _, run := range f.runs[workflowID] {
+		if opts.Created == "" || run.GetCreatedAt().Time.Before(createdBefore(opts.Created)) {
+			runs = append(runs, run)
+		}
+	}
+
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	if page > len(runs) {
+		return &github.WorkflowRuns{}, &github.Response{}, nil
+	}
+
+	next := page + 1
+	if next > len(runs) {
+		next = 0
+	}
+	return &github.WorkflowRuns{WorkflowRuns: runs[page-1 : page]}, &github.Response{NextPage: next}, nil
+}
+
+func (f *fakeCli) ListWorkflowJobs(ctx context.Context, owner, repo string, runID int64, opts *github.ListWorkflowJobsOptions) (*github.Jobs, *github.Response, error) {
+	return &github.Jobs{Jobs: f.jobs[runID]}, &github.Response{}, nil
+}
+
+func createdBefore(filter string) time.Time {
+	t, _ := time.Parse(time.RFC3339, filter[1:])
+	return t
+}
@@ -1,11 +1,12 @@
 package github
 
 import (
+	"context"
 	"fmt"
 	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
 )
 
-func NewGroupProvider(db Database) sdk.GroupProvider {
+func NewGroupProvider(db Database) sdk.GroupProviderContext {
 	return &provider{
 		db: db,
 	}
@@ -15,7 +16,7 @@ type provider struct {
 	db Database
 }
 
-func (p *provider) ListGroups() ([]sdk.Group, error) {
+func (p *provider) ListGroups(ctx context.Context) ([]sdk.Group, error) {
 	ghTeams, err := p.db.ListTeams()
 	if err != nil {
 		return nil, err
@@ -28,7 +29,7 @@ func (p *provider) ListGroups() ([]sdk.Group, error) {
 	return res, nil
 }
 
-func (p *provider) GetGroup(id string) (sdk.Group, error) {
+func (p *provider) GetGroup(ctx context.Context, id string) (sdk.Group, error) {
 	team, err := p.db.GetTeam(id)
 	if err != nil {
 		return sdk.Group{}, err
@@ -41,13 +42,22 @@ func (t Team) toSdkGroup() sdk.Group {
 	var members []sdk.Member
 	for _, member := range t.Members {
 		members = append(members, sdk.Member{
-			Id:   member.Guid,
-			Name: member.Name,
+			Id:        member.Guid,
+			Name:      member.Name,
+			Login:     member.Login,
+			Email:     member.Email,
+			AvatarUrl: member.AvatarUrl,
+			Role:      member.Role,
 		})
 	}
-	return sdk.Group{
+
+	group := sdk.Group{
 		Id:      fmt.Sprintf("%s:%s", t.Org.Guid, t.Guid),
 		Name:    t.Name,
 		Members: members,
 	}
+	if t.Parent != "" {
+		group.Parent = fmt.Sprintf("%s:%s", t.Org.Guid, t.Parent)
+	}
+	return group
 }
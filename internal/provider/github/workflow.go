@@ -0,0 +1,58 @@
+package github
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+type workflowFile struct {
+	Jobs yaml.Node `yaml:"jobs"`
+}
+
+type workflowFileJob struct {
+	Name  string    `yaml:"name"`
+	Needs yaml.Node `yaml:"needs"`
+}
+
+// parseWorkflowJobs reads the jobs of a workflow file in the order they are defined in.
+func parseWorkflowJobs(content []byte) ([]WorkflowJob, error) {
+	f := workflowFile{}
+	err := yaml.Unmarshal(content, &f)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []WorkflowJob
+	for i := 0; i+1 < len(f.Jobs.Content); i += 2 {
+		key := f.Jobs.Content[i].Value
+
+		j := workflowFileJob{}
+		err := f.Jobs.Content[i+1].Decode(&j)
+		if err != nil {
+			return nil, err
+		}
+
+		// needs can either be a single job or a list of jobs
+		var needs []string
+		switch j.Needs.Kind {
+		case yaml.ScalarNode:
+			needs = []string{j.Needs.Value}
+		case yaml.SequenceNode:
+			err = j.Needs.Decode(&needs)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		name := j.Name
+		if name == "" {
+			name = key
+		}
+
+		res = append(res, WorkflowJob{
+			Key:   key,
+			Name:  name,
+			Needs: needs,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,526 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const ClusterGuid = "main"
+
+// dependenciesAnnotation lists the names of the services a workload calls, separated by commas.
+const dependenciesAnnotation = "dyve.io/dependencies"
+
+var httpRouteResource = schema.GroupVersionResource{
+	Group:    "gateway.networking.k8s.io",
+	Version:  "v1beta1",
+	Resource: "httproutes",
+}
+
+// Login configures how to connect to the cluster. Without a kubeconfig the in-cluster
+// service account is used.
+type Login struct {
+	KubeConfig string `yaml:"kubeConfig"`
+	Context    string `yaml:"context"`
+}
+
+// API is an abstraction around the Kubernetes functionality. The context is passed on
+// to every request against the cluster.
+type API interface {
+	ListNamespaces(ctx context.Context) ([]Namespace, error)
+	ListApps(ctx context.Context, namespace string) ([]App, error)
+	GetRoutes(ctx context.Context, app AppInfo) (Routes, error)
+	GetInstances(ctx context.Context, app AppInfo) (Instances, error)
+	ListServices(ctx context.Context, namespace string) ([]Service, error)
+}
+
+func NewDefaultApi(l Login) (API, error) {
+	var config *rest.Config
+	var err error
+	if l.KubeConfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: l.KubeConfig},
+			&clientcmd.ConfigOverrides{CurrentContext: l.Context},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dyn, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewApi(cli, dyn), nil
+}
+
+// NewApi creates an API from the given clients. The dynamic client is used to retrieve
+// HTTPRoutes of the Gateway API and may be nil, in which case only Ingresses are used for routing.
+func NewApi(cli kubernetes.Interface, dyn dynamic.Interface) API {
+	return &api{
+		cli: cli,
+		dyn: dyn,
+	}
+}
+
+type api struct {
+	cli kubernetes.Interface
+	dyn dynamic.Interface
+}
+
+func (a *api) ListNamespaces(ctx context.Context) ([]Namespace, error) {
+	namespaces, err := a.cli.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Namespace
+	for _, namespace := range namespaces.Items {
+		res = append(res, Namespace{
+			NamespaceInfo: NamespaceInfo{
+				Guid: namespace.Name,
+				Name: namespace.Name,
+				Cluster: ClusterInfo{
+					Guid: ClusterGuid,
+				},
+			},
+		})
+	}
+
+	return res, nil
+}
+
+func (a *api) ListApps(ctx context.Context, namespace string) ([]App, error) {
+	_, err := a.cli.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil, errNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	deployments, err := a.cli.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := a.cli.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []App
+	for _, d := range deployments.Items {
+		apps = append(apps, toApp("Deployment", d.ObjectMeta, d.Spec.Selector, d.Spec.Template))
+	}
+	for _, s := range statefulSets.Items {
+		apps = append(apps, toApp("StatefulSet", s.ObjectMeta, s.Spec.Selector, s.Spec.Template))
+	}
+
+	return apps, nil
+}
+
+func toApp(kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, template corev1.PodTemplateSpec) App {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		s = labels.Nothing()
+	}
+
+	var dependencies []string
+	for _, name := range strings.Split(meta.Annotations[dependenciesAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			dependencies = append(dependencies, name)
+		}
+	}
+
+	return App{
+		AppInfo: AppInfo{
+			Guid:         string(meta.UID),
+			Name:         meta.Name,
+			Kind:         kind,
+			Labels:       meta.Labels,
+			PodLabels:    template.Labels,
+			Selector:     s.String(),
+			Dependencies: dependencies,
+			Namespace: NamespaceInfo{
+				Guid: meta.Namespace,
+				Name: meta.Namespace,
+			},
+		},
+	}
+}
+
+func (a *api) ListServices(ctx context.Context, namespace string) ([]Service, error) {
+	services, err := a.cli.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Service
+	for _, service := range services.Items {
+		res = append(res, Service{
+			Guid:      string(service.UID),
+			Name:      service.Name,
+			Namespace: service.Namespace,
+			Type:      string(service.Spec.Type),
+			Selector:  service.Spec.Selector,
+		})
+	}
+	return res, nil
+}
+
+func (a *api) GetInstances(ctx context.Context, app AppInfo) (Instances, error) {
+	pods, err := a.cli.CoreV1().Pods(app.Namespace.Name).List(ctx, metav1.ListOptions{
+		LabelSelector: app.Selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the API doesn't guarantee an order, but the position of a pod serves as its index
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	var res Instances
+	for _, pod := range pods.Items {
+		res = append(res, toInstance(pod))
+	}
+	return res, nil
+}
+
+func toInstance(pod corev1.Pod) Instance {
+	i := Instance{
+		Phase: string(pod.Status.Phase),
+		Since: pod.CreationTimestamp.Time,
+		Node:  pod.Spec.NodeName,
+	}
+	if pod.Status.StartTime != nil {
+		i.Since = pod.Status.StartTime.Time
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			i.Ready = condition.Status == corev1.ConditionTrue
+			if !condition.LastTransitionTime.IsZero() {
+				i.Since = condition.LastTransitionTime.Time
+			}
+		}
+	}
+
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.State.Waiting != nil && i.Waiting == "" {
+			i.Waiting = container.State.Waiting.Reason
+		}
+		i.Restarts += int(container.RestartCount)
+		if t := container.LastTerminationState.Terminated; t != nil && t.Reason != "" {
+			i.LastCrash = t.Reason
+		}
+	}
+
+	return i
+}
+
+// GetRoutes collects all Ingress and HTTPRoute rules that point to a service selecting
+// the pods of the app.
+func (a *api) GetRoutes(ctx context.Context, app AppInfo) (Routes, error) {
+	services, err := a.appServices(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	res, err := a.ingressRoutes(ctx, app.Namespace.Name, services)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRoutes, err := a.httpRoutes(ctx, app.Namespace.Name, services)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, httpRoutes...), nil
+}
+
+func (a *api) appServices(ctx context.Context, app AppInfo) (map[string]corev1.Service, error) {
+	services, err := a.cli.CoreV1().Services(app.Namespace.Name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]corev1.Service)
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(app.PodLabels)) {
+			res[service.Name] = service
+		}
+	}
+	return res, nil
+}
+
+func (a *api) ingressRoutes(ctx context.Context, namespace string, services map[string]corev1.Service) (Routes, error) {
+	ingresses, err := a.cli.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res Routes
+	for _, ingress := range ingresses.Items {
+		var certs []ingressCert
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				port, ok := backendPort(services, path.Backend)
+				if !ok {
+					continue
+				}
+				if certs == nil {
+					certs = a.ingressCerts(ctx, namespace, ingress.Spec.TLS)
+				}
+
+				route := Route{
+					Host: rule.Host,
+					Path: path.Path,
+					Port: port,
+				}
+				if cert, ok := matchCert(certs, rule.Host); ok {
+					route.Tls = true
+					route.TlsExpires = cert.expires
+				}
+				res = append(res, route)
+			}
+		}
+
+		if ingress.Spec.DefaultBackend != nil {
+			if port, ok := backendPort(services, *ingress.Spec.DefaultBackend); ok {
+				res = append(res, Route{Path: "/", Port: port})
+			}
+		}
+	}
+	return res, nil
+}
+
+type ingressCert struct {
+	hosts   []string
+	expires *time.Time
+}
+
+// ingressCerts reads when the certificates of the ingress expire. The expiry stays unknown if
+// the secret can't be read, e.g. because the provider isn't allowed to.
+func (a *api) ingressCerts(ctx context.Context, namespace string, tls []networkingv1.IngressTLS) []ingressCert {
+	res := make([]ingressCert, 0, len(tls))
+	for _, t := range tls {
+		cert := ingressCert{hosts: t.Hosts}
+		if t.SecretName != "" {
+			secret, err := a.cli.CoreV1().Secrets(namespace).Get(ctx, t.SecretName, metav1.GetOptions{})
+			if err == nil {
+				cert.expires = certificateExpiry(secret.Data[corev1.TLSCertKey])
+			}
+		}
+		res = append(res, cert)
+	}
+	return res
+}
+
+// certificateExpiry returns when the first certificate of the PEM encoded chain expires.
+func certificateExpiry(data []byte) *time.Time {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return &cert.NotAfter
+}
+
+// matchCert finds the certificate for the host. Hosts of certificates may start with a
+// wildcard for a single label, as in '*.example.com'.
+func matchCert(certs []ingressCert, host string) (ingressCert, bool) {
+	for _, cert := range certs {
+		for _, h := range cert.hosts {
+			if h == host {
+				return cert, true
+			}
+			if strings.HasPrefix(h, "*.") {
+				i := strings.Index(host, ".")
+				if i > 0 && host[i:] == h[1:] {
+					return cert, true
+				}
+			}
+		}
+	}
+	return ingressCert{}, false
+}
+
+func backendPort(services map[string]corev1.Service, backend networkingv1.IngressBackend) (int, bool) {
+	if backend.Service == nil {
+		return 0, false
+	}
+	service, ok := services[backend.Service.Name]
+	if !ok {
+		return 0, false
+	}
+	return targetPort(service, backend.Service.Port.Name, int(backend.Service.Port.Number))
+}
+
+// targetPort resolves the port of a service, given either by name or by number, to the
+// port the app's container listens on.
+func targetPort(service corev1.Service, name string, number int) (int, bool) {
+	for _, port := range service.Spec.Ports {
+		if (name != "" && port.Name == name) || (name == "" && int(port.Port) == number) {
+			if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+				return int(port.TargetPort.IntVal), true
+			}
+			return int(port.Port), true
+		}
+	}
+	return 0, false
+}
+
+func (a *api) httpRoutes(ctx context.Context, namespace string, services map[string]corev1.Service) (Routes, error) {
+	if a.dyn == nil {
+		return nil, nil
+	}
+
+	list, err := a.dyn.Resource(httpRouteResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if k8sErrors.IsNotFound(err) {
+		// the gateway api is not installed in the cluster
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var res Routes
+	for _, item := range list.Items {
+		hostnames, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "hostnames")
+		if len(hostnames) == 0 {
+			hostnames = []string{""}
+		}
+
+		rules, _, _ := unstructured.NestedSlice(item.Object, "spec", "rules")
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			backends := httpRouteBackends(rule, services)
+			if len(backends) == 0 {
+				continue
+			}
+
+			for _, path := range httpRoutePaths(rule) {
+				for _, host := range hostnames {
+					for _, backend := range backends {
+						res = append(res, Route{Host: host, Path: path, Port: backend.port, Weight: backend.weight})
+					}
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+type httpRouteBackend struct {
+	port   int
+	weight int
+}
+
+// httpRouteBackends returns the backends of the rule that belong to the app. If the rule
+// splits its traffic between several backends, each gets the percentage of its weight.
+func httpRouteBackends(rule map[string]interface{}, services map[string]corev1.Service) []httpRouteBackend {
+	refs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+
+	var res []httpRouteBackend
+	var total, backends int64
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		weight, found, _ := unstructured.NestedInt64(ref, "weight")
+		if !found {
+			weight = 1
+		}
+		total += weight
+		backends++
+
+		if kind, found, _ := unstructured.NestedString(ref, "kind"); found && kind != "Service" {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(ref, "name")
+		service, ok := services[name]
+		if !ok {
+			continue
+		}
+
+		number, _, _ := unstructured.NestedInt64(ref, "port")
+		if port, ok := targetPort(service, "", int(number)); ok {
+			res = append(res, httpRouteBackend{port: port, weight: int(weight)})
+		}
+	}
+
+	for i := range res {
+		if backends > 1 && total > 0 {
+			res[i].weight = int(int64(res[i].weight) * 100 / total)
+		} else {
+			res[i].weight = 0
+		}
+	}
+	return res
+}
+
+func httpRoutePaths(rule map[string]interface{}) []string {
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+
+	var res []string
+	for _, m := range matches {
+		match, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, found, _ := unstructured.NestedString(match, "path", "value")
+		if !found {
+			path = "/"
+		}
+		res = append(res, path)
+	}
+	if len(res) == 0 {
+		res = []string{"/"}
+	}
+	return res
+}
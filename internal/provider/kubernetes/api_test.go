@@ -0,0 +1,299 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+var webLabels = map[string]string{"app": "web"}
+
+func newTestApi(objects ...runtime.Object) API {
+	cli := fake.NewSimpleClientset(append([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	}, objects...)...)
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		httpRouteResource: "HTTPRouteList",
+	}, httpRoute("default", "web-route", "web-svc", 80))
+
+	return NewApi(cli, dyn)
+}
+
+func TestApiListNamespaces(t *testing.T) {
+	namespaces, err := newTestApi().ListNamespaces(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []Namespace{
+		{NamespaceInfo: NamespaceInfo{Guid: "default", Name: "default", Cluster: ClusterInfo{Guid: ClusterGuid}}},
+		{NamespaceInfo: NamespaceInfo{Guid: "other", Name: "other", Cluster: ClusterInfo{Guid: ClusterGuid}}},
+	}
+	if !cmp.Equal(expected, namespaces) {
+		t.Errorf("namespaces mismatch: \n%s\n", cmp.Diff(expected, namespaces))
+	}
+}
+
+func TestApiListApps(t *testing.T) {
+	a := newTestApi(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", Labels: map[string]string{"team": "a"}, Annotations: map[string]string{
+				dependenciesAnnotation: "db-svc, payments-svc,",
+			}},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: webLabels},
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: webLabels}},
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "db-uid"},
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+		},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other", UID: "other-uid"}},
+	)
+
+	apps, err := a.ListApps(context.Background(), "default")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []App{
+		{AppInfo: AppInfo{Guid: "web-uid", Name: "web", Kind: "Deployment", Labels: map[string]string{"team": "a"}, PodLabels: webLabels, Selector: "app=web", Dependencies: []string{"db-svc", "payments-svc"}, Namespace: NamespaceInfo{Guid: "default", Name: "default"}}},
+		{AppInfo: AppInfo{Guid: "db-uid", Name: "db", Kind: "StatefulSet", Selector: "app=db", Namespace: NamespaceInfo{Guid: "default", Name: "default"}}},
+	}
+	if !cmp.Equal(expected, apps) {
+		t.Errorf("apps mismatch: \n%s\n", cmp.Diff(expected, apps))
+	}
+
+	_, err = a.ListApps(context.Background(), "not-exist")
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestApiListServices(t *testing.T) {
+	a := newTestApi(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-svc", Namespace: "default", UID: "db-svc-uid"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Selector: map[string]string{"app": "db"}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments-svc", Namespace: "default", UID: "payments-svc-uid"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "payments.example.com"},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "other-svc", Namespace: "other", UID: "other-svc-uid"}},
+	)
+
+	services, err := a.ListServices(context.Background(), "default")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := []Service{
+		{Guid: "db-svc-uid", Name: "db-svc", Namespace: "default", Type: "ClusterIP", Selector: map[string]string{"app": "db"}},
+		{Guid: "payments-svc-uid", Name: "payments-svc", Namespace: "default", Type: "ExternalName"},
+	}
+	if !cmp.Equal(expected, services) {
+		t.Errorf("services mismatch: \n%s\n", cmp.Diff(expected, services))
+	}
+}
+
+func TestApiGetInstances(t *testing.T) {
+	pod := func(name string, labels map[string]string, phase corev1.PodPhase, ready corev1.ConditionStatus, waiting string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase:     phase,
+				StartTime: &metav1.Time{Time: someTime},
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: ready},
+				},
+			},
+		}
+		if waiting != "" {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waiting}}, RestartCount: 4,
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}}},
+			}
+		}
+		return p
+	}
+
+	a := newTestApi(
+		pod("web-1", webLabels, corev1.PodRunning, corev1.ConditionTrue, ""),
+		pod("web-2", webLabels, corev1.PodRunning, corev1.ConditionFalse, "CrashLoopBackOff"),
+		pod("db-1", map[string]string{"app": "db"}, corev1.PodRunning, corev1.ConditionTrue, ""),
+	)
+
+	instances, err := a.GetInstances(context.Background(), AppInfo{Selector: "app=web", Namespace: NamespaceInfo{Name: "default"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := Instances{
+		{Phase: "Running", Ready: true, Since: someTime},
+		{Phase: "Running", Ready: false, Waiting: "CrashLoopBackOff", Since: someTime, Restarts: 4, LastCrash: "OOMKilled"},
+	}
+	if !cmp.Equal(expected, instances) {
+		t.Errorf("instances mismatch: \n%s\n", cmp.Diff(expected, instances))
+	}
+}
+
+func TestApiGetInstancesOrderedByName(t *testing.T) {
+	cli := fake.NewSimpleClientset()
+	cli.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &corev1.PodList{Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-c", Labels: webLabels}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-a", Labels: webLabels}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "web-b", Labels: webLabels}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		}}, nil
+	})
+
+	instances, err := NewApi(cli, nil).GetInstances(context.Background(), AppInfo{Selector: "app=web", Namespace: NamespaceInfo{Name: "default"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := Instances{{Phase: "Running"}, {Phase: "Pending"}, {Phase: "Failed"}}
+	if !cmp.Equal(expected, instances) {
+		t.Errorf("instances mismatch: \n%s\n", cmp.Diff(expected, instances))
+	}
+}
+
+func TestApiGetRoutes(t *testing.T) {
+	pathType := networkingv1.PathTypePrefix
+	a := newTestApi(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-svc", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: webLabels,
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-svc", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "db"},
+				Ports:    []corev1.ServicePort{{Port: 5432}},
+			},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "web-tls"}},
+				Rules: []networkingv1.IngressRule{{
+					Host: "web.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/", PathType: &pathType, Backend: ingressBackend("web-svc", "http", 0)},
+							{Path: "/db", PathType: &pathType, Backend: ingressBackend("db-svc", "", 5432)},
+						},
+					}},
+				}},
+			},
+		},
+		tlsSecret(t, "default", "web-tls", someTime),
+	)
+
+	routes, err := a.GetRoutes(context.Background(), AppInfo{PodLabels: map[string]string{"app": "web", "version": "1"}, Namespace: NamespaceInfo{Name: "default"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := Routes{
+		{Host: "web.example.com", Path: "/", Port: 8080, Tls: true, TlsExpires: &someTime},
+		{Host: "route.example.com", Path: "/api", Port: 8080, Weight: 90},
+	}
+	if !cmp.Equal(expected, routes) {
+		t.Errorf("routes mismatch: \n%s\n", cmp.Diff(expected, routes))
+	}
+
+	routes, err = a.GetRoutes(context.Background(), AppInfo{PodLabels: map[string]string{"app": "unknown"}, Namespace: NamespaceInfo{Name: "default"}})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(Routes(nil), routes, cmpopts.EquateEmpty()) {
+		t.Errorf("expected no routes, got %v", routes)
+	}
+}
+
+func ingressBackend(service string, portName string, port int32) networkingv1.IngressBackend {
+	return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+		Name: service,
+		Port: networkingv1.ServiceBackendPort{Name: portName, Number: port},
+	}}
+}
+
+func httpRoute(namespace string, name string, service string, port int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"route.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": service, "port": port, "weight": int64(9)},
+						map[string]interface{}{"name": service + "-canary", "port": port, "weight": int64(1)},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func tlsSecret(t *testing.T, namespace string, name string, expires time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: expires.Add(-24 * time.Hour), NotAfter: expires}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package kubernetes
+
+import (
+	"github.com/joscha-alisch/dyve/internal/reconciliation"
+	"time"
+)
+
+type Database interface {
+	AcceptReconcileJob(olderThan time.Duration) (reconciliation.Job, bool)
+
+	UpsertNamespaces(clusterGuid string, namespaces []Namespace) error
+	UpsertNamespaceApps(namespaceGuid string, apps []App) error
+
+	DeleteNamespace(guid string) (bool, error)
+
+	ListApps() ([]App, error)
+	// ListAppUpdates returns the apps that were created or changed since the given time and the
+	// tombstones of the apps deleted since then.
+	ListAppUpdates(since time.Time) ([]App, []Tombstone, error)
+	GetApp(id string) (App, error)
+
+	Cached(id string, duration time.Duration, cached interface{}, f func() (interface{}, error)) (interface{}, error)
+}
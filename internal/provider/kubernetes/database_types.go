@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"strings"
+	"time"
+)
+
+type ClusterInfo struct {
+	Guid string
+}
+
+type Cluster struct {
+	ClusterInfo `bson:",inline"`
+	Namespaces  []string
+	LastUpdated time.Time `bson:"lastUpdated"`
+}
+
+type NamespaceInfo struct {
+	Guid    string
+	Name    string
+	Cluster ClusterInfo
+}
+
+type Namespace struct {
+	NamespaceInfo `bson:",inline"`
+	Apps          []string
+	LastUpdated   time.Time `bson:"lastUpdated"`
+}
+
+// AppInfo describes a workload, i.e. a Deployment or a StatefulSet. Selector is the label
+// selector of its pods and PodLabels are the labels of its pod template, which services select.
+// Dependencies are the names of the services in the namespace the app calls.
+type AppInfo struct {
+	Guid         string
+	Name         string
+	Kind         string
+	Labels       map[string]string
+	PodLabels    map[string]string `bson:"podLabels"`
+	Selector     string
+	Dependencies []string `bson:"dependencies"`
+	Namespace    NamespaceInfo
+}
+
+type App struct {
+	AppInfo `bson:",inline"`
+	// Changed is when the app was created or last changed.
+	Changed time.Time `bson:"changed"`
+}
+
+// Tombstone marks a deleted app, so that it can be reported with the app updates.
+type Tombstone struct {
+	Guid    string
+	Deleted time.Time `bson:"deleted"`
+}
+
+type Routes []Route
+type Route struct {
+	Host string `bson:"host"`
+	Path string `bson:"path"`
+	Port int    `bson:"port"`
+	// Weight is the percentage of the rule's traffic the app receives if it is split between backends.
+	Weight int  `bson:"weight"`
+	Tls    bool `bson:"tls"`
+	// TlsExpires is when the certificate of the route expires, nil if it can't be read.
+	TlsExpires *time.Time `bson:"tlsExpires"`
+}
+
+// Service is a Kubernetes service. Services without a selector, e.g. of type ExternalName,
+// point to something outside the cluster.
+type Service struct {
+	Guid      string
+	Name      string
+	Namespace string
+	Type      string
+	Selector  map[string]string
+}
+
+// Instances are the pods of an app, ordered by their name.
+type Instances []Instance
+type Instance struct {
+	Phase   string
+	Ready   bool
+	Waiting string
+	Since   time.Time
+	Node    string
+	// Restarts sums up the restarts of the containers of the pod.
+	Restarts int
+	// LastCrash is the reason the last terminated container of the pod exited with.
+	LastCrash string
+}
+
+func (a App) toSdkApp() sdk.App {
+	app := sdk.App{
+		Id:       a.Guid,
+		Name:     a.Name,
+		Labels:   sdk.AppLabels{},
+		Position: sdk.AppPosition{},
+	}
+
+	for k, v := range a.Labels {
+		app.Labels[k] = v
+	}
+
+	if a.Kind != "" {
+		app.Labels["kind"] = strings.ToLower(a.Kind)
+	}
+
+	if a.Namespace.Name != "" {
+		app.Labels["namespace"] = a.Namespace.Name
+		app.Position = append(app.Position, a.Namespace.Name)
+	}
+
+	if partOf := a.Labels["app.kubernetes.io/part-of"]; partOf != "" {
+		app.Position = append(app.Position, partOf)
+	}
+
+	if len(app.Labels) == 0 {
+		app.Labels = nil
+	}
+
+	if len(app.Position) == 0 {
+		app.Position = nil
+	}
+
+	return app
+}
+
+func (r Route) toSdkRoute() sdk.AppRoute {
+	route := sdk.AppRoute{
+		Host:     r.Host,
+		Path:     r.Path,
+		AppPort:  r.Port,
+		Protocol: sdk.RouteProtocolHttp,
+		Weight:   r.Weight,
+	}
+	if r.Tls {
+		route.Tls = &sdk.RouteTls{Enabled: true, Expires: r.TlsExpires}
+	}
+	return route
+}
@@ -0,0 +1,404 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type MongoLogin struct {
+	Uri string
+	DB  string
+}
+
+func NewMongoDatabase(l MongoLogin) (Database, error) {
+	ctx := context.Background()
+	c, err := mongo.Connect(
+		ctx,
+		options.Client().ApplyURI(l.Uri),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Ping(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	db := c.Database(l.DB)
+
+	m := &mongoDatabase{
+		ctx:        ctx,
+		cli:        c,
+		db:         db,
+		clusters:   db.Collection("clusters"),
+		namespaces: db.Collection("namespaces"),
+		apps:       db.Collection("apps"),
+		tombstones: db.Collection("tombstones"),
+		cache:      db.Collection("cache"),
+	}
+
+	err = m.setupBaseJob()
+	if err != nil {
+		return nil, err
+	}
+
+	// the core lists all apps instead of asking for older updates, so their tombstones can go
+	_, err = m.tombstones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "deleted", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(sdk.MaxUpdatesAge.Seconds())),
+	})
+	return m, err
+}
+
+type mongoDatabase struct {
+	cli        *mongo.Client
+	db         *mongo.Database
+	clusters   *mongo.Collection
+	namespaces *mongo.Collection
+	apps       *mongo.Collection
+	tombstones *mongo.Collection
+	cache      *mongo.Collection
+	ctx        context.Context
+}
+
+var currentTime = time.Now
+
+func (d *mongoDatabase) Cached(id string, duration time.Duration, cached interface{}, f func() (interface{}, error)) (interface{}, error) {
+	cacheTime := currentTime().Add(-duration)
+	cacheRes := d.cache.FindOne(d.ctx, bson.M{"id": id, "last": bson.M{"$gte": cacheTime}})
+
+	err := cacheRes.Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	} else if err == nil {
+		return nil, cacheRes.Decode(cached)
+	}
+
+	data, err := f()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = d.cache.UpdateOne(d.ctx, bson.M{"id": id}, bson.M{"$set": bson.M{"id": id, "last": currentTime(), "data": data}}, options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (d *mongoDatabase) GetApp(id string) (App, error) {
+	res := d.apps.FindOne(d.ctx, bson.M{
+		"guid": bson.M{
+			"$eq": id,
+		},
+	})
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return App{}, errNotFound
+	} else if res.Err() != nil {
+		return App{}, res.Err()
+	}
+
+	a := App{}
+	err := res.Decode(&a)
+	if err != nil {
+		return App{}, errDecode
+	}
+
+	return a, nil
+}
+
+func (d *mongoDatabase) ListApps() ([]App, error) {
+	return d.listApps(bson.M{})
+}
+
+func (d *mongoDatabase) ListAppUpdates(since time.Time) ([]App, []Tombstone, error) {
+	apps, err := d.listApps(bson.M{"changed": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := d.tombstones.Find(d.ctx, bson.M{"deleted": bson.M{"$gte": since}}, options.Find().SetSort(bson.M{"guid": 1}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tombstones []Tombstone
+	for c.Next(d.ctx) {
+		tombstone := Tombstone{}
+		err = c.Decode(&tombstone)
+		if err != nil {
+			return nil, nil, err
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	return apps, tombstones, nil
+}
+
+func (d *mongoDatabase) listApps(filter bson.M) ([]App, error) {
+	c, err := d.apps.Find(d.ctx, filter, options.Find().SetSort(bson.M{"guid": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []App
+	for c.Next(d.ctx) {
+		app := App{}
+		err = c.Decode(&app)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+func (d *mongoDatabase) UpsertNamespaces(clusterGuid string, namespaces []Namespace) error {
+	var namespaceGuids []string
+	for i, namespace := range namespaces {
+		namespaceGuids = append(namespaceGuids, namespace.Guid)
+		namespaces[i].Cluster = ClusterInfo{Guid: clusterGuid}
+	}
+
+	res, err := d.clusters.UpdateOne(d.ctx, bson.M{
+		"guid": clusterGuid,
+	}, bson.M{
+		"$set": bson.M{
+			"namespaces":  namespaceGuids,
+			"lastUpdated": currentTime(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errNotFound
+	}
+
+	for _, n := range namespaces {
+		_, err := d.namespaces.UpdateOne(d.ctx, bson.M{
+			"guid": n.Guid,
+		}, bson.M{
+			"$set": n.NamespaceInfo,
+		}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = d.namespaces.DeleteMany(d.ctx, outdatedIn("cluster.guid", clusterGuid, "guid", namespaceGuids))
+	if err != nil {
+		return err
+	}
+
+	_, err = d.deleteApps(outdatedIn("namespace.cluster.guid", clusterGuid, "namespace.guid", namespaceGuids))
+	return err
+}
+
+func (d *mongoDatabase) UpsertNamespaceApps(namespaceGuid string, apps []App) error {
+	namespace, err := d.getNamespace(namespaceGuid)
+	if err != nil {
+		return err
+	}
+
+	var appGuids []string
+	for i, app := range apps {
+		appGuids = append(appGuids, app.Guid)
+		apps[i].Namespace = namespace.NamespaceInfo
+	}
+
+	_, err = d.namespaces.UpdateOne(d.ctx, bson.M{
+		"guid": namespaceGuid,
+	}, bson.M{
+		"$set": bson.M{
+			"apps":        appGuids,
+			"lastUpdated": currentTime(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = d.deleteApps(outdatedIn("namespace.guid", namespaceGuid, "guid", appGuids))
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		res, err := d.apps.UpdateOne(d.ctx, bson.M{
+			"guid": app.Guid,
+		}, bson.M{
+			"$set": app.AppInfo,
+		}, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+
+		// mongodb doesn't modify apps that are set to what they already are
+		if res.ModifiedCount == 0 && res.UpsertedCount == 0 {
+			continue
+		}
+		err = d.markChanged(app.Guid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markChanged reports the app with the next app updates. Apps that are created again
+// aren't deleted anymore, so their tombstone is removed.
+func (d *mongoDatabase) markChanged(guid string) error {
+	_, err := d.apps.UpdateOne(d.ctx, bson.M{
+		"guid": guid,
+	}, bson.M{
+		"$set": bson.M{"changed": currentTime()},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = d.tombstones.DeleteMany(d.ctx, bson.M{"guid": guid})
+	return err
+}
+
+// deleteApps deletes the matching apps and leaves tombstones for them.
+func (d *mongoDatabase) deleteApps(filter bson.M) (bool, error) {
+	c, err := d.apps.Find(d.ctx, filter, options.Find().SetProjection(bson.M{"guid": 1}))
+	if err != nil {
+		return false, err
+	}
+
+	var guids []string
+	for c.Next(d.ctx) {
+		app := App{}
+		err = c.Decode(&app)
+		if err != nil {
+			return false, err
+		}
+		guids = append(guids, app.Guid)
+	}
+	if len(guids) == 0 {
+		return false, nil
+	}
+
+	_, err = d.apps.DeleteMany(d.ctx, bson.M{"guid": bson.M{"$in": guids}})
+	if err != nil {
+		return false, err
+	}
+
+	t := currentTime()
+	for _, guid := range guids {
+		_, err = d.tombstones.UpdateOne(d.ctx, bson.M{
+			"guid": guid,
+		}, bson.M{
+			"$set": Tombstone{Guid: guid, Deleted: t},
+		}, options.Update().SetUpsert(true))
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func (d *mongoDatabase) DeleteNamespace(guid string) (bool, error) {
+	res, err := d.namespaces.DeleteMany(d.ctx, bson.M{"guid": guid})
+	if err != nil {
+		return false, err
+	}
+
+	deletedApps, err := d.deleteApps(bson.M{"namespace.guid": guid})
+	if err != nil {
+		return res.DeletedCount > 0, err
+	}
+
+	return res.DeletedCount > 0 || deletedApps, nil
+}
+
+func (d *mongoDatabase) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool) {
+	t := currentTime()
+
+	j, ok := d.acceptCollectionReconcileJob(ReconcileApps, d.namespaces, t, olderThan)
+	if ok {
+		return j, true
+	}
+
+	return d.acceptCollectionReconcileJob(ReconcileNamespaces, d.clusters, t, olderThan)
+}
+
+func (d *mongoDatabase) acceptCollectionReconcileJob(typ recon.Type, coll *mongo.Collection, t time.Time, olderThan time.Duration) (recon.Job, bool) {
+	lessThanTime := t.Add(-olderThan)
+	res := coll.FindOneAndUpdate(d.ctx, bson.M{
+		"$or": bson.A{
+			bson.M{
+				"lastUpdated": bson.M{"$lte": lessThanTime},
+			},
+			bson.M{"lastUpdated": nil},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"lastUpdated": t,
+		},
+	}, options.FindOneAndUpdate().SetSort(bson.D{{Key: "lastUpdated", Value: 1}}))
+
+	j := recon.Job{}
+	err := res.Decode(&j)
+	if err != nil {
+		return recon.Job{}, false
+	}
+
+	j.Type = typ
+
+	return j, true
+}
+
+func (d *mongoDatabase) setupBaseJob() error {
+	_, err := d.clusters.UpdateOne(d.ctx, bson.M{
+		"guid": ClusterGuid,
+	}, bson.M{
+		"$set": bson.M{
+			"guid": ClusterGuid,
+		},
+	}, options.Update().SetUpsert(true))
+
+	return err
+}
+
+func (d *mongoDatabase) getNamespace(guid string) (Namespace, error) {
+	res := d.namespaces.FindOne(d.ctx, bson.M{
+		"guid": guid,
+	})
+	if res.Err() != nil {
+		return Namespace{}, errNotFound
+	}
+
+	n := Namespace{}
+	err := res.Decode(&n)
+	if err != nil {
+		return Namespace{}, errDecode
+	}
+
+	return n, nil
+}
+
+// outdatedIn matches the items of the parent that aren't listed anymore.
+func outdatedIn(where, equals, and string, notIn []string) bson.M {
+	if notIn == nil {
+		notIn = []string{}
+	}
+
+	return bson.M{
+		where: bson.M{
+			"$eq": equals,
+		},
+		and: bson.M{
+			"$nin": notIn,
+		},
+	}
+}
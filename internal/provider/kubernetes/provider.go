@@ -0,0 +1,250 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"k8s.io/apimachinery/pkg/labels"
+	"time"
+)
+
+func NewProvider(db Database, k8s API) *Provider {
+	return &Provider{
+		db:  db,
+		k8s: k8s,
+	}
+}
+
+type Provider struct {
+	db  Database
+	k8s API
+}
+
+func (p *Provider) ListApps(ctx context.Context) ([]sdk.App, error) {
+	k8sApps, err := p.db.ListApps()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []sdk.App
+	for _, app := range k8sApps {
+		res = append(res, app.toSdkApp())
+	}
+	return res, nil
+}
+
+// ListAppsPage pages the apps in memory, as they are read from the database at once anyway.
+func (p *Provider) ListAppsPage(ctx context.Context, perPage int, page int) (sdk.AppPage, error) {
+	apps, err := p.ListApps(ctx)
+	if err != nil {
+		return sdk.AppPage{}, err
+	}
+	return sdk.PageApps(apps, perPage, page)
+}
+
+func (p *Provider) ListAppUpdates(ctx context.Context, since time.Time) (sdk.AppUpdates, error) {
+	k8sApps, tombstones, err := p.db.ListAppUpdates(since)
+	if err != nil {
+		return sdk.AppUpdates{}, err
+	}
+
+	res := sdk.AppUpdates{}
+	for _, app := range k8sApps {
+		res.Apps = append(res.Apps, app.toSdkApp())
+	}
+	for _, tombstone := range tombstones {
+		res.Deleted = append(res.Deleted, sdk.AppTombstone{Id: tombstone.Guid, Deleted: tombstone.Deleted})
+	}
+	return res, nil
+}
+
+func (p *Provider) GetApp(ctx context.Context, id string) (sdk.App, error) {
+	app, err := p.db.GetApp(id)
+	if errors.Is(err, errNotFound) {
+		return sdk.App{}, sdk.ErrNotFound
+	} else if err != nil {
+		return sdk.App{}, err
+	}
+
+	return app.toSdkApp(), nil
+}
+
+func (p *Provider) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
+	cached := sdk.AppRouting{}
+
+	res, err := p.db.Cached(id+"/routing", 5*time.Second, &cached, func() (interface{}, error) {
+		app, err := p.db.GetApp(id)
+		if err != nil {
+			return nil, err
+		}
+		routes, err := p.k8s.GetRoutes(ctx, app.AppInfo)
+		if err != nil {
+			return nil, err
+		}
+		appRouting := sdk.AppRouting{}
+		for _, route := range routes {
+			appRouting.Routes = append(appRouting.Routes, route.toSdkRoute())
+		}
+		return appRouting, nil
+	})
+	if err != nil {
+		return sdk.AppRouting{}, err
+	}
+	if res != nil {
+		return res.(sdk.AppRouting), nil
+	}
+
+	return cached, nil
+}
+
+func (p *Provider) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
+	cached := sdk.AppInstances{}
+	res, err := p.db.Cached(id+"/instances", 5*time.Second, &cached, func() (interface{}, error) {
+		app, err := p.db.GetApp(id)
+		if err != nil {
+			return nil, err
+		}
+		instances, err := p.k8s.GetInstances(ctx, app.AppInfo)
+		if err != nil {
+			return nil, err
+		}
+		appInstances := sdk.AppInstances{}
+		for index, instance := range instances {
+			appInstances = append(appInstances, sdk.AppInstance{
+				Index:     index,
+				State:     podStateToSdkState(instance),
+				Since:     instance.Since,
+				Host:      instance.Node,
+				Restarts:  instance.Restarts,
+				LastCrash: instance.LastCrash,
+			})
+		}
+		return appInstances, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		return res.(sdk.AppInstances), nil
+	}
+	return cached, nil
+}
+
+// GetTopology resolves the services the apps declare as their dependencies. Services selecting
+// the pods of other apps become dependencies between the apps, all others are backing services.
+func (p *Provider) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	cached := sdk.Topology{}
+	res, err := p.db.Cached("topology", 30*time.Second, &cached, func() (interface{}, error) {
+		apps, err := p.db.ListApps()
+		if err != nil {
+			return nil, err
+		}
+
+		namespaceApps := make(map[string][]App)
+		for _, app := range apps {
+			namespaceApps[app.Namespace.Name] = append(namespaceApps[app.Namespace.Name], app)
+		}
+
+		topology := sdk.Topology{}
+		backingServices := make(map[string]bool)
+		namespaceServices := make(map[string]map[string]Service)
+		for _, app := range apps {
+			if len(app.Dependencies) == 0 {
+				continue
+			}
+
+			services, ok := namespaceServices[app.Namespace.Name]
+			if !ok {
+				list, err := p.k8s.ListServices(ctx, app.Namespace.Name)
+				if err != nil {
+					return nil, err
+				}
+				services = make(map[string]Service, len(list))
+				for _, service := range list {
+					services[service.Name] = service
+				}
+				namespaceServices[app.Namespace.Name] = services
+			}
+
+			for _, name := range app.Dependencies {
+				service, ok := services[name]
+				if !ok {
+					continue
+				}
+
+				targets := selectedApps(service, namespaceApps[app.Namespace.Name])
+				for _, target := range targets {
+					topology.Dependencies = append(topology.Dependencies, sdk.Dependency{
+						AppId:    app.Guid,
+						Target:   sdk.DependencyTargetApp,
+						TargetId: target.Guid,
+						Name:     service.Name,
+					})
+				}
+				if len(targets) > 0 {
+					continue
+				}
+
+				if !backingServices[service.Guid] {
+					backingServices[service.Guid] = true
+					topology.Services = append(topology.Services, sdk.BackingService{
+						Id:   service.Guid,
+						Name: service.Name,
+						Kind: service.Type,
+					})
+				}
+				topology.Dependencies = append(topology.Dependencies, sdk.Dependency{
+					AppId:    app.Guid,
+					Target:   sdk.DependencyTargetService,
+					TargetId: service.Guid,
+				})
+			}
+		}
+		return topology, nil
+	})
+	if err != nil {
+		return sdk.Topology{}, err
+	}
+	if res != nil {
+		return res.(sdk.Topology), nil
+	}
+	return cached, nil
+}
+
+func selectedApps(service Service, apps []App) []App {
+	if len(service.Selector) == 0 {
+		return nil
+	}
+
+	var res []App
+	selector := labels.SelectorFromSet(service.Selector)
+	for _, app := range apps {
+		if selector.Matches(labels.Set(app.PodLabels)) {
+			res = append(res, app)
+		}
+	}
+	return res
+}
+
+// podStateToSdkState derives the state of an instance from the phase of its pod. A running pod
+// is only considered running once all of its containers are ready.
+func podStateToSdkState(i Instance) sdk.AppState {
+	switch i.Phase {
+	case "Pending":
+		return sdk.AppStateStarting
+	case "Running":
+		if i.Waiting == "CrashLoopBackOff" {
+			return sdk.AppStateCrashed
+		}
+		if !i.Ready {
+			return sdk.AppStateStarting
+		}
+		return sdk.AppStateRunning
+	case "Succeeded":
+		return sdk.AppStateStopped
+	case "Failed":
+		return sdk.AppStateCrashed
+	default:
+		return sdk.AppStateUnknown
+	}
+}
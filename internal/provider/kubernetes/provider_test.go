@@ -0,0 +1,254 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk/sdktest"
+	"testing"
+	"time"
+)
+
+var someErr = errors.New("error")
+
+var someApps = map[string]*App{
+	"app-guid-a": {AppInfo: AppInfo{
+		Guid:      "app-guid-a",
+		Name:      "app-name-a",
+		Kind:      "Deployment",
+		Labels:    map[string]string{"app.kubernetes.io/part-of": "shop"},
+		Namespace: NamespaceInfo{Guid: "ns", Name: "ns"},
+	}},
+	"app-guid-b": {AppInfo: AppInfo{Guid: "app-guid-b", Name: "app-name-b"}},
+}
+
+func TestListApps(t *testing.T) {
+	tests := []struct {
+		desc        string
+		db          Database
+		expected    []sdk.App
+		expectedErr error
+	}{
+		{desc: "lists apps", db: &fakeDb{b: backend{Apps: someApps}}, expected: []sdk.App{
+			{Id: "app-guid-a", Name: "app-name-a", Labels: sdk.AppLabels{
+				"app.kubernetes.io/part-of": "shop",
+				"kind":                      "deployment",
+				"namespace":                 "ns",
+			}, Position: sdk.AppPosition{"ns", "shop"}},
+			{Id: "app-guid-b", Name: "app-name-b"},
+		}},
+		{desc: "returns error", db: &fakeDb{err: someErr}, expected: nil, expectedErr: someErr},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.db, nil)
+			apps, err := p.ListApps(context.Background())
+
+			if err != test.expectedErr {
+				tt.Errorf("\ndiff between errors: \n%s\n", cmp.Diff(test.expectedErr, err))
+			}
+
+			if !cmp.Equal(test.expected, apps) {
+				tt.Errorf("\ndiff between returned apps: \n%s\n", cmp.Diff(test.expected, apps))
+			}
+		})
+	}
+}
+
+func TestGetApp(t *testing.T) {
+	p := NewProvider(&fakeDb{b: backend{Apps: someApps}}, nil)
+
+	app, err := p.GetApp(context.Background(), "app-guid-b")
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(sdk.App{Id: "app-guid-b", Name: "app-name-b"}, app) {
+		t.Errorf("\ndiff between returned app: \n%s\n", cmp.Diff(sdk.App{Id: "app-guid-b", Name: "app-name-b"}, app))
+	}
+
+	_, err = p.GetApp(context.Background(), "not-exist")
+	if !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestListAppUpdates(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	db := &fakeDb{b: backend{
+		Apps: map[string]*App{
+			"app-guid-a": {AppInfo: AppInfo{Guid: "app-guid-a", Name: "app-name-a"}, Changed: since.Add(-time.Hour)},
+			"app-guid-b": {AppInfo: AppInfo{Guid: "app-guid-b", Name: "app-name-b"}, Changed: since.Add(time.Hour)},
+		},
+		Tombstones: []Tombstone{
+			{Guid: "app-guid-c", Deleted: since.Add(-time.Hour)},
+			{Guid: "app-guid-d", Deleted: since.Add(time.Minute)},
+		},
+	}}
+	p := NewProvider(db, nil)
+
+	updates, err := p.ListAppUpdates(context.Background(), since)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+
+	expected := sdk.AppUpdates{
+		Apps:    []sdk.App{{Id: "app-guid-b", Name: "app-name-b"}},
+		Deleted: []sdk.AppTombstone{{Id: "app-guid-d", Deleted: since.Add(time.Minute)}},
+	}
+	if !cmp.Equal(expected, updates) {
+		t.Errorf("\ndiff between returned updates: \n%s\n", cmp.Diff(expected, updates))
+	}
+
+	db.err = someErr
+	_, err = p.ListAppUpdates(context.Background(), since)
+	if !errors.Is(err, someErr) {
+		t.Errorf("expected error %v, got %v", someErr, err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	sdktest.RunConfig(t, sdk.ProviderConfig{
+		Name: "kubernetes",
+		Apps: NewProvider(&fakeDb{b: backend{Apps: someApps}}, nil),
+	})
+}
+
+func TestGetAppInstances(t *testing.T) {
+	tests := []struct {
+		desc        string
+		db          Database
+		k8s         API
+		id          string
+		expected    sdk.AppInstances
+		expectedErr error
+	}{
+		{desc: "gets app instances cached", db: &fakeDb{b: backend{Cache: map[string]interface{}{
+			"app-guid-a/instances": sdk.AppInstances{{State: "running", Since: someTime}},
+		}}}, id: "app-guid-a", expected: sdk.AppInstances{{State: "running", Since: someTime}}},
+		{desc: "returns error", db: &fakeDb{err: someErr}, expected: nil, expectedErr: someErr},
+		{desc: "returns error for unknown app", db: &fakeDb{}, id: "not-exist", expected: nil, expectedErr: errNotFound},
+		{desc: "gets app instances from kubernetes", db: &fakeDb{b: backend{Apps: someApps}}, k8s: &fakeK8s{b: backend{
+			AppInstances: map[string]Instances{
+				"app-guid-a": {
+					{Phase: "Running", Ready: true, Since: someTime},
+					{Phase: "Running", Ready: false, Since: someTime},
+					{Phase: "Running", Waiting: "CrashLoopBackOff", Since: someTime},
+					{Phase: "Pending", Since: someTime},
+					{Phase: "Succeeded", Since: someTime},
+					{Phase: "Failed", Since: someTime},
+					{Phase: "Unknown", Since: someTime},
+				},
+			}},
+		}, id: "app-guid-a", expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateRunning, Since: someTime},
+			{Index: 1, State: sdk.AppStateStarting, Since: someTime},
+			{Index: 2, State: sdk.AppStateCrashed, Since: someTime},
+			{Index: 3, State: sdk.AppStateStarting, Since: someTime},
+			{Index: 4, State: sdk.AppStateStopped, Since: someTime},
+			{Index: 5, State: sdk.AppStateCrashed, Since: someTime},
+			{Index: 6, State: sdk.AppStateUnknown, Since: someTime},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.db, test.k8s)
+			instances, err := p.GetAppInstances(context.Background(), test.id)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, instances) {
+				tt.Errorf("\ndiff between returned instances: \n%s\n", cmp.Diff(test.expected, instances))
+			}
+		})
+	}
+}
+
+func TestGetAppRouting(t *testing.T) {
+	tests := []struct {
+		desc        string
+		db          Database
+		k8s         API
+		id          string
+		expected    sdk.AppRouting
+		expectedErr error
+	}{
+		{desc: "gets app routing cached", db: &fakeDb{b: backend{Cache: map[string]interface{}{
+			"app-guid-a/routing": sdk.AppRouting{Routes: sdk.AppRoutes{{Host: "host", Path: "/", AppPort: 80}}},
+		}}}, id: "app-guid-a", expected: sdk.AppRouting{Routes: sdk.AppRoutes{{Host: "host", Path: "/", AppPort: 80}}}},
+		{desc: "returns error", db: &fakeDb{err: someErr}, expected: sdk.AppRouting{}, expectedErr: someErr},
+		{desc: "gets app routing from kubernetes", db: &fakeDb{b: backend{Apps: someApps}}, k8s: &fakeK8s{b: backend{
+			AppRoutes: map[string]Routes{
+				"app-guid-a": {
+					{Host: "host", Path: "/api", Port: 8080},
+					{Host: "secure", Path: "/", Port: 8443, Weight: 50, Tls: true, TlsExpires: &someTime},
+				},
+			}},
+		}, id: "app-guid-a", expected: sdk.AppRouting{Routes: sdk.AppRoutes{
+			{Host: "host", Path: "/api", AppPort: 8080, Protocol: sdk.RouteProtocolHttp},
+			{Host: "secure", Path: "/", AppPort: 8443, Protocol: sdk.RouteProtocolHttp, Weight: 50, Tls: &sdk.RouteTls{Enabled: true, Expires: &someTime}},
+		}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.db, test.k8s)
+			routing, err := p.GetAppRouting(context.Background(), test.id)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, routing) {
+				tt.Errorf("\ndiff between returned routing: \n%s\n", cmp.Diff(test.expected, routing))
+			}
+		})
+	}
+}
+
+func TestGetTopology(t *testing.T) {
+	namespace := NamespaceInfo{Guid: "ns", Name: "ns"}
+	apps := map[string]*App{
+		"web":    {AppInfo: AppInfo{Guid: "web", Namespace: namespace, Dependencies: []string{"api-svc", "db-svc", "unknown-svc"}}},
+		"api":    {AppInfo: AppInfo{Guid: "api", Namespace: namespace, PodLabels: map[string]string{"app": "api"}, Dependencies: []string{"db-svc"}}},
+		"worker": {AppInfo: AppInfo{Guid: "worker", Namespace: NamespaceInfo{Guid: "other", Name: "other"}, PodLabels: map[string]string{"app": "api"}}},
+	}
+	services := map[string][]Service{"ns": {
+		{Guid: "api-svc-uid", Name: "api-svc", Namespace: "ns", Type: "ClusterIP", Selector: map[string]string{"app": "api"}},
+		{Guid: "db-svc-uid", Name: "db-svc", Namespace: "ns", Type: "ExternalName"},
+	}}
+
+	tests := []struct {
+		desc        string
+		db          Database
+		k8s         API
+		expected    sdk.Topology
+		expectedErr error
+	}{
+		{desc: "gets topology cached", db: &fakeDb{b: backend{Cache: map[string]interface{}{
+			"topology": sdk.Topology{Services: []sdk.BackingService{{Id: "db-svc-uid", Name: "db-svc"}}},
+		}}}, expected: sdk.Topology{Services: []sdk.BackingService{{Id: "db-svc-uid", Name: "db-svc"}}}},
+		{desc: "returns error", db: &fakeDb{err: someErr}, expectedErr: someErr},
+		{desc: "resolves dependencies from kubernetes", db: &fakeDb{b: backend{Apps: apps}}, k8s: &fakeK8s{b: backend{Services: services}}, expected: sdk.Topology{
+			Services: []sdk.BackingService{{Id: "db-svc-uid", Name: "db-svc", Kind: "ExternalName"}},
+			Dependencies: []sdk.Dependency{
+				{AppId: "api", Target: sdk.DependencyTargetService, TargetId: "db-svc-uid"},
+				{AppId: "web", Target: sdk.DependencyTargetApp, TargetId: "api", Name: "api-svc"},
+				{AppId: "web", Target: sdk.DependencyTargetService, TargetId: "db-svc-uid"},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.db, test.k8s)
+			topology, err := p.GetTopology(context.Background())
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, topology) {
+				tt.Errorf("\ndiff between returned topology: \n%s\n", cmp.Diff(test.expected, topology))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"time"
+)
+
+const (
+	ReconcileNamespaces recon.Type = "namespaces"
+	ReconcileApps       recon.Type = "apps"
+)
+
+// NewReconciler fetches new reconciliation work from the database and updates the corresponding
+// item via the Kubernetes API.
+//
+// It returns true, if there was work to be done and false, if there was no open reconciliation work.
+func NewReconciler(db Database, k8s API, olderThan time.Duration) recon.Reconciler {
+	if olderThan == 0 {
+		olderThan = time.Minute
+	}
+
+	r := &reconciler{
+		Reconciler: recon.NewReconciler(db, olderThan),
+		db:         db,
+		k8s:        k8s,
+	}
+
+	r.Handler(ReconcileNamespaces, r.reconcileNamespaces)
+	r.Handler(ReconcileApps, r.reconcileApps)
+
+	return r
+}
+
+type reconciler struct {
+	recon.Reconciler
+
+	db  Database
+	k8s API
+}
+
+func (r *reconciler) reconcileNamespaces(j recon.Job) error {
+	namespaces, err := r.k8s.ListNamespaces(context.Background())
+	if err != nil {
+		return &errReconcileFailed{Err: err, Job: j}
+	}
+
+	_ = r.db.UpsertNamespaces(j.Guid, namespaces)
+	return nil
+}
+
+func (r *reconciler) reconcileApps(j recon.Job) error {
+	apps, err := r.k8s.ListApps(context.Background(), j.Guid)
+	if errors.Is(err, errNotFound) {
+		r.db.DeleteNamespace(j.Guid)
+		return nil
+	} else if err != nil {
+		return &errReconcileFailed{Err: err, Job: j}
+	}
+
+	_ = r.db.UpsertNamespaceApps(j.Guid, apps)
+	return nil
+}
@@ -0,0 +1,273 @@
+package kubernetes
+
+import (
+	"context"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	recon "github.com/joscha-alisch/dyve/internal/reconciliation"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestReconciler(t *testing.T) {
+	tests := []struct {
+		desc  string
+		db    fakeDb
+		k8s   fakeK8s
+		sleep bool
+		err   error
+	}{
+		{
+			desc: "updates namespaces",
+			db: fakeDb{
+				job: &recon.Job{Type: ReconcileNamespaces, Guid: ClusterGuid},
+			},
+			k8s: fakeK8s{b: backend{
+				Namespaces: map[string]*Namespace{
+					"ns-a": {NamespaceInfo: NamespaceInfo{Guid: "ns-a"}},
+					"ns-b": {NamespaceInfo: NamespaceInfo{Guid: "ns-b"}},
+				},
+			}},
+		},
+		{
+			desc: "updates namespace apps",
+			db: fakeDb{
+				job: &recon.Job{Type: ReconcileApps, Guid: "ns-a"},
+				b: backend{
+					Namespaces: map[string]*Namespace{"ns-a": {NamespaceInfo: NamespaceInfo{Guid: "ns-a"}}},
+				},
+			},
+			k8s: fakeK8s{b: backend{
+				Namespaces: map[string]*Namespace{"ns-a": {NamespaceInfo: NamespaceInfo{Guid: "ns-a"}}},
+				Apps: map[string]*App{
+					"app-a": {AppInfo: AppInfo{Guid: "app-a", Namespace: NamespaceInfo{Guid: "ns-a"}}},
+					"app-b": {AppInfo: AppInfo{Guid: "app-b", Namespace: NamespaceInfo{Guid: "ns-a"}}},
+				},
+			}},
+		},
+		{
+			desc: "no work to be done",
+			db: fakeDb{
+				job: nil,
+			},
+			k8s:   fakeK8s{},
+			sleep: true,
+		},
+		{
+			desc: "removes namespace when not found",
+			db: fakeDb{
+				job: &recon.Job{Type: ReconcileApps, Guid: "not-exist"},
+				b: backend{
+					Namespaces: map[string]*Namespace{
+						"not-exist": {NamespaceInfo: NamespaceInfo{Guid: "not-exist"}},
+					},
+				},
+			},
+			k8s: fakeK8s{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := NewReconciler(&test.db, &test.k8s, 1*time.Minute)
+			worked, err := r.Run()
+			if worked == test.sleep {
+				tt.Errorf("\nexpected return: %v, was: %v", !test.sleep, worked)
+			}
+
+			if !cmp.Equal(test.err, err, cmpopts.EquateErrors()) {
+				tt.Errorf("\nerr not as expected: \n%s", cmp.Diff(test.err, err, cmpopts.EquateErrors()))
+			}
+
+			if !cmp.Equal(test.k8s.b, test.db.b) {
+				tt.Errorf("\nkubernetes api and reconciled db differ: \n%s", cmp.Diff(test.k8s.b, test.db.b))
+			}
+		})
+	}
+}
+
+type fakeK8s struct {
+	b backend
+}
+
+func (f *fakeK8s) ListNamespaces(ctx context.Context) ([]Namespace, error) {
+	var res []Namespace
+	for _, namespace := range f.b.Namespaces {
+		res = append(res, *namespace)
+	}
+	return res, nil
+}
+
+func (f *fakeK8s) ListApps(ctx context.Context, namespace string) ([]App, error) {
+	if f.b.Namespaces[namespace] == nil {
+		return nil, errNotFound
+	}
+
+	var res []App
+	for _, app := range f.b.Apps {
+		if app.Namespace.Guid == namespace {
+			res = append(res, *app)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeK8s) GetRoutes(ctx context.Context, app AppInfo) (Routes, error) {
+	if f.b.AppRoutes[app.Guid] == nil {
+		return nil, errNotFound
+	}
+	return f.b.AppRoutes[app.Guid], nil
+}
+
+func (f *fakeK8s) GetInstances(ctx context.Context, app AppInfo) (Instances, error) {
+	if f.b.AppInstances[app.Guid] == nil {
+		return nil, errNotFound
+	}
+	return f.b.AppInstances[app.Guid], nil
+}
+
+func (f *fakeK8s) ListServices(ctx context.Context, namespace string) ([]Service, error) {
+	return f.b.Services[namespace], nil
+}
+
+type fakeDb struct {
+	job *recon.Job
+	b   backend
+	err error
+}
+
+func (f *fakeDb) AcceptReconcileJob(olderThan time.Duration) (recon.Job, bool) {
+	if f.job == nil {
+		return recon.Job{}, false
+	}
+	return *f.job, true
+}
+
+func (f *fakeDb) UpsertNamespaces(clusterGuid string, namespaces []Namespace) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if f.b.Namespaces == nil {
+		f.b.Namespaces = make(map[string]*Namespace)
+	}
+	for _, namespace := range namespaces {
+		namespace := namespace
+		f.b.Namespaces[namespace.Guid] = &namespace
+	}
+	return nil
+}
+
+func (f *fakeDb) UpsertNamespaceApps(namespaceGuid string, apps []App) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if f.b.Apps == nil {
+		f.b.Apps = make(map[string]*App)
+	}
+	for _, app := range apps {
+		app := app
+		f.b.Apps[app.Guid] = &app
+	}
+	return nil
+}
+
+func (f *fakeDb) DeleteNamespace(guid string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+
+	ok := f.b.Namespaces[guid] != nil
+	delete(f.b.Namespaces, guid)
+	if len(f.b.Namespaces) == 0 {
+		f.b.Namespaces = nil
+	}
+	return ok, nil
+}
+
+func (f *fakeDb) ListApps() ([]App, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var res []App
+	for _, app := range f.b.Apps {
+		res = append(res, *app)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Guid < res[j].Guid
+	})
+	return res, nil
+}
+
+func (f *fakeDb) ListAppUpdates(since time.Time) ([]App, []Tombstone, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	var apps []App
+	for _, app := range f.b.Apps {
+		if !app.Changed.Before(since) {
+			apps = append(apps, *app)
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Guid < apps[j].Guid
+	})
+
+	var tombstones []Tombstone
+	for _, tombstone := range f.b.Tombstones {
+		if !tombstone.Deleted.Before(since) {
+			tombstones = append(tombstones, tombstone)
+		}
+	}
+	return apps, tombstones, nil
+}
+
+func (f *fakeDb) GetApp(id string) (App, error) {
+	if f.err != nil {
+		return App{}, f.err
+	}
+
+	if f.b.Apps[id] == nil {
+		return App{}, errNotFound
+	}
+
+	return *f.b.Apps[id], nil
+}
+
+func (f *fakeDb) Cached(id string, duration time.Duration, res interface{}, fun func() (interface{}, error)) (interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	if f.b.Cache[id] != nil {
+		if res, ok := res.(*sdk.AppInstances); ok {
+			*res = f.b.Cache[id].(sdk.AppInstances)
+			return nil, nil
+		}
+		if res, ok := res.(*sdk.AppRouting); ok {
+			*res = f.b.Cache[id].(sdk.AppRouting)
+			return nil, nil
+		}
+		if res, ok := res.(*sdk.Topology); ok {
+			*res = f.b.Cache[id].(sdk.Topology)
+			return nil, nil
+		}
+	}
+	return fun()
+}
+
+type backend struct {
+	Namespaces   map[string]*Namespace
+	Apps         map[string]*App
+	Tombstones   []Tombstone
+	Cache        map[string]interface{}
+	AppInstances map[string]Instances
+	AppRoutes    map[string]Routes
+	Services     map[string][]Service
+}
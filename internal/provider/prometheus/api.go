@@ -0,0 +1,159 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errQueryFailed = errors.New("prometheus query failed")
+
+// Login configures how to reach the Prometheus HTTP API.
+type Login struct {
+	Url string `yaml:"url"`
+	// BearerToken is sent with every request, if set.
+	BearerToken string `yaml:"bearerToken"`
+}
+
+// API is an abstraction around the Prometheus HTTP API.
+type API interface {
+	QueryRange(ctx context.Context, query string, r Range) (Matrix, error)
+}
+
+// Range is the range of a query, start and end inclusive, with a sample every step.
+type Range struct {
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+type Matrix []Series
+
+type Series struct {
+	Labels  map[string]string
+	Samples []Sample
+}
+
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+func NewDefaultApi(l Login) API {
+	c := http.DefaultClient
+	if l.BearerToken != "" {
+		c = &http.Client{Transport: &bearerTransport{token: l.BearerToken, next: http.DefaultTransport}}
+	}
+	return NewApi(l.Url, c)
+}
+
+func NewApi(uri string, c *http.Client) API {
+	return &api{
+		uri: strings.TrimSuffix(uri, "/"),
+		c:   c,
+	}
+}
+
+type api struct {
+	uri string
+	c   *http.Client
+}
+
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (b *bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.token)
+	return b.next.RoundTrip(r)
+}
+
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (a *api) QueryRange(ctx context.Context, query string, r Range) (Matrix, error) {
+	values := url.Values{}
+	values.Set("query", query)
+	values.Set("start", formatTime(r.Start))
+	values.Set("end", formatTime(r.End))
+	values.Set("step", strconv.FormatFloat(r.Step.Seconds(), 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", a.uri+"/api/v1/query_range?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resp := queryResponse{}
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: status %d: %v", errQueryFailed, res.StatusCode, err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("%w: %s: %s", errQueryFailed, resp.ErrorType, resp.Error)
+	}
+	if resp.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("%w: expected matrix, got %s", errQueryFailed, resp.Data.ResultType)
+	}
+
+	matrix := Matrix{}
+	for _, result := range resp.Data.Result {
+		series := Series{Labels: result.Metric}
+		for _, value := range result.Values {
+			sample, err := parseSample(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", errQueryFailed, err)
+			}
+			series.Samples = append(series.Samples, sample)
+		}
+		matrix = append(matrix, series)
+	}
+	return matrix, nil
+}
+
+// parseSample parses a sample in the form [<unix time>, "<value>"].
+func parseSample(value [2]interface{}) (Sample, error) {
+	t, ok := value[0].(float64)
+	if !ok {
+		return Sample{}, fmt.Errorf("malformed sample time %v", value[0])
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return Sample{}, fmt.Errorf("malformed sample value %v", value[1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("malformed sample value %v", value[1])
+	}
+
+	seconds := int64(t)
+	nanos := int64((t - float64(seconds)) * 1e9)
+	return Sample{Time: time.Unix(seconds, nanos).UTC().Round(time.Millisecond), Value: v}, nil
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
@@ -0,0 +1,103 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+var someRange = Range{Start: someTime, End: someTime.Add(2 * time.Minute), Step: time.Minute}
+
+func TestApiQueryRange(t *testing.T) {
+	tests := []struct {
+		desc          string
+		status        int
+		body          string
+		expectedQuery url.Values
+		expected      Matrix
+		expectedErr   error
+	}{
+		{desc: "queries matrix", status: http.StatusOK, body: `{
+			"status": "success",
+			"data": {"resultType": "matrix", "result": [
+				{"metric": {"instance": "0"}, "values": [[1136127600, "1.5"], [1136127660.5, "2"]]},
+				{"metric": {"instance": "1"}, "values": [[1136127600, "NaN"]]}
+			]}
+		}`, expectedQuery: url.Values{
+			"query": {`up{app_id="app"}`},
+			"start": {"1136127600"},
+			"end":   {"1136127720"},
+			"step":  {"60"},
+		}, expected: Matrix{
+			{Labels: map[string]string{"instance": "0"}, Samples: []Sample{
+				{Time: someTime, Value: 1.5},
+				{Time: someTime.Add(time.Minute + 500*time.Millisecond), Value: 2},
+			}},
+			{Labels: map[string]string{"instance": "1"}, Samples: []Sample{
+				{Time: someTime, Value: nan},
+			}},
+		}},
+		{desc: "returns empty matrix", status: http.StatusOK, body: `{
+			"status": "success",
+			"data": {"resultType": "matrix", "result": []}
+		}`, expected: Matrix{}},
+		{desc: "returns query error", status: http.StatusBadRequest, body: `{
+			"status": "error", "errorType": "bad_data", "error": "parse error"
+		}`, expectedErr: errQueryFailed},
+		{desc: "returns error for non-json response", status: http.StatusBadGateway, body: `bad gateway`, expectedErr: errQueryFailed},
+		{desc: "returns error for malformed sample", status: http.StatusOK, body: `{
+			"status": "success",
+			"data": {"resultType": "matrix", "result": [{"metric": {}, "values": [[1136127600, 1.5]]}]}
+		}`, expectedErr: errQueryFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			var query url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/query_range" {
+					tt.Errorf("unexpected path %s", r.URL.Path)
+				}
+				query = r.URL.Query()
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			matrix, err := NewApi(server.URL+"/", server.Client()).QueryRange(context.Background(), `up{app_id="app"}`, someRange)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if test.expectedQuery != nil && !cmp.Equal(test.expectedQuery, query) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(test.expectedQuery, query))
+			}
+			if !cmp.Equal(test.expected, matrix, equateNaN) {
+				tt.Errorf("\ndiff between matrices: \n%s\n", cmp.Diff(test.expected, matrix, equateNaN))
+			}
+		})
+	}
+}
+
+func TestApiBearerToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status": "success", "data": {"resultType": "matrix", "result": []}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewDefaultApi(Login{Url: server.URL, BearerToken: "token"}).QueryRange(context.Background(), "up", someRange)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("wanted bearer token, got %s", auth)
+	}
+}
@@ -0,0 +1,89 @@
+package prometheus
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"math"
+	"strings"
+)
+
+// appPlaceholder is replaced by the id of the app in queries. The SDK only routes ids made of
+// lowercase letters, digits and dashes, so they can be put into label matchers as they are.
+const appPlaceholder = "$app"
+
+// Query is the PromQL query for a metric of an app, containing the app id as $app.
+type Query struct {
+	Metric sdk.Metric `yaml:"metric"`
+	Query  string     `yaml:"query"`
+}
+
+// DefaultQueries expect the series of an app to carry its id in the label 'app_id', as
+// cAdvisor and common http middlewares report them.
+var DefaultQueries = []Query{
+	{Metric: sdk.MetricCpu, Query: `sum by (instance) (rate(container_cpu_usage_seconds_total{app_id="$app"}[5m])) * 100`},
+	{Metric: sdk.MetricMemory, Query: `sum by (instance) (container_memory_working_set_bytes{app_id="$app"})`},
+	{Metric: sdk.MetricDisk, Query: `sum by (instance) (container_fs_usage_bytes{app_id="$app"})`},
+	{Metric: sdk.MetricRequestRate, Query: `sum(rate(http_requests_total{app_id="$app"}[5m]))`},
+	{Metric: sdk.MetricErrorRate, Query: `sum(rate(http_requests_total{app_id="$app",code=~"5.."}[5m]))`},
+}
+
+func NewProvider(prom API, queries []Query) *Provider {
+	if len(queries) == 0 {
+		queries = DefaultQueries
+	}
+	return &Provider{
+		prom:    prom,
+		queries: queries,
+	}
+}
+
+type Provider struct {
+	prom    API
+	queries []Query
+}
+
+func (p *Provider) GetAppMetrics(ctx context.Context, id string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	r := Range{Start: q.From, End: q.To, Step: q.Step}
+
+	res := sdk.AppMetrics{}
+	for _, query := range p.queries {
+		matrix, err := p.prom.QueryRange(ctx, strings.ReplaceAll(query.Query, appPlaceholder, id), r)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, series := range matrix {
+			res = append(res, toSdkSeries(query.Metric, series))
+		}
+	}
+	return res, nil
+}
+
+// toSdkSeries converts the series, dropping samples that aren't numbers, as they can't be
+// represented in json.
+func toSdkSeries(metric sdk.Metric, series Series) sdk.MetricSeries {
+	labels := make(map[string]string)
+	for k, v := range series.Labels {
+		if k != "__name__" {
+			labels[k] = v
+		}
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	points := []sdk.MetricPoint{}
+	for _, sample := range series.Samples {
+		if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			continue
+		}
+		points = append(points, sdk.MetricPoint{Time: sample.Time, Value: sample.Value})
+	}
+
+	return sdk.MetricSeries{
+		Metric: metric,
+		Unit:   metric.Unit(),
+		Labels: labels,
+		Points: points,
+	}
+}
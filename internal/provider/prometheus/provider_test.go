@@ -0,0 +1,102 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"math"
+	"testing"
+	"time"
+)
+
+var someErr = errors.New("error")
+
+var nan = math.NaN()
+
+var equateNaN = cmpopts.EquateNaNs()
+
+func TestGetAppMetrics(t *testing.T) {
+	tests := []struct {
+		desc            string
+		queries         []Query
+		prom            *fakePrometheus
+		expected        sdk.AppMetrics
+		expectedQueries []string
+		expectedErr     error
+	}{
+		{desc: "gets metrics", queries: []Query{
+			{Metric: sdk.MetricCpu, Query: `cpu{app_id="$app"}`},
+			{Metric: sdk.MetricMemory, Query: `memory{app_id="$app"}`},
+		}, prom: &fakePrometheus{results: map[string]Matrix{
+			`cpu{app_id="app-a"}`: {
+				{Labels: map[string]string{"__name__": "cpu", "instance": "0"}, Samples: []Sample{
+					{Time: someTime, Value: 10},
+					{Time: someTime.Add(time.Minute), Value: nan},
+					{Time: someTime.Add(2 * time.Minute), Value: math.Inf(1)},
+				}},
+			},
+			`memory{app_id="app-a"}`: {
+				{Labels: map[string]string{}, Samples: []Sample{{Time: someTime, Value: 1024}}},
+			},
+		}}, expected: sdk.AppMetrics{
+			{Metric: sdk.MetricCpu, Unit: sdk.MetricUnitPercent, Labels: map[string]string{"instance": "0"}, Points: []sdk.MetricPoint{
+				{Time: someTime, Value: 10},
+			}},
+			{Metric: sdk.MetricMemory, Unit: sdk.MetricUnitBytes, Points: []sdk.MetricPoint{
+				{Time: someTime, Value: 1024},
+			}},
+		}, expectedQueries: []string{`cpu{app_id="app-a"}`, `memory{app_id="app-a"}`}},
+		{desc: "returns no metrics", queries: []Query{
+			{Metric: sdk.MetricCpu, Query: `cpu{app_id="$app"}`},
+		}, prom: &fakePrometheus{}, expected: sdk.AppMetrics{}, expectedQueries: []string{`cpu{app_id="app-a"}`}},
+		{desc: "uses default queries", prom: &fakePrometheus{}, expected: sdk.AppMetrics{}, expectedQueries: []string{
+			`sum by (instance) (rate(container_cpu_usage_seconds_total{app_id="app-a"}[5m])) * 100`,
+			`sum by (instance) (container_memory_working_set_bytes{app_id="app-a"})`,
+			`sum by (instance) (container_fs_usage_bytes{app_id="app-a"})`,
+			`sum(rate(http_requests_total{app_id="app-a"}[5m]))`,
+			`sum(rate(http_requests_total{app_id="app-a",code=~"5.."}[5m]))`,
+		}},
+		{desc: "returns error", prom: &fakePrometheus{err: someErr}, expectedErr: someErr, expectedQueries: []string{
+			`sum by (instance) (rate(container_cpu_usage_seconds_total{app_id="app-a"}[5m])) * 100`,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := NewProvider(test.prom, test.queries)
+			q := sdk.MetricsQuery{From: someTime, To: someTime.Add(2 * time.Minute), Step: time.Minute}
+			metrics, err := p.GetAppMetrics(context.Background(), "app-a", q)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted error: %v, got %v\n", test.expectedErr, err)
+			}
+			if !cmp.Equal(test.expected, metrics) {
+				tt.Errorf("\ndiff between returned metrics: \n%s\n", cmp.Diff(test.expected, metrics))
+			}
+			if !cmp.Equal(test.expectedQueries, test.prom.queries) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(test.expectedQueries, test.prom.queries))
+			}
+			expectedRange := Range{Start: q.From, End: q.To, Step: q.Step}
+			if test.prom.r != expectedRange {
+				tt.Errorf("\nwanted range %v, got %v\n", expectedRange, test.prom.r)
+			}
+		})
+	}
+}
+
+type fakePrometheus struct {
+	results map[string]Matrix
+	err     error
+	queries []string
+	r       Range
+}
+
+func (f *fakePrometheus) QueryRange(_ context.Context, query string, r Range) (Matrix, error) {
+	f.queries = append(f.queries, query)
+	f.r = r
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.results[query], nil
+}
@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"container/heap"
+	"time"
+)
+
+// NewMemoryStore creates a store holding the items of a single process.
+func NewMemoryStore() Store {
+	return &memoryStore{h: &itemHeap{indices: make(map[string]int)}}
+}
+
+type memoryStore struct {
+	h *itemHeap
+}
+
+func (s *memoryStore) Push(item Item, agingStep time.Duration) (bool, error) {
+	i, ok := s.h.indices[item.Key]
+	if !ok {
+		item.Rank = rank(item.Enqueued, item.Priority, agingStep)
+		heap.Push(s.h, item)
+		return false, nil
+	}
+
+	queued := s.h.items[i]
+	if queued.Enqueued.Before(item.Enqueued) {
+		item.Enqueued = queued.Enqueued
+	}
+	if queued.Priority > item.Priority {
+		item.Priority = queued.Priority
+	}
+	item.Rank = rank(item.Enqueued, item.Priority, agingStep)
+
+	s.h.items[i] = item
+	heap.Fix(s.h, i)
+	return true, nil
+}
+
+func (s *memoryStore) PopFirst() (Item, bool, error) {
+	if s.h.Len() == 0 {
+		return Item{}, false, nil
+	}
+	return heap.Pop(s.h).(Item), true, nil
+}
+
+func (s *memoryStore) Len() (int, error) {
+	return s.h.Len(), nil
+}
+
+// itemHeap orders items by rank and keeps track of where each key is in the heap.
+type itemHeap struct {
+	items   []Item
+	indices map[string]int
+}
+
+func (h *itemHeap) Len() int {
+	return len(h.items)
+}
+
+func (h *itemHeap) Less(i, j int) bool {
+	return h.items[i].Rank.Before(h.items[j].Rank)
+}
+
+func (h *itemHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.indices[h.items[i].Key] = i
+	h.indices[h.items[j].Key] = j
+}
+
+func (h *itemHeap) Push(x interface{}) {
+	item := x.(Item)
+	h.indices[item.Key] = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *itemHeap) Pop() interface{} {
+	last := len(h.items) - 1
+	item := h.items[last]
+	h.items = h.items[:last]
+	delete(h.indices, item.Key)
+	return item
+}
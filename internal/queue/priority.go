@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"sync"
+	"time"
+)
+
+// Priority orders the items of a PriorityQueue, items with higher priorities are popped first.
+type Priority int
+
+// Item is a request waiting in a PriorityQueue.
+type Item struct {
+	// Key identifies the request. Pushing a key that is queued already coalesces both pushes.
+	Key      string    `bson:"key"`
+	Priority Priority  `bson:"priority"`
+	Enqueued time.Time `bson:"enqueued"`
+	// Rank orders the items, the item with the lowest rank is popped first.
+	Rank time.Time `bson:"rank"`
+}
+
+// Store holds the items of a PriorityQueue, e.g. in memory or in a database shared by several
+// processes.
+type Store interface {
+	// Push queues the item ranked by its enqueued time and priority. If an item with the same key
+	// is queued already, both are coalesced atomically into one which keeps the earlier enqueued
+	// time and the higher priority and is ranked anew. Push reports whether the key was queued.
+	Push(item Item, agingStep time.Duration) (bool, error)
+	// PopFirst removes the item with the lowest rank and returns it.
+	PopFirst() (Item, bool, error)
+	Len() (int, error)
+}
+
+// Metrics tells how the queue was used since the process started.
+type Metrics struct {
+	// Length is the number of queued items, including those pushed by other processes.
+	Length    int `json:"length"`
+	Pushed    int `json:"pushed"`
+	Coalesced int `json:"coalesced"`
+	Popped    int `json:"popped"`
+	// MaxWaitMillis is the longest time a popped item was queued.
+	MaxWaitMillis int64 `json:"maxWaitMillis"`
+	// AvgWaitMillis is the average time popped items were queued.
+	AvgWaitMillis int64 `json:"avgWaitMillis"`
+}
+
+var currentTime = time.Now
+
+// NewPriorityQueue creates a priority queue holding its items in memory. Items wait for at most
+// agingStep behind items pushed later with the next higher priority, so that items with lower
+// priorities aren't starved.
+func NewPriorityQueue(agingStep time.Duration) *PriorityQueue {
+	return NewPriorityQueueWithStore(NewMemoryStore(), agingStep)
+}
+
+func NewPriorityQueueWithStore(s Store, agingStep time.Duration) *PriorityQueue {
+	return &PriorityQueue{
+		store:     s,
+		agingStep: agingStep,
+	}
+}
+
+type PriorityQueue struct {
+	mu        sync.Mutex
+	store     Store
+	agingStep time.Duration
+
+	pushed    int
+	coalesced int
+	popped    int
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+// Push queues the key. If the key is queued already, the items are coalesced into one which
+// keeps its place in the queue and takes the higher of both priorities.
+func (q *PriorityQueue) Push(key string, p Priority) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	coalesced, err := q.store.Push(Item{Key: key, Priority: p, Enqueued: currentTime()}, q.agingStep)
+	if err != nil {
+		return err
+	}
+
+	q.pushed++
+	if coalesced {
+		q.coalesced++
+	}
+	return nil
+}
+
+// rank moves the item ahead of those enqueued up to agingStep later for each step of its priority.
+func rank(enqueued time.Time, p Priority, agingStep time.Duration) time.Time {
+	return enqueued.Add(-time.Duration(p) * agingStep)
+}
+
+// Pop removes the item with the highest priority, or the one waiting for longest among items
+// with the same priority.
+func (q *PriorityQueue) Pop() (Item, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item, ok, err := q.store.PopFirst()
+	if err != nil || !ok {
+		return Item{}, false, err
+	}
+
+	wait := currentTime().Sub(item.Enqueued)
+	q.popped++
+	q.totalWait += wait
+	if wait > q.maxWait {
+		q.maxWait = wait
+	}
+	return item, true, nil
+}
+
+func (q *PriorityQueue) Metrics() (Metrics, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	length, err := q.store.Len()
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	m := Metrics{
+		Length:        length,
+		Pushed:        q.pushed,
+		Coalesced:     q.coalesced,
+		Popped:        q.popped,
+		MaxWaitMillis: q.maxWait.Milliseconds(),
+	}
+	if q.popped > 0 {
+		m.AvgWaitMillis = (q.totalWait / time.Duration(q.popped)).Milliseconds()
+	}
+	return m, nil
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+
+func TestPriorityQueue(t *testing.T) {
+	type push struct {
+		key      string
+		priority Priority
+		at       time.Duration
+	}
+
+	tests := []struct {
+		desc     string
+		pushes   []push
+		expected []string
+	}{
+		{desc: "pops in order of pushes", pushes: []push{
+			{key: "a"}, {key: "b", at: time.Second}, {key: "c", at: 2 * time.Second},
+		}, expected: []string{"a", "b", "c"}},
+		{desc: "pops higher priorities first", pushes: []push{
+			{key: "a"}, {key: "b", priority: 1, at: time.Second}, {key: "c", priority: 2, at: 2 * time.Second},
+		}, expected: []string{"c", "b", "a"}},
+		{desc: "pops items that waited longer than the aging step first", pushes: []push{
+			{key: "a"}, {key: "b", priority: 1, at: 11 * time.Second}, {key: "c", priority: 1, at: 5 * time.Second},
+		}, expected: []string{"c", "a", "b"}},
+		{desc: "coalesces pushes of the same key", pushes: []push{
+			{key: "a"}, {key: "b", at: time.Second}, {key: "a", at: 2 * time.Second},
+		}, expected: []string{"a", "b"}},
+		{desc: "raises priority when coalescing", pushes: []push{
+			{key: "a"}, {key: "b", at: time.Second}, {key: "b", priority: 1, at: 2 * time.Second},
+		}, expected: []string{"b", "a"}},
+		{desc: "keeps priority when coalescing", pushes: []push{
+			{key: "a", priority: 1}, {key: "b", priority: 1, at: time.Second}, {key: "a", at: 2 * time.Second},
+		}, expected: []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			defer func() { currentTime = time.Now }()
+
+			q := NewPriorityQueue(10 * time.Second)
+			for _, p := range test.pushes {
+				currentTime = func() time.Time {
+					return someTime.Add(p.at)
+				}
+				err := q.Push(p.key, p.priority)
+				if err != nil {
+					tt.Fatal(err)
+				}
+			}
+
+			var popped []string
+			for {
+				item, ok, err := q.Pop()
+				if err != nil {
+					tt.Fatal(err)
+				}
+				if !ok {
+					break
+				}
+				popped = append(popped, item.Key)
+			}
+
+			if !cmp.Equal(test.expected, popped) {
+				tt.Errorf("\ndiff between popped keys: \n%s\n", cmp.Diff(test.expected, popped))
+			}
+		})
+	}
+}
+
+func TestPriorityQueue_Metrics(t *testing.T) {
+	defer func() { currentTime = time.Now }()
+	at := func(d time.Duration) {
+		currentTime = func() time.Time {
+			return someTime.Add(d)
+		}
+	}
+
+	q := NewPriorityQueue(10 * time.Second)
+	at(0)
+	_ = q.Push("a", 0)
+	_ = q.Push("b", 0)
+	_ = q.Push("a", 1)
+	at(4 * time.Second)
+	_, _, _ = q.Pop()
+	at(6 * time.Second)
+	_ = q.Push("c", 0)
+
+	m, err := q.Metrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Metrics{Length: 2, Pushed: 4, Coalesced: 1, Popped: 1, MaxWaitMillis: 4000, AvgWaitMillis: 4000}
+	if !cmp.Equal(expected, m) {
+		t.Errorf("\ndiff between metrics: \n%s\n", cmp.Diff(expected, m))
+	}
+}
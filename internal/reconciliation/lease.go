@@ -0,0 +1,10 @@
+package reconciliation
+
+// LeasingJobProvider hands out jobs under a lease, so that several replicas can share its
+// jobs without running them twice. Leases of replicas that crashed expire on their own.
+type LeasingJobProvider interface {
+	JobProvider
+	// ReleaseReconcileJob ends the lease the job was accepted under, once it ran. It returns
+	// ErrJobNotFound if the lease already expired or the job isn't stored.
+	ReleaseReconcileJob(j Job) error
+}
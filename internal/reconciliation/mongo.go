@@ -0,0 +1,123 @@
+package reconciliation
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// JobCollection is the collection holding the items that jobs of the type reconcile, each
+// identified by its guid.
+type JobCollection struct {
+	Type       Type
+	Collection *mongo.Collection
+}
+
+// MongoJobStore records the attempts of failed jobs on the items they reconcile. Providers
+// storing their items in Mongo embed it to retry and dead-letter their jobs.
+type MongoJobStore struct {
+	collections []JobCollection
+}
+
+// NewMongoJobStore creates a store for jobs of the given types. Dead letters are listed in
+// the order of the collections.
+func NewMongoJobStore(collections ...JobCollection) *MongoJobStore {
+	return &MongoJobStore{collections: collections}
+}
+
+// collection returns the collection holding the items a job of the given type reconciles.
+func (s *MongoJobStore) collection(typ Type) (*mongo.Collection, bool) {
+	for _, c := range s.collections {
+		if c.Type == typ {
+			return c.Collection, true
+		}
+	}
+	return nil, false
+}
+
+func (s *MongoJobStore) RetryReconcileJob(j Job, at time.Time, cause error) error {
+	return s.updateJob(j.Type, bson.M{"guid": j.Guid}, bson.M{
+		"attempts":    j.Attempts + 1,
+		"lastError":   cause.Error(),
+		"nextAttempt": at,
+	})
+}
+
+func (s *MongoJobStore) DeadLetterReconcileJob(j Job, cause error) error {
+	return s.updateJob(j.Type, bson.M{"guid": j.Guid}, bson.M{
+		"attempts":     j.Attempts + 1,
+		"lastError":    cause.Error(),
+		"nextAttempt":  nil,
+		"deadLettered": true,
+	})
+}
+
+func (s *MongoJobStore) SucceedReconcileJob(j Job) error {
+	return s.updateJob(j.Type, bson.M{"guid": j.Guid}, bson.M{
+		"attempts":    0,
+		"lastError":   "",
+		"nextAttempt": nil,
+	})
+}
+
+func (s *MongoJobStore) RequeueDeadLetter(typ Type, guid string) error {
+	return s.updateJob(typ, bson.M{"guid": guid, "deadLettered": true}, bson.M{
+		"attempts":     0,
+		"deadLettered": false,
+		"nextAttempt":  currentTime(),
+	})
+}
+
+func (s *MongoJobStore) updateJob(typ Type, filter bson.M, update bson.M) error {
+	coll, ok := s.collection(typ)
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	res, err := coll.UpdateOne(context.Background(), filter, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+// deadLetter is the part of a reconciled item that describes its job.
+type deadLetter struct {
+	Guid        string    `bson:"guid"`
+	LastUpdated time.Time `bson:"lastUpdated"`
+	Attempts    int       `bson:"attempts"`
+	LastError   string    `bson:"lastError"`
+}
+
+func (s *MongoJobStore) ListDeadLetters() ([]Job, error) {
+	ctx := context.Background()
+
+	res := []Job{}
+	for _, c := range s.collections {
+		cursor, err := c.Collection.Find(ctx, bson.M{"deadLettered": true}, options.Find().SetSort(bson.M{"guid": 1}))
+		if err != nil {
+			return nil, err
+		}
+
+		for cursor.Next(ctx) {
+			l := deadLetter{}
+			err = cursor.Decode(&l)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, Job{
+				Type:        c.Type,
+				Guid:        l.Guid,
+				LastUpdated: l.LastUpdated,
+				Attempts:    l.Attempts,
+				LastError:   l.LastError,
+			})
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,195 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tryvium-travels/memongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"runtime"
+	"testing"
+	"time"
+)
+
+const (
+	typeA Type = "a"
+	typeB Type = "b"
+)
+
+func TestMongoJobStore(t *testing.T) {
+	someTime := time.Date(2006, 1, 1, 15, 0, 0, 0, time.UTC)
+	currentTime = func() time.Time {
+		return someTime
+	}
+	defer func() { currentTime = time.Now }()
+
+	tests := []struct {
+		desc     string
+		state    map[string][]bson.M
+		f        func(s *MongoJobStore) error
+		err      error
+		expected map[string][]bson.M
+	}{
+		{desc: "retries job", state: map[string][]bson.M{
+			"a": {{"guid": "a-1", "attempts": 1}},
+		}, f: func(s *MongoJobStore) error {
+			return s.RetryReconcileJob(Job{Type: typeA, Guid: "a-1", Attempts: 1}, someTime.Add(time.Minute), errors.New("failed"))
+		}, expected: map[string][]bson.M{
+			"a": {{"guid": "a-1", "attempts": int32(2), "lastError": "failed", "nextAttempt": someTime.Add(time.Minute)}},
+		}},
+		{desc: "dead-letters job", state: map[string][]bson.M{
+			"a": {{"guid": "a-1", "attempts": 4, "nextAttempt": someTime}},
+		}, f: func(s *MongoJobStore) error {
+			return s.DeadLetterReconcileJob(Job{Type: typeA, Guid: "a-1", Attempts: 4}, errors.New("failed"))
+		}, expected: map[string][]bson.M{
+			"a": {{"guid": "a-1", "attempts": int32(5), "lastError": "failed", "nextAttempt": nil, "deadLettered": true}},
+		}},
+		{desc: "resets succeeded job", state: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": 2, "lastError": "failed", "nextAttempt": someTime}},
+		}, f: func(s *MongoJobStore) error {
+			return s.SucceedReconcileJob(Job{Type: typeB, Guid: "b-1", Attempts: 2})
+		}, expected: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": int32(0), "lastError": "", "nextAttempt": nil}},
+		}},
+		{desc: "requeues dead letter", state: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": 5, "lastError": "failed", "deadLettered": true}},
+		}, f: func(s *MongoJobStore) error {
+			return s.RequeueDeadLetter(typeB, "b-1")
+		}, expected: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": int32(0), "lastError": "failed", "deadLettered": false, "nextAttempt": someTime}},
+		}},
+		{desc: "doesn't requeue job that isn't dead-lettered", state: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": 2}},
+		}, f: func(s *MongoJobStore) error {
+			return s.RequeueDeadLetter(typeB, "b-1")
+		}, err: ErrJobNotFound, expected: map[string][]bson.M{
+			"b": {{"guid": "b-1", "attempts": int32(2)}},
+		}},
+		{desc: "fails for missing item", f: func(s *MongoJobStore) error {
+			return s.SucceedReconcileJob(Job{Type: typeA, Guid: "a-1"})
+		}, err: ErrJobNotFound},
+		{desc: "fails for unknown type", f: func(s *MongoJobStore) error {
+			return s.SucceedReconcileJob(Job{Type: "unknown", Guid: "a-1"})
+		}, err: ErrJobNotFound},
+	}
+
+	opts := &memongo.Options{
+		MongoVersion: "5.0.5",
+	}
+	if runtime.GOARCH == "arm64" {
+		if runtime.GOOS == "darwin" {
+			opts.DownloadURL = "https://fastdl.mongodb.org/osx/mongodb-macos-x86_64-5.0.5.tgz"
+		}
+	}
+
+	mongodb, err := memongo.StartWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mongodb.Stop()
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			db, stop := newJobStoreDatabase(tt, mongodb, test.state)
+			defer stop()
+
+			s := NewMongoJobStore(
+				JobCollection{Type: typeA, Collection: db.Collection("a")},
+				JobCollection{Type: typeB, Collection: db.Collection("b")},
+			)
+
+			err := test.f(s)
+			if !errors.Is(err, test.err) {
+				tt.Fatalf("\nwanted err %v, got %v", test.err, err)
+			}
+
+			for _, coll := range []string{"a", "b"} {
+				docs := dumpCollection(tt, db.Collection(coll))
+				if !cmp.Equal(test.expected[coll], docs) {
+					tt.Errorf("\ncollection %s differs from expected:\n%s", coll, cmp.Diff(test.expected[coll], docs))
+				}
+			}
+		})
+	}
+
+	t.Run("lists dead letters in order of the collections", func(tt *testing.T) {
+		db, stop := newJobStoreDatabase(tt, mongodb, map[string][]bson.M{
+			"a": {
+				{"guid": "a-2", "attempts": 5, "lastError": "failed", "lastUpdated": someTime, "deadLettered": true},
+				{"guid": "a-1", "attempts": 5, "lastError": "failed", "lastUpdated": someTime, "deadLettered": true},
+				{"guid": "a-3", "attempts": 2, "lastUpdated": someTime},
+			},
+			"b": {
+				{"guid": "b-1", "attempts": 5, "lastError": "other", "lastUpdated": someTime, "deadLettered": true},
+			},
+		})
+		defer stop()
+
+		s := NewMongoJobStore(
+			JobCollection{Type: typeB, Collection: db.Collection("b")},
+			JobCollection{Type: typeA, Collection: db.Collection("a")},
+		)
+
+		jobs, err := s.ListDeadLetters()
+		if err != nil {
+			tt.Fatal(err)
+		}
+
+		expected := []Job{
+			{Type: typeB, Guid: "b-1", LastUpdated: someTime, Attempts: 5, LastError: "other"},
+			{Type: typeA, Guid: "a-1", LastUpdated: someTime, Attempts: 5, LastError: "failed"},
+			{Type: typeA, Guid: "a-2", LastUpdated: someTime, Attempts: 5, LastError: "failed"},
+		}
+		if !cmp.Equal(expected, jobs) {
+			tt.Errorf("\ndead letters differ from expected:\n%s", cmp.Diff(expected, jobs))
+		}
+	})
+}
+
+func newJobStoreDatabase(t *testing.T, s *memongo.Server, state map[string][]bson.M) (*mongo.Database, func()) {
+	conn, err := mongo.Connect(context.Background(), options.Client().ApplyURI(s.URI()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := conn.Database(memongo.RandomDatabase())
+	for coll, docs := range state {
+		for _, doc := range docs {
+			_, err = db.Collection(coll).InsertOne(context.Background(), doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return db, func() {
+		_ = db.Drop(context.Background())
+		_ = conn.Disconnect(context.Background())
+	}
+}
+
+func dumpCollection(t *testing.T, coll *mongo.Collection) []bson.M {
+	cursor, err := coll.Find(context.Background(), bson.M{}, options.Find().SetProjection(bson.M{"_id": 0}).SetSort(bson.M{"guid": 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var docs []bson.M
+	for cursor.Next(context.Background()) {
+		doc := bson.M{}
+		err = cursor.Decode(&doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range doc {
+			if d, ok := v.(primitive.DateTime); ok {
+				doc[k] = d.Time().UTC()
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
@@ -0,0 +1,72 @@
+package reconciliation
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrJobNotFound is returned by job providers for jobs they don't store, e.g. jobs that
+// were only requested in memory or dead-lettered jobs that were requeued already.
+var ErrJobNotFound = errors.New("reconcile job not found")
+
+// RetryingJobProvider stores the attempts of failed jobs, so that they are retried with
+// backoff rather than on the next regular reconciliation.
+type RetryingJobProvider interface {
+	JobProvider
+	// RetryReconcileJob records another failed attempt of the job, which is accepted again at
+	// the given time at the earliest.
+	RetryReconcileJob(j Job, at time.Time, cause error) error
+	// DeadLetterReconcileJob records the last failed attempt of the job, which isn't accepted
+	// anymore until it is requeued.
+	DeadLetterReconcileJob(j Job, cause error) error
+	// SucceedReconcileJob resets the attempts of the job after it failed before.
+	SucceedReconcileJob(j Job) error
+}
+
+// DeadLetterQueue lists the dead-lettered jobs of a job provider and requeues them.
+type DeadLetterQueue interface {
+	ListDeadLetters() ([]Job, error)
+	// RequeueDeadLetter resets the attempts of the dead-lettered job and makes it acceptable
+	// right away. It returns ErrJobNotFound if there is no such dead-lettered job.
+	RequeueDeadLetter(t Type, guid string) error
+}
+
+// Backoff decides when failed jobs are attempted again.
+type Backoff struct {
+	// Base is the delay after the first failed attempt, which doubles with every further one.
+	Base time.Duration
+	// Max caps the delay between two attempts.
+	Max time.Duration
+	// Jitter randomly shortens or lengthens delays by up to the given fraction, so that jobs
+	// failing together aren't retried together.
+	Jitter float64
+	// MaxAttempts is the number of attempts after which a job is dead-lettered.
+	MaxAttempts int
+}
+
+var DefaultBackoff = Backoff{
+	Base:        10 * time.Second,
+	Max:         10 * time.Minute,
+	Jitter:      0.2,
+	MaxAttempts: 8,
+}
+
+var random = rand.Float64
+
+// Delay returns for how long to wait after the given number of failed attempts.
+func (b Backoff) Delay(attempts int) time.Duration {
+	d := float64(b.Base) * math.Pow(2, float64(attempts-1))
+	if d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+
+	d *= 1 + b.Jitter*(2*random()-1)
+	return time.Duration(d)
+}
+
+// Exhausted reports whether a job failing the given number of attempts is dead-lettered.
+func (b Backoff) Exhausted(attempts int) bool {
+	return attempts >= b.MaxAttempts
+}
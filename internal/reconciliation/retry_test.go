@@ -0,0 +1,45 @@
+package reconciliation
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Base: 10 * time.Second, Max: time.Minute, Jitter: 0.5, MaxAttempts: 3}
+
+	tests := []struct {
+		desc     string
+		attempts int
+		random   float64
+		expected time.Duration
+	}{
+		{desc: "waits base after first attempt", attempts: 1, random: 0.5, expected: 10 * time.Second},
+		{desc: "doubles with every attempt", attempts: 3, random: 0.5, expected: 40 * time.Second},
+		{desc: "caps at max", attempts: 5, random: 0.5, expected: time.Minute},
+		{desc: "shortens by jitter", attempts: 1, random: 0, expected: 5 * time.Second},
+		{desc: "lengthens by jitter", attempts: 2, random: 1, expected: 30 * time.Second},
+	}
+
+	defer func() { random = rand.Float64 }()
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			random = func() float64 { return test.random }
+			d := b.Delay(test.attempts)
+			if d != test.expected {
+				tt.Errorf("\nwanted delay %v, got %v", test.expected, d)
+			}
+		})
+	}
+}
+
+func TestBackoffExhausted(t *testing.T) {
+	b := Backoff{MaxAttempts: 3}
+	if b.Exhausted(2) {
+		t.Error("expected backoff not to be exhausted after 2 attempts")
+	}
+	if !b.Exhausted(3) {
+		t.Error("expected backoff to be exhausted after 3 attempts")
+	}
+}
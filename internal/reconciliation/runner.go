@@ -1,6 +1,7 @@
 package reconciliation
 
 import (
+	"errors"
 	"github.com/rs/zerolog/log"
 	"time"
 )
@@ -12,9 +13,15 @@ type JobProvider interface {
 type Type string
 type ReconcileHandler func(j Job) error
 type Job struct {
-	Type        Type      `gson:"type"`
-	Guid        string    `gson:"guid"`
+	Type        Type      `gson:"type" json:"type"`
+	Guid        string    `gson:"guid" json:"guid"`
 	LastUpdated time.Time `gson:"lastUpdated" json:"lastUpdated"`
+	// LastSynced is when the last successful reconciliation of the job started, zero if none
+	// succeeded yet. Providers serving deltas are asked for the changes since then.
+	LastSynced time.Time `bson:"lastSynced" json:"lastSynced"`
+	// Attempts counts the failed attempts since the job last succeeded.
+	Attempts  int    `bson:"attempts" json:"attempts,omitempty"`
+	LastError string `bson:"lastError" json:"lastError,omitempty"`
 }
 
 type Reconciler interface {
@@ -23,10 +30,17 @@ type Reconciler interface {
 }
 
 func NewReconciler(p JobProvider, olderThan time.Duration) Reconciler {
+	return NewReconcilerWithBackoff(p, olderThan, DefaultBackoff)
+}
+
+// NewReconcilerWithBackoff creates a reconciler retrying failed jobs with the given backoff,
+// if the job provider is a RetryingJobProvider.
+func NewReconcilerWithBackoff(p JobProvider, olderThan time.Duration, b Backoff) Reconciler {
 	return &reconciler{
 		p:         p,
 		mapping:   map[Type]ReconcileHandler{},
 		olderThan: olderThan,
+		backoff:   b,
 	}
 }
 
@@ -34,6 +48,7 @@ type reconciler struct {
 	p         JobProvider
 	mapping   map[Type]ReconcileHandler
 	olderThan time.Duration
+	backoff   Backoff
 }
 
 func (r *reconciler) Handler(t Type, f ReconcileHandler) {
@@ -47,11 +62,50 @@ func (r *reconciler) Run() (bool, error) {
 	}
 
 	log.Info().Interface("job", j).Msg("reconciling")
+	defer r.release(j)
 
 	f := r.mapping[j.Type]
 	if f == nil {
 		return true, nil
 	}
 
-	return true, f(j)
+	err := f(j)
+	r.recordAttempt(j, err)
+	return true, err
+}
+
+func (r *reconciler) recordAttempt(j Job, err error) {
+	p, ok := r.p.(RetryingJobProvider)
+	if !ok || (err == nil && j.Attempts == 0) {
+		return
+	}
+
+	var recordErr error
+	attempts := j.Attempts + 1
+	if err == nil {
+		recordErr = p.SucceedReconcileJob(j)
+	} else if r.backoff.Exhausted(attempts) {
+		log.Warn().Err(err).Interface("job", j).Int("attempts", attempts).Msg("giving up on job, dead-lettering it")
+		recordErr = p.DeadLetterReconcileJob(j, err)
+	} else {
+		recordErr = p.RetryReconcileJob(j, currentTime().Add(r.backoff.Delay(attempts)), err)
+	}
+
+	if recordErr != nil && !errors.Is(recordErr, ErrJobNotFound) {
+		log.Error().Err(recordErr).Interface("job", j).Msg("error recording job attempt")
+	}
+}
+
+func (r *reconciler) release(j Job) {
+	p, ok := r.p.(LeasingJobProvider)
+	if !ok {
+		return
+	}
+
+	err := p.ReleaseReconcileJob(j)
+	if err != nil && !errors.Is(err, ErrJobNotFound) {
+		log.Error().Err(err).Interface("job", j).Msg("error releasing job")
+	}
 }
+
+var currentTime = time.Now
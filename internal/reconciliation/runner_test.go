@@ -1,7 +1,10 @@
 package reconciliation
 
 import (
+	"errors"
+	"fmt"
 	"github.com/google/go-cmp/cmp"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -87,3 +90,115 @@ func (f *fakeJobProvider) AcceptReconcileJob(olderThan time.Duration) (Job, bool
 	f.recorded = olderThan
 	return f.job, f.ok
 }
+
+func TestRunnerRetries(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+	random = func() float64 { return 0.5 }
+	defer func() {
+		currentTime = time.Now
+		random = rand.Float64
+	}()
+
+	b := Backoff{Base: time.Minute, Max: time.Hour, MaxAttempts: 3}
+
+	tests := []struct {
+		desc     string
+		job      Job
+		err      error
+		expected []string
+	}{
+		{desc: "retries failed job", job: Job{Type: "someType", Guid: "a"}, err: someErr,
+			expected: []string{"retry a/1 at 2006-01-01T15:01:00Z: some error"}},
+		{desc: "backs off exponentially", job: Job{Type: "someType", Guid: "a", Attempts: 1}, err: someErr,
+			expected: []string{"retry a/2 at 2006-01-01T15:02:00Z: some error"}},
+		{desc: "dead-letters job after max attempts", job: Job{Type: "someType", Guid: "a", Attempts: 2}, err: someErr,
+			expected: []string{"dead-letter a/2: some error"}},
+		{desc: "resets attempts after success", job: Job{Type: "someType", Guid: "a", Attempts: 2},
+			expected: []string{"succeed a/2"}},
+		{desc: "doesn't record success of jobs that didn't fail", job: Job{Type: "someType", Guid: "a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := &fakeRetryingJobProvider{fakeJobProvider: fakeJobProvider{job: test.job, ok: true}}
+			r := NewReconcilerWithBackoff(p, time.Minute, b)
+			r.Handler("someType", func(j Job) error {
+				return test.err
+			})
+
+			_, err := r.Run()
+			if err != test.err {
+				tt.Errorf("\nwanted err %v, got %v", test.err, err)
+			}
+			if !cmp.Equal(test.expected, p.recorded) {
+				tt.Errorf("\ndiff between recorded attempts: \n%s\n", cmp.Diff(test.expected, p.recorded))
+			}
+		})
+	}
+}
+
+func TestRunnerReleasesLease(t *testing.T) {
+	tests := []struct {
+		desc     string
+		job      Job
+		err      error
+		expected []string
+	}{
+		{desc: "releases job after it ran", job: Job{Type: "someType", Guid: "a"},
+			expected: []string{"someType/a"}},
+		{desc: "releases failed job", job: Job{Type: "someType", Guid: "a"}, err: someErr,
+			expected: []string{"someType/a"}},
+		{desc: "releases job without handler", job: Job{Type: "otherType", Guid: "a"},
+			expected: []string{"otherType/a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			p := &fakeLeasingJobProvider{fakeJobProvider: fakeJobProvider{job: test.job, ok: true}}
+			r := NewReconciler(p, time.Minute)
+			r.Handler("someType", func(j Job) error {
+				return test.err
+			})
+
+			_, _ = r.Run()
+			if !cmp.Equal(test.expected, p.released) {
+				tt.Errorf("\ndiff between released jobs: \n%s\n", cmp.Diff(test.expected, p.released))
+			}
+		})
+	}
+}
+
+var someTime, _ = time.Parse(time.RFC3339, "2006-01-01T15:00:00Z")
+var someErr = errors.New("some error")
+
+type fakeRetryingJobProvider struct {
+	fakeJobProvider
+	recorded []string
+}
+
+func (f *fakeRetryingJobProvider) RetryReconcileJob(j Job, at time.Time, cause error) error {
+	f.recorded = append(f.recorded, fmt.Sprintf("retry %s/%d at %s: %v", j.Guid, j.Attempts+1, at.Format(time.RFC3339), cause))
+	return nil
+}
+
+func (f *fakeRetryingJobProvider) DeadLetterReconcileJob(j Job, cause error) error {
+	f.recorded = append(f.recorded, fmt.Sprintf("dead-letter %s/%d: %v", j.Guid, j.Attempts, cause))
+	return nil
+}
+
+func (f *fakeRetryingJobProvider) SucceedReconcileJob(j Job) error {
+	f.recorded = append(f.recorded, fmt.Sprintf("succeed %s/%d", j.Guid, j.Attempts))
+	return nil
+}
+
+type fakeLeasingJobProvider struct {
+	fakeJobProvider
+	released []string
+}
+
+func (f *fakeLeasingJobProvider) ReleaseReconcileJob(j Job) error {
+	f.released = append(f.released, string(j.Type)+"/"+j.Guid)
+	return nil
+}
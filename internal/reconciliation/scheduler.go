@@ -37,7 +37,10 @@ func (s *scheduler) worker(d time.Duration) {
 		case <-s.cancel:
 			return
 		default:
-			worked, _ := s.r.Run()
+			worked, err := s.r.Run()
+			if err != nil {
+				log.Warn().Err(err).Msg("reconciliation failed")
+			}
 			if !worked {
 				log.Trace().Msg("nothing to reconcile, sleeping...")
 				t.Reset(d)
@@ -1,5 +1,7 @@
 package pipeviz
 
+import "sort"
+
 type grid struct {
 	constraints      []constraint
 	constraintSource map[int][]constraint
@@ -189,22 +191,32 @@ func (g *grid) reorderColumn(col int) {
 	}
 
 	row := 0
-	for id := range single {
+	for _, id := range sorted(single) {
 		g.moveToRow(id, row)
 		row += g.boxes[id].height
 	}
 
-	for id := range multiStarting {
+	for _, id := range sorted(multiStarting) {
 		g.moveToRow(id, row)
 		row += g.boxes[id].height
 	}
 
-	for id := range multi {
+	for _, id := range sorted(multi) {
 		g.moveToRow(id, row)
 		row += g.boxes[id].height
 	}
 }
 
+// sorted returns the ids of the set in ascending order, so that the layout doesn't depend on map iteration.
+func sorted(set map[int]bool) []int {
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 func (g *grid) moveToRow(id int, row int) {
 	before := g.boxes[id].row
 	g.boxes[id].row = row
@@ -1,7 +1,7 @@
 package pipeviz
 
 import (
-	"gonum.org/v1/gonum/graph/simple"
+	"sort"
 )
 
 type layouted struct {
@@ -22,15 +22,19 @@ type box struct {
 func generateLayout(in Graph) layouted {
 	nodes := make(map[int]Node)
 	edges := make(map[int][]Edge)
+	incoming := make(map[int][]int)
 
-	g := simple.NewDirectedGraph()
 	for _, n := range in.Nodes {
-		g.AddNode(simple.Node(n.Id))
 		nodes[n.Id] = n
 	}
 	for _, edge := range in.Edges {
-		g.SetEdge(g.NewEdge(simple.Node(edge.From), simple.Node(edge.To)))
+		_, fromKnown := nodes[edge.From]
+		_, toKnown := nodes[edge.To]
+		if !fromKnown || !toKnown || edge.From == edge.To {
+			continue
+		}
 		edges[edge.From] = append(edges[edge.From], edge)
+		incoming[edge.To] = append(incoming[edge.To], edge.From)
 	}
 
 	layout := newGrid()
@@ -39,9 +43,8 @@ func generateLayout(in Graph) layouted {
 		layout.addBox(n.Id)
 	}
 	for _, n := range in.Nodes {
-		to := g.To(int64(n.Id))
-		for to.Next() {
-			layout.constrainRightOf(n.Id, int(to.Node().ID()))
+		for _, from := range incoming[n.Id] {
+			layout.constrainRightOf(n.Id, from)
 		}
 	}
 
@@ -50,8 +53,9 @@ func generateLayout(in Graph) layouted {
 	boxes := layout.getBoxes()
 	var out []box
 
-	for id, b := range boxes {
-		b.Node = nodes[boxes[id].Node.Id]
+	for _, id := range sortedIds(boxes) {
+		b := boxes[id]
+		b.Node = nodes[id]
 
 		for _, edge := range edges[id] {
 			start := float64(boxes[edge.To].StartRow)
@@ -70,3 +74,12 @@ func generateLayout(in Graph) layouted {
 		Boxes:   out,
 	}
 }
+
+func sortedIds(boxes map[int]box) []int {
+	ids := make([]int, 0, len(boxes))
+	for id := range boxes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
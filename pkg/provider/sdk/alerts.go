@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// AlertsProvider serves the alerts and incidents that are currently active for apps.
+type AlertsProvider interface {
+	// ListAlerts returns all active alerts. Resolved alerts are no longer listed.
+	ListAlerts(ctx context.Context) ([]Alert, error)
+}
+
+// Alert is a firing alert or an open incident of an app.
+type Alert struct {
+	// Id identifies the alert within its provider.
+	Id    string `json:"id" bson:"id"`
+	AppId string `json:"appId" bson:"appId"`
+	// Team is the id of the team responsible for the alert, if known.
+	Team     string            `json:"team,omitempty" bson:"team,omitempty"`
+	Kind     AlertKind         `json:"kind" bson:"kind"`
+	Name     string            `json:"name" bson:"name"`
+	Summary  string            `json:"summary,omitempty" bson:"summary,omitempty"`
+	Severity AlertSeverity     `json:"severity" bson:"severity"`
+	Started  time.Time         `json:"started" bson:"started"`
+	Labels   map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	// Link points to the alert in the tool it originates from.
+	Link string `json:"link,omitempty" bson:"link,omitempty"`
+}
+
+type AlertKind string
+
+const (
+	AlertKindAlert    AlertKind = "alert"
+	AlertKindIncident AlertKind = "incident"
+)
+
+type AlertSeverity string
+
+const (
+	AlertSeverityCritical AlertSeverity = "critical"
+	AlertSeverityWarning  AlertSeverity = "warning"
+	AlertSeverityInfo     AlertSeverity = "info"
+)
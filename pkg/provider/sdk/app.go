@@ -1,10 +1,83 @@
 package sdk
 
+import (
+	"context"
+	"time"
+)
+
 type AppProvider interface {
 	ListApps() ([]App, error)
 	GetApp(id string) (App, error)
 }
 
+// AppProviderContext is the context aware version of AppProvider.
+type AppProviderContext interface {
+	ListApps(ctx context.Context) ([]App, error)
+	GetApp(ctx context.Context, id string) (App, error)
+}
+
+// AppUpdatesProvider is implemented by app providers that can list their apps in pages and
+// report which apps changed since a point in time. The core then applies these deltas instead
+// of fetching all apps on every reconciliation. Providers implementing it advertise FeatureAppUpdates.
+type AppUpdatesProvider interface {
+	AppProviderContext
+	ListAppsPage(ctx context.Context, perPage int, page int) (AppPage, error)
+	// ListAppUpdates returns the apps that were created or changed since the given time, as well
+	// as tombstones for the apps that were deleted.
+	ListAppUpdates(ctx context.Context, since time.Time) (AppUpdates, error)
+}
+
+// MaxUpdatesAge is how far back the core asks for app updates at most. If it synced longer ago,
+// it lists all apps instead, so providers only need to keep their tombstones that long.
+const MaxUpdatesAge = 7 * 24 * time.Hour
+
+// AppUpdates are the changes to the apps of a provider since a point in time.
+type AppUpdates struct {
+	Apps    []App          `json:"apps,omitempty"`
+	Deleted []AppTombstone `json:"deleted,omitempty"`
+}
+
+// AppTombstone marks an app that was deleted.
+type AppTombstone struct {
+	Id      string    `json:"id"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// PageApps returns the given page of the apps. Pages are counted from 0.
+func PageApps(apps []App, perPage int, page int) (AppPage, error) {
+	if perPage <= 0 {
+		return AppPage{}, ErrQueryPerPageMalformed
+	}
+	if page < 0 {
+		return AppPage{}, ErrQueryPageMalformed
+	}
+
+	pages := (len(apps) + perPage - 1) / perPage
+	if page > 0 && page >= pages {
+		return AppPage{}, ErrPageExceeded
+	}
+
+	res := AppPage{
+		Pagination: Pagination{
+			TotalResults: len(apps),
+			TotalPages:   pages,
+			PerPage:      perPage,
+			Page:         page,
+		},
+		Apps: []App{},
+	}
+
+	start := page * perPage
+	end := start + perPage
+	if end > len(apps) {
+		end = len(apps)
+	}
+	if start < end {
+		res.Apps = apps[start:end]
+	}
+	return res, nil
+}
+
 type AppPage struct {
 	Pagination
 	Apps []App `json:"apps"`
@@ -0,0 +1,28 @@
+package sdk
+
+import "context"
+
+// AppActionsProvider changes the lifecycle state of apps on the platform.
+// All actions return ErrNotFound for apps or instances the provider doesn't know.
+type AppActionsProvider interface {
+	RestartApp(ctx context.Context, id string) error
+	StopApp(ctx context.Context, id string) error
+	StartApp(ctx context.Context, id string) error
+	// ScaleApp sets the number of instances the app runs with.
+	ScaleApp(ctx context.Context, id string, instances int) error
+	// RestartAppInstance restarts a single instance, identified by its index in the app's instances.
+	RestartAppInstance(ctx context.Context, id string, index int) error
+}
+
+// AppScaleRequest is the body of a request to scale an app.
+type AppScaleRequest struct {
+	Instances int `json:"instances"`
+}
+
+// Validate returns ErrScaleInvalid if the number of instances is negative.
+func (s AppScaleRequest) Validate() error {
+	if s.Instances < 0 {
+		return ErrScaleInvalid
+	}
+	return nil
+}
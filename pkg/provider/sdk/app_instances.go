@@ -1,15 +1,46 @@
 package sdk
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type InstancesProvider interface {
 	GetAppInstances(id string) (AppInstances, error)
 }
 
+// InstancesProviderContext is the context aware version of InstancesProvider.
+type InstancesProviderContext interface {
+	GetAppInstances(ctx context.Context, id string) (AppInstances, error)
+}
+
 type AppInstances []AppInstance
 type AppInstance struct {
 	State AppState  `json:"state"`
 	Since time.Time `json:"since"`
+	// Index identifies the instance within its app, as used by RestartAppInstance.
+	Index int `json:"index"`
+	// Host is the cell, node or machine the instance runs on.
+	Host string `json:"host,omitempty"`
+	// Uptime is the number of seconds the instance has been running.
+	Uptime int64 `json:"uptime,omitempty"`
+	// Restarts counts how often the instance was restarted. Providers that don't keep track of
+	// restarts report the crashes within a recent window instead, e.g. CloudFoundry the last day.
+	Restarts int `json:"restarts,omitempty"`
+	// LastCrash is the reason the instance crashed the last time, if it did.
+	LastCrash string `json:"lastCrash,omitempty"`
+	// Usage is nil if the provider doesn't know the resources the instance uses.
+	Usage *InstanceUsage `json:"usage,omitempty"`
+}
+
+// InstanceUsage is what an instance currently uses of its quota. Cpu is the share of a
+// single core, memory and disk are in bytes. Quotas are zero if the instance has none.
+type InstanceUsage struct {
+	Cpu         float64 `json:"cpu"`
+	Memory      int64   `json:"memory"`
+	MemoryQuota int64   `json:"memoryQuota,omitempty"`
+	Disk        int64   `json:"disk"`
+	DiskQuota   int64   `json:"diskQuota,omitempty"`
 }
 
 type AppState string
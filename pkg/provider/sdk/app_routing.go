@@ -1,9 +1,19 @@
 package sdk
 
+import (
+	"context"
+	"time"
+)
+
 type RoutingProvider interface {
 	GetAppRouting(id string) (AppRouting, error)
 }
 
+// RoutingProviderContext is the context aware version of RoutingProvider.
+type RoutingProviderContext interface {
+	GetAppRouting(ctx context.Context, id string) (AppRouting, error)
+}
+
 type AppRouting struct {
 	Routes AppRoutes `json:"routes"`
 }
@@ -14,4 +24,33 @@ type AppRoute struct {
 	Host    string `json:"host"`
 	Path    string `json:"path"`
 	AppPort int    `json:"appPort"`
+	// Domain is the part of the host that is shared with other routes, if the platform knows about it.
+	Domain   string        `json:"domain,omitempty"`
+	Protocol RouteProtocol `json:"protocol,omitempty"`
+	// Internal routes are only reachable from within the platform.
+	Internal bool `json:"internal,omitempty"`
+	// Weight is the percentage of the traffic to the host and path the app receives. It is
+	// zero if the traffic isn't split between apps.
+	Weight int `json:"weight,omitempty"`
+	// Tls is nil if the provider doesn't know whether the route is served via TLS.
+	Tls *RouteTls `json:"tls,omitempty"`
+}
+
+type RouteProtocol string
+
+const (
+	RouteProtocolHttp RouteProtocol = "http"
+	RouteProtocolTcp  RouteProtocol = "tcp"
+	RouteProtocolGrpc RouteProtocol = "grpc"
+)
+
+type RouteTls struct {
+	Enabled bool `json:"enabled"`
+	// Expires is when the certificate of the route expires, nil if unknown.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// CertificateExpiresBefore reports whether the route has a certificate that expires before the given time.
+func (r AppRoute) CertificateExpiresBefore(t time.Time) bool {
+	return r.Tls != nil && r.Tls.Expires != nil && r.Tls.Expires.Before(t)
 }
@@ -0,0 +1,218 @@
+package sdk
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderTimestamp = "X-Dyve-Timestamp"
+	HeaderSignature = "X-Dyve-Signature"
+)
+
+// MaxSignatureAge is how far the timestamp of a signed request may differ from the
+// providers clock before the request is rejected.
+const MaxSignatureAge = 5 * time.Minute
+
+// AuthConfig configures the authentication between core and provider. Both sides need
+// to be configured with the same token or secret.
+type AuthConfig struct {
+	// Token is a shared secret the core sends as bearer token.
+	Token string `yaml:"token"`
+	// HmacSecret is a shared secret the core signs each request with.
+	HmacSecret string    `yaml:"hmacSecret"`
+	TLS        TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig configures mutual TLS. On the provider side the CA is used to verify client
+// certificates, on the core side to verify the provider.
+type TLSConfig struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	CAFile   string `yaml:"caFile"`
+}
+
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// ServerTLS creates the tls config for a provider that requires client certificates.
+func (t TLSConfig) ServerTLS() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := loadCertPool(t.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientTLS creates the tls config for the core presenting its client certificate.
+func (t TLSConfig) ClientTLS() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if t.CAFile != "" {
+		c.RootCAs, err = loadCertPool(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// AuthorizeRequest adds the credentials of the config to the request.
+func (a AuthConfig) AuthorizeRequest(r *http.Request, now time.Time) error {
+	if a.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+a.Token)
+	}
+
+	if a.HmacSecret != "" {
+		return SignRequest(r, a.HmacSecret, now)
+	}
+
+	return nil
+}
+
+// VerifyRequest checks that the request carries the credentials of the config.
+func (a AuthConfig) VerifyRequest(r *http.Request, now time.Time) error {
+	if a.Token != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) != 1 {
+			return ErrUnauthorized
+		}
+	}
+
+	if a.HmacSecret != "" {
+		return VerifySignature(r, a.HmacSecret, now)
+	}
+
+	return nil
+}
+
+// SignRequest signs method, url, body and the current time of the request with the secret.
+func SignRequest(r *http.Request, secret string, now time.Time) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderSignature, signature(secret, r.Method, r.URL.RequestURI(), timestamp, body))
+	return nil
+}
+
+// VerifySignature checks the signature of a request signed with SignRequest.
+func VerifySignature(r *http.Request, secret string, now time.Time) error {
+	timestamp := r.Header.Get(HeaderTimestamp)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	age := now.Sub(time.Unix(sent, 0))
+	if age > MaxSignatureAge || age < -MaxSignatureAge {
+		return ErrSignatureExpired
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+
+	expected := signature(secret, r.Method, r.URL.RequestURI(), timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(HeaderSignature))) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func signature(secret string, method string, uri string, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{method, uri, timestamp, hex.EncodeToString(bodyHash[:])}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readBody reads the body of the request and replaces it, so it can be read again.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.Body.Close()
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(b)), nil
+	}
+	return b, nil
+}
+
+// NewAuthMiddleware rejects all requests that don't carry the credentials of the config.
+func NewAuthMiddleware(a AuthConfig, next http.Handler) http.Handler {
+	if a.Token == "" && a.HmacSecret == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := a.VerifyRequest(r, currentTime())
+		if errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrSignatureExpired) {
+			respondErr(w, http.StatusUnauthorized, err)
+			return
+		} else if err != nil {
+			respondErr(w, http.StatusBadRequest, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2006, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc        string
+		secret      string
+		modify      func(r *http.Request)
+		verifyAt    time.Time
+		expectedErr error
+	}{
+		{desc: "valid signature", secret: "secret", verifyAt: now},
+		{desc: "wrong secret", secret: "other", verifyAt: now, expectedErr: ErrUnauthorized},
+		{desc: "tampered path", secret: "secret", verifyAt: now, modify: func(r *http.Request) {
+			r.URL.Path = "/apps/other"
+		}, expectedErr: ErrUnauthorized},
+		{desc: "tampered timestamp", secret: "secret", verifyAt: now, modify: func(r *http.Request) {
+			r.Header.Set(HeaderTimestamp, "1136127601")
+		}, expectedErr: ErrUnauthorized},
+		{desc: "missing timestamp", secret: "secret", verifyAt: now, modify: func(r *http.Request) {
+			r.Header.Del(HeaderTimestamp)
+		}, expectedErr: ErrUnauthorized},
+		{desc: "expired signature", secret: "secret", verifyAt: now.Add(MaxSignatureAge + time.Second), expectedErr: ErrSignatureExpired},
+		{desc: "signature from the future", secret: "secret", verifyAt: now.Add(-MaxSignatureAge - time.Second), expectedErr: ErrSignatureExpired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRequest("POST", "/apps/app?page=1", strings.NewReader("body"))
+			err := SignRequest(r, "secret", now)
+			if err != nil {
+				tt.Fatal("unexpected error: ", err)
+			}
+
+			if test.modify != nil {
+				test.modify(r)
+			}
+
+			err = VerifySignature(r, test.secret, test.verifyAt)
+			if !errors.Is(err, test.expectedErr) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// AppProviderWithContext adapts an AppProvider without context support to AppProviderContext.
+// Only use it for providers whose calls can't be cancelled, see withContext.
+func AppProviderWithContext(p AppProvider) AppProviderContext {
+	return &appProviderAdapter{p: p}
+}
+
+type appProviderAdapter struct {
+	p AppProvider
+}
+
+func (a *appProviderAdapter) ListApps(ctx context.Context) ([]App, error) {
+	var res []App
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.ListApps()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (a *appProviderAdapter) GetApp(ctx context.Context, id string) (App, error) {
+	var res App
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetApp(id)
+		return err
+	})
+	if err != nil {
+		return App{}, err
+	}
+	return res, nil
+}
+
+// PipelineProviderWithContext adapts a PipelineProvider without context support to PipelineProviderContext.
+// Only use it for providers whose calls can't be cancelled, see withContext.
+func PipelineProviderWithContext(p PipelineProvider) PipelineProviderContext {
+	return &pipelineProviderAdapter{p: p}
+}
+
+type pipelineProviderAdapter struct {
+	p PipelineProvider
+}
+
+func (a *pipelineProviderAdapter) ListPipelines(ctx context.Context) ([]Pipeline, error) {
+	var res []Pipeline
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.ListPipelines()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (a *pipelineProviderAdapter) ListUpdates(ctx context.Context, since time.Time) (PipelineUpdates, error) {
+	var res PipelineUpdates
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.ListUpdates(since)
+		return err
+	})
+	if err != nil {
+		return PipelineUpdates{}, err
+	}
+	return res, nil
+}
+
+func (a *pipelineProviderAdapter) GetPipeline(ctx context.Context, id string) (Pipeline, error) {
+	var res Pipeline
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetPipeline(id)
+		return err
+	})
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return res, nil
+}
+
+func (a *pipelineProviderAdapter) GetHistory(ctx context.Context, id string, before time.Time, limit int) (PipelineStatusList, error) {
+	var res PipelineStatusList
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetHistory(id, before, limit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GroupProviderWithContext adapts a GroupProvider without context support to GroupProviderContext.
+// Only use it for providers whose calls can't be cancelled, see withContext.
+func GroupProviderWithContext(p GroupProvider) GroupProviderContext {
+	return &groupProviderAdapter{p: p}
+}
+
+type groupProviderAdapter struct {
+	p GroupProvider
+}
+
+func (a *groupProviderAdapter) ListGroups(ctx context.Context) ([]Group, error) {
+	var res []Group
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.ListGroups()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (a *groupProviderAdapter) GetGroup(ctx context.Context, id string) (Group, error) {
+	var res Group
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetGroup(id)
+		return err
+	})
+	if err != nil {
+		return Group{}, err
+	}
+	return res, nil
+}
+
+// RoutingProviderWithContext adapts a RoutingProvider without context support to RoutingProviderContext.
+// Only use it for providers whose calls can't be cancelled, see withContext.
+func RoutingProviderWithContext(p RoutingProvider) RoutingProviderContext {
+	return &routingProviderAdapter{p: p}
+}
+
+type routingProviderAdapter struct {
+	p RoutingProvider
+}
+
+func (a *routingProviderAdapter) GetAppRouting(ctx context.Context, id string) (AppRouting, error) {
+	var res AppRouting
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetAppRouting(id)
+		return err
+	})
+	if err != nil {
+		return AppRouting{}, err
+	}
+	return res, nil
+}
+
+// InstancesProviderWithContext adapts an InstancesProvider without context support to InstancesProviderContext.
+// Only use it for providers whose calls can't be cancelled, see withContext.
+func InstancesProviderWithContext(p InstancesProvider) InstancesProviderContext {
+	return &instancesProviderAdapter{p: p}
+}
+
+type instancesProviderAdapter struct {
+	p InstancesProvider
+}
+
+func (a *instancesProviderAdapter) GetAppInstances(ctx context.Context, id string) (AppInstances, error) {
+	var res AppInstances
+	err := withContext(ctx, func() (err error) {
+		res, err = a.p.GetAppInstances(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// withContext runs f unless the context is already done. If the context is done before f returns,
+// the error of the context is returned right away. This abandons the call rather than cancelling it:
+// f keeps running in its own goroutine until it returns on its own, so callers must not read anything
+// f writes to unless withContext returned no error. Providers that can pass the context on to their
+// backends should implement the context interfaces instead.
+func withContext(ctx context.Context, f func() error) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingAppProvider struct {
+	called  int32
+	release chan struct{}
+}
+
+func (b *blockingAppProvider) ListApps() ([]App, error) {
+	atomic.StoreInt32(&b.called, 1)
+	<-b.release
+	return []App{{Id: "app"}}, nil
+}
+
+func (b *blockingAppProvider) GetApp(id string) (App, error) {
+	return App{Id: id}, nil
+}
+
+func TestAppProviderWithContext(t *testing.T) {
+	p := &blockingAppProvider{release: make(chan struct{})}
+	a := AppProviderWithContext(p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	apps, err := a.ListApps(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("\nwanted err: %v\ngot: %v", context.DeadlineExceeded, err)
+	}
+	if apps != nil {
+		t.Errorf("expected no apps, got %v", apps)
+	}
+	close(p.release)
+
+	apps, err = a.ListApps(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal([]App{{Id: "app"}}, apps) {
+		t.Errorf("\ndiff between apps: \n%s\n", cmp.Diff([]App{{Id: "app"}}, apps))
+	}
+}
+
+func TestWithContextDoneBeforeCall(t *testing.T) {
+	p := &blockingAppProvider{release: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := AppProviderWithContext(p).ListApps(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("\nwanted err: %v\ngot: %v", context.Canceled, err)
+	}
+	if atomic.LoadInt32(&p.called) == 1 {
+		t.Errorf("expected provider not to be called with a done context")
+	}
+}
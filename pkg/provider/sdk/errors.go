@@ -9,3 +9,14 @@ var ErrQueryPerPageMalformed = errors.New("query parameter 'perPage' is malforme
 var ErrQuerySinceMalformed = errors.New("query parameter 'since' is malformed")
 var ErrQueryLimitMalformed = errors.New("query parameter 'limit' is malformed")
 var ErrInternal = errors.New("internal error occurred")
+var ErrIncompatibleProtocol = errors.New("incompatible protocol version")
+var ErrUnauthorized = errors.New("request is not authorized")
+var ErrSignatureExpired = errors.New("request signature expired")
+var ErrQueryFromMalformed = errors.New("query parameter 'from' is malformed")
+var ErrQueryToMalformed = errors.New("query parameter 'to' is malformed")
+var ErrQueryStepMalformed = errors.New("query parameter 'step' is malformed")
+var ErrMetricsQueryInvalid = errors.New("metrics query is invalid")
+var ErrBodyMalformed = errors.New("request body is malformed")
+var ErrScaleInvalid = errors.New("number of instances must not be negative")
+var ErrConflict = errors.New("action conflicts with the current state")
+var ErrRunMalformed = errors.New("run is malformed, it has to be the RFC3339 time the run started")
@@ -1,22 +1,98 @@
 package sdk
 
+import "context"
+
 type GroupProvider interface {
 	ListGroups() ([]Group, error)
 	GetGroup(id string) (Group, error)
 }
 
+// GroupProviderContext is the context aware version of GroupProvider.
+type GroupProviderContext interface {
+	ListGroups(ctx context.Context) ([]Group, error)
+	GetGroup(ctx context.Context, id string) (Group, error)
+}
+
 type GroupPage struct {
 	Pagination
 	Groups []Group `json:"groups"`
 }
 
 type Group struct {
-	Id      string   `json:"id" bson:"id"`
-	Name    string   `json:"name" bson:"name"`
+	Id   string `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+	// Parent is the id of the group this group is nested in. Members of a group are
+	// members of all of its ancestors as well.
+	Parent  string   `json:"parent,omitempty" bson:"parent,omitempty"`
 	Members []Member `json:"members,omitempty"`
 }
 
 type Member struct {
-	Id   string
-	Name string
+	Id        string `json:"id" bson:"id"`
+	Name      string `json:"name" bson:"name"`
+	Login     string `json:"login,omitempty" bson:"login,omitempty"`
+	Email     string `json:"email,omitempty" bson:"email,omitempty"`
+	AvatarUrl string `json:"avatarUrl,omitempty" bson:"avatarUrl,omitempty"`
+	// Role is the role of the member within the group as named by the provider,
+	// e.g. 'maintainer' for GitHub teams.
+	Role string `json:"role,omitempty" bson:"role,omitempty"`
+}
+
+// GroupList resolves the nesting of groups which reference each other by their parent ids.
+type GroupList []Group
+
+// Ancestors returns the ids of all groups the group is nested in, starting with its parent.
+func (l GroupList) Ancestors(id string) []string {
+	parents := make(map[string]string, len(l))
+	for _, group := range l {
+		parents[group.Id] = group.Parent
+	}
+
+	var res []string
+	seen := map[string]bool{id: true}
+	for parent := parents[id]; parent != "" && !seen[parent]; parent = parents[parent] {
+		seen[parent] = true
+		res = append(res, parent)
+	}
+	return res
+}
+
+// TransitiveMembers returns the members of the group and of all groups nested in it. A member
+// of several of these groups is listed once, with the role of the group closest to the given one.
+func (l GroupList) TransitiveMembers(id string) []Member {
+	children := make(map[string][]Group, len(l))
+	var root *Group
+	for i, group := range l {
+		if group.Id == id {
+			root = &l[i]
+		}
+		if group.Parent != "" {
+			children[group.Parent] = append(children[group.Parent], group)
+		}
+	}
+	if root == nil {
+		return nil
+	}
+
+	var res []Member
+	seenGroups := map[string]bool{}
+	seenMembers := map[string]bool{}
+	queue := []Group{*root}
+	for len(queue) > 0 {
+		group := queue[0]
+		queue = queue[1:]
+		if seenGroups[group.Id] {
+			continue
+		}
+		seenGroups[group.Id] = true
+
+		for _, member := range group.Members {
+			if !seenMembers[member.Id] {
+				seenMembers[member.Id] = true
+				res = append(res, member)
+			}
+		}
+		queue = append(queue, children[group.Id]...)
+	}
+	return res
 }
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+var nestedGroups = GroupList{
+	{Id: "org", Members: []Member{{Id: "owner", Role: "maintainer"}}},
+	{Id: "eng", Parent: "org", Members: []Member{{Id: "lead", Role: "maintainer"}, {Id: "dev-a", Role: "member"}}},
+	{Id: "backend", Parent: "eng", Members: []Member{{Id: "dev-a", Role: "maintainer"}, {Id: "dev-b", Role: "member"}}},
+	{Id: "frontend", Parent: "eng", Members: []Member{{Id: "dev-c", Role: "member"}}},
+	{Id: "other"},
+	{Id: "cycle-a", Parent: "cycle-b", Members: []Member{{Id: "dev-d"}}},
+	{Id: "cycle-b", Parent: "cycle-a", Members: []Member{{Id: "dev-e"}}},
+}
+
+func TestGroupListAncestors(t *testing.T) {
+	tests := []struct {
+		desc     string
+		id       string
+		expected []string
+	}{
+		{"lists ancestors", "backend", []string{"eng", "org"}},
+		{"lists no ancestors for root", "org", nil},
+		{"lists no ancestors for unknown group", "unknown", nil},
+		{"stops at cycles", "cycle-a", []string{"cycle-b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			res := nestedGroups.Ancestors(test.id)
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("diff: %s\n", cmp.Diff(test.expected, res))
+			}
+		})
+	}
+}
+
+func TestGroupListTransitiveMembers(t *testing.T) {
+	tests := []struct {
+		desc     string
+		id       string
+		expected []Member
+	}{
+		{"lists members of nested groups", "eng", []Member{
+			{Id: "lead", Role: "maintainer"},
+			{Id: "dev-a", Role: "member"},
+			{Id: "dev-b", Role: "member"},
+			{Id: "dev-c", Role: "member"},
+		}},
+		{"lists members of leaf group", "backend", []Member{
+			{Id: "dev-a", Role: "maintainer"},
+			{Id: "dev-b", Role: "member"},
+		}},
+		{"lists no members of empty group", "other", nil},
+		{"lists no members of unknown group", "unknown", nil},
+		{"stops at cycles", "cycle-a", []Member{{Id: "dev-d"}, {Id: "dev-e"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			res := nestedGroups.TransitiveMembers(test.id)
+			if !cmp.Equal(test.expected, res) {
+				tt.Errorf("diff: %s\n", cmp.Diff(test.expected, res))
+			}
+		})
+	}
+}
@@ -10,16 +10,53 @@ import (
 )
 
 type ProviderConfig struct {
-	Apps      AppProvider
-	Pipelines PipelineProvider
-	Groups    GroupProvider
-	Routing   RoutingProvider
-	Instances InstancesProvider
+	// Name is advertised to the core via the info endpoint.
+	Name string
+	// Auth configures which credentials the core has to present.
+	Auth AuthConfig
+
+	// Providers without context support can be adapted, e.g. with AppProviderWithContext.
+	Apps      AppProviderContext
+	Pipelines PipelineProviderContext
+	Groups    GroupProviderContext
+	Routing   RoutingProviderContext
+	Instances InstancesProviderContext
+	Logs      LogsProvider
+	Metrics   MetricsProvider
+	Alerts    AlertsProvider
+	StepLogs  StepLogsProvider
+	Topology  TopologyProvider
+
+	AppActions AppActionsProvider
+	// Jobs lets operators inspect and requeue failed reconcile jobs. It isn't advertised to the core.
+	Jobs JobsProvider
 }
 
 func ListenAndServe(addr string, p ProviderConfig) error {
+	if !p.Auth.TLS.Enabled() {
+		return http.ListenAndServe(addr, NewHandler(p))
+	}
+
+	tlsConfig, err := p.Auth.TLS.ServerTLS()
+	if err != nil {
+		return err
+	}
+
+	s := &http.Server{
+		Addr:      addr,
+		Handler:   NewHandler(p),
+		TLSConfig: tlsConfig,
+	}
+	return s.ListenAndServeTLS("", "")
+}
+
+// NewHandler serves all providers of the config, as well as the info endpoint describing them.
+// Requests without the credentials configured in Auth are rejected.
+func NewHandler(p ProviderConfig) http.Handler {
 	h := mux.NewRouter()
 
+	h.PathPrefix("/info").Handler(NewInfoHandler(p.Info()))
+
 	if p.Apps != nil {
 		h.PathPrefix("/apps").Handler(NewAppProviderHandler(p.Apps))
 	}
@@ -40,7 +77,35 @@ func ListenAndServe(addr string, p ProviderConfig) error {
 		h.PathPrefix("/instances").Handler(NewAppInstancesProviderHandler(p.Instances))
 	}
 
-	return http.ListenAndServe(addr, h)
+	if p.Logs != nil {
+		h.PathPrefix("/logs").Handler(NewLogsProviderHandler(p.Logs))
+	}
+
+	if p.Metrics != nil {
+		h.PathPrefix("/metrics").Handler(NewMetricsProviderHandler(p.Metrics))
+	}
+
+	if p.Alerts != nil {
+		h.PathPrefix("/alerts").Handler(NewAlertsProviderHandler(p.Alerts))
+	}
+
+	if p.StepLogs != nil {
+		h.PathPrefix("/steplogs").Handler(NewStepLogsProviderHandler(p.StepLogs))
+	}
+
+	if p.Topology != nil {
+		h.PathPrefix("/topology").Handler(NewTopologyProviderHandler(p.Topology))
+	}
+
+	if p.AppActions != nil {
+		h.PathPrefix("/actions").Handler(NewAppActionsProviderHandler(p.AppActions))
+	}
+
+	if p.Jobs != nil {
+		h.PathPrefix("/jobs").Handler(NewJobsProviderHandler(p.Jobs))
+	}
+
+	return NewAuthMiddleware(p.Auth, h)
 }
 
 type response struct {
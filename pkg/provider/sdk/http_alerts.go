@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"net/http"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeAlertsProvider(addr string, p AlertsProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Alerts: p})
+}
+
+func NewAlertsProviderHandler(p AlertsProvider) http.Handler {
+	h := &alertsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/alerts", h.listAlerts)
+	h.HandleFunc("/alerts/", h.listAlerts)
+
+	return h
+}
+
+type alertsProviderHandler struct {
+	*mux.Router
+
+	p AlertsProvider
+}
+
+func (h *alertsProviderHandler) listAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.p.ListAlerts(r.Context())
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, alerts)
+}
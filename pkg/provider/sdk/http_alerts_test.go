@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var alerts = []Alert{
+	{Id: "alert-a", AppId: "a", Team: "team-a", Kind: AlertKindAlert, Name: "HighErrorRate", Summary: "errors above 5%",
+		Severity: AlertSeverityCritical, Started: someTime, Labels: map[string]string{"env": "prod"}, Link: "http://alerts/a"},
+	{Id: "incident-b", AppId: "b", Kind: AlertKindIncident, Name: "Outage", Severity: AlertSeverityWarning, Started: someTime},
+}
+
+func TestAlerts(t *testing.T) {
+	tests := []struct {
+		desc           string
+		state          []Alert
+		err            error
+		path           string
+		expectedStatus int
+		expectedResp   response
+	}{
+		{desc: "returns alerts", state: alerts, path: "/alerts", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"id": "alert-a", "appId": "a", "team": "team-a", "kind": "alert", "name": "HighErrorRate",
+					"summary": "errors above 5%", "severity": "critical", "started": "2006-01-01T15:00:00Z",
+					"labels": map[string]interface{}{"env": "prod"}, "link": "http://alerts/a"},
+				map[string]interface{}{"id": "incident-b", "appId": "b", "kind": "incident", "name": "Outage",
+					"severity": "warning", "started": "2006-01-01T15:00:00Z"},
+			},
+		}},
+		{desc: "returns alerts with trailing slash", state: []Alert{}, path: "/alerts/", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{},
+		}},
+		{desc: "returns 5xx for errors", err: ErrInternal, path: "/alerts", expectedStatus: http.StatusInternalServerError, expectedResp: response{
+			Status: http.StatusInternalServerError,
+			Err:    "internal error occurred",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			handler := NewAlertsProviderHandler(&fakeAlertsProvider{state: test.state, err: test.err})
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+		})
+	}
+}
+
+type fakeAlertsProvider struct {
+	err   error
+	state []Alert
+}
+
+func (f *fakeAlertsProvider) ListAlerts(ctx context.Context) ([]Alert, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.state, nil
+}
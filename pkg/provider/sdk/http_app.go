@@ -3,18 +3,20 @@ package sdk
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 import "github.com/gorilla/mux"
 
 func ListenAndServeAppProvider(addr string, p AppProvider) error {
-	return ListenAndServe(addr, ProviderConfig{Apps: p})
+	return ListenAndServe(addr, ProviderConfig{Apps: AppProviderWithContext(p)})
 }
 
-func NewAppProviderHandler(p AppProvider) http.Handler {
+func NewAppProviderHandler(p AppProviderContext) http.Handler {
 	h := &appProviderHandler{Router: mux.NewRouter(), p: p}
 
 	h.HandleFunc("/apps", h.listApps)
 	h.HandleFunc("/apps/", h.listApps)
+	h.HandleFunc("/apps/updates", h.listAppUpdates)
 	h.HandleFunc("/apps/{id:[0-9a-z-]+}", h.getApp)
 
 	return h
@@ -23,11 +25,16 @@ func NewAppProviderHandler(p AppProvider) http.Handler {
 type appProviderHandler struct {
 	*mux.Router
 
-	p AppProvider
+	p AppProviderContext
 }
 
 func (h *appProviderHandler) listApps(w http.ResponseWriter, r *http.Request) {
-	apps, err := h.p.ListApps()
+	if r.FormValue("perPage") != "" {
+		h.listAppsPage(w, r)
+		return
+	}
+
+	apps, err := h.p.ListApps(r.Context())
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, err)
 		return
@@ -35,9 +42,64 @@ func (h *appProviderHandler) listApps(w http.ResponseWriter, r *http.Request) {
 	respondOk(w, apps)
 }
 
+// listAppsPage serves a single page of apps. Providers that don't implement AppUpdatesProvider
+// are paged in memory.
+func (h *appProviderHandler) listAppsPage(w http.ResponseWriter, r *http.Request) {
+	perPage, err := defaultQueryInt(r, "perPage", 0)
+	if err != nil || perPage <= 0 {
+		respondErr(w, http.StatusBadRequest, ErrQueryPerPageMalformed)
+		return
+	}
+	page, err := defaultQueryInt(r, "page", 0)
+	if err != nil || page < 0 {
+		respondErr(w, http.StatusBadRequest, ErrQueryPageMalformed)
+		return
+	}
+
+	var res AppPage
+	if p, ok := h.p.(AppUpdatesProvider); ok {
+		res, err = p.ListAppsPage(r.Context(), perPage, page)
+	} else {
+		var apps []App
+		apps, err = h.p.ListApps(r.Context())
+		if err == nil {
+			res, err = PageApps(apps, perPage, page)
+		}
+	}
+	if errors.Is(err, ErrPageExceeded) {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, res)
+}
+
+func (h *appProviderHandler) listAppUpdates(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.p.(AppUpdatesProvider)
+	if !ok {
+		respondErr(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	since, err := defaultQueryTime(r, "since", time.Time{})
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, ErrQuerySinceMalformed)
+		return
+	}
+
+	updates, err := p.ListAppUpdates(r.Context(), since)
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, updates)
+}
+
 func (h *appProviderHandler) getApp(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	app, err := h.p.GetApp(id)
+	app, err := h.p.GetApp(r.Context(), id)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeAppActionsProvider(addr string, p AppActionsProvider) error {
+	return ListenAndServe(addr, ProviderConfig{AppActions: p})
+}
+
+func NewAppActionsProviderHandler(p AppActionsProvider) http.Handler {
+	h := &appActionsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/actions/{id:[0-9a-z-]+}/restart", h.restartApp).Methods("POST")
+	h.HandleFunc("/actions/{id:[0-9a-z-]+}/stop", h.stopApp).Methods("POST")
+	h.HandleFunc("/actions/{id:[0-9a-z-]+}/start", h.startApp).Methods("POST")
+	h.HandleFunc("/actions/{id:[0-9a-z-]+}/scale", h.scaleApp).Methods("POST")
+	h.HandleFunc("/actions/{id:[0-9a-z-]+}/instances/{index:[0-9]+}/restart", h.restartAppInstance).Methods("POST")
+
+	return h
+}
+
+type appActionsProviderHandler struct {
+	*mux.Router
+
+	p AppActionsProvider
+}
+
+func (h *appActionsProviderHandler) restartApp(w http.ResponseWriter, r *http.Request) {
+	respondAction(w, h.p.RestartApp(r.Context(), mux.Vars(r)["id"]))
+}
+
+func (h *appActionsProviderHandler) stopApp(w http.ResponseWriter, r *http.Request) {
+	respondAction(w, h.p.StopApp(r.Context(), mux.Vars(r)["id"]))
+}
+
+func (h *appActionsProviderHandler) startApp(w http.ResponseWriter, r *http.Request) {
+	respondAction(w, h.p.StartApp(r.Context(), mux.Vars(r)["id"]))
+}
+
+func (h *appActionsProviderHandler) scaleApp(w http.ResponseWriter, r *http.Request) {
+	req := AppScaleRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, ErrBodyMalformed)
+		return
+	}
+
+	err = req.Validate()
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondAction(w, h.p.ScaleApp(r.Context(), mux.Vars(r)["id"], req.Instances))
+}
+
+func (h *appActionsProviderHandler) restartAppInstance(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// the route only matches digits, so this can only fail on overflow
+	index, err := strconv.Atoi(vars["index"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	respondAction(w, h.p.RestartAppInstance(r.Context(), vars["id"], index))
+}
+
+func respondAction(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, nil)
+}
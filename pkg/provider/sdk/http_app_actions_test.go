@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAppActions(t *testing.T) {
+	tests := []struct {
+		desc           string
+		err            error
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedResp   response
+		expectedCalls  []string
+	}{
+		{desc: "restarts app", path: "/actions/a/restart", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"restart a"}},
+		{desc: "stops app", path: "/actions/a/stop", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"stop a"}},
+		{desc: "starts app", path: "/actions/a/start", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"start a"}},
+		{desc: "scales app", path: "/actions/a/scale", body: `{"instances": 3}`, expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"scale a 3"}},
+		{desc: "restarts app instance", path: "/actions/a/instances/2/restart", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"restart a 2"}},
+		{desc: "returns 400 for malformed scale", path: "/actions/a/scale", body: `{"instances": "many"}`, expectedStatus: http.StatusBadRequest,
+			expectedResp: response{Status: http.StatusBadRequest, Err: ErrBodyMalformed.Error()}},
+		{desc: "returns 400 for negative scale", path: "/actions/a/scale", body: `{"instances": -1}`, expectedStatus: http.StatusBadRequest,
+			expectedResp: response{Status: http.StatusBadRequest, Err: ErrScaleInvalid.Error()}},
+		{desc: "returns 404 for non-existent", err: ErrNotFound, path: "/actions/dont-exist/restart", expectedStatus: http.StatusNotFound,
+			expectedResp: response{Status: http.StatusNotFound, Err: "not found"}, expectedCalls: []string{"restart dont-exist"}},
+		{desc: "returns 5xx for other errors", err: ErrInternal, path: "/actions/a/stop", expectedStatus: http.StatusInternalServerError,
+			expectedResp: response{Status: http.StatusInternalServerError, Err: "internal error occurred"}, expectedCalls: []string{"stop a"}},
+		{desc: "rejects other methods", method: "GET", path: "/actions/a/restart", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			method := test.method
+			if method == "" {
+				method = "POST"
+			}
+
+			r := httptest.NewRecorder()
+			p := &fakeAppActionsProvider{err: test.err}
+			handler := NewAppActionsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest(method, test.path, strings.NewReader(test.body)))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if !cmp.Equal(test.expectedCalls, p.calls) {
+				tt.Errorf("\ndiff between calls: \n%s\n", cmp.Diff(test.expectedCalls, p.calls))
+			}
+		})
+	}
+}
+
+type fakeAppActionsProvider struct {
+	err   error
+	calls []string
+}
+
+func (f *fakeAppActionsProvider) RestartApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "restart "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) StopApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "stop "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) StartApp(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "start "+id)
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) ScaleApp(ctx context.Context, id string, instances int) error {
+	f.calls = append(f.calls, "scale "+id+" "+strconv.Itoa(instances))
+	return f.err
+}
+
+func (f *fakeAppActionsProvider) RestartAppInstance(ctx context.Context, id string, index int) error {
+	f.calls = append(f.calls, "restart "+id+" "+strconv.Itoa(index))
+	return f.err
+}
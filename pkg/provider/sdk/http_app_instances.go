@@ -6,11 +6,11 @@ import (
 )
 import "github.com/gorilla/mux"
 
-func ListenAndServeAppInstancesProvider(addr string, p AppProvider) error {
-	return ListenAndServe(addr, ProviderConfig{Apps: p})
+func ListenAndServeAppInstancesProvider(addr string, p InstancesProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Instances: InstancesProviderWithContext(p)})
 }
 
-func NewAppInstancesProviderHandler(p InstancesProvider) http.Handler {
+func NewAppInstancesProviderHandler(p InstancesProviderContext) http.Handler {
 	h := &appInstancesProviderHandler{Router: mux.NewRouter(), p: p}
 
 	h.HandleFunc("/instances/{id:[0-9a-z-]+}", h.getAppInstances)
@@ -21,12 +21,12 @@ func NewAppInstancesProviderHandler(p InstancesProvider) http.Handler {
 type appInstancesProviderHandler struct {
 	*mux.Router
 
-	p InstancesProvider
+	p InstancesProviderContext
 }
 
 func (h *appInstancesProviderHandler) getAppInstances(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	app, err := h.p.GetAppInstances(id)
+	app, err := h.p.GetAppInstances(r.Context(), id)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/go-cmp/cmp"
 	"net/http"
@@ -14,6 +15,15 @@ var instances = map[string]AppInstances{
 			State: "stopped",
 			Since: someTime,
 		},
+		{
+			Index:    1,
+			State:    "running",
+			Since:    someTime,
+			Host:     "cell-a",
+			Uptime:   60,
+			Restarts: 2,
+			Usage:    &InstanceUsage{Cpu: 0.5, Memory: 128, MemoryQuota: 256, Disk: 512},
+		},
 	}}
 
 func TestAppInstances(t *testing.T) {
@@ -29,7 +39,11 @@ func TestAppInstances(t *testing.T) {
 		{desc: "returns app instances", state: instances, method: "GET", path: "/instances/a", expectedStatus: http.StatusOK, expectedResp: response{
 			Status: http.StatusOK,
 			Result: []interface{}{
-				map[string]interface{}{"since": "2006-01-01T15:00:00Z", "state": "stopped"},
+				map[string]interface{}{"index": float64(0), "since": "2006-01-01T15:00:00Z", "state": "stopped"},
+				map[string]interface{}{"index": float64(1), "since": "2006-01-01T15:00:00Z", "state": "running",
+					"host": "cell-a", "uptime": float64(60), "restarts": float64(2), "usage": map[string]interface{}{
+						"cpu": 0.5, "memory": float64(128), "memoryQuota": float64(256), "disk": float64(512),
+					}},
 			},
 		}},
 		{desc: "returns 404 for non-existent", state: instances, method: "GET", path: "/instances/dont-exist", expectedStatus: http.StatusNotFound, expectedResp: response{
@@ -69,7 +83,7 @@ type fakeInstancesProvider struct {
 	err   error
 }
 
-func (f *fakeInstancesProvider) GetAppInstances(id string) (AppInstances, error) {
+func (f *fakeInstancesProvider) GetAppInstances(ctx context.Context, id string) (AppInstances, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -6,11 +6,11 @@ import (
 )
 import "github.com/gorilla/mux"
 
-func ListenAndServeAppRoutingProvider(addr string, p AppProvider) error {
-	return ListenAndServe(addr, ProviderConfig{Apps: p})
+func ListenAndServeAppRoutingProvider(addr string, p RoutingProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Routing: RoutingProviderWithContext(p)})
 }
 
-func NewAppRoutingProviderHandler(p RoutingProvider) http.Handler {
+func NewAppRoutingProviderHandler(p RoutingProviderContext) http.Handler {
 	h := &appRoutingProviderHandler{Router: mux.NewRouter(), p: p}
 
 	h.HandleFunc("/routing/{id:[0-9a-z-]+}", h.getAppRouting)
@@ -21,12 +21,12 @@ func NewAppRoutingProviderHandler(p RoutingProvider) http.Handler {
 type appRoutingProviderHandler struct {
 	*mux.Router
 
-	p RoutingProvider
+	p RoutingProviderContext
 }
 
 func (h *appRoutingProviderHandler) getAppRouting(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	app, err := h.p.GetAppRouting(id)
+	app, err := h.p.GetAppRouting(r.Context(), id)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
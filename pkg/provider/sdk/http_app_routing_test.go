@@ -1,11 +1,13 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/go-cmp/cmp"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 var routes = map[string]AppRoutes{
@@ -15,6 +17,15 @@ var routes = map[string]AppRoutes{
 			Path:    "path",
 			AppPort: 900,
 		},
+		{
+			Host:     "app.example.com",
+			Path:     "/",
+			AppPort:  8080,
+			Domain:   "example.com",
+			Protocol: RouteProtocolHttp,
+			Weight:   20,
+			Tls:      &RouteTls{Enabled: true, Expires: &someTime},
+		},
 	}}
 
 func TestAppRouting(t *testing.T) {
@@ -31,6 +42,9 @@ func TestAppRouting(t *testing.T) {
 			Status: http.StatusOK,
 			Result: map[string]interface{}{"routes": []interface{}{
 				map[string]interface{}{"host": "host", "path": "path", "appPort": float64(900)},
+				map[string]interface{}{"host": "app.example.com", "path": "/", "appPort": float64(8080), "domain": "example.com",
+					"protocol": "http", "weight": float64(20), "tls": map[string]interface{}{"enabled": true, "expires": "2006-01-01T15:00:00Z"},
+				},
 			}},
 		}},
 		{desc: "returns 404 for non-existent", state: routes, method: "GET", path: "/routing/dont-exist", expectedStatus: http.StatusNotFound, expectedResp: response{
@@ -70,7 +84,7 @@ type fakeRoutingProvider struct {
 	err   error
 }
 
-func (f *fakeRoutingProvider) GetAppRouting(id string) (AppRouting, error) {
+func (f *fakeRoutingProvider) GetAppRouting(ctx context.Context, id string) (AppRouting, error) {
 	if f.err != nil {
 		return AppRouting{}, f.err
 	}
@@ -81,3 +95,25 @@ func (f *fakeRoutingProvider) GetAppRouting(id string) (AppRouting, error) {
 	}
 	return AppRouting{}, ErrNotFound
 }
+
+func TestCertificateExpiresBefore(t *testing.T) {
+	tests := []struct {
+		desc     string
+		route    AppRoute
+		at       time.Time
+		expected bool
+	}{
+		{"without tls", AppRoute{}, someTime, false},
+		{"without known expiry", AppRoute{Tls: &RouteTls{Enabled: true}}, someTime, false},
+		{"expires before", AppRoute{Tls: &RouteTls{Enabled: true, Expires: &someTime}}, someTime.Add(time.Hour), true},
+		{"expires after", AppRoute{Tls: &RouteTls{Enabled: true, Expires: &someTime}}, someTime, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			if test.route.CertificateExpiresBefore(test.at) != test.expected {
+				tt.Errorf("expected %v", test.expected)
+			}
+		})
+	}
+}
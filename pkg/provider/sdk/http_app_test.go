@@ -1,11 +1,13 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/go-cmp/cmp"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 var apps = []App{
@@ -68,6 +70,42 @@ func TestApps(t *testing.T) {
 			Status: http.StatusInternalServerError,
 			Err:    "internal error occurred",
 		}},
+		{desc: "returns page of apps", state: apps, method: "GET", path: "/apps?perPage=4&page=2", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"totalResults": float64(11),
+				"totalPages":   float64(3),
+				"perPage":      float64(4),
+				"page":         float64(2),
+				"apps": []interface{}{
+					map[string]interface{}{"id": "i", "name": "name-i"},
+					map[string]interface{}{"id": "j", "name": "name-j"},
+					map[string]interface{}{"id": "840e560f-38d3-460e-be23-8677a4539f35", "name": "name-k"},
+				},
+			},
+		}},
+		{desc: "returns empty first page", state: []App{}, method: "GET", path: "/apps?perPage=4", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"totalResults": float64(0),
+				"totalPages":   float64(0),
+				"perPage":      float64(4),
+				"page":         float64(0),
+				"apps":         []interface{}{},
+			},
+		}},
+		{desc: "returns 400 for exceeded page", state: apps, method: "GET", path: "/apps?perPage=4&page=3", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrPageExceeded.Error(),
+		}},
+		{desc: "returns 400 for malformed perPage", state: apps, method: "GET", path: "/apps?perPage=abc", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryPerPageMalformed.Error(),
+		}},
+		{desc: "returns 404 for updates if not supported", state: apps, method: "GET", path: "/apps/updates", expectedStatus: http.StatusNotFound, expectedResp: response{
+			Status: http.StatusNotFound,
+			Err:    "not found",
+		}},
 	}
 
 	for _, test := range tests {
@@ -90,19 +128,88 @@ func TestApps(t *testing.T) {
 
 }
 
+func TestAppUpdates(t *testing.T) {
+	deleted, _ := time.Parse(time.RFC3339, "2021-01-01T15:00:00Z")
+	tests := []struct {
+		desc           string
+		path           string
+		expectedStatus int
+		expectedSince  time.Time
+		expectedResp   response
+	}{
+		{desc: "returns updates", path: "/apps/updates?since=2021-01-01T12:00:00Z", expectedStatus: http.StatusOK, expectedSince: deleted.Add(-3 * time.Hour), expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"apps": []interface{}{
+					map[string]interface{}{"id": "a", "name": "name-a"},
+				},
+				"deleted": []interface{}{
+					map[string]interface{}{"id": "b", "deleted": "2021-01-01T15:00:00Z"},
+				},
+			},
+		}},
+		{desc: "returns 400 for malformed since", path: "/apps/updates?since=abc", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQuerySinceMalformed.Error(),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			f := &fakeAppUpdatesProvider{updates: AppUpdates{
+				Apps:    []App{{Id: "a", Name: "name-a"}},
+				Deleted: []AppTombstone{{Id: "b", Deleted: deleted}},
+			}}
+
+			r := httptest.NewRecorder()
+			handler := NewAppProviderHandler(f)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted stats %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if !f.since.Equal(test.expectedSince) {
+				tt.Errorf("\nwanted since %v\n   got %v", test.expectedSince, f.since)
+			}
+		})
+	}
+}
+
+type fakeAppUpdatesProvider struct {
+	fakeAppProvider
+	updates AppUpdates
+	since   time.Time
+}
+
+func (f *fakeAppUpdatesProvider) ListAppsPage(ctx context.Context, perPage int, page int) (AppPage, error) {
+	return PageApps(f.state, perPage, page)
+}
+
+func (f *fakeAppUpdatesProvider) ListAppUpdates(ctx context.Context, since time.Time) (AppUpdates, error) {
+	f.since = since
+	return f.updates, nil
+}
+
 type fakeAppProvider struct {
 	state []App
 	err   error
 }
 
-func (f *fakeAppProvider) ListApps() ([]App, error) {
+func (f *fakeAppProvider) ListApps(ctx context.Context) ([]App, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
 	return f.state, nil
 }
 
-func (f *fakeAppProvider) GetApp(id string) (App, error) {
+func (f *fakeAppProvider) GetApp(ctx context.Context, id string) (App, error) {
 	if f.err != nil {
 		return App{}, f.err
 	}
@@ -7,10 +7,10 @@ import (
 import "github.com/gorilla/mux"
 
 func ListenAndServeGroupProvider(addr string, p GroupProvider) error {
-	return ListenAndServe(addr, ProviderConfig{Groups: p})
+	return ListenAndServe(addr, ProviderConfig{Groups: GroupProviderWithContext(p)})
 }
 
-func NewGroupProviderHandler(p GroupProvider) http.Handler {
+func NewGroupProviderHandler(p GroupProviderContext) http.Handler {
 	h := &groupProviderHandler{Router: mux.NewRouter(), p: p}
 
 	h.HandleFunc("/groups", h.listGroups)
@@ -23,11 +23,11 @@ func NewGroupProviderHandler(p GroupProvider) http.Handler {
 type groupProviderHandler struct {
 	*mux.Router
 
-	p GroupProvider
+	p GroupProviderContext
 }
 
 func (h *groupProviderHandler) listGroups(w http.ResponseWriter, r *http.Request) {
-	apps, err := h.p.ListGroups()
+	apps, err := h.p.ListGroups(r.Context())
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, err)
 		return
@@ -37,7 +37,7 @@ func (h *groupProviderHandler) listGroups(w http.ResponseWriter, r *http.Request
 
 func (h *groupProviderHandler) getGroup(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	app, err := h.p.GetGroup(id)
+	app, err := h.p.GetGroup(r.Context(), id)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/google/go-cmp/cmp"
 	"net/http"
@@ -95,14 +96,14 @@ type fakeGroupProvider struct {
 	state []Group
 }
 
-func (f *fakeGroupProvider) ListGroups() ([]Group, error) {
+func (f *fakeGroupProvider) ListGroups(ctx context.Context) ([]Group, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
 	return f.state, nil
 }
 
-func (f *fakeGroupProvider) GetGroup(id string) (Group, error) {
+func (f *fakeGroupProvider) GetGroup(ctx context.Context, id string) (Group, error) {
 	if f.err != nil {
 		return Group{}, f.err
 	}
@@ -0,0 +1,25 @@
+package sdk
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func NewInfoHandler(info ProviderInfo) http.Handler {
+	h := &infoHandler{Router: mux.NewRouter(), info: info}
+
+	h.HandleFunc("/info", h.getInfo)
+	h.HandleFunc("/info/", h.getInfo)
+
+	return h
+}
+
+type infoHandler struct {
+	*mux.Router
+
+	info ProviderInfo
+}
+
+func (h *infoHandler) getInfo(w http.ResponseWriter, r *http.Request) {
+	respondOk(w, h.info)
+}
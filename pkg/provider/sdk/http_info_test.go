@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInfo(t *testing.T) {
+	tests := []struct {
+		desc         string
+		config       ProviderConfig
+		expectedResp response
+	}{
+		{desc: "advertises all features", config: ProviderConfig{
+			Name:      "all",
+			Apps:      &fakeAppProvider{},
+			Pipelines: &fakePipelineProvider{},
+			Groups:    &fakeGroupProvider{},
+			Routing:   &fakeRoutingProvider{},
+			Instances: &fakeInstancesProvider{},
+			Logs:      &fakeLogsProvider{},
+			Metrics:   &fakeMetricsProvider{},
+			Alerts:    &fakeAlertsProvider{},
+			StepLogs:  &fakeStepLogsProvider{},
+			Topology:  &fakeTopologyProvider{},
+
+			AppActions: &fakeAppActionsProvider{},
+		}, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"name":            "all",
+				"protocolVersion": float64(ProtocolVersion),
+				"features":        []interface{}{"apps", "pipelines", "groups", "routing", "instances", "logs", "metrics", "alerts", "stepLogs", "topology", "appActions"},
+			},
+		}},
+		{desc: "advertises only configured features", config: ProviderConfig{
+			Name:      "some",
+			Groups:    &fakeGroupProvider{},
+			Instances: &fakeInstancesProvider{},
+		}, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"name":            "some",
+				"protocolVersion": float64(ProtocolVersion),
+				"features":        []interface{}{"groups", "instances"},
+			},
+		}},
+		{desc: "advertises app updates", config: ProviderConfig{
+			Name: "updates",
+			Apps: &fakeAppUpdatesProvider{},
+		}, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"name":            "updates",
+				"protocolVersion": float64(ProtocolVersion),
+				"features":        []interface{}{"apps", "appUpdates"},
+			},
+		}},
+		{desc: "advertises pipeline actions", config: ProviderConfig{
+			Name:      "actions",
+			Pipelines: &fakePipelineActionsProvider{},
+		}, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"name":            "actions",
+				"protocolVersion": float64(ProtocolVersion),
+				"features":        []interface{}{"pipelines", "pipelineActions"},
+			},
+		}},
+		{desc: "advertises no features", config: ProviderConfig{}, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"name":            "",
+				"protocolVersion": float64(ProtocolVersion),
+				"features":        []interface{}{},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			handler := NewHandler(test.config)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", "/info", nil))
+			res := r.Result()
+			if res.StatusCode != http.StatusOK {
+				tt.Errorf("\nwanted status %v\n   got %v", http.StatusOK, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+		})
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		desc    string
+		version int
+		err     error
+	}{
+		{desc: "current version", version: ProtocolVersion},
+		{desc: "too old", version: MinProtocolVersion - 1, err: ErrIncompatibleProtocol},
+		{desc: "too new", version: ProtocolVersion + 1, err: ErrIncompatibleProtocol},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := ProviderInfo{Name: "p", ProtocolVersion: test.version}.CheckCompatible()
+			if !errors.Is(err, test.err) {
+				tt.Errorf("\nwanted err: %v\ngot: %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"net/http"
+)
+import "github.com/gorilla/mux"
+
+func NewJobsProviderHandler(p JobsProvider) http.Handler {
+	h := &jobsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/jobs/dead-letters", h.listDeadLetters).Methods("GET")
+	h.HandleFunc("/jobs/dead-letters/{type:[0-9a-z-]+}/{guid}/requeue", h.requeueDeadLetter).Methods("POST")
+
+	return h
+}
+
+type jobsProviderHandler struct {
+	*mux.Router
+
+	p JobsProvider
+}
+
+func (h *jobsProviderHandler) listDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := h.p.ListDeadLetters(r.Context())
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, deadLetters)
+}
+
+func (h *jobsProviderHandler) requeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	respondAction(w, h.p.RequeueDeadLetter(r.Context(), vars["type"], vars["guid"]))
+}
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJobs(t *testing.T) {
+	lastAttempt := time.Date(2006, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc           string
+		state          []DeadLetter
+		err            error
+		method         string
+		path           string
+		expectedStatus int
+		expectedResp   response
+		expectedCalls  []string
+	}{
+		{desc: "lists dead letters", method: "GET", path: "/jobs/dead-letters", state: []DeadLetter{
+			{Type: "orgs", Guid: "a", Attempts: 8, LastError: "some error", LastAttempt: lastAttempt},
+		}, expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"type": "orgs", "guid": "a", "attempts": float64(8), "lastError": "some error",
+					"lastAttempt": "2006-01-01T15:00:00Z"},
+			},
+		}},
+		{desc: "returns 5xx if listing fails", err: ErrInternal, method: "GET", path: "/jobs/dead-letters",
+			expectedStatus: http.StatusInternalServerError,
+			expectedResp:   response{Status: http.StatusInternalServerError, Err: "internal error occurred"}},
+		{desc: "requeues dead letter", method: "POST", path: "/jobs/dead-letters/orgs/a/requeue",
+			expectedStatus: http.StatusOK, expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"orgs/a"}},
+		{desc: "returns 404 for non-existent", err: ErrNotFound, method: "POST", path: "/jobs/dead-letters/orgs/b/requeue",
+			expectedStatus: http.StatusNotFound, expectedResp: response{Status: http.StatusNotFound, Err: "not found"},
+			expectedCalls: []string{"orgs/b"}},
+		{desc: "rejects other methods", method: "GET", path: "/jobs/dead-letters/orgs/a/requeue", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			p := &fakeJobsProvider{state: test.state, err: test.err}
+			handler := NewJobsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest(test.method, test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+			if !cmp.Equal(test.expectedCalls, p.calls) {
+				tt.Errorf("\ndiff between calls: \n%s\n", cmp.Diff(test.expectedCalls, p.calls))
+			}
+		})
+	}
+}
+
+type fakeJobsProvider struct {
+	err   error
+	state []DeadLetter
+	calls []string
+}
+
+func (f *fakeJobsProvider) ListDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.state, nil
+}
+
+func (f *fakeJobsProvider) RequeueDeadLetter(ctx context.Context, jobType string, guid string) error {
+	f.calls = append(f.calls, jobType+"/"+guid)
+	return f.err
+}
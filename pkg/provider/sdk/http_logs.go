@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeLogsProvider(addr string, p LogsProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Logs: p})
+}
+
+func NewLogsProviderHandler(p LogsProvider) http.Handler {
+	h := &logsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/logs/{id:[0-9a-z-]+}", h.getRecentLogs)
+	h.HandleFunc("/logs/{id:[0-9a-z-]+}/tail", h.tailLogs)
+
+	return h
+}
+
+type logsProviderHandler struct {
+	*mux.Router
+
+	p LogsProvider
+}
+
+func (h *logsProviderHandler) getRecentLogs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	limit, err := defaultQueryInt(r, "limit", 100)
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, ErrQueryLimitMalformed)
+		return
+	}
+
+	since, err := defaultQueryTime(r, "since", time.Time{})
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, ErrQuerySinceMalformed)
+		return
+	}
+
+	logs, err := h.p.GetRecentLogs(r.Context(), id, since, limit)
+	if errors.Is(err, ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondOk(w, logs)
+}
+
+// tailLogs streams the log lines as newline delimited json until the client goes away.
+// Errors that occur before the first line is sent are answered with the usual envelope.
+func (h *logsProviderHandler) tailLogs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondErr(w, http.StatusInternalServerError, ErrInternal)
+		return
+	}
+
+	lines := make(chan LogLine)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- h.p.TailLogs(r.Context(), id, lines)
+	}()
+
+	started := false
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case line := <-lines:
+			if !started {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				w.WriteHeader(http.StatusOK)
+				started = true
+			}
+			if enc.Encode(line) != nil {
+				// the client went away, TailLogs stops with the request context
+				return
+			}
+			flusher.Flush()
+		case err := <-errs:
+			if started {
+				return
+			}
+			if errors.Is(err, ErrNotFound) {
+				respondErr(w, http.StatusNotFound, err)
+			} else if err != nil && r.Context().Err() == nil {
+				respondErr(w, http.StatusInternalServerError, err)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var logs = map[string]AppLogs{
+	"a": {
+		{Time: someTime, Instance: "0", Source: "APP", Stream: LogStreamOut, Message: "first"},
+		{Time: someTime.Add(time.Second), Instance: "1", Stream: LogStreamErr, Message: "second"},
+	},
+}
+
+func TestRecentLogs(t *testing.T) {
+	tests := []struct {
+		desc           string
+		state          map[string]AppLogs
+		err            error
+		path           string
+		expectedStatus int
+		expectedResp   response
+		expectedSince  time.Time
+		expectedLimit  int
+	}{
+		{desc: "returns recent logs", state: logs, path: "/logs/a", expectedStatus: http.StatusOK, expectedLimit: 100, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"time": "2006-01-01T15:00:00Z", "instance": "0", "source": "APP", "stream": "stdout", "message": "first"},
+				map[string]interface{}{"time": "2006-01-01T15:00:01Z", "instance": "1", "stream": "stderr", "message": "second"},
+			},
+		}},
+		{desc: "passes since and limit", state: logs, path: "/logs/a?since=2006-01-01T15:00:00Z&limit=1", expectedStatus: http.StatusOK, expectedSince: someTime, expectedLimit: 1, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"time": "2006-01-01T15:00:00Z", "instance": "0", "source": "APP", "stream": "stdout", "message": "first"},
+			},
+		}},
+		{desc: "returns 400 for malformed limit", state: logs, path: "/logs/a?limit=abc", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryLimitMalformed.Error(),
+		}},
+		{desc: "returns 400 for malformed since", state: logs, path: "/logs/a?since=yesterday", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQuerySinceMalformed.Error(),
+		}},
+		{desc: "returns 404 for non-existent", state: logs, path: "/logs/dont-exist", expectedStatus: http.StatusNotFound, expectedLimit: 100, expectedResp: response{
+			Status: http.StatusNotFound,
+			Err:    "not found",
+		}},
+		{desc: "returns 5xx for other errors", state: logs, err: ErrInternal, path: "/logs/a", expectedStatus: http.StatusInternalServerError, expectedLimit: 100, expectedResp: response{
+			Status: http.StatusInternalServerError,
+			Err:    "internal error occurred",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			p := &fakeLogsProvider{state: test.state, err: test.err}
+			handler := NewLogsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if !test.expectedSince.Equal(p.since) || test.expectedLimit != p.limit {
+				tt.Errorf("\nwanted since %v and limit %d\n   got %v and %d", test.expectedSince, test.expectedLimit, p.since, p.limit)
+			}
+		})
+	}
+}
+
+func TestTailLogs(t *testing.T) {
+	tests := []struct {
+		desc           string
+		state          map[string]AppLogs
+		err            error
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{desc: "streams log lines", state: logs, path: "/logs/a/tail", expectedStatus: http.StatusOK, expectedBody: "" +
+			`{"time":"2006-01-01T15:00:00Z","instance":"0","source":"APP","stream":"stdout","message":"first"}` + "\n" +
+			`{"time":"2006-01-01T15:00:01Z","instance":"1","stream":"stderr","message":"second"}` + "\n",
+		},
+		{desc: "returns 404 for non-existent", state: logs, path: "/logs/dont-exist/tail", expectedStatus: http.StatusNotFound, expectedBody: `{"status":404,"error":"not found"}`},
+		{desc: "returns 5xx for other errors", state: logs, err: ErrInternal, path: "/logs/a/tail", expectedStatus: http.StatusInternalServerError, expectedBody: `{"status":500,"error":"internal error occurred"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			p := &fakeLogsProvider{state: test.state, err: test.err}
+			handler := NewLogsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			body := r.Body.String()
+			if strings.TrimSpace(test.expectedBody) != strings.TrimSpace(body) {
+				tt.Errorf("\ndiff between bodies: \n%s\n", cmp.Diff(test.expectedBody, body))
+			}
+		})
+	}
+}
+
+type fakeLogsProvider struct {
+	state map[string]AppLogs
+	err   error
+	since time.Time
+	limit int
+}
+
+func (f *fakeLogsProvider) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (AppLogs, error) {
+	f.since = since
+	f.limit = limit
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	l, ok := f.state[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if len(l) > limit {
+		l = l[:limit]
+	}
+	return l, nil
+}
+
+// TailLogs sends the lines of the app and returns, as if the app stopped logging.
+func (f *fakeLogsProvider) TailLogs(ctx context.Context, id string, lines chan<- LogLine) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	l, ok := f.state[id]
+	if !ok {
+		return ErrNotFound
+	}
+	for _, line := range l {
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeMetricsProvider(addr string, p MetricsProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Metrics: p})
+}
+
+func NewMetricsProviderHandler(p MetricsProvider) http.Handler {
+	h := &metricsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/metrics/{id:[0-9a-z-]+}", h.getAppMetrics)
+
+	return h
+}
+
+type metricsProviderHandler struct {
+	*mux.Router
+
+	p MetricsProvider
+}
+
+func (h *metricsProviderHandler) getAppMetrics(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	q, err := ParseMetricsQuery(r, currentTime())
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	}
+
+	metrics, err := h.p.GetAppMetrics(r.Context(), id, q)
+	if errors.Is(err, ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondOk(w, metrics)
+}
+
+// ParseMetricsQuery reads the query parameters 'from' and 'to' as RFC3339 times and 'step' as
+// duration, e.g. '30s'. Missing parameters default to DefaultMetricsQuery.
+func ParseMetricsQuery(r *http.Request, now time.Time) (MetricsQuery, error) {
+	q := DefaultMetricsQuery(now)
+
+	to, err := defaultQueryTime(r, "to", q.To)
+	if err != nil {
+		return MetricsQuery{}, ErrQueryToMalformed
+	}
+	from, err := defaultQueryTime(r, "from", to.Add(-q.To.Sub(q.From)))
+	if err != nil {
+		return MetricsQuery{}, ErrQueryFromMalformed
+	}
+	q.From, q.To = from, to
+
+	if step := r.FormValue("step"); step != "" {
+		q.Step, err = time.ParseDuration(step)
+		if err != nil {
+			return MetricsQuery{}, ErrQueryStepMalformed
+		}
+	}
+
+	return q, q.Validate()
+}
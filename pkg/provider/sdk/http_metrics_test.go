@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var metrics = map[string]AppMetrics{
+	"a": {
+		{Metric: MetricCpu, Unit: MetricUnitPercent, Labels: map[string]string{"instance": "0"}, Points: []MetricPoint{
+			{Time: someTime, Value: 12.5},
+		}},
+	},
+}
+
+func TestAppMetrics(t *testing.T) {
+	currentTime = func() time.Time {
+		return someTime
+	}
+
+	tests := []struct {
+		desc           string
+		state          map[string]AppMetrics
+		err            error
+		path           string
+		expectedStatus int
+		expectedResp   response
+		expectedQuery  MetricsQuery
+	}{
+		{desc: "returns app metrics for the last hour", state: metrics, path: "/metrics/a", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"metric": "cpu", "unit": "percent", "labels": map[string]interface{}{"instance": "0"}, "points": []interface{}{
+					map[string]interface{}{"time": "2006-01-01T15:00:00Z", "value": 12.5},
+				}},
+			},
+		}, expectedQuery: MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: time.Minute}},
+		{desc: "passes range", state: metrics, path: "/metrics/a?from=2006-01-01T14:00:00Z&to=2006-01-01T14:30:00Z&step=30s", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: []interface{}{
+				map[string]interface{}{"metric": "cpu", "unit": "percent", "labels": map[string]interface{}{"instance": "0"}, "points": []interface{}{
+					map[string]interface{}{"time": "2006-01-01T15:00:00Z", "value": 12.5},
+				}},
+			},
+		}, expectedQuery: MetricsQuery{From: someTime.Add(-time.Hour), To: someTime.Add(-30 * time.Minute), Step: 30 * time.Second}},
+		{desc: "returns 400 for malformed from", state: metrics, path: "/metrics/a?from=yesterday", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryFromMalformed.Error(),
+		}},
+		{desc: "returns 400 for malformed to", state: metrics, path: "/metrics/a?to=today", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryToMalformed.Error(),
+		}},
+		{desc: "returns 400 for malformed step", state: metrics, path: "/metrics/a?step=often", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryStepMalformed.Error(),
+		}},
+		{desc: "returns 400 for too many points", state: metrics, path: "/metrics/a?step=100ms", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    "metrics query is invalid: more than 11000 points, increase the step",
+		}},
+		{desc: "returns 404 for non-existent", state: metrics, path: "/metrics/dont-exist", expectedStatus: http.StatusNotFound, expectedResp: response{
+			Status: http.StatusNotFound,
+			Err:    "not found",
+		}, expectedQuery: MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: time.Minute}},
+		{desc: "returns 5xx for other errors", state: metrics, err: ErrInternal, path: "/metrics/a", expectedStatus: http.StatusInternalServerError, expectedResp: response{
+			Status: http.StatusInternalServerError,
+			Err:    "internal error occurred",
+		}, expectedQuery: MetricsQuery{From: someTime.Add(-time.Hour), To: someTime, Step: time.Minute}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			p := &fakeMetricsProvider{state: test.state, err: test.err}
+			handler := NewMetricsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if !cmp.Equal(test.expectedQuery, p.query) {
+				tt.Errorf("\ndiff between queries: \n%s\n", cmp.Diff(test.expectedQuery, p.query))
+			}
+		})
+	}
+}
+
+func TestMetricsQueryValidate(t *testing.T) {
+	tests := []struct {
+		desc  string
+		q     MetricsQuery
+		valid bool
+	}{
+		{desc: "accepts default", q: DefaultMetricsQuery(someTime), valid: true},
+		{desc: "accepts single point", q: MetricsQuery{From: someTime, To: someTime, Step: time.Second}, valid: true},
+		{desc: "rejects missing step", q: MetricsQuery{From: someTime, To: someTime}},
+		{desc: "rejects reversed range", q: MetricsQuery{From: someTime, To: someTime.Add(-time.Second), Step: time.Second}},
+		{desc: "rejects too many points", q: MetricsQuery{From: someTime, To: someTime.Add(24 * time.Hour), Step: time.Second}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := test.q.Validate()
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+type fakeMetricsProvider struct {
+	state map[string]AppMetrics
+	err   error
+	query MetricsQuery
+}
+
+func (f *fakeMetricsProvider) GetAppMetrics(ctx context.Context, id string, q MetricsQuery) (AppMetrics, error) {
+	f.query = q
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	m, ok := f.state[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m, nil
+}
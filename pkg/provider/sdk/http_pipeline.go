@@ -3,6 +3,7 @@ package sdk
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 )
 import "github.com/gorilla/mux"
@@ -10,10 +11,10 @@ import "github.com/gorilla/mux"
 var currentTime = time.Now
 
 func ListenAndServePipelineProvider(addr string, p PipelineProvider) error {
-	return ListenAndServe(addr, ProviderConfig{Pipelines: p})
+	return ListenAndServe(addr, ProviderConfig{Pipelines: PipelineProviderWithContext(p)})
 }
 
-func NewPipelineProviderHandler(p PipelineProvider) http.Handler {
+func NewPipelineProviderHandler(p PipelineProviderContext) http.Handler {
 	h := &pipelineProviderHandler{Router: mux.NewRouter(), p: p}
 
 	h.HandleFunc("/pipelines", h.listPipelines)
@@ -21,6 +22,10 @@ func NewPipelineProviderHandler(p PipelineProvider) http.Handler {
 	h.HandleFunc("/pipelines/updates", h.listPipelineUpdates)
 	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}", h.getPipeline)
 	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}/history", h.getHistory)
+	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}/trigger", h.triggerPipeline).Methods("POST")
+	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}/abort", h.abortRun).Methods("POST")
+	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}/steps/{step:[0-9]+}/rerun", h.rerunStep).Methods("POST")
+	h.HandleFunc("/pipelines/{id:[0-9a-z-]+}/steps/{step:[0-9]+}/approve", h.approveStep).Methods("POST")
 
 	return h
 }
@@ -28,11 +33,11 @@ func NewPipelineProviderHandler(p PipelineProvider) http.Handler {
 type pipelineProviderHandler struct {
 	*mux.Router
 
-	p PipelineProvider
+	p PipelineProviderContext
 }
 
 func (h *pipelineProviderHandler) listPipelines(w http.ResponseWriter, r *http.Request) {
-	apps, err := h.p.ListPipelines()
+	apps, err := h.p.ListPipelines(r.Context())
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, ErrInternal)
 		return
@@ -42,7 +47,7 @@ func (h *pipelineProviderHandler) listPipelines(w http.ResponseWriter, r *http.R
 
 func (h *pipelineProviderHandler) getPipeline(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	app, err := h.p.GetPipeline(id)
+	app, err := h.p.GetPipeline(r.Context(), id)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
@@ -68,7 +73,7 @@ func (h *pipelineProviderHandler) getHistory(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	history, err := h.p.GetHistory(id, since, limit)
+	history, err := h.p.GetHistory(r.Context(), id, since, limit)
 	if errors.Is(err, ErrNotFound) {
 		respondErr(w, http.StatusNotFound, err)
 		return
@@ -87,10 +92,69 @@ func (h *pipelineProviderHandler) listPipelineUpdates(w http.ResponseWriter, r *
 		return
 	}
 
-	updates, err := h.p.ListUpdates(since)
+	updates, err := h.p.ListUpdates(r.Context(), since)
 	if err != nil {
 		respondErr(w, http.StatusInternalServerError, ErrInternal)
 		return
 	}
 	respondOk(w, updates)
 }
+
+func (h *pipelineProviderHandler) triggerPipeline(w http.ResponseWriter, r *http.Request) {
+	h.takeAction(w, r, func(p PipelineActionsProvider, id string) error {
+		return p.TriggerPipeline(r.Context(), id)
+	})
+}
+
+func (h *pipelineProviderHandler) abortRun(w http.ResponseWriter, r *http.Request) {
+	h.takeAction(w, r, func(p PipelineActionsProvider, id string) error {
+		return p.AbortRun(r.Context(), id)
+	})
+}
+
+func (h *pipelineProviderHandler) rerunStep(w http.ResponseWriter, r *http.Request) {
+	h.takeStepAction(w, r, func(p PipelineActionsProvider, id string, stepId int) error {
+		return p.RerunStep(r.Context(), id, stepId)
+	})
+}
+
+func (h *pipelineProviderHandler) approveStep(w http.ResponseWriter, r *http.Request) {
+	h.takeStepAction(w, r, func(p PipelineActionsProvider, id string, stepId int) error {
+		return p.ApproveStep(r.Context(), id, stepId)
+	})
+}
+
+func (h *pipelineProviderHandler) takeStepAction(w http.ResponseWriter, r *http.Request, action func(p PipelineActionsProvider, id string, stepId int) error) {
+	// the route only matches digits, so this can only fail on overflow
+	stepId, err := strconv.Atoi(mux.Vars(r)["step"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	h.takeAction(w, r, func(p PipelineActionsProvider, id string) error {
+		return action(p, id, stepId)
+	})
+}
+
+func (h *pipelineProviderHandler) takeAction(w http.ResponseWriter, r *http.Request, action func(p PipelineActionsProvider, id string) error) {
+	p, ok := h.p.(PipelineActionsProvider)
+	if !ok {
+		respondErr(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	err := action(p, mux.Vars(r)["id"])
+	if errors.Is(err, ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, ErrConflict) {
+		respondErr(w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, ErrInternal)
+		return
+	}
+
+	respondOk(w, nil)
+}
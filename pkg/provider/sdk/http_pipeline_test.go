@@ -1,11 +1,13 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/google/go-cmp/cmp"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -264,6 +266,95 @@ func TestPipelines(t *testing.T) {
 
 }
 
+func TestPipelineActions(t *testing.T) {
+	tests := []struct {
+		desc           string
+		err            error
+		method         string
+		path           string
+		noActions      bool
+		expectedStatus int
+		expectedResp   response
+		expectedCalls  []string
+	}{
+		{desc: "triggers pipeline", path: "/pipelines/a/trigger", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"trigger a"}},
+		{desc: "aborts run", path: "/pipelines/a/abort", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"abort a"}},
+		{desc: "reruns step", path: "/pipelines/a/steps/1/rerun", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"rerun a 1"}},
+		{desc: "approves step", path: "/pipelines/a/steps/0/approve", expectedStatus: http.StatusOK,
+			expectedResp: response{Status: http.StatusOK}, expectedCalls: []string{"approve a 0"}},
+		{desc: "returns 404 for non-existent", err: ErrNotFound, path: "/pipelines/dont-exist/trigger", expectedStatus: http.StatusNotFound,
+			expectedResp: response{Status: http.StatusNotFound, Err: ErrNotFound.Error()}, expectedCalls: []string{"trigger dont-exist"}},
+		{desc: "returns 409 for conflicts", err: ErrConflict, path: "/pipelines/a/steps/1/approve", expectedStatus: http.StatusConflict,
+			expectedResp: response{Status: http.StatusConflict, Err: ErrConflict.Error()}, expectedCalls: []string{"approve a 1"}},
+		{desc: "returns 5xx for other errors", err: errors.New("error that should not be returned"), path: "/pipelines/a/abort", expectedStatus: http.StatusInternalServerError,
+			expectedResp: response{Status: http.StatusInternalServerError, Err: ErrInternal.Error()}, expectedCalls: []string{"abort a"}},
+		{desc: "returns 404 if actions are not supported", noActions: true, path: "/pipelines/a/trigger", expectedStatus: http.StatusNotFound,
+			expectedResp: response{Status: http.StatusNotFound, Err: ErrNotFound.Error()}},
+		{desc: "rejects other methods", method: "GET", path: "/pipelines/a/trigger", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			method := test.method
+			if method == "" {
+				method = "POST"
+			}
+
+			p := &fakePipelineActionsProvider{err: test.err}
+			var handler http.Handler = NewPipelineProviderHandler(p)
+			if test.noActions {
+				handler = NewPipelineProviderHandler(&p.fakePipelineProvider)
+			}
+
+			r := httptest.NewRecorder()
+			handler.ServeHTTP(r, httptest.NewRequest(method, test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if !cmp.Equal(test.expectedCalls, p.calls) {
+				tt.Errorf("\ndiff between calls: \n%s\n", cmp.Diff(test.expectedCalls, p.calls))
+			}
+		})
+	}
+}
+
+type fakePipelineActionsProvider struct {
+	fakePipelineProvider
+	err   error
+	calls []string
+}
+
+func (f *fakePipelineActionsProvider) TriggerPipeline(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "trigger "+id)
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) RerunStep(ctx context.Context, id string, stepId int) error {
+	f.calls = append(f.calls, "rerun "+id+" "+strconv.Itoa(stepId))
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) ApproveStep(ctx context.Context, id string, stepId int) error {
+	f.calls = append(f.calls, "approve "+id+" "+strconv.Itoa(stepId))
+	return f.err
+}
+
+func (f *fakePipelineActionsProvider) AbortRun(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "abort "+id)
+	return f.err
+}
+
 type fakePipelineProvider struct {
 	pipelines []Pipeline
 	pipeline  Pipeline
@@ -276,7 +367,7 @@ type fakePipelineProvider struct {
 	recordedLimit int
 }
 
-func (f *fakePipelineProvider) ListUpdates(since time.Time) (PipelineUpdates, error) {
+func (f *fakePipelineProvider) ListUpdates(ctx context.Context, since time.Time) (PipelineUpdates, error) {
 	if f.err != nil {
 		return PipelineUpdates{}, f.err
 	}
@@ -285,7 +376,7 @@ func (f *fakePipelineProvider) ListUpdates(since time.Time) (PipelineUpdates, er
 	return f.updates, nil
 }
 
-func (f *fakePipelineProvider) ListPipelines() ([]Pipeline, error) {
+func (f *fakePipelineProvider) ListPipelines(ctx context.Context) ([]Pipeline, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -293,7 +384,7 @@ func (f *fakePipelineProvider) ListPipelines() ([]Pipeline, error) {
 	return f.pipelines, nil
 }
 
-func (f *fakePipelineProvider) GetPipeline(id string) (Pipeline, error) {
+func (f *fakePipelineProvider) GetPipeline(ctx context.Context, id string) (Pipeline, error) {
 	if f.err != nil {
 		return Pipeline{}, f.err
 	}
@@ -302,7 +393,7 @@ func (f *fakePipelineProvider) GetPipeline(id string) (Pipeline, error) {
 	return f.pipeline, nil
 }
 
-func (f *fakePipelineProvider) GetHistory(id string, before time.Time, limit int) (PipelineStatusList, error) {
+func (f *fakePipelineProvider) GetHistory(ctx context.Context, id string, before time.Time, limit int) (PipelineStatusList, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeStepLogsProvider(addr string, p StepLogsProvider) error {
+	return ListenAndServe(addr, ProviderConfig{StepLogs: p})
+}
+
+func NewStepLogsProviderHandler(p StepLogsProvider) http.Handler {
+	h := &stepLogsProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/steplogs/{id:[0-9a-z-]+}/runs/{run}/steps/{step:[0-9]+}", h.getStepLogs)
+
+	return h
+}
+
+type stepLogsProviderHandler struct {
+	*mux.Router
+
+	p StepLogsProvider
+}
+
+func (h *stepLogsProviderHandler) getStepLogs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	run, err := time.Parse(time.RFC3339, mux.Vars(r)["run"])
+	if err != nil {
+		respondErr(w, http.StatusBadRequest, ErrRunMalformed)
+		return
+	}
+	stepId, err := strconv.Atoi(mux.Vars(r)["step"])
+	if err != nil {
+		respondErr(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	perPage, err := defaultQueryInt(r, "perPage", 100)
+	if err != nil || perPage <= 0 {
+		respondErr(w, http.StatusBadRequest, ErrQueryPerPageMalformed)
+		return
+	}
+	page, err := defaultQueryInt(r, "page", 0)
+	if err != nil || page < 0 {
+		respondErr(w, http.StatusBadRequest, ErrQueryPageMalformed)
+		return
+	}
+
+	logs, err := h.p.GetStepLogs(r.Context(), id, run, stepId, perPage, page)
+	if errors.Is(err, ErrNotFound) {
+		respondErr(w, http.StatusNotFound, err)
+		return
+	} else if errors.Is(err, ErrPageExceeded) {
+		respondErr(w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		respondErr(w, http.StatusInternalServerError, ErrInternal)
+		return
+	}
+
+	respondOk(w, logs)
+}
@@ -0,0 +1,116 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var stepLogs = AppLogs{
+	{Time: someTime, Stream: LogStreamOut, Message: "first"},
+	{Time: someTime.Add(time.Second), Stream: LogStreamErr, Message: "second"},
+	{Time: someTime.Add(2 * time.Second), Stream: LogStreamOut, Message: "third"},
+}
+
+func TestStepLogs(t *testing.T) {
+	tests := []struct {
+		desc            string
+		err             error
+		path            string
+		expectedStatus  int
+		expectedResp    response
+		expectedRequest string
+	}{
+		{desc: "returns step logs", path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1", expectedStatus: http.StatusOK,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 100 0", expectedResp: response{
+				Status: http.StatusOK,
+				Result: map[string]interface{}{
+					"totalResults": float64(3), "totalPages": float64(1), "perPage": float64(100), "page": float64(0),
+					"lines": []interface{}{
+						map[string]interface{}{"time": "2006-01-01T15:00:00Z", "stream": "stdout", "message": "first"},
+						map[string]interface{}{"time": "2006-01-01T15:00:01Z", "stream": "stderr", "message": "second"},
+						map[string]interface{}{"time": "2006-01-01T15:00:02Z", "stream": "stdout", "message": "third"},
+					},
+				},
+			}},
+		{desc: "returns page of step logs", path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1?perPage=2&page=1", expectedStatus: http.StatusOK,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 2 1", expectedResp: response{
+				Status: http.StatusOK,
+				Result: map[string]interface{}{
+					"totalResults": float64(3), "totalPages": float64(2), "perPage": float64(2), "page": float64(1),
+					"lines": []interface{}{
+						map[string]interface{}{"time": "2006-01-01T15:00:02Z", "stream": "stdout", "message": "third"},
+					},
+				},
+			}},
+		{desc: "returns 400 for exceeded page", path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1?perPage=2&page=2", expectedStatus: http.StatusBadRequest,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 2 2", expectedResp: response{
+				Status: http.StatusBadRequest,
+				Err:    ErrPageExceeded.Error(),
+			}},
+		{desc: "returns 400 for malformed run", path: "/steplogs/a/runs/yesterday/steps/1", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrRunMalformed.Error(),
+		}},
+		{desc: "returns 400 for malformed perPage", path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1?perPage=0", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryPerPageMalformed.Error(),
+		}},
+		{desc: "returns 400 for malformed page", path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1?page=abc", expectedStatus: http.StatusBadRequest, expectedResp: response{
+			Status: http.StatusBadRequest,
+			Err:    ErrQueryPageMalformed.Error(),
+		}},
+		{desc: "returns 404 for non-existent", err: ErrNotFound, path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/5", expectedStatus: http.StatusNotFound,
+			expectedRequest: "a 2006-01-01T15:00:00Z 5 100 0", expectedResp: response{
+				Status: http.StatusNotFound,
+				Err:    ErrNotFound.Error(),
+			}},
+		{desc: "returns 5xx for other errors", err: ErrInternal, path: "/steplogs/a/runs/2006-01-01T15:00:00Z/steps/1", expectedStatus: http.StatusInternalServerError,
+			expectedRequest: "a 2006-01-01T15:00:00Z 1 100 0", expectedResp: response{
+				Status: http.StatusInternalServerError,
+				Err:    ErrInternal.Error(),
+			}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			p := &fakeStepLogsProvider{state: stepLogs, err: test.err}
+			handler := NewStepLogsProviderHandler(p)
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+
+			if test.expectedRequest != p.request {
+				tt.Errorf("\nwanted request '%s'\n   got '%s'", test.expectedRequest, p.request)
+			}
+		})
+	}
+}
+
+type fakeStepLogsProvider struct {
+	state   AppLogs
+	err     error
+	request string
+}
+
+func (f *fakeStepLogsProvider) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (StepLogsPage, error) {
+	f.request = fmt.Sprintf("%s %s %d %d %d", pipelineId, runStarted.Format(time.RFC3339), stepId, perPage, page)
+	if f.err != nil {
+		return StepLogsPage{}, f.err
+	}
+	return PageStepLogs(f.state, perPage, page)
+}
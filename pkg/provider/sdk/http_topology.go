@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"net/http"
+)
+import "github.com/gorilla/mux"
+
+func ListenAndServeTopologyProvider(addr string, p TopologyProvider) error {
+	return ListenAndServe(addr, ProviderConfig{Topology: p})
+}
+
+func NewTopologyProviderHandler(p TopologyProvider) http.Handler {
+	h := &topologyProviderHandler{Router: mux.NewRouter(), p: p}
+
+	h.HandleFunc("/topology", h.getTopology)
+	h.HandleFunc("/topology/", h.getTopology)
+
+	return h
+}
+
+type topologyProviderHandler struct {
+	*mux.Router
+
+	p TopologyProvider
+}
+
+func (h *topologyProviderHandler) getTopology(w http.ResponseWriter, r *http.Request) {
+	topology, err := h.p.GetTopology(r.Context())
+	if err != nil {
+		respondErr(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondOk(w, topology)
+}
@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var topology = Topology{
+	Services: []BackingService{
+		{Id: "db", Name: "orders-db", Kind: "postgres", Labels: map[string]string{"plan": "small"}},
+	},
+	Dependencies: []Dependency{
+		{AppId: "a", Target: DependencyTargetApp, TargetId: "b"},
+		{AppId: "b", Target: DependencyTargetService, TargetId: "db", Name: "orders-binding"},
+	},
+}
+
+func TestTopology(t *testing.T) {
+	tests := []struct {
+		desc           string
+		state          Topology
+		err            error
+		path           string
+		expectedStatus int
+		expectedResp   response
+	}{
+		{desc: "returns topology", state: topology, path: "/topology", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{
+				"services": []interface{}{
+					map[string]interface{}{"id": "db", "name": "orders-db", "kind": "postgres", "labels": map[string]interface{}{"plan": "small"}},
+				},
+				"dependencies": []interface{}{
+					map[string]interface{}{"appId": "a", "target": "app", "targetId": "b"},
+					map[string]interface{}{"appId": "b", "target": "service", "targetId": "db", "name": "orders-binding"},
+				},
+			},
+		}},
+		{desc: "returns topology with trailing slash", state: Topology{Services: []BackingService{}, Dependencies: []Dependency{}}, path: "/topology/", expectedStatus: http.StatusOK, expectedResp: response{
+			Status: http.StatusOK,
+			Result: map[string]interface{}{"services": []interface{}{}, "dependencies": []interface{}{}},
+		}},
+		{desc: "returns 5xx for errors", err: ErrInternal, path: "/topology", expectedStatus: http.StatusInternalServerError, expectedResp: response{
+			Status: http.StatusInternalServerError,
+			Err:    "internal error occurred",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			r := httptest.NewRecorder()
+			handler := NewTopologyProviderHandler(&fakeTopologyProvider{state: test.state, err: test.err})
+			handler.ServeHTTP(r, httptest.NewRequest("GET", test.path, nil))
+			res := r.Result()
+			if res.StatusCode != test.expectedStatus {
+				tt.Errorf("\nwanted status %v\n   got %v", test.expectedStatus, res.StatusCode)
+			}
+
+			resp := response{}
+			_ = json.NewDecoder(res.Body).Decode(&resp)
+			if !cmp.Equal(test.expectedResp, resp) {
+				tt.Errorf("\ndiff between responses: \n%s\n", cmp.Diff(test.expectedResp, resp))
+			}
+		})
+	}
+}
+
+type fakeTopologyProvider struct {
+	err   error
+	state Topology
+}
+
+func (f *fakeTopologyProvider) GetTopology(ctx context.Context) (Topology, error) {
+	if f.err != nil {
+		return Topology{}, f.err
+	}
+	return f.state, nil
+}
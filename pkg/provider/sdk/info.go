@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProtocolVersion is the version of the protocol between core and providers this SDK speaks.
+// It is increased on every incompatible change to the HTTP api.
+const ProtocolVersion = 1
+
+// MinProtocolVersion is the oldest protocol version of providers this SDK can still talk to.
+const MinProtocolVersion = 1
+
+type InfoProvider interface {
+	GetInfo(ctx context.Context) (ProviderInfo, error)
+}
+
+type Feature string
+
+const (
+	FeatureApps      Feature = "apps"
+	FeaturePipelines Feature = "pipelines"
+	FeatureGroups    Feature = "groups"
+	FeatureRouting   Feature = "routing"
+	FeatureInstances Feature = "instances"
+	FeatureLogs      Feature = "logs"
+	FeatureMetrics   Feature = "metrics"
+	FeatureAlerts    Feature = "alerts"
+	FeatureStepLogs  Feature = "stepLogs"
+	FeatureTopology  Feature = "topology"
+
+	FeatureAppActions Feature = "appActions"
+
+	// FeatureAppUpdates is advertised in addition to FeatureApps by app providers
+	// implementing AppUpdatesProvider.
+	FeatureAppUpdates Feature = "appUpdates"
+	// FeaturePipelineActions is advertised in addition to FeaturePipelines by pipeline providers
+	// implementing PipelineActionsProvider.
+	FeaturePipelineActions Feature = "pipelineActions"
+)
+
+// ProviderInfo describes a provider and the features it serves.
+type ProviderInfo struct {
+	Name            string    `json:"name"`
+	ProtocolVersion int       `json:"protocolVersion"`
+	Features        []Feature `json:"features"`
+}
+
+// CheckCompatible returns an error if the provider speaks a protocol version this SDK can't talk to.
+func (i ProviderInfo) CheckCompatible() error {
+	if i.ProtocolVersion < MinProtocolVersion || i.ProtocolVersion > ProtocolVersion {
+		return fmt.Errorf("%w: provider '%s' speaks version %d, supported are versions %d to %d",
+			ErrIncompatibleProtocol, i.Name, i.ProtocolVersion, MinProtocolVersion, ProtocolVersion)
+	}
+	return nil
+}
+
+// Info describes the provider config, advertising a feature for every provider that is set.
+// Optional capabilities of a provider are advertised as additional features.
+func (p ProviderConfig) Info() ProviderInfo {
+	info := ProviderInfo{
+		Name:            p.Name,
+		ProtocolVersion: ProtocolVersion,
+		Features:        []Feature{},
+	}
+
+	if p.Apps != nil {
+		info.Features = append(info.Features, FeatureApps)
+		if _, ok := p.Apps.(AppUpdatesProvider); ok {
+			info.Features = append(info.Features, FeatureAppUpdates)
+		}
+	}
+	if p.Pipelines != nil {
+		info.Features = append(info.Features, FeaturePipelines)
+		if _, ok := p.Pipelines.(PipelineActionsProvider); ok {
+			info.Features = append(info.Features, FeaturePipelineActions)
+		}
+	}
+	if p.Groups != nil {
+		info.Features = append(info.Features, FeatureGroups)
+	}
+	if p.Routing != nil {
+		info.Features = append(info.Features, FeatureRouting)
+	}
+	if p.Instances != nil {
+		info.Features = append(info.Features, FeatureInstances)
+	}
+	if p.Logs != nil {
+		info.Features = append(info.Features, FeatureLogs)
+	}
+	if p.Metrics != nil {
+		info.Features = append(info.Features, FeatureMetrics)
+	}
+	if p.Alerts != nil {
+		info.Features = append(info.Features, FeatureAlerts)
+	}
+	if p.StepLogs != nil {
+		info.Features = append(info.Features, FeatureStepLogs)
+	}
+	if p.Topology != nil {
+		info.Features = append(info.Features, FeatureTopology)
+	}
+	if p.AppActions != nil {
+		info.Features = append(info.Features, FeatureAppActions)
+	}
+
+	return info
+}
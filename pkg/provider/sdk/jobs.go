@@ -0,0 +1,23 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// JobsProvider exposes the reconcile jobs a provider gave up on after too many failed attempts.
+type JobsProvider interface {
+	ListDeadLetters(ctx context.Context) ([]DeadLetter, error)
+	// RequeueDeadLetter makes the job run again right away. It returns ErrNotFound if there
+	// is no such dead-lettered job.
+	RequeueDeadLetter(ctx context.Context, jobType string, guid string) error
+}
+
+// DeadLetter is a reconcile job that isn't attempted anymore until it is requeued.
+type DeadLetter struct {
+	Type        string    `json:"type"`
+	Guid        string    `json:"guid"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastAttempt time.Time `json:"lastAttempt"`
+}
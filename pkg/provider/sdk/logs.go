@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// LogsProvider serves the logs of apps. Unlike the older provider interfaces it is context
+// aware only, as tailing logs has to be stopped once the consumer goes away.
+type LogsProvider interface {
+	// GetRecentLogs returns the latest lines the app logged since the given time, at most limit
+	// lines, oldest first.
+	GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (AppLogs, error)
+	// TailLogs sends the lines the app logs from now on to the channel until the context is
+	// done. The channel is not closed when TailLogs returns.
+	TailLogs(ctx context.Context, id string, lines chan<- LogLine) error
+}
+
+type AppLogs []LogLine
+
+type LogLine struct {
+	Time time.Time `json:"time"`
+	// Instance identifies the instance of the app that logged the line, if known.
+	Instance string `json:"instance,omitempty"`
+	// Source describes the component that logged the line, e.g. the app itself or its router.
+	Source  string    `json:"source,omitempty"`
+	Stream  LogStream `json:"stream"`
+	Message string    `json:"message"`
+}
+
+type LogStream string
+
+const (
+	LogStreamOut LogStream = "stdout"
+	LogStreamErr LogStream = "stderr"
+)
@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxMetricsPoints is the most points a single series of a metrics query may have.
+const MaxMetricsPoints = 11000
+
+// MetricsProvider serves time series of metrics of apps, such as their resource usage.
+type MetricsProvider interface {
+	// GetAppMetrics returns the series of all metrics the provider knows for the app within the
+	// range of the query. Apps the provider has no metrics for return no series.
+	GetAppMetrics(ctx context.Context, id string, q MetricsQuery) (AppMetrics, error)
+}
+
+// MetricsQuery selects the range of time series, from and to inclusive, with a point every step.
+type MetricsQuery struct {
+	From time.Time
+	To   time.Time
+	Step time.Duration
+}
+
+// DefaultMetricsQuery covers the last hour with a point per minute.
+func DefaultMetricsQuery(now time.Time) MetricsQuery {
+	return MetricsQuery{
+		From: now.Add(-time.Hour),
+		To:   now,
+		Step: time.Minute,
+	}
+}
+
+// Validate returns ErrMetricsQueryInvalid if the range is empty or would have too many points.
+func (q MetricsQuery) Validate() error {
+	if q.Step <= 0 {
+		return fmt.Errorf("%w: step has to be positive", ErrMetricsQueryInvalid)
+	}
+	if q.To.Before(q.From) {
+		return fmt.Errorf("%w: 'to' is before 'from'", ErrMetricsQueryInvalid)
+	}
+	if q.To.Sub(q.From)/q.Step >= MaxMetricsPoints {
+		return fmt.Errorf("%w: more than %d points, increase the step", ErrMetricsQueryInvalid, MaxMetricsPoints)
+	}
+	return nil
+}
+
+type AppMetrics []MetricSeries
+
+// MetricSeries is a time series of one metric. Providers may return several series per metric,
+// e.g. one per instance, which are told apart by their labels.
+type MetricSeries struct {
+	Metric Metric            `json:"metric"`
+	Unit   MetricUnit        `json:"unit"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Points []MetricPoint     `json:"points"`
+}
+
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+type Metric string
+
+const (
+	MetricCpu         Metric = "cpu"
+	MetricMemory      Metric = "memory"
+	MetricDisk        Metric = "disk"
+	MetricRequestRate Metric = "requestRate"
+	MetricErrorRate   Metric = "errorRate"
+)
+
+type MetricUnit string
+
+const (
+	MetricUnitPercent   MetricUnit = "percent"
+	MetricUnitBytes     MetricUnit = "bytes"
+	MetricUnitPerSecond MetricUnit = "perSecond"
+)
+
+// Unit returns the unit the metric is reported in.
+func (m Metric) Unit() MetricUnit {
+	switch m {
+	case MetricCpu:
+		return MetricUnitPercent
+	case MetricMemory, MetricDisk:
+		return MetricUnitBytes
+	case MetricRequestRate, MetricErrorRate:
+		return MetricUnitPerSecond
+	}
+	return ""
+}
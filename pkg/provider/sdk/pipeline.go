@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"time"
 )
 
@@ -70,14 +71,43 @@ type PipelineStatusList []PipelineStatus
 type PipelineStatus struct {
 	PipelineId string    `json:"pipelineId" bson:"pipelineId"`
 	Started    time.Time `json:"started" bson:"started"`
-	Steps      []StepRun `json:"steps,omitempty" bson:"steps,omitempty"`
+	// Trigger describes why the run was started. It is nil if the provider doesn't know.
+	Trigger *RunTrigger `json:"trigger,omitempty" bson:"trigger,omitempty"`
+	Steps   []StepRun   `json:"steps,omitempty" bson:"steps,omitempty"`
 }
 
+// RunTrigger describes what started a run and which commit it runs for. Fields the provider
+// can't tell are left empty.
+type RunTrigger struct {
+	Type    TriggerType `json:"type,omitempty" bson:"type,omitempty"`
+	Commit  string      `json:"commit,omitempty" bson:"commit,omitempty"`
+	Branch  string      `json:"branch,omitempty" bson:"branch,omitempty"`
+	Author  string      `json:"author,omitempty" bson:"author,omitempty"`
+	Message string      `json:"message,omitempty" bson:"message,omitempty"`
+}
+
+type TriggerType string
+
+const (
+	TriggerManual   = "manual"
+	TriggerWebhook  = "webhook"
+	TriggerSchedule = "schedule"
+)
+
 type StepRun struct {
 	StepId  int        `json:"stepId" bson:"stepId"`
 	Status  StepStatus `json:"status" bson:"status"`
 	Started time.Time  `json:"started" bson:"started"`
 	Ended   time.Time  `json:"ended" bson:"ended"`
+	// Url links to the step run in the pipeline system, if it has a web interface.
+	Url       string         `json:"url,omitempty" bson:"url,omitempty"`
+	Artifacts []StepArtifact `json:"artifacts,omitempty" bson:"artifacts,omitempty"`
+}
+
+// StepArtifact is something a step run produced, e.g. a test report or a built package.
+type StepArtifact struct {
+	Name string `json:"name" bson:"name"`
+	Url  string `json:"url" bson:"url"`
 }
 
 type StepStatus string
@@ -97,6 +127,30 @@ type PipelineProvider interface {
 	GetHistory(id string, before time.Time, limit int) (PipelineStatusList, error)
 }
 
+// PipelineProviderContext is the context aware version of PipelineProvider.
+type PipelineProviderContext interface {
+	ListPipelines(ctx context.Context) ([]Pipeline, error)
+	ListUpdates(ctx context.Context, since time.Time) (PipelineUpdates, error)
+	GetPipeline(ctx context.Context, id string) (Pipeline, error)
+	GetHistory(ctx context.Context, id string, before time.Time, limit int) (PipelineStatusList, error)
+}
+
+// PipelineActionsProvider is implemented by pipeline providers that can act on their pipelines.
+// Steps are acted on in the latest run of the pipeline. Providers implementing it advertise
+// FeaturePipelineActions. Actions return ErrNotFound for unknown pipelines and steps, and
+// ErrConflict if the step or run is in no state to take the action.
+type PipelineActionsProvider interface {
+	PipelineProviderContext
+	// TriggerPipeline starts a new run of the pipeline.
+	TriggerPipeline(ctx context.Context, id string) error
+	// RerunStep runs a finished step again, e.g. after it failed.
+	RerunStep(ctx context.Context, id string, stepId int) error
+	// ApproveStep starts a step waiting behind a manual connection.
+	ApproveStep(ctx context.Context, id string, stepId int) error
+	// AbortRun aborts the running steps of the pipeline.
+	AbortRun(ctx context.Context, id string) error
+}
+
 func (pl PipelineStatusList) Fold() PipelineStatus {
 	if len(pl) == 1 {
 		return pl[0]
@@ -126,6 +180,7 @@ func (p PipelineStatus) Fold(other PipelineStatus) PipelineStatus {
 
 	if p.Started.Before(other.Started) {
 		p.Started = other.Started
+		p.Trigger = other.Trigger
 	}
 
 	m := make(map[int]StepRun, len(p.Steps))
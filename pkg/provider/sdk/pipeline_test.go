@@ -163,6 +163,14 @@ func TestStatusListFold(t *testing.T) {
 			list:     PipelineStatusList{PipelineStatus{Started: someTime}, PipelineStatus{Started: someTime.Add(10 * time.Minute)}},
 			expected: PipelineStatus{Started: someTime.Add(10 * time.Minute), Steps: []StepRun{}},
 		},
+		{
+			desc: "keeps trigger of latest run",
+			list: PipelineStatusList{
+				PipelineStatus{Started: someTime.Add(10 * time.Minute), Trigger: &RunTrigger{Type: TriggerWebhook, Branch: "main"}},
+				PipelineStatus{Started: someTime, Trigger: &RunTrigger{Type: TriggerManual}},
+			},
+			expected: PipelineStatus{Started: someTime.Add(10 * time.Minute), Trigger: &RunTrigger{Type: TriggerWebhook, Branch: "main"}, Steps: []StepRun{}},
+		},
 	}
 
 	for _, test := range tests {
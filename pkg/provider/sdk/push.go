@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Update carries changes a provider pushes to the core, so they become visible without
+// waiting for the next reconciliation.
+type Update struct {
+	Apps             []App               `json:"apps,omitempty"`
+	DeletedApps      []string            `json:"deletedApps,omitempty"`
+	Pipelines        []Pipeline          `json:"pipelines,omitempty"`
+	PipelineVersions PipelineVersionList `json:"pipelineVersions,omitempty"`
+	PipelineRuns     PipelineStatusList  `json:"pipelineRuns,omitempty"`
+	Groups           []Group             `json:"groups,omitempty"`
+	DeletedGroups    []string            `json:"deletedGroups,omitempty"`
+}
+
+// ReconcileRequest asks the core to reconcile a provider right away.
+type ReconcileRequest struct {
+	// Features to reconcile. If empty, all features the provider is registered for are reconciled.
+	Features []Feature `json:"features,omitempty"`
+	// Apps whose routing and instances should be refreshed.
+	Apps []string `json:"apps,omitempty"`
+}
+
+// CoreClient is used by providers to push changes to the core.
+type CoreClient interface {
+	PushUpdate(u Update) error
+	RequestReconcile(r ReconcileRequest) error
+}
+
+// NewCoreClient creates a client pushing to the core at the given url as the provider with the
+// given id. The core verifies the requests with the credentials it has configured for the provider.
+func NewCoreClient(coreUrl string, providerId string, auth AuthConfig, c *http.Client) CoreClient {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	return &coreClient{
+		c:        c,
+		auth:     auth,
+		basePath: strings.TrimSuffix(coreUrl, "/") + "/hooks/providers/" + providerId,
+	}
+}
+
+type coreClient struct {
+	c        *http.Client
+	auth     AuthConfig
+	basePath string
+}
+
+func (c *coreClient) PushUpdate(u Update) error {
+	return c.post("/updates", u)
+}
+
+func (c *coreClient) RequestReconcile(r ReconcileRequest) error {
+	return c.post("/reconcile", r)
+}
+
+func (c *coreClient) post(path string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", c.basePath+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = c.auth.AuthorizeRequest(req, currentTime())
+	if err != nil {
+		return err
+	}
+
+	res, err := c.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	}
+
+	resp := response{}
+	_ = json.NewDecoder(res.Body).Decode(&resp)
+	return fmt.Errorf("core responded with status %d: %s", res.StatusCode, resp.Err)
+}
@@ -0,0 +1,799 @@
+package sdktest
+
+import (
+	"context"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory provider serving every feature of the SDK. It is the reference
+// implementation the conformance suite is checked against and can be used as a fake
+// provider in tests. Changes are made through its Put, Add and Delete methods, which
+// record the time of the change for the update endpoints.
+type Memory struct {
+	mu  sync.RWMutex
+	now func() time.Time
+
+	apps       map[string]sdk.App
+	appChanges map[string]time.Time
+	tombstones map[string]sdk.AppTombstone
+	routing    map[string]sdk.AppRouting
+	instances  map[string]sdk.AppInstances
+
+	pipelines map[string]sdk.Pipeline
+	versions  []versionChange
+	runs      []runChange
+
+	groups map[string]sdk.Group
+
+	metrics map[string]sdk.AppMetrics
+	alerts  map[string]sdk.Alert
+
+	services     map[string]sdk.BackingService
+	dependencies map[string]sdk.Dependency
+
+	logs  map[string]sdk.AppLogs
+	tails map[*logTail]bool
+
+	stepLogs map[stepKey]sdk.AppLogs
+}
+
+// stepKey identifies a step in a run. Runs are keyed by the unix time they started, as
+// equal times in different locations are different map keys.
+type stepKey struct {
+	pipelineId string
+	run        int64
+	stepId     int
+}
+
+type logTail struct {
+	id    string
+	lines chan sdk.LogLine
+	done  chan struct{}
+}
+
+type versionChange struct {
+	version sdk.PipelineVersion
+	changed time.Time
+}
+
+type runChange struct {
+	run     sdk.PipelineStatus
+	changed time.Time
+}
+
+// NewMemory creates an empty Memory provider.
+func NewMemory() *Memory {
+	return NewMemoryWithClock(time.Now)
+}
+
+// NewMemoryWithClock creates an empty Memory provider that takes the time of changes from now.
+func NewMemoryWithClock(now func() time.Time) *Memory {
+	return &Memory{
+		now:          now,
+		apps:         make(map[string]sdk.App),
+		appChanges:   make(map[string]time.Time),
+		tombstones:   make(map[string]sdk.AppTombstone),
+		routing:      make(map[string]sdk.AppRouting),
+		instances:    make(map[string]sdk.AppInstances),
+		pipelines:    make(map[string]sdk.Pipeline),
+		groups:       make(map[string]sdk.Group),
+		metrics:      make(map[string]sdk.AppMetrics),
+		alerts:       make(map[string]sdk.Alert),
+		services:     make(map[string]sdk.BackingService),
+		dependencies: make(map[string]sdk.Dependency),
+		logs:         make(map[string]sdk.AppLogs),
+		tails:        make(map[*logTail]bool),
+		stepLogs:     make(map[stepKey]sdk.AppLogs),
+	}
+}
+
+// Config serves all features of the provider under the given name.
+func (m *Memory) Config(name string) sdk.ProviderConfig {
+	return sdk.ProviderConfig{
+		Name:      name,
+		Apps:      m,
+		Pipelines: m,
+		Groups:    m,
+		Routing:   m,
+		Instances: m,
+		Logs:      m,
+		Metrics:   m,
+		Alerts:    m,
+		StepLogs:  m,
+		Topology:  m,
+
+		AppActions: m,
+	}
+}
+
+// PutApps creates or updates the given apps.
+func (m *Memory) PutApps(apps ...sdk.App) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for _, app := range apps {
+		m.apps[app.Id] = app
+		m.appChanges[app.Id] = now
+		delete(m.tombstones, app.Id)
+	}
+}
+
+// DeleteApps deletes the given apps together with their routing and instances.
+// Unknown ids are ignored.
+func (m *Memory) DeleteApps(ids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for _, id := range ids {
+		if _, ok := m.apps[id]; !ok {
+			continue
+		}
+		delete(m.apps, id)
+		delete(m.appChanges, id)
+		delete(m.routing, id)
+		delete(m.instances, id)
+		delete(m.logs, id)
+		delete(m.metrics, id)
+		for alertId, alert := range m.alerts {
+			if alert.AppId == id {
+				delete(m.alerts, alertId)
+			}
+		}
+		for dependencyId, dependency := range m.dependencies {
+			if dependency.AppId == id || (dependency.Target == sdk.DependencyTargetApp && dependency.TargetId == id) {
+				delete(m.dependencies, dependencyId)
+			}
+		}
+		m.tombstones[id] = sdk.AppTombstone{Id: id, Deleted: now}
+	}
+}
+
+// SetRouting sets the routing of an app.
+func (m *Memory) SetRouting(id string, routing sdk.AppRouting) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routing[id] = routing
+}
+
+// SetInstances sets the instances of an app.
+func (m *Memory) SetInstances(id string, instances sdk.AppInstances) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instances[id] = instances
+}
+
+// PutPipelines creates or updates the given pipelines. Their current versions are
+// reported as updates.
+func (m *Memory) PutPipelines(pipelines ...sdk.Pipeline) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for _, pipeline := range pipelines {
+		m.pipelines[pipeline.Id] = pipeline
+
+		version := pipeline.Current
+		version.PipelineId = pipeline.Id
+		m.versions = append(m.versions, versionChange{version: version, changed: now})
+	}
+}
+
+// AddPipelineRuns adds the given runs. A run that was added before with the same start
+// is replaced, so runs can be updated as their steps progress.
+func (m *Memory) AddPipelineRuns(runs ...sdk.PipelineStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for _, run := range runs {
+		m.runs = removeRun(m.runs, run)
+		m.runs = append(m.runs, runChange{run: run, changed: now})
+	}
+}
+
+func removeRun(runs []runChange, run sdk.PipelineStatus) []runChange {
+	res := runs[:0]
+	for _, r := range runs {
+		if r.run.PipelineId == run.PipelineId && r.run.Started.Equal(run.Started) {
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// PutGroups creates or updates the given groups.
+func (m *Memory) PutGroups(groups ...sdk.Group) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, group := range groups {
+		m.groups[group.Id] = group
+	}
+}
+
+// SetMetrics sets the metrics of an app. Queries return the points within their range.
+func (m *Memory) SetMetrics(id string, metrics sdk.AppMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics[id] = metrics
+}
+
+// PutAlerts fires the given alerts or updates them, if they are already active.
+func (m *Memory) PutAlerts(alerts ...sdk.Alert) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, alert := range alerts {
+		m.alerts[alert.Id] = alert
+	}
+}
+
+// ResolveAlerts resolves the alerts with the given ids. Unknown ids are ignored.
+func (m *Memory) ResolveAlerts(ids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.alerts, id)
+	}
+}
+
+// PutServices creates or updates the given backing services.
+func (m *Memory) PutServices(services ...sdk.BackingService) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, service := range services {
+		m.services[service.Id] = service
+	}
+}
+
+// DeleteServices deletes the given backing services together with the dependencies on them.
+// Unknown ids are ignored.
+func (m *Memory) DeleteServices(ids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.services, id)
+		for dependencyId, dependency := range m.dependencies {
+			if dependency.Target == sdk.DependencyTargetService && dependency.TargetId == id {
+				delete(m.dependencies, dependencyId)
+			}
+		}
+	}
+}
+
+// PutDependencies adds the given dependencies, replacing equal ones.
+func (m *Memory) PutDependencies(dependencies ...sdk.Dependency) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, dependency := range dependencies {
+		m.dependencies[dependency.Id()] = dependency
+	}
+}
+
+// AddLogs appends lines to the logs of an app and sends them to everyone tailing it.
+// The lines have to be logged after the ones added before.
+func (m *Memory) AddLogs(id string, lines ...sdk.LogLine) {
+	m.mu.Lock()
+	m.logs[id] = append(m.logs[id], lines...)
+	var tails []*logTail
+	for tail := range m.tails {
+		if tail.id == id {
+			tails = append(tails, tail)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, tail := range tails {
+		for _, line := range lines {
+			select {
+			case tail.lines <- line:
+			case <-tail.done:
+			}
+		}
+	}
+}
+
+// AddStepLogs appends lines to the logs of a step in the run of a pipeline that started at
+// the given time.
+func (m *Memory) AddStepLogs(pipelineId string, runStarted time.Time, stepId int, lines ...sdk.LogLine) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := stepKey{pipelineId: pipelineId, run: runStarted.UnixNano(), stepId: stepId}
+	m.stepLogs[key] = append(m.stepLogs[key], lines...)
+}
+
+func (m *Memory) ListApps(ctx context.Context) ([]sdk.App, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]sdk.App, 0, len(m.apps))
+	for _, app := range m.apps {
+		res = append(res, app)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (m *Memory) GetApp(ctx context.Context, id string) (sdk.App, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	app, ok := m.apps[id]
+	if !ok {
+		return sdk.App{}, sdk.ErrNotFound
+	}
+	return app, nil
+}
+
+func (m *Memory) ListAppsPage(ctx context.Context, perPage int, page int) (sdk.AppPage, error) {
+	apps, err := m.ListApps(ctx)
+	if err != nil {
+		return sdk.AppPage{}, err
+	}
+	return sdk.PageApps(apps, perPage, page)
+}
+
+func (m *Memory) ListAppUpdates(ctx context.Context, since time.Time) (sdk.AppUpdates, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := sdk.AppUpdates{}
+	for id, changed := range m.appChanges {
+		if !changed.Before(since) {
+			res.Apps = append(res.Apps, m.apps[id])
+		}
+	}
+	for _, tombstone := range m.tombstones {
+		if !tombstone.Deleted.Before(since) {
+			res.Deleted = append(res.Deleted, tombstone)
+		}
+	}
+
+	sort.Slice(res.Apps, func(i, j int) bool {
+		return res.Apps[i].Id < res.Apps[j].Id
+	})
+	sort.Slice(res.Deleted, func(i, j int) bool {
+		return res.Deleted[i].Id < res.Deleted[j].Id
+	})
+	return res, nil
+}
+
+func (m *Memory) GetAppRouting(ctx context.Context, id string) (sdk.AppRouting, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return sdk.AppRouting{}, sdk.ErrNotFound
+	}
+	return m.routing[id], nil
+}
+
+func (m *Memory) GetAppInstances(ctx context.Context, id string) (sdk.AppInstances, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return nil, sdk.ErrNotFound
+	}
+	return m.instances[id], nil
+}
+
+func (m *Memory) ListPipelines(ctx context.Context) ([]sdk.Pipeline, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]sdk.Pipeline, 0, len(m.pipelines))
+	for _, pipeline := range m.pipelines {
+		res = append(res, pipeline)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (m *Memory) GetPipeline(ctx context.Context, id string) (sdk.Pipeline, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pipeline, ok := m.pipelines[id]
+	if !ok {
+		return sdk.Pipeline{}, sdk.ErrNotFound
+	}
+	return pipeline, nil
+}
+
+func (m *Memory) ListUpdates(ctx context.Context, since time.Time) (sdk.PipelineUpdates, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := sdk.PipelineUpdates{}
+	for _, v := range m.versions {
+		if !v.changed.Before(since) {
+			res.Versions = append(res.Versions, v.version)
+		}
+	}
+	for _, r := range m.runs {
+		if !r.changed.Before(since) {
+			res.Runs = append(res.Runs, r.run)
+		}
+	}
+	return res, nil
+}
+
+// GetHistory returns the latest runs of the pipeline that started before the given time,
+// newest first.
+func (m *Memory) GetHistory(ctx context.Context, id string, before time.Time, limit int) (sdk.PipelineStatusList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.pipelines[id]; !ok {
+		return nil, sdk.ErrNotFound
+	}
+
+	res := sdk.PipelineStatusList{}
+	for _, r := range m.runs {
+		if r.run.PipelineId == id && r.run.Started.Before(before) {
+			res = append(res, r.run)
+		}
+	}
+	sort.Sort(sort.Reverse(res))
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (m *Memory) ListGroups(ctx context.Context) ([]sdk.Group, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]sdk.Group, 0, len(m.groups))
+	for _, group := range m.groups {
+		res = append(res, group)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (m *Memory) GetGroup(ctx context.Context, id string) (sdk.Group, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	group, ok := m.groups[id]
+	if !ok {
+		return sdk.Group{}, sdk.ErrNotFound
+	}
+	return group, nil
+}
+
+func (m *Memory) GetRecentLogs(ctx context.Context, id string, since time.Time, limit int) (sdk.AppLogs, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return nil, sdk.ErrNotFound
+	}
+
+	res := sdk.AppLogs{}
+	for _, line := range m.logs[id] {
+		if !line.Time.Before(since) {
+			res = append(res, line)
+		}
+	}
+	if len(res) > limit {
+		res = res[len(res)-limit:]
+	}
+	return res, nil
+}
+
+// GetStepLogs pages the lines added with AddStepLogs. Steps of runs that were added
+// without them have empty logs.
+func (m *Memory) GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (sdk.StepLogsPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.hasStepRun(pipelineId, runStarted, stepId) {
+		return sdk.StepLogsPage{}, sdk.ErrNotFound
+	}
+	return sdk.PageStepLogs(m.stepLogs[stepKey{pipelineId: pipelineId, run: runStarted.UnixNano(), stepId: stepId}], perPage, page)
+}
+
+func (m *Memory) hasStepRun(pipelineId string, runStarted time.Time, stepId int) bool {
+	for _, r := range m.runs {
+		if r.run.PipelineId != pipelineId || !r.run.Started.Equal(runStarted) {
+			continue
+		}
+		for _, step := range r.run.Steps {
+			if step.StepId == stepId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TailLogs sends the lines added to the app with AddLogs until the context is done. AddLogs
+// blocks until the lines are received.
+func (m *Memory) TailLogs(ctx context.Context, id string, lines chan<- sdk.LogLine) error {
+	m.mu.Lock()
+	if _, ok := m.apps[id]; !ok {
+		m.mu.Unlock()
+		return sdk.ErrNotFound
+	}
+
+	tail := &logTail{id: id, lines: make(chan sdk.LogLine), done: make(chan struct{})}
+	m.tails[tail] = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.tails, tail)
+		m.mu.Unlock()
+		close(tail.done)
+	}()
+
+	for {
+		select {
+		case line := <-tail.lines:
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *Memory) GetAppMetrics(ctx context.Context, id string, q sdk.MetricsQuery) (sdk.AppMetrics, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return nil, sdk.ErrNotFound
+	}
+
+	res := sdk.AppMetrics{}
+	for _, series := range m.metrics[id] {
+		points := []sdk.MetricPoint{}
+		for _, point := range series.Points {
+			if !point.Time.Before(q.From) && !point.Time.After(q.To) {
+				points = append(points, point)
+			}
+		}
+		series.Points = points
+		res = append(res, series)
+	}
+	return res, nil
+}
+
+func (m *Memory) ListAlerts(ctx context.Context) ([]sdk.Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]sdk.Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		res = append(res, alert)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (m *Memory) GetTopology(ctx context.Context) (sdk.Topology, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := sdk.Topology{
+		Services:     make([]sdk.BackingService, 0, len(m.services)),
+		Dependencies: make([]sdk.Dependency, 0, len(m.dependencies)),
+	}
+	for _, service := range m.services {
+		res.Services = append(res.Services, service)
+	}
+	for _, dependency := range m.dependencies {
+		res.Dependencies = append(res.Dependencies, dependency)
+	}
+	sort.Slice(res.Services, func(i, j int) bool {
+		return res.Services[i].Id < res.Services[j].Id
+	})
+	sort.Slice(res.Dependencies, func(i, j int) bool {
+		return res.Dependencies[i].Id() < res.Dependencies[j].Id()
+	})
+	return res, nil
+}
+
+// RestartApp sets all instances of the app running since now.
+func (m *Memory) RestartApp(ctx context.Context, id string) error {
+	return m.setInstanceStates(id, sdk.AppStateRunning)
+}
+
+// StopApp sets all instances of the app stopped since now.
+func (m *Memory) StopApp(ctx context.Context, id string) error {
+	return m.setInstanceStates(id, sdk.AppStateStopped)
+}
+
+// StartApp sets all instances of the app running since now.
+func (m *Memory) StartApp(ctx context.Context, id string) error {
+	return m.setInstanceStates(id, sdk.AppStateRunning)
+}
+
+// ScaleApp removes instances from the end or adds running ones.
+func (m *Memory) ScaleApp(ctx context.Context, id string, instances int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return sdk.ErrNotFound
+	}
+
+	res := make(sdk.AppInstances, 0, instances)
+	for i := 0; i < instances; i++ {
+		if i < len(m.instances[id]) {
+			res = append(res, m.instances[id][i])
+		} else {
+			res = append(res, sdk.AppInstance{Index: i, State: sdk.AppStateRunning, Since: m.now()})
+		}
+	}
+	m.instances[id] = res
+	return nil
+}
+
+// RestartAppInstance sets the instance at index running since now and counts the restart.
+func (m *Memory) RestartAppInstance(ctx context.Context, id string, index int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.apps[id]; !ok || index >= len(m.instances[id]) {
+		return sdk.ErrNotFound
+	}
+	m.instances[id][index] = sdk.AppInstance{
+		Index:    index,
+		State:    sdk.AppStateRunning,
+		Since:    m.now(),
+		Restarts: m.instances[id][index].Restarts + 1,
+	}
+	return nil
+}
+
+func (m *Memory) setInstanceStates(id string, state sdk.AppState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.apps[id]; !ok {
+		return sdk.ErrNotFound
+	}
+
+	res := make(sdk.AppInstances, 0, len(m.instances[id]))
+	for i := range m.instances[id] {
+		res = append(res, sdk.AppInstance{Index: i, State: state, Since: m.now()})
+	}
+	m.instances[id] = res
+	return nil
+}
+
+// TriggerPipeline adds a run started now, with all steps of the current version pending.
+func (m *Memory) TriggerPipeline(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pipeline, ok := m.pipelines[id]
+	if !ok {
+		return sdk.ErrNotFound
+	}
+
+	run := sdk.PipelineStatus{PipelineId: id, Started: m.now(), Trigger: &sdk.RunTrigger{Type: sdk.TriggerManual}}
+	for _, step := range pipeline.Current.Definition.Steps {
+		run.Steps = append(run.Steps, sdk.StepRun{StepId: step.Id, Status: sdk.StatusPending})
+	}
+	m.runs = append(m.runs, runChange{run: run, changed: m.now()})
+	return nil
+}
+
+// RerunStep sets a failed or aborted step of the latest run running since now.
+func (m *Memory) RerunStep(ctx context.Context, id string, stepId int) error {
+	return m.updateStep(id, stepId, func(step *sdk.StepRun) error {
+		if step.Status != sdk.StatusFailure && step.Status != sdk.StatusAborted {
+			return sdk.ErrConflict
+		}
+		*step = sdk.StepRun{StepId: stepId, Status: sdk.StatusRunning, Started: m.now()}
+		return nil
+	})
+}
+
+// ApproveStep sets a pending step of the latest run running since now.
+func (m *Memory) ApproveStep(ctx context.Context, id string, stepId int) error {
+	return m.updateStep(id, stepId, func(step *sdk.StepRun) error {
+		if step.Status != sdk.StatusPending {
+			return sdk.ErrConflict
+		}
+		*step = sdk.StepRun{StepId: stepId, Status: sdk.StatusRunning, Started: m.now()}
+		return nil
+	})
+}
+
+// AbortRun sets the running and pending steps of the latest run aborted.
+func (m *Memory) AbortRun(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	run, err := m.latestRun(id)
+	if err != nil {
+		return err
+	}
+
+	aborted := false
+	for i, step := range run.Steps {
+		if step.Status == sdk.StatusRunning || step.Status == sdk.StatusPending {
+			run.Steps[i].Status = sdk.StatusAborted
+			run.Steps[i].Ended = m.now()
+			aborted = true
+		}
+	}
+	if !aborted {
+		return sdk.ErrConflict
+	}
+
+	m.runs = removeRun(m.runs, run)
+	m.runs = append(m.runs, runChange{run: run, changed: m.now()})
+	return nil
+}
+
+func (m *Memory) updateStep(id string, stepId int, update func(step *sdk.StepRun) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	run, err := m.latestRun(id)
+	if err != nil {
+		return err
+	}
+
+	for i := range run.Steps {
+		if run.Steps[i].StepId != stepId {
+			continue
+		}
+		err = update(&run.Steps[i])
+		if err != nil {
+			return err
+		}
+		m.runs = removeRun(m.runs, run)
+		m.runs = append(m.runs, runChange{run: run, changed: m.now()})
+		return nil
+	}
+	return sdk.ErrNotFound
+}
+
+// latestRun returns a copy of the newest run of the pipeline that can be changed and
+// added again. Pipelines that never ran have nothing to act on.
+func (m *Memory) latestRun(id string) (sdk.PipelineStatus, error) {
+	if _, ok := m.pipelines[id]; !ok {
+		return sdk.PipelineStatus{}, sdk.ErrNotFound
+	}
+
+	var latest *sdk.PipelineStatus
+	for i, r := range m.runs {
+		if r.run.PipelineId == id && (latest == nil || r.run.Started.After(latest.Started)) {
+			latest = &m.runs[i].run
+		}
+	}
+	if latest == nil {
+		return sdk.PipelineStatus{}, sdk.ErrConflict
+	}
+
+	run := *latest
+	run.Steps = append([]sdk.StepRun(nil), latest.Steps...)
+	return run, nil
+}
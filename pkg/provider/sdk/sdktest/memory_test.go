@@ -0,0 +1,385 @@
+package sdktest
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"testing"
+	"time"
+)
+
+var someTime, _ = time.Parse(time.RFC3339, "2021-01-01T15:00:00Z")
+
+func TestMemoryConformance(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		RunConfig(t, NewMemory().Config("empty"))
+	})
+
+	t.Run("filled", func(t *testing.T) {
+		m := NewMemory()
+		for i := 0; i < 25; i++ {
+			id := fmt.Sprintf("app-%d", i)
+			m.PutApps(sdk.App{Id: id, Name: id})
+			m.SetRouting(id, sdk.AppRouting{Routes: sdk.AppRoutes{{Host: id + ".example.com", Path: "/", AppPort: 8080}}})
+			m.SetInstances(id, sdk.AppInstances{{State: sdk.AppStateRunning, Since: someTime}})
+			m.SetMetrics(id, sdk.AppMetrics{{Metric: sdk.MetricCpu, Unit: sdk.MetricUnitPercent, Points: []sdk.MetricPoint{
+				{Time: time.Now().Add(-2 * time.Hour), Value: 1},
+				{Time: time.Now().Add(-time.Minute), Value: 2},
+			}}})
+			m.AddLogs(id,
+				sdk.LogLine{Time: time.Now().Add(-2 * time.Hour), Stream: sdk.LogStreamOut, Message: "old"},
+				sdk.LogLine{Time: time.Now(), Stream: sdk.LogStreamErr, Message: "new"},
+			)
+		}
+		m.PutAlerts(
+			sdk.Alert{Id: "alert-1", AppId: "app-1", Kind: sdk.AlertKindAlert, Name: "HighLatency", Severity: sdk.AlertSeverityWarning, Started: someTime},
+			sdk.Alert{Id: "alert-3", AppId: "app-3", Kind: sdk.AlertKindIncident, Name: "Outage", Severity: sdk.AlertSeverityCritical, Started: someTime},
+		)
+		m.PutServices(sdk.BackingService{Id: "db", Name: "db", Kind: "postgres"})
+		m.PutDependencies(
+			sdk.Dependency{AppId: "app-1", Target: sdk.DependencyTargetApp, TargetId: "app-2"},
+			sdk.Dependency{AppId: "app-2", Target: sdk.DependencyTargetService, TargetId: "db", Name: "binding"},
+			sdk.Dependency{AppId: "app-7", Target: sdk.DependencyTargetService, TargetId: "db"},
+		)
+		m.DeleteApps("app-3", "app-7")
+
+		m.PutPipelines(sdk.Pipeline{Id: "pipeline", Name: "pipeline", Current: sdk.PipelineVersion{
+			Created: someTime,
+			Definition: sdk.PipelineDefinition{
+				Steps:       []sdk.PipelineStep{{Id: 1, Name: "build"}, {Id: 2, Name: "deploy"}},
+				Connections: []sdk.PipelineConnection{{From: 1, To: 2}},
+			},
+		}})
+		for i := 0; i < 10; i++ {
+			started := someTime.Add(time.Duration(i) * time.Hour)
+			m.AddPipelineRuns(sdk.PipelineStatus{PipelineId: "pipeline", Started: started, Steps: []sdk.StepRun{
+				{StepId: 1, Status: sdk.StatusSuccess, Started: started, Ended: started.Add(time.Minute)},
+				{StepId: 2, Status: sdk.StatusFailure, Started: started.Add(time.Minute), Ended: started.Add(2 * time.Minute),
+					Artifacts: []sdk.StepArtifact{{Name: "report", Url: "https://ci.example.com/report"}}},
+			}})
+			for j := 0; j < 15; j++ {
+				m.AddStepLogs("pipeline", started, 2, sdk.LogLine{Time: started.Add(time.Duration(j) * time.Second), Stream: sdk.LogStreamOut, Message: "deploying"})
+			}
+		}
+
+		m.PutGroups(
+			sdk.Group{Id: "group", Name: "group", Members: []sdk.Member{{Id: "member", Name: "member"}}},
+			sdk.Group{Id: "nested", Name: "nested", Parent: "group", Members: []sdk.Member{{Id: "maintainer", Name: "maintainer", Role: "maintainer"}}},
+		)
+
+		RunConfig(t, m.Config("filled"))
+	})
+}
+
+func TestMemoryUpdates(t *testing.T) {
+	now := someTime
+	m := NewMemoryWithClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	m.PutApps(sdk.App{Id: "a"}, sdk.App{Id: "b"})
+	now = now.Add(time.Hour)
+	m.PutApps(sdk.App{Id: "a", Name: "changed"})
+	m.DeleteApps("b", "unknown")
+
+	updates, err := m.ListAppUpdates(ctx, someTime.Add(time.Minute))
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	expected := sdk.AppUpdates{
+		Apps:    []sdk.App{{Id: "a", Name: "changed"}},
+		Deleted: []sdk.AppTombstone{{Id: "b", Deleted: now}},
+	}
+	if !cmp.Equal(expected, updates) {
+		t.Errorf("\ndiff between updates: \n%s\n", cmp.Diff(expected, updates))
+	}
+
+	m.PutPipelines(sdk.Pipeline{Id: "pipeline"})
+	m.AddPipelineRuns(sdk.PipelineStatus{PipelineId: "pipeline", Started: someTime})
+	now = now.Add(time.Hour)
+	m.AddPipelineRuns(sdk.PipelineStatus{PipelineId: "pipeline", Started: someTime, Steps: []sdk.StepRun{{StepId: 1, Status: sdk.StatusSuccess}}})
+
+	pipelineUpdates, err := m.ListUpdates(ctx, now)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	expectedRuns := sdk.PipelineStatusList{{PipelineId: "pipeline", Started: someTime, Steps: []sdk.StepRun{{StepId: 1, Status: sdk.StatusSuccess}}}}
+	if !cmp.Equal(expectedRuns, pipelineUpdates.Runs) || len(pipelineUpdates.Versions) != 0 {
+		t.Errorf("\nunexpected updates: %v\n", pipelineUpdates)
+	}
+}
+
+func TestMemoryLogs(t *testing.T) {
+	m := NewMemory()
+	m.PutApps(sdk.App{Id: "a"})
+	first := sdk.LogLine{Time: someTime, Message: "first"}
+	second := sdk.LogLine{Time: someTime.Add(time.Minute), Message: "second"}
+	m.AddLogs("a", first, second)
+
+	logs, err := m.GetRecentLogs(context.Background(), "a", time.Time{}, 1)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(sdk.AppLogs{second}, logs) {
+		t.Errorf("\ndiff between logs: \n%s\n", cmp.Diff(sdk.AppLogs{second}, logs))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan sdk.LogLine)
+	errs := make(chan error)
+	go func() {
+		errs <- m.TailLogs(ctx, "a", lines)
+	}()
+
+	third := sdk.LogLine{Time: someTime.Add(2 * time.Minute), Message: "third"}
+	for {
+		go m.AddLogs("a", third)
+		select {
+		case line := <-lines:
+			if !cmp.Equal(third, line) {
+				t.Errorf("\ndiff between tailed lines: \n%s\n", cmp.Diff(third, line))
+			}
+		case <-time.After(10 * time.Millisecond):
+			// the tail wasn't registered yet
+			continue
+		}
+		break
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err = m.GetRecentLogs(context.Background(), "unknown", time.Time{}, 1)
+	if err != sdk.ErrNotFound {
+		t.Errorf("wanted not found error, got %v", err)
+	}
+}
+
+func TestMemoryAlerts(t *testing.T) {
+	m := NewMemory()
+	m.PutApps(sdk.App{Id: "a"}, sdk.App{Id: "b"})
+	m.PutAlerts(
+		sdk.Alert{Id: "alert-a", AppId: "a", Started: someTime},
+		sdk.Alert{Id: "alert-b", AppId: "b", Started: someTime},
+		sdk.Alert{Id: "alert-c", AppId: "a", Started: someTime},
+	)
+	m.ResolveAlerts("alert-c", "unknown")
+	m.DeleteApps("b")
+
+	alerts, err := m.ListAlerts(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	expected := []sdk.Alert{{Id: "alert-a", AppId: "a", Started: someTime}}
+	if !cmp.Equal(expected, alerts) {
+		t.Errorf("\ndiff between alerts: \n%s\n", cmp.Diff(expected, alerts))
+	}
+}
+
+func TestMemoryTopology(t *testing.T) {
+	m := NewMemory()
+	m.PutApps(sdk.App{Id: "a"}, sdk.App{Id: "b"}, sdk.App{Id: "c"})
+	m.PutServices(sdk.BackingService{Id: "db"}, sdk.BackingService{Id: "queue"})
+	m.PutDependencies(
+		sdk.Dependency{AppId: "a", Target: sdk.DependencyTargetApp, TargetId: "b"},
+		sdk.Dependency{AppId: "a", Target: sdk.DependencyTargetService, TargetId: "db"},
+		sdk.Dependency{AppId: "a", Target: sdk.DependencyTargetService, TargetId: "queue"},
+		sdk.Dependency{AppId: "c", Target: sdk.DependencyTargetApp, TargetId: "a"},
+	)
+	m.DeleteApps("b")
+	m.DeleteServices("queue", "unknown")
+
+	topology, err := m.GetTopology(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	expected := sdk.Topology{
+		Services: []sdk.BackingService{{Id: "db"}},
+		Dependencies: []sdk.Dependency{
+			{AppId: "a", Target: sdk.DependencyTargetService, TargetId: "db"},
+			{AppId: "c", Target: sdk.DependencyTargetApp, TargetId: "a"},
+		},
+	}
+	if !cmp.Equal(expected, topology) {
+		t.Errorf("\ndiff between topologies: \n%s\n", cmp.Diff(expected, topology))
+	}
+}
+
+func TestMemoryAppActions(t *testing.T) {
+	later := someTime.Add(time.Hour)
+	m := NewMemoryWithClock(func() time.Time { return later })
+	m.PutApps(sdk.App{Id: "a"})
+	m.SetInstances("a", sdk.AppInstances{
+		{Index: 0, State: sdk.AppStateRunning, Since: someTime},
+		{Index: 1, State: sdk.AppStateCrashed, Since: someTime, LastCrash: "out of memory"},
+	})
+
+	steps := []struct {
+		desc     string
+		action   func(ctx context.Context) error
+		expected sdk.AppInstances
+	}{
+		{desc: "restarts instance", action: func(ctx context.Context) error {
+			return m.RestartAppInstance(ctx, "a", 1)
+		}, expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateRunning, Since: someTime},
+			{Index: 1, State: sdk.AppStateRunning, Since: later, Restarts: 1},
+		}},
+		{desc: "scales up", action: func(ctx context.Context) error {
+			return m.ScaleApp(ctx, "a", 3)
+		}, expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateRunning, Since: someTime},
+			{Index: 1, State: sdk.AppStateRunning, Since: later, Restarts: 1},
+			{Index: 2, State: sdk.AppStateRunning, Since: later},
+		}},
+		{desc: "scales down", action: func(ctx context.Context) error {
+			return m.ScaleApp(ctx, "a", 1)
+		}, expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateRunning, Since: someTime},
+		}},
+		{desc: "stops", action: func(ctx context.Context) error {
+			return m.StopApp(ctx, "a")
+		}, expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateStopped, Since: later},
+		}},
+		{desc: "starts", action: func(ctx context.Context) error {
+			return m.StartApp(ctx, "a")
+		}, expected: sdk.AppInstances{
+			{Index: 0, State: sdk.AppStateRunning, Since: later},
+		}},
+	}
+
+	for _, step := range steps {
+		err := step.action(context.Background())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.desc, err)
+		}
+		instances, _ := m.GetAppInstances(context.Background(), "a")
+		if !cmp.Equal(step.expected, instances) {
+			t.Errorf("%s: \ndiff between instances: \n%s\n", step.desc, cmp.Diff(step.expected, instances))
+		}
+	}
+
+	if err := m.RestartAppInstance(context.Background(), "a", 1); err != sdk.ErrNotFound {
+		t.Errorf("wanted not found for unknown instance, got %v", err)
+	}
+	if err := m.RestartApp(context.Background(), "unknown"); err != sdk.ErrNotFound {
+		t.Errorf("wanted not found for unknown app, got %v", err)
+	}
+}
+
+func TestMemoryPipelineActions(t *testing.T) {
+	later := someTime.Add(time.Hour)
+	m := NewMemoryWithClock(func() time.Time { return later })
+	m.PutPipelines(sdk.Pipeline{Id: "p", Current: sdk.PipelineVersion{Definition: sdk.PipelineDefinition{
+		Steps:       []sdk.PipelineStep{{Id: 0}, {Id: 1}},
+		Connections: []sdk.PipelineConnection{{From: 0, To: 1, Manual: true}},
+	}}})
+	ctx := context.Background()
+
+	if err := m.AbortRun(ctx, "p"); err != sdk.ErrConflict {
+		t.Errorf("wanted conflict for pipeline without runs, got %v", err)
+	}
+
+	m.AddPipelineRuns(sdk.PipelineStatus{PipelineId: "p", Started: someTime, Steps: []sdk.StepRun{
+		{StepId: 0, Status: sdk.StatusFailure, Started: someTime, Ended: someTime},
+		{StepId: 1, Status: sdk.StatusPending},
+	}})
+
+	steps := []struct {
+		desc     string
+		action   func(ctx context.Context) error
+		err      error
+		expected []sdk.StepRun
+	}{
+		{desc: "doesn't approve failed step", action: func(ctx context.Context) error {
+			return m.ApproveStep(ctx, "p", 0)
+		}, err: sdk.ErrConflict, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusFailure, Started: someTime, Ended: someTime},
+			{StepId: 1, Status: sdk.StatusPending},
+		}},
+		{desc: "reruns failed step", action: func(ctx context.Context) error {
+			return m.RerunStep(ctx, "p", 0)
+		}, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusRunning, Started: later},
+			{StepId: 1, Status: sdk.StatusPending},
+		}},
+		{desc: "approves pending step", action: func(ctx context.Context) error {
+			return m.ApproveStep(ctx, "p", 1)
+		}, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusRunning, Started: later},
+			{StepId: 1, Status: sdk.StatusRunning, Started: later},
+		}},
+		{desc: "aborts run", action: func(ctx context.Context) error {
+			return m.AbortRun(ctx, "p")
+		}, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusAborted, Started: later, Ended: later},
+			{StepId: 1, Status: sdk.StatusAborted, Started: later, Ended: later},
+		}},
+		{desc: "doesn't rerun unknown step", action: func(ctx context.Context) error {
+			return m.RerunStep(ctx, "p", 2)
+		}, err: sdk.ErrNotFound, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusAborted, Started: later, Ended: later},
+			{StepId: 1, Status: sdk.StatusAborted, Started: later, Ended: later},
+		}},
+		{desc: "triggers pipeline", action: func(ctx context.Context) error {
+			return m.TriggerPipeline(ctx, "p")
+		}, expected: []sdk.StepRun{
+			{StepId: 0, Status: sdk.StatusPending},
+			{StepId: 1, Status: sdk.StatusPending},
+		}},
+	}
+
+	for _, step := range steps {
+		err := step.action(ctx)
+		if err != step.err {
+			t.Fatalf("%s: wanted error %v, got %v", step.desc, step.err, err)
+		}
+		history, _ := m.GetHistory(ctx, "p", later.Add(time.Second), 1)
+		if len(history) != 1 {
+			t.Fatalf("%s: wanted latest run, got %d", step.desc, len(history))
+		}
+		if !cmp.Equal(step.expected, history[0].Steps) {
+			t.Errorf("%s: \ndiff between steps: \n%s\n", step.desc, cmp.Diff(step.expected, history[0].Steps))
+		}
+	}
+
+	if err := m.TriggerPipeline(ctx, "unknown"); err != sdk.ErrNotFound {
+		t.Errorf("wanted not found for unknown pipeline, got %v", err)
+	}
+}
+
+func TestMemoryStepLogs(t *testing.T) {
+	m := NewMemory()
+	m.PutPipelines(sdk.Pipeline{Id: "p"})
+	m.AddPipelineRuns(sdk.PipelineStatus{PipelineId: "p", Started: someTime, Steps: []sdk.StepRun{{StepId: 0}, {StepId: 1}}})
+	lines := []sdk.LogLine{
+		{Time: someTime, Stream: sdk.LogStreamOut, Message: "first"},
+		{Time: someTime.Add(time.Second), Stream: sdk.LogStreamErr, Message: "second"},
+		{Time: someTime.Add(2 * time.Second), Stream: sdk.LogStreamOut, Message: "third"},
+	}
+	m.AddStepLogs("p", someTime.In(time.FixedZone("other", 3600)), 0, lines...)
+	ctx := context.Background()
+
+	page, err := m.GetStepLogs(ctx, "p", someTime, 0, 2, 1)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if !cmp.Equal(lines[2:], page.Lines) || page.TotalResults != 3 {
+		t.Errorf("\ndiff between lines: \n%s\n", cmp.Diff(lines[2:], page.Lines))
+	}
+
+	page, err = m.GetStepLogs(ctx, "p", someTime, 1, 2, 0)
+	if err != nil || len(page.Lines) != 0 {
+		t.Errorf("wanted empty logs for step without lines, got %v, %v", page.Lines, err)
+	}
+
+	if _, err := m.GetStepLogs(ctx, "p", someTime, 2, 2, 0); err != sdk.ErrNotFound {
+		t.Errorf("wanted not found for unknown step, got %v", err)
+	}
+	if _, err := m.GetStepLogs(ctx, "p", someTime.Add(time.Hour), 0, 2, 0); err != sdk.ErrNotFound {
+		t.Errorf("wanted not found for unknown run, got %v", err)
+	}
+}
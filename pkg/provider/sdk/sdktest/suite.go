@@ -0,0 +1,833 @@
+// Package sdktest helps to test providers built with the SDK. It contains a conformance
+// suite checking that a provider speaks the protocol the core expects, and Memory, an
+// in-memory reference provider.
+package sdktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// notFoundId is an id no provider is expected to know.
+const notFoundId = "sdktest-not-found"
+
+// sampleSize is the number of items per feature that are requested one by one.
+const sampleSize = 20
+
+// historyLimit is the limit used when requesting the history of pipelines.
+const historyLimit = 5
+
+// logsLimit is the limit used when requesting the recent logs of apps.
+const logsLimit = 10
+
+// idPattern matches the ids the SDK serves in its routes.
+var idPattern = regexp.MustCompile(`^[0-9a-z-]+$`)
+
+// RunConfig runs the conformance suite against the provider config. The config is served
+// by a test server without its auth settings, as the suite only covers the protocol.
+func RunConfig(t *testing.T, c sdk.ProviderConfig) {
+	c.Auth = sdk.AuthConfig{}
+	server := httptest.NewServer(sdk.NewHandler(c))
+	defer server.Close()
+
+	RunURL(t, server.URL, server.Client())
+}
+
+// RunURL runs the conformance suite against the provider served at the given url. The
+// client has to present the credentials the provider expects, if nil http.DefaultClient
+// is used. The data of the provider must not change while the suite runs.
+func RunURL(t *testing.T, uri string, c *http.Client) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	s := &suite{
+		uri: strings.TrimSuffix(uri, "/"),
+		c:   c,
+		now: time.Now(),
+	}
+
+	info := sdk.ProviderInfo{}
+	t.Run("info", func(t *testing.T) {
+		if s.get(t, "/info", &info) != http.StatusOK {
+			t.Fatalf("GET /info: wanted status %d", http.StatusOK)
+		}
+		err := info.CheckCompatible()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	if t.Failed() {
+		return
+	}
+
+	features := make(map[sdk.Feature]bool)
+	for _, feature := range info.Features {
+		features[feature] = true
+	}
+
+	if features[sdk.FeatureApps] {
+		t.Run("apps", s.testApps)
+		if features[sdk.FeatureAppUpdates] {
+			t.Run("app updates", s.testAppUpdates)
+		}
+	}
+	if features[sdk.FeatureRouting] {
+		t.Run("routing", func(t *testing.T) {
+			s.testAppDetails(t, "/routing", features[sdk.FeatureApps], &sdk.AppRouting{})
+		})
+	}
+	if features[sdk.FeatureInstances] {
+		t.Run("instances", func(t *testing.T) {
+			s.testAppDetails(t, "/instances", features[sdk.FeatureApps], &sdk.AppInstances{})
+		})
+	}
+	if features[sdk.FeaturePipelines] {
+		t.Run("pipelines", s.testPipelines)
+		t.Run("pipeline updates", s.testPipelineUpdates)
+		if features[sdk.FeaturePipelineActions] {
+			t.Run("pipeline actions", s.testPipelineActions)
+		}
+	}
+	if features[sdk.FeatureGroups] {
+		t.Run("groups", s.testGroups)
+	}
+	if features[sdk.FeatureMetrics] {
+		t.Run("metrics", func(t *testing.T) {
+			s.testMetrics(t, features[sdk.FeatureApps])
+		})
+	}
+	if features[sdk.FeatureLogs] {
+		t.Run("logs", func(t *testing.T) {
+			s.testLogs(t, features[sdk.FeatureApps])
+		})
+	}
+	if features[sdk.FeatureAlerts] {
+		t.Run("alerts", s.testAlerts)
+	}
+	if features[sdk.FeatureStepLogs] {
+		t.Run("step logs", func(t *testing.T) {
+			s.testStepLogs(t, features[sdk.FeaturePipelines])
+		})
+	}
+	if features[sdk.FeatureTopology] {
+		t.Run("topology", func(t *testing.T) {
+			s.testTopology(t, features[sdk.FeatureApps])
+		})
+	}
+	if features[sdk.FeatureAppActions] {
+		t.Run("app actions", s.testAppActions)
+	}
+}
+
+type suite struct {
+	uri string
+	c   *http.Client
+	now time.Time
+}
+
+func (s *suite) testApps(t *testing.T) {
+	var apps []sdk.App
+	if s.get(t, "/apps", &apps) != http.StatusOK {
+		t.Fatalf("GET /apps: wanted status %d", http.StatusOK)
+	}
+
+	ids := appIds(apps)
+	if err := checkIds(ids); err != nil {
+		t.Errorf("GET /apps: %v", err)
+	}
+
+	for _, id := range sample(ids) {
+		app := sdk.App{}
+		if s.get(t, "/apps/"+id, &app) != http.StatusOK {
+			t.Errorf("GET /apps/%s: wanted status %d for listed app", id, http.StatusOK)
+		} else if app.Id != id {
+			t.Errorf("GET /apps/%s: returned app with id '%s'", id, app.Id)
+		}
+	}
+
+	s.expectNotFound(t, "/apps/"+notFoundId)
+
+	perPage := (len(apps)+2)/3 + 1
+	var pages []sdk.AppPage
+	for page := 0; page <= len(apps); page++ {
+		p := sdk.AppPage{}
+		path := fmt.Sprintf("/apps?perPage=%d&page=%d", perPage, page)
+		if s.get(t, path, &p) != http.StatusOK {
+			t.Fatalf("GET %s: wanted status %d", path, http.StatusOK)
+		}
+		pages = append(pages, p)
+		if page+1 >= p.TotalPages {
+			break
+		}
+	}
+	if err := checkAppPages(apps, perPage, pages); err != nil {
+		t.Errorf("GET /apps?perPage=%d: %v", perPage, err)
+	}
+
+	exceeded := fmt.Sprintf("/apps?perPage=%d&page=%d", perPage, len(pages))
+	if code := s.get(t, exceeded, nil); code != http.StatusBadRequest {
+		t.Errorf("GET %s: wanted status %d for page after the last, got %d", exceeded, http.StatusBadRequest, code)
+	}
+}
+
+func (s *suite) testAppUpdates(t *testing.T) {
+	var previous []string
+	for i, since := range s.sinces() {
+		updates := sdk.AppUpdates{}
+		path := "/apps/updates?since=" + url.QueryEscape(since.Format(time.RFC3339))
+		if s.get(t, path, &updates) != http.StatusOK {
+			t.Fatalf("GET %s: wanted status %d", path, http.StatusOK)
+		}
+
+		var keys []string
+		for _, app := range updates.Apps {
+			keys = append(keys, "app/"+app.Id)
+		}
+		for _, tombstone := range updates.Deleted {
+			keys = append(keys, "tombstone/"+tombstone.Id)
+			if tombstone.Deleted.Before(since.Truncate(time.Second)) {
+				t.Errorf("GET %s: tombstone of app '%s' was deleted before the requested time", path, tombstone.Id)
+			}
+		}
+
+		if i > 0 {
+			if err := checkSubset(keys, previous); err != nil {
+				t.Errorf("GET %s: updates aren't monotonic: %v", path, err)
+			}
+		}
+		previous = keys
+	}
+}
+
+// testAppDetails checks the routing and instances endpoints, which are keyed by app.
+func (s *suite) testAppDetails(t *testing.T, path string, withApps bool, res interface{}) {
+	if withApps {
+		var apps []sdk.App
+		if s.get(t, "/apps", &apps) != http.StatusOK {
+			t.Fatalf("GET /apps: wanted status %d", http.StatusOK)
+		}
+		for _, app := range sample(appIds(apps)) {
+			if code := s.get(t, path+"/"+app, res); code != http.StatusOK {
+				t.Errorf("GET %s/%s: wanted status %d for listed app, got %d", path, app, http.StatusOK, code)
+			}
+		}
+	}
+
+	// providers may know nothing about an app without failing, but must answer in the envelope
+	code := s.get(t, path+"/"+notFoundId, nil)
+	if code != http.StatusOK && code != http.StatusNotFound {
+		t.Errorf("GET %s/%s: wanted status %d or %d, got %d", path, notFoundId, http.StatusOK, http.StatusNotFound, code)
+	}
+}
+
+// testMetrics checks the metrics of apps over the last hour.
+func (s *suite) testMetrics(t *testing.T, withApps bool) {
+	q := sdk.DefaultMetricsQuery(s.now.Truncate(time.Second))
+	query := fmt.Sprintf("?from=%s&to=%s&step=%s", url.QueryEscape(q.From.Format(time.RFC3339)), url.QueryEscape(q.To.Format(time.RFC3339)), q.Step)
+
+	if withApps {
+		var apps []sdk.App
+		if s.get(t, "/apps", &apps) != http.StatusOK {
+			t.Fatalf("GET /apps: wanted status %d", http.StatusOK)
+		}
+		for _, app := range sample(appIds(apps)) {
+			path := "/metrics/" + app + query
+			metrics := sdk.AppMetrics{}
+			if code := s.get(t, path, &metrics); code != http.StatusOK {
+				t.Errorf("GET %s: wanted status %d for listed app, got %d", path, http.StatusOK, code)
+				continue
+			}
+			if err := checkMetrics(metrics, q); err != nil {
+				t.Errorf("GET %s: %v", path, err)
+			}
+		}
+	}
+
+	code := s.get(t, "/metrics/"+notFoundId+query, nil)
+	if code != http.StatusOK && code != http.StatusNotFound {
+		t.Errorf("GET /metrics/%s: wanted status %d or %d, got %d", notFoundId, http.StatusOK, http.StatusNotFound, code)
+	}
+
+	path := "/metrics/" + notFoundId + "?step=often"
+	if code := s.get(t, path, nil); code != http.StatusBadRequest {
+		t.Errorf("GET %s: wanted status %d for malformed step, got %d", path, http.StatusBadRequest, code)
+	}
+}
+
+// testLogs checks the recent logs of apps. Tailing is not covered, as it blocks until the
+// app logs something.
+func (s *suite) testLogs(t *testing.T, withApps bool) {
+	if withApps {
+		var apps []sdk.App
+		if s.get(t, "/apps", &apps) != http.StatusOK {
+			t.Fatalf("GET /apps: wanted status %d", http.StatusOK)
+		}
+		for _, app := range sample(appIds(apps)) {
+			since := s.now.Add(-time.Hour).Truncate(time.Second)
+			path := fmt.Sprintf("/logs/%s?since=%s&limit=%d", app, url.QueryEscape(since.Format(time.RFC3339)), logsLimit)
+			logs := sdk.AppLogs{}
+			if code := s.get(t, path, &logs); code != http.StatusOK {
+				t.Errorf("GET %s: wanted status %d for listed app, got %d", path, http.StatusOK, code)
+				continue
+			}
+			if err := checkLogs(logs, since, logsLimit); err != nil {
+				t.Errorf("GET %s: %v", path, err)
+			}
+		}
+	}
+
+	code := s.get(t, "/logs/"+notFoundId, nil)
+	if code != http.StatusOK && code != http.StatusNotFound {
+		t.Errorf("GET /logs/%s: wanted status %d or %d, got %d", notFoundId, http.StatusOK, http.StatusNotFound, code)
+	}
+}
+
+// testStepLogs checks the logs of the steps in the latest runs of pipelines.
+func (s *suite) testStepLogs(t *testing.T, withPipelines bool) {
+	if withPipelines {
+		var pipelines []sdk.Pipeline
+		if s.get(t, "/pipelines", &pipelines) != http.StatusOK {
+			t.Fatalf("GET /pipelines: wanted status %d", http.StatusOK)
+		}
+		ids := make([]string, 0, len(pipelines))
+		for _, pipeline := range pipelines {
+			ids = append(ids, pipeline.Id)
+		}
+
+		for _, id := range sample(ids) {
+			path := fmt.Sprintf("/pipelines/%s/history?limit=%d", id, historyLimit)
+			history := sdk.PipelineStatusList{}
+			if s.get(t, path, &history) != http.StatusOK {
+				t.Errorf("GET %s: wanted status %d for listed pipeline", path, http.StatusOK)
+				continue
+			}
+			for _, run := range history {
+				for _, step := range run.Steps {
+					path := fmt.Sprintf("/steplogs/%s/runs/%s/steps/%d?perPage=%d", id, run.Started.Format(time.RFC3339Nano), step.StepId, logsLimit)
+					page := sdk.StepLogsPage{}
+					if code := s.get(t, path, &page); code != http.StatusOK {
+						t.Errorf("GET %s: wanted status %d for step of listed run, got %d", path, http.StatusOK, code)
+						continue
+					}
+					if err := checkStepLogs(page, logsLimit); err != nil {
+						t.Errorf("GET %s: %v", path, err)
+					}
+				}
+			}
+		}
+	}
+
+	run := s.now.Truncate(time.Second).Format(time.RFC3339)
+	s.expectNotFound(t, "/steplogs/"+notFoundId+"/runs/"+run+"/steps/0")
+
+	path := "/steplogs/" + notFoundId + "/runs/yesterday/steps/0"
+	if code := s.get(t, path, nil); code != http.StatusBadRequest {
+		t.Errorf("GET %s: wanted status %d for malformed run, got %d", path, http.StatusBadRequest, code)
+	}
+}
+
+func (s *suite) testPipelines(t *testing.T) {
+	var pipelines []sdk.Pipeline
+	if s.get(t, "/pipelines", &pipelines) != http.StatusOK {
+		t.Fatalf("GET /pipelines: wanted status %d", http.StatusOK)
+	}
+
+	ids := make([]string, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		ids = append(ids, pipeline.Id)
+		if err := checkDefinition(pipeline.Current.Definition); err != nil {
+			t.Errorf("GET /pipelines: pipeline '%s': %v", pipeline.Id, err)
+		}
+	}
+	if err := checkIds(ids); err != nil {
+		t.Errorf("GET /pipelines: %v", err)
+	}
+
+	for _, id := range sample(ids) {
+		pipeline := sdk.Pipeline{}
+		if s.get(t, "/pipelines/"+id, &pipeline) != http.StatusOK {
+			t.Errorf("GET /pipelines/%s: wanted status %d for listed pipeline", id, http.StatusOK)
+		} else if pipeline.Id != id {
+			t.Errorf("GET /pipelines/%s: returned pipeline with id '%s'", id, pipeline.Id)
+		}
+
+		before := s.now.Truncate(time.Second)
+		path := fmt.Sprintf("/pipelines/%s/history?before=%s&limit=%d", id, url.QueryEscape(before.Format(time.RFC3339)), historyLimit)
+		history := sdk.PipelineStatusList{}
+		if s.get(t, path, &history) != http.StatusOK {
+			t.Errorf("GET %s: wanted status %d for listed pipeline", path, http.StatusOK)
+			continue
+		}
+		if len(history) > historyLimit {
+			t.Errorf("GET %s: returned %d runs, more than the limit", path, len(history))
+		}
+		for _, run := range history {
+			if run.PipelineId != id {
+				t.Errorf("GET %s: returned run of pipeline '%s'", path, run.PipelineId)
+			}
+			if !run.Started.Before(before) {
+				t.Errorf("GET %s: returned run started at %v", path, run.Started)
+			}
+		}
+	}
+
+	s.expectNotFound(t, "/pipelines/"+notFoundId)
+	s.expectNotFound(t, "/pipelines/"+notFoundId+"/history")
+}
+
+func (s *suite) testPipelineUpdates(t *testing.T) {
+	var previous []string
+	for i, since := range s.sinces() {
+		updates := sdk.PipelineUpdates{}
+		path := "/pipelines/updates?since=" + url.QueryEscape(since.Format(time.RFC3339))
+		if s.get(t, path, &updates) != http.StatusOK {
+			t.Fatalf("GET %s: wanted status %d", path, http.StatusOK)
+		}
+
+		var keys []string
+		for _, version := range updates.Versions {
+			keys = append(keys, fmt.Sprintf("version/%s/%s", version.PipelineId, version.Created.Format(time.RFC3339Nano)))
+			if err := checkDefinition(version.Definition); err != nil {
+				t.Errorf("GET %s: version of pipeline '%s': %v", path, version.PipelineId, err)
+			}
+		}
+		for _, run := range updates.Runs {
+			keys = append(keys, fmt.Sprintf("run/%s/%s", run.PipelineId, run.Started.Format(time.RFC3339Nano)))
+		}
+
+		if i > 0 {
+			if err := checkSubset(keys, previous); err != nil {
+				t.Errorf("GET %s: updates aren't monotonic: %v", path, err)
+			}
+		}
+		previous = keys
+	}
+}
+
+func (s *suite) testGroups(t *testing.T) {
+	var groups []sdk.Group
+	if s.get(t, "/groups", &groups) != http.StatusOK {
+		t.Fatalf("GET /groups: wanted status %d", http.StatusOK)
+	}
+
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.Id)
+	}
+	if err := checkIds(ids); err != nil {
+		t.Errorf("GET /groups: %v", err)
+	}
+
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	for _, group := range groups {
+		if group.Parent != "" && !listed[group.Parent] {
+			t.Errorf("GET /groups: group '%s' is nested in unlisted group '%s'", group.Id, group.Parent)
+		}
+	}
+
+	for _, id := range sample(ids) {
+		group := sdk.Group{}
+		if s.get(t, "/groups/"+id, &group) != http.StatusOK {
+			t.Errorf("GET /groups/%s: wanted status %d for listed group", id, http.StatusOK)
+		} else if group.Id != id {
+			t.Errorf("GET /groups/%s: returned group with id '%s'", id, group.Id)
+		}
+	}
+
+	s.expectNotFound(t, "/groups/"+notFoundId)
+}
+
+// testAlerts checks the active alerts. Their app ids aren't checked against the apps, as
+// alerts are commonly served by monitoring tools that don't serve apps themselves.
+func (s *suite) testAlerts(t *testing.T) {
+	var alerts []sdk.Alert
+	if s.get(t, "/alerts", &alerts) != http.StatusOK {
+		t.Fatalf("GET /alerts: wanted status %d", http.StatusOK)
+	}
+
+	if err := checkAlerts(alerts); err != nil {
+		t.Errorf("GET /alerts: %v", err)
+	}
+}
+
+// testTopology checks the dependencies and backing services. If the provider serves apps as
+// well, the depending apps have to be listed. Target apps may be served by other providers.
+func (s *suite) testTopology(t *testing.T, withApps bool) {
+	var topology sdk.Topology
+	if s.get(t, "/topology", &topology) != http.StatusOK {
+		t.Fatalf("GET /topology: wanted status %d", http.StatusOK)
+	}
+
+	if err := checkTopology(topology); err != nil {
+		t.Errorf("GET /topology: %v", err)
+	}
+
+	if withApps {
+		var apps []sdk.App
+		if s.get(t, "/apps", &apps) != http.StatusOK {
+			t.Fatalf("GET /apps: wanted status %d", http.StatusOK)
+		}
+		listed := make(map[string]bool, len(apps))
+		for _, id := range appIds(apps) {
+			listed[id] = true
+		}
+		for _, dependency := range topology.Dependencies {
+			if !listed[dependency.AppId] {
+				t.Errorf("GET /topology: dependency '%s' of unlisted app", dependency.Id())
+			}
+		}
+	}
+}
+
+// testAppActions checks that actions on unknown apps fail and that malformed requests are
+// rejected. Listed apps are left alone, as the suite must not change the provider's data.
+func (s *suite) testAppActions(t *testing.T) {
+	for _, action := range []string{"restart", "stop", "start", "instances/0/restart"} {
+		path := "/actions/" + notFoundId + "/" + action
+		if code := s.post(t, path, ""); code != http.StatusNotFound {
+			t.Errorf("POST %s: wanted status %d for unknown id, got %d", path, http.StatusNotFound, code)
+		}
+	}
+
+	path := "/actions/" + notFoundId + "/scale"
+	if code := s.post(t, path, `{"instances": 1}`); code != http.StatusNotFound {
+		t.Errorf("POST %s: wanted status %d for unknown id, got %d", path, http.StatusNotFound, code)
+	}
+	if code := s.post(t, path, `{"instances": -1}`); code != http.StatusBadRequest {
+		t.Errorf("POST %s: wanted status %d for negative instances, got %d", path, http.StatusBadRequest, code)
+	}
+	if code := s.post(t, path, `{"instances": "many"}`); code != http.StatusBadRequest {
+		t.Errorf("POST %s: wanted status %d for malformed body, got %d", path, http.StatusBadRequest, code)
+	}
+}
+
+// testPipelineActions checks that actions on unknown pipelines fail. Like testAppActions, it
+// leaves listed pipelines alone.
+func (s *suite) testPipelineActions(t *testing.T) {
+	for _, action := range []string{"trigger", "abort", "steps/0/rerun", "steps/0/approve"} {
+		path := "/pipelines/" + notFoundId + "/" + action
+		if code := s.post(t, path, ""); code != http.StatusNotFound {
+			t.Errorf("POST %s: wanted status %d for unknown id, got %d", path, http.StatusNotFound, code)
+		}
+	}
+}
+
+// sinces are the points in time updates are requested for, from oldest to newest.
+func (s *suite) sinces() []time.Time {
+	return []time.Time{
+		{},
+		s.now.Add(-24 * time.Hour),
+		s.now.Add(-time.Hour),
+		s.now,
+	}
+}
+
+func (s *suite) expectNotFound(t *testing.T, path string) {
+	t.Helper()
+	if code := s.get(t, path, nil); code != http.StatusNotFound {
+		t.Errorf("GET %s: wanted status %d for unknown id, got %d", path, http.StatusNotFound, code)
+	}
+}
+
+// get requests the path and checks that the response is wrapped in the envelope of the
+// protocol. Successful results are decoded into res if given. It returns the status code.
+func (s *suite) get(t *testing.T, path string, res interface{}) int {
+	t.Helper()
+	return s.do(t, "GET", path, "", res)
+}
+
+// post sends the body to the path, checking the response like get.
+func (s *suite) post(t *testing.T, path string, body string) int {
+	t.Helper()
+	return s.do(t, "POST", path, body, nil)
+}
+
+func (s *suite) do(t *testing.T, method string, path string, body string, res interface{}) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.uri+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+
+	resp, err := s.c.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+
+	e, err := checkEnvelope(resp.StatusCode, b)
+	if err != nil {
+		t.Errorf("%s %s: %v", method, path, err)
+		return resp.StatusCode
+	}
+
+	if resp.StatusCode == http.StatusOK && res != nil {
+		err = json.Unmarshal(e.Result, res)
+		if err != nil {
+			t.Errorf("%s %s: malformed result: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+type envelope struct {
+	Status int             `json:"status"`
+	Err    string          `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// checkEnvelope checks that the body is an envelope matching the status code. Errors
+// have to carry a message, successful responses must not.
+func checkEnvelope(code int, body []byte) (envelope, error) {
+	e := envelope{}
+	err := json.Unmarshal(body, &e)
+	if err != nil {
+		return envelope{}, fmt.Errorf("response is not a json envelope: %w", err)
+	}
+	if e.Status != code {
+		return envelope{}, fmt.Errorf("envelope status %d differs from http status %d", e.Status, code)
+	}
+	if code == http.StatusOK && e.Err != "" {
+		return envelope{}, fmt.Errorf("successful response contains error '%s'", e.Err)
+	}
+	if code != http.StatusOK && e.Err == "" {
+		return envelope{}, fmt.Errorf("response with status %d has no error message", code)
+	}
+	return e, nil
+}
+
+// checkIds checks that the ids are unique and can be requested from the SDK's routes.
+func checkIds(ids []string) error {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !idPattern.MatchString(id) {
+			return fmt.Errorf("id '%s' doesn't match %s", id, idPattern)
+		}
+		if seen[id] {
+			return fmt.Errorf("id '%s' is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// checkAppPages checks that the pages describe the listed apps consistently and together
+// contain every app exactly once.
+func checkAppPages(apps []sdk.App, perPage int, pages []sdk.AppPage) error {
+	totalPages := (len(apps) + perPage - 1) / perPage
+	seen := make(map[string]bool, len(apps))
+	for i, page := range pages {
+		if page.Page != i || page.PerPage != perPage {
+			return fmt.Errorf("page %d claims to be page %d with %d per page", i, page.Page, page.PerPage)
+		}
+		if page.TotalResults != len(apps) || page.TotalPages != totalPages {
+			return fmt.Errorf("page %d claims %d results on %d pages, wanted %d on %d", i, page.TotalResults, page.TotalPages, len(apps), totalPages)
+		}
+		if len(page.Apps) > perPage || (i < totalPages-1 && len(page.Apps) != perPage) {
+			return fmt.Errorf("page %d contains %d apps", i, len(page.Apps))
+		}
+		for _, app := range page.Apps {
+			if seen[app.Id] {
+				return fmt.Errorf("app '%s' is on more than one page", app.Id)
+			}
+			seen[app.Id] = true
+		}
+	}
+
+	for _, app := range apps {
+		if !seen[app.Id] {
+			return fmt.Errorf("app '%s' is on no page", app.Id)
+		}
+	}
+	return nil
+}
+
+// checkDefinition checks that step ids are unique and connections only point at existing steps.
+func checkDefinition(d sdk.PipelineDefinition) error {
+	steps := make(map[int]bool, len(d.Steps))
+	for _, step := range d.Steps {
+		if steps[step.Id] {
+			return fmt.Errorf("step id %d is used more than once", step.Id)
+		}
+		steps[step.Id] = true
+	}
+
+	for _, c := range d.Connections {
+		if !steps[c.From] {
+			return fmt.Errorf("connection from %d to %d starts at unknown step", c.From, c.To)
+		}
+		if !steps[c.To] {
+			return fmt.Errorf("connection from %d to %d ends at unknown step", c.From, c.To)
+		}
+	}
+	return nil
+}
+
+// checkMetrics checks that every series names its metric and unit and that its points are
+// ordered and within the range of the query.
+func checkMetrics(metrics sdk.AppMetrics, q sdk.MetricsQuery) error {
+	for _, series := range metrics {
+		if series.Metric == "" || series.Unit == "" {
+			return fmt.Errorf("series without metric or unit: %v", series)
+		}
+		for i, point := range series.Points {
+			if point.Time.Before(q.From) || point.Time.After(q.To) {
+				return fmt.Errorf("point of %s at %v, outside of the range", series.Metric, point.Time)
+			}
+			if i > 0 && !point.Time.After(series.Points[i-1].Time) {
+				return fmt.Errorf("points of %s aren't ordered at %v", series.Metric, point.Time)
+			}
+		}
+	}
+	return nil
+}
+
+// checkAlerts checks that every alert is listed once, belongs to an app and has a known kind
+// and severity.
+func checkAlerts(alerts []sdk.Alert) error {
+	seen := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		if alert.Id == "" || alert.AppId == "" {
+			return fmt.Errorf("alert without id or app id: %v", alert)
+		}
+		if seen[alert.Id] {
+			return fmt.Errorf("alert '%s' is listed more than once", alert.Id)
+		}
+		seen[alert.Id] = true
+
+		switch alert.Kind {
+		case sdk.AlertKindAlert, sdk.AlertKindIncident:
+		default:
+			return fmt.Errorf("alert '%s' has unknown kind '%s'", alert.Id, alert.Kind)
+		}
+		switch alert.Severity {
+		case sdk.AlertSeverityCritical, sdk.AlertSeverityWarning, sdk.AlertSeverityInfo:
+		default:
+			return fmt.Errorf("alert '%s' has unknown severity '%s'", alert.Id, alert.Severity)
+		}
+		if alert.Started.IsZero() {
+			return fmt.Errorf("alert '%s' has no start time", alert.Id)
+		}
+	}
+	return nil
+}
+
+// checkTopology checks that services and dependencies are listed once and that dependencies
+// have a known target. Dependencies on services have to point to listed services.
+func checkTopology(topology sdk.Topology) error {
+	services := make(map[string]bool, len(topology.Services))
+	for _, service := range topology.Services {
+		if service.Id == "" {
+			return fmt.Errorf("service without id: %v", service)
+		}
+		if services[service.Id] {
+			return fmt.Errorf("service '%s' is listed more than once", service.Id)
+		}
+		services[service.Id] = true
+	}
+
+	seen := make(map[string]bool, len(topology.Dependencies))
+	for _, dependency := range topology.Dependencies {
+		if dependency.AppId == "" || dependency.TargetId == "" {
+			return fmt.Errorf("dependency without app or target id: %v", dependency)
+		}
+		if seen[dependency.Id()] {
+			return fmt.Errorf("dependency '%s' is listed more than once", dependency.Id())
+		}
+		seen[dependency.Id()] = true
+
+		switch dependency.Target {
+		case sdk.DependencyTargetApp:
+		case sdk.DependencyTargetService:
+			if !services[dependency.TargetId] {
+				return fmt.Errorf("dependency '%s' points to unlisted service", dependency.Id())
+			}
+		default:
+			return fmt.Errorf("dependency '%s' has unknown target '%s'", dependency.Id(), dependency.Target)
+		}
+	}
+	return nil
+}
+
+// checkLogs checks that the lines are within the limit, not older than since and
+// ordered oldest first.
+func checkLogs(logs sdk.AppLogs, since time.Time, limit int) error {
+	if len(logs) > limit {
+		return fmt.Errorf("returned %d lines, more than the limit of %d", len(logs), limit)
+	}
+	for i, line := range logs {
+		if line.Time.Before(since) {
+			return fmt.Errorf("line %d logged at %v, before %v", i, line.Time, since)
+		}
+		if i > 0 && line.Time.Before(logs[i-1].Time) {
+			return fmt.Errorf("line %d logged at %v, before the previous line", i, line.Time)
+		}
+	}
+	return nil
+}
+
+func checkStepLogs(page sdk.StepLogsPage, perPage int) error {
+	if page.PerPage != perPage || page.Page != 0 {
+		return fmt.Errorf("returned page %d with %d per page, wanted page 0 with %d", page.Page, page.PerPage, perPage)
+	}
+	if len(page.Lines) > perPage {
+		return fmt.Errorf("returned %d lines, more than %d per page", len(page.Lines), perPage)
+	}
+	if page.TotalResults < len(page.Lines) || page.TotalPages != (page.TotalResults+perPage-1)/perPage {
+		return fmt.Errorf("returned %d total lines on %d pages, which doesn't match the page", page.TotalResults, page.TotalPages)
+	}
+	for i, line := range page.Lines {
+		if i > 0 && line.Time.Before(page.Lines[i-1].Time) {
+			return fmt.Errorf("line %d logged at %v, before the previous line", i, line.Time)
+		}
+	}
+	return nil
+}
+
+// checkSubset checks that the updates since a later time are contained in the updates
+// since an earlier one.
+func checkSubset(later []string, earlier []string) error {
+	contained := make(map[string]bool, len(earlier))
+	for _, key := range earlier {
+		contained[key] = true
+	}
+	for _, key := range later {
+		if !contained[key] {
+			return fmt.Errorf("%s is only returned for the later time", key)
+		}
+	}
+	return nil
+}
+
+func sample(ids []string) []string {
+	if len(ids) > sampleSize {
+		return ids[:sampleSize]
+	}
+	return ids
+}
+
+func appIds(apps []sdk.App) []string {
+	res := make([]string, 0, len(apps))
+	for _, app := range apps {
+		res = append(res, app.Id)
+	}
+	return res
+}
@@ -0,0 +1,270 @@
+package sdktest
+
+import (
+	"github.com/joscha-alisch/dyve/pkg/provider/sdk"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckEnvelope(t *testing.T) {
+	tests := []struct {
+		desc  string
+		code  int
+		body  string
+		valid bool
+	}{
+		{desc: "accepts result", code: http.StatusOK, body: `{"status":200,"result":[]}`, valid: true},
+		{desc: "accepts error", code: http.StatusNotFound, body: `{"status":404,"error":"not found"}`, valid: true},
+		{desc: "rejects plain body", code: http.StatusOK, body: `[]`},
+		{desc: "rejects differing status", code: http.StatusNotFound, body: `{"status":200,"error":"not found"}`},
+		{desc: "rejects error without message", code: http.StatusInternalServerError, body: `{"status":500}`},
+		{desc: "rejects result with message", code: http.StatusOK, body: `{"status":200,"error":"oops"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			_, err := checkEnvelope(test.code, []byte(test.body))
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckIds(t *testing.T) {
+	tests := []struct {
+		desc  string
+		ids   []string
+		valid bool
+	}{
+		{desc: "accepts ids", ids: []string{"a", "840e560f-38d3-460e-be23-8677a4539f35"}, valid: true},
+		{desc: "rejects duplicates", ids: []string{"a", "a"}},
+		{desc: "rejects ids not served by routes", ids: []string{"App_A"}},
+		{desc: "rejects empty ids", ids: []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkIds(test.ids)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckAppPages(t *testing.T) {
+	apps := []sdk.App{{Id: "a"}, {Id: "b"}, {Id: "c"}}
+	pagination := func(page int) sdk.Pagination {
+		return sdk.Pagination{TotalResults: 3, TotalPages: 2, PerPage: 2, Page: page}
+	}
+
+	tests := []struct {
+		desc  string
+		pages []sdk.AppPage
+		valid bool
+	}{
+		{desc: "accepts pages", pages: []sdk.AppPage{
+			{Pagination: pagination(0), Apps: []sdk.App{{Id: "a"}, {Id: "b"}}},
+			{Pagination: pagination(1), Apps: []sdk.App{{Id: "c"}}},
+		}, valid: true},
+		{desc: "rejects missing app", pages: []sdk.AppPage{
+			{Pagination: pagination(0), Apps: []sdk.App{{Id: "a"}, {Id: "b"}}},
+			{Pagination: pagination(1), Apps: []sdk.App{}},
+		}},
+		{desc: "rejects duplicate app", pages: []sdk.AppPage{
+			{Pagination: pagination(0), Apps: []sdk.App{{Id: "a"}, {Id: "b"}}},
+			{Pagination: pagination(1), Apps: []sdk.App{{Id: "b"}}},
+		}},
+		{desc: "rejects wrong totals", pages: []sdk.AppPage{
+			{Pagination: sdk.Pagination{TotalResults: 2, TotalPages: 1, PerPage: 2}, Apps: []sdk.App{{Id: "a"}, {Id: "b"}}},
+		}},
+		{desc: "rejects short page before the last", pages: []sdk.AppPage{
+			{Pagination: pagination(0), Apps: []sdk.App{{Id: "a"}}},
+			{Pagination: pagination(1), Apps: []sdk.App{{Id: "b"}, {Id: "c"}}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkAppPages(apps, 2, test.pages)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckDefinition(t *testing.T) {
+	steps := []sdk.PipelineStep{{Id: 1}, {Id: 2}}
+
+	tests := []struct {
+		desc       string
+		definition sdk.PipelineDefinition
+		valid      bool
+	}{
+		{desc: "accepts connected steps", definition: sdk.PipelineDefinition{
+			Steps: steps, Connections: []sdk.PipelineConnection{{From: 1, To: 2}},
+		}, valid: true},
+		{desc: "rejects unknown source", definition: sdk.PipelineDefinition{
+			Steps: steps, Connections: []sdk.PipelineConnection{{From: 0, To: 2}},
+		}},
+		{desc: "rejects unknown target", definition: sdk.PipelineDefinition{
+			Steps: steps, Connections: []sdk.PipelineConnection{{From: 1, To: 3}},
+		}},
+		{desc: "rejects duplicate step ids", definition: sdk.PipelineDefinition{
+			Steps: []sdk.PipelineStep{{Id: 1}, {Id: 1}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkDefinition(test.definition)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckSubset(t *testing.T) {
+	if err := checkSubset([]string{"a"}, []string{"a", "b"}); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	if err := checkSubset([]string{"a", "c"}, []string{"a", "b"}); err == nil {
+		t.Error("expected err for update only returned for the later time")
+	}
+}
+
+func TestCheckLogs(t *testing.T) {
+	line := func(seconds int) sdk.LogLine {
+		return sdk.LogLine{Time: someTime.Add(time.Duration(seconds) * time.Second)}
+	}
+	tests := []struct {
+		desc  string
+		logs  sdk.AppLogs
+		valid bool
+	}{
+		{desc: "accepts ordered lines", logs: sdk.AppLogs{line(0), line(1), line(1)}, valid: true},
+		{desc: "accepts no lines", logs: sdk.AppLogs{}, valid: true},
+		{desc: "rejects lines over limit", logs: sdk.AppLogs{line(0), line(1), line(2), line(3)}},
+		{desc: "rejects lines before since", logs: sdk.AppLogs{line(-1), line(0)}},
+		{desc: "rejects newest first", logs: sdk.AppLogs{line(1), line(0)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkLogs(test.logs, someTime, 3)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckMetrics(t *testing.T) {
+	q := sdk.MetricsQuery{From: someTime, To: someTime.Add(time.Hour), Step: time.Minute}
+	series := func(metric sdk.Metric, minutes ...int) sdk.MetricSeries {
+		s := sdk.MetricSeries{Metric: metric, Unit: metric.Unit()}
+		for _, m := range minutes {
+			s.Points = append(s.Points, sdk.MetricPoint{Time: someTime.Add(time.Duration(m) * time.Minute)})
+		}
+		return s
+	}
+	tests := []struct {
+		desc    string
+		metrics sdk.AppMetrics
+		valid   bool
+	}{
+		{desc: "accepts series", metrics: sdk.AppMetrics{series(sdk.MetricCpu, 0, 1, 60), series(sdk.MetricMemory)}, valid: true},
+		{desc: "rejects series without unit", metrics: sdk.AppMetrics{{Metric: "custom"}}},
+		{desc: "rejects points outside of range", metrics: sdk.AppMetrics{series(sdk.MetricCpu, -1, 0)}},
+		{desc: "rejects unordered points", metrics: sdk.AppMetrics{series(sdk.MetricCpu, 1, 0)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkMetrics(test.metrics, q)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckAlerts(t *testing.T) {
+	alert := func(id string, kind sdk.AlertKind, severity sdk.AlertSeverity) sdk.Alert {
+		return sdk.Alert{Id: id, AppId: "app", Kind: kind, Severity: severity, Started: someTime}
+	}
+	tests := []struct {
+		desc   string
+		alerts []sdk.Alert
+		valid  bool
+	}{
+		{desc: "accepts alerts", alerts: []sdk.Alert{
+			alert("a", sdk.AlertKindAlert, sdk.AlertSeverityCritical),
+			alert("b", sdk.AlertKindIncident, sdk.AlertSeverityInfo),
+		}, valid: true},
+		{desc: "accepts no alerts", alerts: []sdk.Alert{}, valid: true},
+		{desc: "rejects alerts without app", alerts: []sdk.Alert{{Id: "a", Kind: sdk.AlertKindAlert, Severity: sdk.AlertSeverityInfo, Started: someTime}}},
+		{desc: "rejects duplicate alerts", alerts: []sdk.Alert{
+			alert("a", sdk.AlertKindAlert, sdk.AlertSeverityCritical),
+			alert("a", sdk.AlertKindAlert, sdk.AlertSeverityCritical),
+		}},
+		{desc: "rejects unknown kind", alerts: []sdk.Alert{alert("a", "page", sdk.AlertSeverityCritical)}},
+		{desc: "rejects unknown severity", alerts: []sdk.Alert{alert("a", sdk.AlertKindAlert, "high")}},
+		{desc: "rejects alerts without start", alerts: []sdk.Alert{{Id: "a", AppId: "app", Kind: sdk.AlertKindAlert, Severity: sdk.AlertSeverityInfo}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkAlerts(test.alerts)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckTopology(t *testing.T) {
+	db := sdk.BackingService{Id: "db", Name: "db"}
+	tests := []struct {
+		desc     string
+		topology sdk.Topology
+		valid    bool
+	}{
+		{desc: "accepts topology", topology: sdk.Topology{
+			Services: []sdk.BackingService{db},
+			Dependencies: []sdk.Dependency{
+				{AppId: "a", Target: sdk.DependencyTargetApp, TargetId: "b"},
+				{AppId: "a", Target: sdk.DependencyTargetService, TargetId: "db"},
+			},
+		}, valid: true},
+		{desc: "accepts empty topology", topology: sdk.Topology{}, valid: true},
+		{desc: "rejects duplicate services", topology: sdk.Topology{Services: []sdk.BackingService{db, db}}},
+		{desc: "rejects services without id", topology: sdk.Topology{Services: []sdk.BackingService{{Name: "db"}}}},
+		{desc: "rejects duplicate dependencies", topology: sdk.Topology{Dependencies: []sdk.Dependency{
+			{AppId: "a", Target: sdk.DependencyTargetApp, TargetId: "b"},
+			{AppId: "a", Target: sdk.DependencyTargetApp, TargetId: "b"},
+		}}},
+		{desc: "rejects dependencies without app", topology: sdk.Topology{Dependencies: []sdk.Dependency{
+			{Target: sdk.DependencyTargetApp, TargetId: "b"},
+		}}},
+		{desc: "rejects dependencies on unlisted services", topology: sdk.Topology{Dependencies: []sdk.Dependency{
+			{AppId: "a", Target: sdk.DependencyTargetService, TargetId: "db"},
+		}}},
+		{desc: "rejects unknown targets", topology: sdk.Topology{Dependencies: []sdk.Dependency{
+			{AppId: "a", Target: "queue", TargetId: "b"},
+		}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(tt *testing.T) {
+			err := checkTopology(test.topology)
+			if (err == nil) != test.valid {
+				tt.Errorf("\nwanted valid: %v, got err: %v", test.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// StepLogsProvider serves the logs of steps in pipeline runs. Runs are identified by the
+// time they started, as in PipelineStatus.
+type StepLogsProvider interface {
+	// GetStepLogs returns the given page of the lines the step logged in the run, oldest first.
+	// Pages are counted from 0. It returns ErrNotFound if the pipeline, run or step is unknown.
+	GetStepLogs(ctx context.Context, pipelineId string, runStarted time.Time, stepId int, perPage int, page int) (StepLogsPage, error)
+}
+
+type StepLogsPage struct {
+	Pagination
+	Lines []LogLine `json:"lines"`
+}
+
+// PageStepLogs returns the given page of the lines. Pages are counted from 0.
+func PageStepLogs(lines []LogLine, perPage int, page int) (StepLogsPage, error) {
+	if perPage <= 0 {
+		return StepLogsPage{}, ErrQueryPerPageMalformed
+	}
+	if page < 0 {
+		return StepLogsPage{}, ErrQueryPageMalformed
+	}
+
+	pages := (len(lines) + perPage - 1) / perPage
+	if page > 0 && page >= pages {
+		return StepLogsPage{}, ErrPageExceeded
+	}
+
+	res := StepLogsPage{
+		Pagination: Pagination{
+			TotalResults: len(lines),
+			TotalPages:   pages,
+			PerPage:      perPage,
+			Page:         page,
+		},
+		Lines: []LogLine{},
+	}
+
+	start := page * perPage
+	end := start + perPage
+	if end > len(lines) {
+		end = len(lines)
+	}
+	if start < end {
+		res.Lines = lines[start:end]
+	}
+	return res, nil
+}
@@ -0,0 +1,47 @@
+package sdk
+
+import "context"
+
+// TopologyProvider serves the dependencies of apps on other apps and on backing services.
+type TopologyProvider interface {
+	// GetTopology returns all dependencies known to the provider, along with the backing
+	// services they point to.
+	GetTopology(ctx context.Context) (Topology, error)
+}
+
+type Topology struct {
+	Services     []BackingService `json:"services"`
+	Dependencies []Dependency     `json:"dependencies"`
+}
+
+// BackingService is a service apps depend on which isn't an app itself, e.g. a database or a queue.
+type BackingService struct {
+	// Id identifies the service within its provider.
+	Id   string `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+	// Kind describes what the service offers, e.g. 'postgres' or 'rabbitmq'.
+	Kind   string            `json:"kind,omitempty" bson:"kind,omitempty"`
+	Labels map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+}
+
+// Dependency is an edge from an app to the app or backing service it depends on.
+type Dependency struct {
+	AppId  string           `json:"appId" bson:"appId"`
+	Target DependencyTarget `json:"target" bson:"target"`
+	// TargetId is the id of the app or of the backing service depended on.
+	TargetId string `json:"targetId" bson:"targetId"`
+	// Name describes the dependency, e.g. the name of a service binding.
+	Name string `json:"name,omitempty" bson:"name,omitempty"`
+}
+
+// Id identifies the dependency by the app and its target.
+func (d Dependency) Id() string {
+	return d.AppId + "/" + string(d.Target) + "/" + d.TargetId
+}
+
+type DependencyTarget string
+
+const (
+	DependencyTargetApp     DependencyTarget = "app"
+	DependencyTargetService DependencyTarget = "service"
+)